@@ -29,6 +29,7 @@ var (
 	ScopeProvisionersDatasources   = ac.Scope("provisioners", "datasources")
 	ScopeProvisionersNotifications = ac.Scope("provisioners", "notifications")
 	ScopeProvisionersAlertRules    = ac.Scope("provisioners", "alerting")
+	ScopeProvisionersAccess        = ac.Scope("provisioners", "access")
 )
 
 // declareFixedRoles declares to the AccessControl service fixed roles and their
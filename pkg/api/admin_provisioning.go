@@ -85,3 +85,26 @@ func (hs *HTTPServer) AdminProvisioningReloadAlerting(c *contextmodel.ReqContext
 	}
 	return response.Success("Alerting config reloaded")
 }
+
+// swagger:route POST /admin/provisioning/access/reload admin_provisioning adminProvisioningReloadAccess
+//
+// Reload access provisioning configurations.
+//
+// Reloads the provisioning config files for users, teams, service accounts, folders and permissions again. It won’t return until the new provisioned entities are already stored in the database.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `provisioning:reload` and scope `provisioners:access`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminProvisioningReloadAccess(c *contextmodel.ReqContext) response.Response {
+	err := hs.ProvisioningService.ProvisionAccess(c.Req.Context())
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to reload access config", err)
+	}
+	return response.Success("Access config reloaded")
+}
@@ -621,6 +621,16 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 		return apierrors.ToDashboardErrorResponse(ctx, hs.pluginStore, saveErr)
 	}
 
+	// Write the changes of provisioned dashboards back to the source of the provisioner, if it supports it.
+	// The dashboard is saved either way, so a failure is returned as a warning.
+	var warning string
+	if provisioningData != nil && allowUiUpdate {
+		if err := hs.ProvisioningService.PushDashboardFromUI(ctx, provisioningData, dashboard, cmd.Message, c.SignedInUser); err != nil {
+			hs.log.Warn("Failed to push provisioned dashboard to its source", "uid", dashboard.UID, "provisioner", provisioningData.Name, "error", err)
+			warning = fmt.Sprintf("The dashboard was saved, but the changes could not be written to provisioner %s, and are overwritten when it provisions the dashboard again: %s", provisioningData.Name, err)
+		}
+	}
+
 	// Clear permission cache for the user who's created the dashboard, so that new permissions are fetched for their next call
 	// Required for cases when caller wants to immediately interact with the newly created object
 	if newDashboard {
@@ -634,7 +644,7 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 	}
 
 	c.TimeRequest(metrics.MApiDashboardSave)
-	return response.JSON(http.StatusOK, util.DynMap{
+	result := util.DynMap{
 		"status":    "success",
 		"slug":      dashboard.Slug,
 		"version":   dashboard.Version,
@@ -642,7 +652,11 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 		"uid":       dashboard.UID,
 		"url":       dashboard.GetURL(),
 		"folderUid": dashboard.FolderUID,
-	})
+	}
+	if warning != "" {
+		result["warning"] = warning
+	}
+	return response.JSON(http.StatusOK, result)
 }
 
 // swagger:route GET /dashboards/home dashboards getHomeDashboard
@@ -1058,6 +1072,138 @@ func (hs *HTTPServer) CalculateDashboardDiff(c *contextmodel.ReqContext) respons
 	return response.Respond(http.StatusOK, result.Delta).SetHeader("Content-Type", "text/html")
 }
 
+// swagger:route GET /dashboards/uid/{uid}/diff dashboard_versions getDashboardVersionsDiffByUID
+//
+// Get the JSON patch between two versions of a dashboard.
+//
+// Returns the operations of a JSON patch (RFC 6902) that transforms the base version into the new version.
+//
+// Responses:
+// 200: dashboardVersionsDiffResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetDashboardVersionsDiff(c *contextmodel.ReqContext) response.Response {
+	ctx, span := tracer.Start(c.Req.Context(), "api.GetDashboardVersionsDiff")
+	defer span.End()
+	c.Req = c.Req.WithContext(ctx)
+
+	baseVersion, newVersion := c.QueryInt("base"), c.QueryInt("new")
+	if baseVersion < 1 || newVersion < 1 {
+		return response.Error(http.StatusBadRequest, "base and new versions are required", nil)
+	}
+
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.SignedInUser.GetOrgID(), 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+
+	guardian, err := guardian.NewByDashboard(c.Req.Context(), dash, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	if canSave, err := guardian.CanSave(); err != nil || !canSave {
+		return dashboardGuardianResponse(err)
+	}
+
+	base, rsp := hs.getDashboardVersionHelper(c.Req.Context(), dash, baseVersion)
+	if rsp != nil {
+		return rsp
+	}
+	updated, rsp := hs.getDashboardVersionHelper(c.Req.Context(), dash, newVersion)
+	if rsp != nil {
+		return rsp
+	}
+
+	patch, err := dashdiffs.CalculatePatch(base.Data, updated.Data)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Unable to compute diff", err)
+	}
+
+	return response.JSON(http.StatusOK, dtos.DashboardVersionsDiff{Base: baseVersion, New: newVersion, Patch: patch})
+}
+
+// swagger:route POST /dashboards/uid/{uid}/merge dashboard_versions mergeDashboardByUID
+//
+// Merge unsaved changes with the saved dashboard.
+//
+// Does a three-way merge of a dashboard with unsaved changes and the dashboard that was saved after the version
+// the changes were made to. Changes to different panels are merged. Panels and other properties that were changed
+// on both sides are returned as conflicts, and have the saved value in the merged dashboard.
+// The merged dashboard is not saved.
+//
+// Responses:
+// 200: mergeDashboardResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) MergeDashboard(c *contextmodel.ReqContext) response.Response {
+	ctx, span := tracer.Start(c.Req.Context(), "api.MergeDashboard")
+	defer span.End()
+	c.Req = c.Req.WithContext(ctx)
+
+	cmd := dtos.MergeDashboardCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.SignedInUser.GetOrgID(), 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+
+	guardian, err := guardian.NewByDashboard(c.Req.Context(), dash, c.SignedInUser.GetOrgID(), c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	if canSave, err := guardian.CanSave(); err != nil || !canSave {
+		return dashboardGuardianResponse(err)
+	}
+
+	if cmd.Version == dash.Version {
+		return response.JSON(http.StatusOK, dtos.MergeDashboardResult{Version: dash.Version, Dashboard: cmd.Dashboard, Conflicts: []dashdiffs.Conflict{}})
+	}
+
+	base, rsp := hs.getDashboardVersionHelper(c.Req.Context(), dash, cmd.Version)
+	if rsp != nil {
+		return rsp
+	}
+
+	result, err := dashdiffs.Merge(base.Data, cmd.Dashboard, dash.Data)
+	if err != nil {
+		if errors.Is(err, dashdiffs.ErrNotAnObject) {
+			return response.Error(http.StatusBadRequest, "Dashboard is not an object", err)
+		}
+		return response.Error(http.StatusInternalServerError, "Unable to merge dashboard", err)
+	}
+	result.Dashboard.Set("version", dash.Version)
+
+	return response.JSON(http.StatusOK, dtos.MergeDashboardResult{Version: dash.Version, Dashboard: result.Dashboard, Conflicts: result.Conflicts})
+}
+
+func (hs *HTTPServer) getDashboardVersionHelper(ctx context.Context, dash *dashboards.Dashboard, version int) (*dashver.DashboardVersionDTO, response.Response) {
+	query := dashver.GetDashboardVersionQuery{
+		OrgID:        dash.OrgID,
+		DashboardID:  dash.ID,
+		DashboardUID: dash.UID,
+		Version:      version,
+	}
+	res, err := hs.dashboardVersionService.Get(ctx, &query)
+	if err != nil {
+		if errors.Is(err, dashver.ErrDashboardVersionNotFound) {
+			return nil, response.Error(http.StatusNotFound, fmt.Sprintf("Dashboard version %d not found", version), err)
+		}
+		return nil, response.Error(http.StatusInternalServerError, "Failed to get dashboard version", err)
+	}
+	return res, nil
+}
+
 // swagger:route POST /dashboards/id/{DashboardID}/restore dashboard_versions restoreDashboardVersionByID
 //
 // Restore a dashboard to a given dashboard version.
@@ -1367,9 +1513,51 @@ type PostDashboardResponse struct {
 		// FolderUID The unique identifier (uid) of the folder the dashboard belongs to.
 		// required: false
 		FolderUID string `json:"folderUid"`
+
+		// Warning is set if the dashboard was saved, but a follow-up step failed. For example, if the changes of a
+		// provisioned dashboard could not be pushed to the git repository that it is provisioned from.
+		// required: false
+		Warning string `json:"warning,omitempty"`
 	} `json:"body"`
 }
 
+// swagger:parameters getDashboardVersionsDiffByUID
+type GetDashboardVersionsDiffByUIDParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// The version to compare from
+	// in:query
+	// required:true
+	Base int `json:"base"`
+	// The version to compare to
+	// in:query
+	// required:true
+	New int `json:"new"`
+}
+
+// swagger:parameters mergeDashboardByUID
+type MergeDashboardByUIDParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// in:body
+	// required:true
+	Body dtos.MergeDashboardCommand
+}
+
+// swagger:response dashboardVersionsDiffResponse
+type DashboardVersionsDiffResponse struct {
+	// in: body
+	Body dtos.DashboardVersionsDiff `json:"body"`
+}
+
+// swagger:response mergeDashboardResponse
+type MergeDashboardResponse struct {
+	// in: body
+	Body dtos.MergeDashboardResult `json:"body"`
+}
+
 // swagger:response calculateDashboardDiffResponse
 type CalculateDashboardDiffResponse struct {
 	// in: body
@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboards/dashboardaccess"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -147,6 +148,9 @@ func (hs *HTTPServer) UpdateDashboardPermissions(c *contextmodel.ReqContext) res
 	if rsp != nil {
 		return rsp
 	}
+	if err := provenance.CheckNotProvisioned(c.Req.Context(), hs.provenanceService, dash.OrgID, provenance.KindDashboardPermissions, dash.UID); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to check the provenance of the dashboard permissions", err)
+	}
 
 	items := make([]*dashboards.DashboardACL, 0, len(apiCmd.Items))
 	for _, item := range apiCmd.Items {
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strings"
@@ -13,6 +14,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance/provenancetest"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web/webtest"
 )
@@ -156,4 +159,23 @@ func TestHTTPServer_UpdateDashboardPermissions(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
 		require.NoError(t, res.Body.Close())
 	})
+
+	t.Run("should not be able to update provisioned acl", func(t *testing.T) {
+		provenanceService := provenancetest.NewFakeService()
+		require.NoError(t, provenanceService.SetProvenance(context.Background(), 1, provenance.KindDashboardPermissions, "1", provenance.ProvenanceFile))
+		server := SetupAPITestServer(t, func(hs *HTTPServer) {
+			hs.DashboardService = dashboards.NewFakeDashboardService(t)
+			hs.dashboardPermissionsService = &actest.FakePermissionsService{}
+			hs.provenanceService = provenanceService
+		})
+
+		body := `{"items": []}`
+		res, err := server.SendJSON(webtest.RequestWithSignedInUser(server.NewPostRequest("/api/dashboards/uid/1/permissions", strings.NewReader(body)), userWithPermissions(1, []accesscontrol.Permission{
+			{Action: dashboards.ActionDashboardsPermissionsWrite, Scope: "dashboards:uid:1"},
+		})))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
 }
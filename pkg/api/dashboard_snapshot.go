@@ -18,6 +18,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/util/errhttp"
 	"github.com/grafana/grafana/pkg/web"
@@ -307,6 +309,207 @@ func (hs *HTTPServer) SearchDashboardSnapshots(c *contextmodel.ReqContext) respo
 	return response.JSON(http.StatusOK, dto)
 }
 
+// swagger:route POST /snapshots/build snapshots buildDashboardSnapshot
+//
+// Create a snapshot of a saved dashboard on the server.
+//
+// The queries of every panel are run for the time range of the request, and the data is stored in the snapshot.
+// Requires permission to save the dashboard.
+//
+// Responses:
+// 200: createDashboardSnapshotResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) BuildDashboardSnapshot(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	cmd := dashboardsnapshots.BuildDashboardSnapshotCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if !hs.canSaveSnapshotDashboard(c, cmd.DashboardUID) {
+		return response.Error(http.StatusForbidden, "forbidden", nil)
+	}
+
+	cmd.SignedInUser = c.SignedInUser
+	result, err := hs.dashboardsnapshotsService.BuildDashboardSnapshot(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to build snapshot", err)
+	}
+
+	metrics.MApiDashboardSnapshotCreate.Inc()
+
+	return response.JSON(http.StatusOK, snapshotCreateResponse(result))
+}
+
+// swagger:route POST /snapshots/{key}/refresh snapshots refreshDashboardSnapshot
+//
+// Refresh the data of a snapshot.
+//
+// Runs the queries of the dashboard of the snapshot again for the time range that the snapshot was built with.
+// Only snapshots that were built on the server can be refreshed. Requires permission to save the dashboard.
+//
+// Responses:
+// 200: createDashboardSnapshotResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) RefreshDashboardSnapshot(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	key := web.Params(c.Req)[":key"]
+	snapshot, err := hs.dashboardsnapshotsService.GetDashboardSnapshot(c.Req.Context(), &dashboardsnapshots.GetDashboardSnapshotQuery{Key: key})
+	if err != nil {
+		return response.Err(err)
+	}
+	if snapshot.OrgID != c.SignedInUser.GetOrgID() {
+		return response.Error(http.StatusNotFound, "Snapshot not found", nil)
+	}
+	if snapshot.DashboardUID != "" && !hs.canSaveSnapshotDashboard(c, snapshot.DashboardUID) {
+		return response.Error(http.StatusForbidden, "forbidden", nil)
+	}
+
+	result, err := hs.dashboardsnapshotsService.RefreshDashboardSnapshot(c.Req.Context(), &dashboardsnapshots.RefreshDashboardSnapshotCommand{
+		Key:          snapshot.Key,
+		SignedInUser: c.SignedInUser,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to refresh snapshot", err)
+	}
+
+	return response.JSON(http.StatusOK, snapshotCreateResponse(result))
+}
+
+// swagger:route GET /snapshot-schedules snapshots searchSnapshotSchedules
+//
+// List snapshot schedules.
+//
+// Admins can see all schedules of the organization, other users can see the schedules that they created.
+//
+// Responses:
+// 200: searchSnapshotSchedulesResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) SearchSnapshotSchedules(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	query := dashboardsnapshots.SearchSnapshotSchedulesQuery{OrgID: c.SignedInUser.GetOrgID()}
+	if c.SignedInUser.GetOrgRole() != org.RoleAdmin {
+		query.UserID = c.SignedInUser.UserID
+	}
+	schedules, err := hs.dashboardsnapshotsService.SearchSnapshotSchedules(c.Req.Context(), &query)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get snapshot schedules", err)
+	}
+	return response.JSON(http.StatusOK, schedules)
+}
+
+// swagger:route POST /snapshot-schedules snapshots createSnapshotSchedule
+//
+// Create a snapshot schedule.
+//
+// A snapshot of the dashboard is built on the server at every time of the cron schedule, with the permissions of the
+// user who created the schedule. Requires permission to save the dashboard.
+//
+// Responses:
+// 200: snapshotScheduleResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) CreateSnapshotSchedule(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	cmd := dashboardsnapshots.CreateSnapshotScheduleCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	if !hs.canSaveSnapshotDashboard(c, cmd.DashboardUID) {
+		return response.Error(http.StatusForbidden, "forbidden", nil)
+	}
+
+	cmd.OrgID = c.SignedInUser.GetOrgID()
+	cmd.UserID = c.SignedInUser.UserID
+	schedule, err := hs.dashboardsnapshotsService.CreateSnapshotSchedule(c.Req.Context(), &cmd)
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return response.Error(http.StatusBadRequest, "Dashboard not found", err)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to create snapshot schedule", err)
+	}
+	return response.JSON(http.StatusOK, schedule)
+}
+
+// swagger:route DELETE /snapshot-schedules/{uid} snapshots deleteSnapshotSchedule
+//
+// Delete a snapshot schedule.
+//
+// The snapshots that were built by the schedule are not deleted. Requires permission to save the dashboard.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DeleteSnapshotSchedule(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	uid := web.Params(c.Req)[":uid"]
+	schedule, err := hs.dashboardsnapshotsService.GetSnapshotSchedule(c.Req.Context(), &dashboardsnapshots.GetSnapshotScheduleQuery{
+		UID:   uid,
+		OrgID: c.SignedInUser.GetOrgID(),
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to get snapshot schedule", err)
+	}
+	if schedule.UserID != c.SignedInUser.UserID && !hs.canSaveSnapshotDashboard(c, schedule.DashboardUID) {
+		return response.Error(http.StatusForbidden, "forbidden", nil)
+	}
+
+	err = hs.dashboardsnapshotsService.DeleteSnapshotSchedule(c.Req.Context(), &dashboardsnapshots.DeleteSnapshotScheduleCommand{
+		UID:   schedule.UID,
+		OrgID: schedule.OrgID,
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to delete snapshot schedule", err)
+	}
+	return response.Success("Snapshot schedule deleted")
+}
+
+// canSaveSnapshotDashboard returns whether the user can save the dashboard, which is required to create snapshots of
+// the dashboard on the server.
+func (hs *HTTPServer) canSaveSnapshotDashboard(c *contextmodel.ReqContext, dashboardUID string) bool {
+	evaluator := ac.EvalPermission(dashboards.ActionDashboardsWrite, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(dashboardUID))
+	canSave, err := hs.AccessControl.Evaluate(c.Req.Context(), c.SignedInUser, evaluator)
+	return err == nil && canSave
+}
+
+func snapshotCreateResponse(snapshot *dashboardsnapshots.DashboardSnapshot) dashboardsnapshot.DashboardCreateResponse {
+	return dashboardsnapshot.DashboardCreateResponse{
+		Key:       snapshot.Key,
+		DeleteKey: snapshot.DeleteKey,
+		URL:       setting.ToAbsUrl("dashboard/snapshot/" + snapshot.Key),
+		DeleteURL: setting.ToAbsUrl("api/snapshots-delete/" + snapshot.DeleteKey),
+	}
+}
+
 // swagger:parameters createDashboardSnapshot
 type CreateSnapshotParams struct {
 	// in:body
@@ -325,6 +528,32 @@ type GetSnapshotsParams struct {
 	Limit int64 `json:"limit"`
 }
 
+// swagger:parameters buildDashboardSnapshot
+type BuildSnapshotParams struct {
+	// in:body
+	// required:true
+	Body dashboardsnapshots.BuildDashboardSnapshotCommand `json:"body"`
+}
+
+// swagger:parameters refreshDashboardSnapshot
+type RefreshDashboardSnapshotParams struct {
+	// in:path
+	Key string `json:"key"`
+}
+
+// swagger:parameters createSnapshotSchedule
+type CreateSnapshotScheduleParams struct {
+	// in:body
+	// required:true
+	Body dashboardsnapshots.CreateSnapshotScheduleCommand `json:"body"`
+}
+
+// swagger:parameters deleteSnapshotSchedule
+type DeleteSnapshotScheduleParams struct {
+	// in:path
+	UID string `json:"uid"`
+}
+
 // swagger:parameters getDashboardSnapshot
 type GetDashboardSnapshotParams struct {
 	// in:path
@@ -364,6 +593,18 @@ type SearchDashboardSnapshotsResponse struct {
 	Body []*dashboardsnapshots.DashboardSnapshotDTO `json:"body"`
 }
 
+// swagger:response searchSnapshotSchedulesResponse
+type SearchSnapshotSchedulesResponse struct {
+	// in:body
+	Body []*dashboardsnapshots.DashboardSnapshotSchedule `json:"body"`
+}
+
+// swagger:response snapshotScheduleResponse
+type SnapshotScheduleResponse struct {
+	// in:body
+	Body *dashboardsnapshots.DashboardSnapshotSchedule `json:"body"`
+}
+
 // swagger:response getDashboardSnapshotResponse
 type GetDashboardSnapshotResponse DashboardResponse
 
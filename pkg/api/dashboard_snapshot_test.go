@@ -125,6 +125,119 @@ func TestHTTPServer_DeleteDashboardSnapshot(t *testing.T) {
 	})
 }
 
+func TestHTTPServer_ServerSideSnapshots(t *testing.T) {
+	setup := func(t *testing.T, svc dashboardsnapshots.Service) *webtest.Server {
+		t.Helper()
+
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			cfg := setting.NewCfg()
+			cfg.SnapshotEnabled = true
+			hs.Cfg = cfg
+			hs.dashboardsnapshotsService = svc
+			hs.AccessControl = acimpl.ProvideAccessControl(featuremgmt.WithFeatures(), zanzana.NewNoopClient())
+		})
+	}
+
+	jsonRequest := func(server *webtest.Server, method, target, body string) *http.Request {
+		req := server.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	allowedUser := userWithPermissions(1, []accesscontrol.Permission{
+		{Action: dashboards.ActionDashboardsWrite, Scope: "dashboards:uid:dash"},
+	})
+
+	t.Run("User should not be able to build a snapshot without permissions", func(t *testing.T) {
+		server := setup(t, dashboardsnapshots.NewMockService(t))
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			jsonRequest(server, http.MethodPost, "/api/snapshots/build", `{"dashboardUid": "dash"}`),
+			&user.SignedInUser{UserID: 1, OrgID: 1},
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("User should be able to build a snapshot of a dashboard they can save", func(t *testing.T) {
+		svc := dashboardsnapshots.NewMockService(t)
+		svc.On("BuildDashboardSnapshot", mock.Anything, mock.MatchedBy(func(cmd *dashboardsnapshots.BuildDashboardSnapshotCommand) bool {
+			return cmd.DashboardUID == "dash" && cmd.From == "now-7d" && cmd.SignedInUser != nil
+		})).Return(&dashboardsnapshots.DashboardSnapshot{Key: "key", DeleteKey: "deleteKey"}, nil)
+		server := setup(t, svc)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			jsonRequest(server, http.MethodPost, "/api/snapshots/build", `{"dashboardUid": "dash", "from": "now-7d"}`),
+			allowedUser,
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		body, err := simplejson.NewFromReader(res.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "key", body.Get("key").MustString())
+		assert.True(t, strings.HasSuffix(body.Get("url").MustString(), "dashboard/snapshot/key"))
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("User should not be able to refresh a snapshot of another org", func(t *testing.T) {
+		svc := dashboardsnapshots.NewMockService(t)
+		svc.On("GetDashboardSnapshot", mock.Anything, mock.Anything).Return(&dashboardsnapshots.DashboardSnapshot{Key: "key", OrgID: 2, DashboardUID: "dash"}, nil)
+		server := setup(t, svc)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			server.NewRequest(http.MethodPost, "/api/snapshots/key/refresh", nil),
+			allowedUser,
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("User should be able to refresh a snapshot of a dashboard they can save", func(t *testing.T) {
+		svc := dashboardsnapshots.NewMockService(t)
+		svc.On("GetDashboardSnapshot", mock.Anything, mock.Anything).Return(&dashboardsnapshots.DashboardSnapshot{Key: "key", OrgID: 1, DashboardUID: "dash"}, nil)
+		svc.On("RefreshDashboardSnapshot", mock.Anything, mock.Anything).Return(&dashboardsnapshots.DashboardSnapshot{Key: "key", DeleteKey: "deleteKey"}, nil)
+		server := setup(t, svc)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			server.NewRequest(http.MethodPost, "/api/snapshots/key/refresh", nil),
+			allowedUser,
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Creating a schedule with an invalid cron expression should return 400", func(t *testing.T) {
+		svc := dashboardsnapshots.NewMockService(t)
+		svc.On("CreateSnapshotSchedule", mock.Anything, mock.Anything).Return(nil, dashboardsnapshots.ErrInvalidSchedule.Errorf("invalid"))
+		server := setup(t, svc)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			jsonRequest(server, http.MethodPost, "/api/snapshot-schedules", `{"dashboardUid": "dash", "schedule": "every monday"}`),
+			allowedUser,
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("User should not be able to delete the schedule of another user without permissions", func(t *testing.T) {
+		svc := dashboardsnapshots.NewMockService(t)
+		svc.On("GetSnapshotSchedule", mock.Anything, mock.Anything).Return(&dashboardsnapshots.DashboardSnapshotSchedule{UID: "weekly", OrgID: 1, UserID: 2, DashboardUID: "dash"}, nil)
+		server := setup(t, svc)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(
+			server.NewRequest(http.MethodDelete, "/api/snapshot-schedules/weekly", nil),
+			&user.SignedInUser{UserID: 1, OrgID: 1},
+		))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
 func TestDashboardSnapshotAPIEndpoint_singleSnapshot(t *testing.T) {
 	setupRemoteServer := func(fn func(http.ResponseWriter, *http.Request)) *httptest.Server {
 		s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
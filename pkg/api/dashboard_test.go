@@ -3,9 +3,12 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -379,6 +382,98 @@ func TestHTTPServer_GetDashboardVersions_AccessControl(t *testing.T) {
 	})
 }
 
+func TestHTTPServer_DashboardVersionsDiffAndMerge(t *testing.T) {
+	permissions := []accesscontrol.Permission{
+		{Action: dashboards.ActionDashboardsRead, Scope: "dashboards:uid:1"},
+		{Action: dashboards.ActionDashboardsWrite, Scope: "dashboards:uid:1"},
+	}
+	version := func(v int, data string) *dashver.DashboardVersionDTO {
+		j, err := simplejson.NewJson([]byte(data))
+		require.NoError(t, err)
+		return &dashver.DashboardVersionDTO{DashboardID: 1, DashboardUID: "1", Version: v, Data: j}
+	}
+	setup := func(saved string, versions ...*dashver.DashboardVersionDTO) *webtest.Server {
+		return SetupAPITestServer(t, func(hs *HTTPServer) {
+			data, err := simplejson.NewJson([]byte(saved))
+			require.NoError(t, err)
+			data.Set("uid", "1")
+			dash := dashboards.NewDashboardFromJson(data)
+			dash.ID = 1
+
+			dashSvc := dashboards.NewFakeDashboardService(t)
+			dashSvc.On("GetDashboard", mock.Anything, mock.Anything).Return(dash, nil).Maybe()
+			hs.DashboardService = dashSvc
+
+			hs.Cfg = setting.NewCfg()
+			hs.AccessControl = acimpl.ProvideAccessControl(featuremgmt.WithFeatures(), zanzana.NewNoopClient())
+			hs.dashboardVersionService = &dashvertest.FakeDashboardVersionService{ExpectedDashboardVersions: versions}
+
+			guardian.InitAccessControlGuardian(hs.Cfg, hs.AccessControl, hs.DashboardService)
+		})
+	}
+
+	t.Run("Should return the JSON patch between two versions", func(t *testing.T) {
+		server := setup(`{"title": "New", "version": 2}`,
+			version(1, `{"title": "Old", "version": 1}`),
+			version(2, `{"title": "New", "version": 2}`),
+		)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/dashboards/uid/1/diff?base=1&new=2"), userWithPermissions(1, permissions)))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		require.JSONEq(t, `{"base": 1, "new": 2, "patch": [
+			{"op": "replace", "path": "/title", "value": "New"},
+			{"op": "replace", "path": "/version", "value": 2}
+		]}`, string(body))
+	})
+
+	t.Run("Should require the versions to compare", func(t *testing.T) {
+		server := setup(`{"title": "New", "version": 2}`)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/dashboards/uid/1/diff?base=1"), userWithPermissions(1, permissions)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Should not be able to diff or merge without correct permission", func(t *testing.T) {
+		server := setup(`{"title": "New", "version": 2}`)
+
+		res, err := server.Send(webtest.RequestWithSignedInUser(server.NewGetRequest("/api/dashboards/uid/1/diff?base=1&new=2"), userWithPermissions(1, nil)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+
+		res, err = server.SendJSON(webtest.RequestWithSignedInUser(server.NewPostRequest("/api/dashboards/uid/1/merge", strings.NewReader(`{"version": 1, "dashboard": {}}`)), userWithPermissions(1, nil)))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("Should merge changes to different panels", func(t *testing.T) {
+		server := setup(`{"title": "Title", "version": 2, "panels": [{"id": 1, "title": "A"}, {"id": 2, "title": "B changed"}]}`,
+			version(1, `{"title": "Title", "version": 1, "panels": [{"id": 1, "title": "A"}, {"id": 2, "title": "B"}]}`),
+		)
+
+		body := `{"version": 1, "dashboard": {"title": "Title", "version": 1, "panels": [{"id": 1, "title": "A changed"}, {"id": 2, "title": "B"}]}}`
+		res, err := server.SendJSON(webtest.RequestWithSignedInUser(server.NewPostRequest("/api/dashboards/uid/1/merge", strings.NewReader(body)), userWithPermissions(1, permissions)))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var result dtos.MergeDashboardResult
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&result))
+		require.NoError(t, res.Body.Close())
+
+		assert.Equal(t, 2, result.Version)
+		assert.Empty(t, result.Conflicts)
+		assert.Equal(t, 2, result.Dashboard.Get("version").MustInt())
+		assert.Equal(t, "A changed", result.Dashboard.Get("panels").GetIndex(0).Get("title").MustString())
+		assert.Equal(t, "B changed", result.Dashboard.Get("panels").GetIndex(1).Get("title").MustString())
+	})
+}
+
 func TestDashboardAPIEndpoint(t *testing.T) {
 	t.Run("Given two dashboards with the same title in different folders", func(t *testing.T) {
 		dashOne := dashboards.NewDashboard("dash")
@@ -936,6 +1031,67 @@ func postDashboardScenario(t *testing.T, desc string, url string, routePattern s
 	})
 }
 
+func TestPostDashboard_PushProvisionedDashboard(t *testing.T) {
+	cmd := dashboards.SaveDashboardCommand{
+		OrgID:     1,
+		UserID:    5,
+		Dashboard: simplejson.NewFromAny(map[string]any{"id": 1, "uid": "uid", "title": "Dash"}),
+		Message:   "msg",
+	}
+
+	for _, tc := range []struct {
+		name    string
+		pushErr error
+		warning string
+	}{
+		{name: "pushed", pushErr: nil, warning: ""},
+		{name: "push failed", pushErr: errors.New("rejected"), warning: "could not be written to provisioner git"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dashboardService := dashboards.NewFakeDashboardService(t)
+			dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), true).
+				Return(&dashboards.Dashboard{ID: 1, UID: "uid", Title: "Dash", Slug: "dash", Version: 2}, nil)
+			provisioningService := provisioning.NewProvisioningServiceMock(context.Background())
+			provisioningService.GetAllowUIUpdatesFromConfigFunc = func(name string) bool { return true }
+			provisioningService.PushDashboardFromUIFunc = func(context.Context, *dashboards.DashboardProvisioning, *dashboards.Dashboard, string, identity.Requester) error {
+				return tc.pushErr
+			}
+			hs := HTTPServer{
+				Cfg:                          setting.NewCfg(),
+				ProvisioningService:          provisioningService,
+				dashboardProvisioningService: provisionedDashboardProvisioningService{data: &dashboards.DashboardProvisioning{Name: "git"}},
+				LibraryPanelService:          &mockLibraryPanelService{},
+				LibraryElementService:        &libraryelementsfake.LibraryElementService{},
+				DashboardService:             dashboardService,
+				Features:                     featuremgmt.WithFeatures(),
+				log:                          log.New("test-logger"),
+				tracer:                       tracing.InitializeTracerForTest(),
+			}
+
+			sc := setupScenarioContext(t, "/api/dashboards")
+			sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+				c.Req.Body = mockRequestBody(cmd)
+				c.Req.Header.Add("Content-Type", "application/json")
+				sc.context = c
+				sc.context.SignedInUser = &user.SignedInUser{OrgID: cmd.OrgID, UserID: cmd.UserID}
+				return hs.PostDashboard(c)
+			})
+			sc.m.Post("/api/dashboards", sc.defaultHandler)
+
+			callPostDashboardShouldReturnSuccess(sc)
+			require.Len(t, provisioningService.Calls.PushDashboardFromUI, 1)
+			result := sc.ToJSON()
+			require.Equal(t, "success", result.Get("status").MustString())
+			if tc.warning == "" {
+				_, ok := result.CheckGet("warning")
+				require.False(t, ok)
+			} else {
+				require.Contains(t, result.Get("warning").MustString(), tc.warning)
+			}
+		})
+	}
+}
+
 func postDiffScenario(t *testing.T, desc string, url string, routePattern string, cmd dtos.CalculateDiffOptions,
 	role org.RoleType, fn scenarioFunc, sqlmock db.DB, fakeDashboardVersionService *dashvertest.FakeDashboardVersionService,
 ) {
@@ -1041,6 +1197,17 @@ func (s mockDashboardProvisioningService) GetProvisionedDashboardDataByDashboard
 	return nil, nil
 }
 
+type provisionedDashboardProvisioningService struct {
+	dashboards.DashboardProvisioningService
+	data *dashboards.DashboardProvisioning
+}
+
+func (s provisionedDashboardProvisioningService) GetProvisionedDashboardDataByDashboardID(ctx context.Context, dashboardID int64) (
+	*dashboards.DashboardProvisioning, error,
+) {
+	return s.data, nil
+}
+
 type mockLibraryPanelService struct{}
 
 var _ librarypanels.Service = (*mockLibraryPanelService)(nil)
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	dashboardsV0 "github.com/grafana/grafana/pkg/apis/dashboard/v0alpha1"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 )
 
@@ -61,3 +62,23 @@ type CalculateDiffTarget struct {
 type RestoreDashboardVersionCommand struct {
 	Version int `json:"version" binding:"Required"`
 }
+
+type DashboardVersionsDiff struct {
+	Base int `json:"base"`
+	New  int `json:"new"`
+	// Patch is the JSON patch that transforms the base version into the new version.
+	Patch []dashdiffs.PatchOperation `json:"patch"`
+}
+
+type MergeDashboardCommand struct {
+	// Version is the version of the dashboard that the unsaved changes were made to.
+	Version   int              `json:"version" binding:"Required"`
+	Dashboard *simplejson.Json `json:"dashboard" binding:"Required"`
+}
+
+type MergeDashboardResult struct {
+	// Version is the current version of the dashboard, which the merged dashboard can be saved on top of.
+	Version   int                  `json:"version"`
+	Dashboard *simplejson.Json     `json:"dashboard"`
+	Conflicts []dashdiffs.Conflict `json:"conflicts"`
+}
@@ -79,6 +79,9 @@ type MetricRequest struct {
 	Queries []*simplejson.Json `json:"queries"`
 	// required: false
 	Debug bool `json:"debug"`
+	// Adds a table with the execution details of each server side expression node to the response, under the refId __explain__.
+	// required: false
+	Explain bool `json:"explain"`
 }
 
 func (mr *MetricRequest) GetUniqueDatasourceTypes() []string {
@@ -106,6 +109,7 @@ func (mr *MetricRequest) CloneWithQueries(queries []*simplejson.Json) MetricRequ
 		To:      mr.To,
 		Queries: queries,
 		Debug:   mr.Debug,
+		Explain: mr.Explain,
 	}
 }
 
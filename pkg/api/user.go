@@ -14,6 +14,7 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/login"
 	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util"
@@ -164,6 +165,10 @@ func (hs *HTTPServer) UpdateSignedInUser(c *contextmodel.ReqContext) response.Re
 		}
 	}
 
+	if err := provenance.CheckNotProvisioned(c.Req.Context(), hs.provenanceService, provenance.GlobalOrgID, provenance.KindUser, strconv.FormatInt(userID, 10)); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "Failed to check the provenance of the user", err)
+	}
+
 	cmd.UserID = userID
 	return hs.handleUpdateUser(c.Req.Context(), cmd)
 }
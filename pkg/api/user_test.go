@@ -36,6 +36,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/login/authinfotest"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/org/orgimpl"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance/provenancetest"
 	"github.com/grafana/grafana/pkg/services/quota/quotatest"
 	"github.com/grafana/grafana/pkg/services/searchusers"
 	"github.com/grafana/grafana/pkg/services/searchusers/filters"
@@ -420,6 +421,7 @@ func setupUpdateEmailTests(t *testing.T, cfg *setting.Cfg) (*user.User, *HTTPSer
 		tempUserService:     tempUserService,
 		NotificationService: nsMock,
 		userVerifier:        verifier,
+		provenanceService:   provenancetest.NewFakeService(),
 	}
 	return usr, hs, nsMock
 }
@@ -1105,10 +1107,11 @@ func TestHTTPServer_UpdateSignedInUser(t *testing.T) {
 	settings.SAMLAuthEnabled = true
 
 	hs := &HTTPServer{
-		Cfg:           settings,
-		SQLStore:      sqlStore,
-		AccessControl: acmock.New(),
-		SocialService: &socialtest.FakeSocialService{},
+		Cfg:               settings,
+		SQLStore:          sqlStore,
+		AccessControl:     acmock.New(),
+		SocialService:     &socialtest.FakeSocialService{},
+		provenanceService: provenancetest.NewFakeService(),
 	}
 
 	updateUserCommand := user.UpdateUserCommand{
@@ -0,0 +1,329 @@
+package dashdiffs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// ErrNotAnObject occurs when a merged dashboard is not a JSON object.
+var ErrNotAnObject = errors.New("dashdiff: dashboard is not an object")
+
+// Conflict is a change that was made to the same part of a dashboard by both sides of a merge.
+type Conflict struct {
+	// Path is the JSON pointer of the conflicting property, or /panels for conflicting panels.
+	Path string `json:"path"`
+	// PanelID is the ID of the conflicting panel, if the conflict is a panel.
+	PanelID *int64 `json:"panelId,omitempty"`
+	// Base is the value in the common base version, or nil if it did not exist.
+	Base any `json:"base"`
+	// Ours is the value in the unsaved dashboard, or nil if it was removed.
+	Ours any `json:"ours"`
+	// Theirs is the value in the saved dashboard, or nil if it was removed.
+	Theirs any `json:"theirs"`
+}
+
+type MergeResult struct {
+	// Dashboard is the merged dashboard. Conflicting properties and panels have the value of the saved dashboard.
+	Dashboard *simplejson.Json `json:"dashboard"`
+	Conflicts []Conflict       `json:"conflicts"`
+}
+
+// mergeTheirs are the properties that are always taken from the saved dashboard, so that the merged dashboard can be
+// saved on top of it.
+var mergeTheirs = map[string]bool{"id": true, "uid": true, "version": true}
+
+// Merge does a three-way merge of two dashboards that were both changed from the same base version.
+// Ours is the dashboard with unsaved changes, and theirs is the dashboard that was saved in the meantime.
+//
+// Panels are merged by their ID, so that changes to different panels never conflict. A panel only conflicts if
+// it was changed differently on both sides. The panels of collapsed rows are merged the same way, and a panel that
+// was moved into or out of a collapsed row on one side keeps its ID, so it is only in the merged dashboard once.
+// Other properties of the dashboard are merged by their top level key.
+func Merge(baseData, oursData, theirsData *simplejson.Json) (*MergeResult, error) {
+	base, err := toPlainObject(baseData)
+	if err != nil {
+		return nil, err
+	}
+	ours, err := toPlainObject(oursData)
+	if err != nil {
+		return nil, err
+	}
+	theirs, err := toPlainObject(theirsData)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]bool{}
+	for _, m := range []map[string]any{base, ours, theirs} {
+		for k := range m {
+			keys[k] = true
+		}
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for k := range keys {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+
+	merged := make(map[string]any, len(keys))
+	conflicts := []Conflict{}
+	for _, key := range sortedKeys {
+		if mergeTheirs[key] {
+			if t, ok := theirs[key]; ok {
+				merged[key] = t
+			}
+			continue
+		}
+		if key == "panels" {
+			panels, panelConflicts := mergePanels(panelsOf(base), panelsOf(ours), panelsOf(theirs))
+			merged[key] = panels
+			conflicts = append(conflicts, panelConflicts...)
+			continue
+		}
+		b, o, t := sideOf(base, key), sideOf(ours, key), sideOf(theirs, key)
+		result, conflict := mergeSides(b, o, t)
+		if result.ok {
+			merged[key] = result.v
+		}
+		if conflict {
+			conflicts = append(conflicts, Conflict{Path: "/" + escapePointer(key), Base: b.v, Ours: o.v, Theirs: t.v})
+		}
+	}
+
+	return &MergeResult{Dashboard: simplejson.NewFromAny(merged), Conflicts: conflicts}, nil
+}
+
+// side is a value on one side of a merge, which might not exist.
+type side struct {
+	v  any
+	ok bool
+}
+
+func sideOf(m map[string]any, key string) side {
+	v, ok := m[key]
+	return side{v: v, ok: ok}
+}
+
+func (s side) equal(other side) bool {
+	return s.ok == other.ok && (!s.ok || reflect.DeepEqual(s.v, other.v))
+}
+
+// mergeSides returns the merged value, and whether both sides changed the value differently. Conflicts are resolved
+// with the saved value.
+func mergeSides(base, ours, theirs side) (side, bool) {
+	switch {
+	case ours.equal(theirs):
+		return ours, false
+	case ours.equal(base):
+		return theirs, false
+	case theirs.equal(base):
+		return ours, false
+	default:
+		return theirs, true
+	}
+}
+
+// mergePanels merges the panels of a dashboard, including the panels of collapsed rows. Panels are identified by their
+// ID wherever they are, so a panel that was moved into or out of a collapsed row is the same panel. The position of a
+// panel, at the top level or in a row, and its content are merged separately. A panel conflicts if its content or
+// its position was changed differently on both sides, or if one side moved it and the other side changed it.
+func mergePanels(base, ours, theirs []any) ([]any, []Conflict) {
+	baseLayout, oursLayout, theirsLayout := layoutOf(base), layoutOf(ours), layoutOf(theirs)
+
+	// Keep the order of the saved dashboard, and add the other panels in the order of the unsaved dashboard.
+	// Panels that were removed from both dashboards are not merged.
+	keys := append([]string{}, theirsLayout.keys...)
+	for _, k := range oursLayout.keys {
+		if _, ok := theirsLayout.panels[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	merged := &panelLayout{panels: map[string]any{}, parents: map[string]string{}, collapsed: map[string]bool{}}
+	var conflicts []Conflict
+	for _, k := range keys {
+		if _, ok := merged.panels[k]; ok {
+			continue
+		}
+		b, o, t := sideOf(baseLayout.panels, k), sideOf(oursLayout.panels, k), sideOf(theirsLayout.panels, k)
+		pb, po, pt := baseLayout.parent(k), oursLayout.parent(k), theirsLayout.parent(k)
+
+		content, conflict := mergeSides(b, o, t)
+		parent, parentConflict := mergeSides(pb, po, pt)
+		moved := b.ok && o.ok && t.ok && !po.equal(pt) && ((!po.equal(pb) && !t.equal(b)) || (!pt.equal(pb) && !o.equal(b)))
+		if conflict || parentConflict || moved {
+			conflicts = append(conflicts, Conflict{Path: "/panels", PanelID: panelID(b, o, t), Base: b.v, Ours: o.v, Theirs: t.v})
+			content, parent = t, pt
+		}
+		if !content.ok {
+			continue
+		}
+		if !parent.ok {
+			// the panel was removed from the side whose position was merged
+			parent = po
+			if pt.ok {
+				parent = pt
+			}
+		}
+		merged.panels[k] = content.v
+		merged.parents[k] = parent.v.(string)
+		merged.keys = append(merged.keys, k)
+	}
+
+	for _, l := range []*panelLayout{&theirsLayout, &oursLayout} {
+		for row := range l.collapsed {
+			if _, ok := merged.panels[row]; ok {
+				merged.collapsed[row] = true
+			}
+		}
+	}
+	return merged.build(theirsLayout, oursLayout), conflicts
+}
+
+// panelLayout holds the panels of a dashboard by their key. The panels of collapsed rows are held next to the
+// other panels, and the rows are held without them.
+type panelLayout struct {
+	panels map[string]any
+	// parents are the keys of the rows of the panels in collapsed rows, and empty for the other panels.
+	parents map[string]string
+	// keys are the keys of the panels in the order of the dashboard, with the panels of a row after the row.
+	keys []string
+	// collapsed are the rows that have a list of panels.
+	collapsed map[string]bool
+}
+
+func layoutOf(panels []any) panelLayout {
+	l := panelLayout{panels: map[string]any{}, parents: map[string]string{}, collapsed: map[string]bool{}}
+	for i, p := range panels {
+		k := panelKey(p, "", i)
+		row, ok := p.(map[string]any)
+		if !ok || row["type"] != "row" || row["panels"] == nil {
+			l.add(k, "", p)
+			continue
+		}
+		l.add(k, "", withoutPanels(row))
+		l.collapsed[k] = true
+		children, _ := row["panels"].([]any)
+		for j, c := range children {
+			l.add(panelKey(c, k, j), k, c)
+		}
+	}
+	return l
+}
+
+// add adds a panel, unless a panel with the same key was added before.
+func (l *panelLayout) add(key, parent string, panel any) {
+	if _, ok := l.panels[key]; ok {
+		return
+	}
+	l.panels[key] = panel
+	l.parents[key] = parent
+	l.keys = append(l.keys, key)
+}
+
+func (l *panelLayout) parent(key string) side {
+	p, ok := l.parents[key]
+	return side{v: p, ok: ok}
+}
+
+// build returns the merged panels. The panels of each level are in the order of the saved dashboard, followed by
+// the panels that are only at that level in the unsaved dashboard. Panels of rows that do not exist anymore are
+// added at the top level.
+func (l *panelLayout) build(theirs, ours panelLayout) []any {
+	levels := map[string][]string{}
+	placed := map[string]bool{}
+	for _, layout := range []panelLayout{theirs, ours, *l} {
+		for _, k := range layout.keys {
+			parent, ok := l.parents[k]
+			if !ok || placed[k] || layout.parents[k] != parent {
+				continue
+			}
+			if _, isRow := l.panels[parent]; parent != "" && !isRow {
+				continue
+			}
+			levels[parent] = append(levels[parent], k)
+			placed[k] = true
+		}
+	}
+	for _, k := range l.keys {
+		if !placed[k] {
+			levels[""] = append(levels[""], k)
+		}
+	}
+
+	panels := make([]any, 0, len(levels[""]))
+	for _, k := range levels[""] {
+		if !l.collapsed[k] && len(levels[k]) == 0 {
+			panels = append(panels, l.panels[k])
+			continue
+		}
+		row := make(map[string]any, len(l.panels[k].(map[string]any))+1)
+		for name, v := range l.panels[k].(map[string]any) {
+			row[name] = v
+		}
+		children := make([]any, 0, len(levels[k]))
+		for _, c := range levels[k] {
+			children = append(children, l.panels[c])
+		}
+		row["panels"] = children
+		panels = append(panels, row)
+	}
+	return panels
+}
+
+func panelsOf(dashboard map[string]any) []any {
+	panels, _ := dashboard["panels"].([]any)
+	return panels
+}
+
+// withoutPanels returns a copy of a row without the panels that it contains.
+func withoutPanels(row map[string]any) map[string]any {
+	props := make(map[string]any, len(row))
+	for k, v := range row {
+		if k != "panels" {
+			props[k] = v
+		}
+	}
+	return props
+}
+
+// panelKey identifies a panel by its ID, or by its index in its row if it has no ID.
+func panelKey(panel any, parent string, index int) string {
+	if p, ok := panel.(map[string]any); ok {
+		if id, ok := p["id"].(float64); ok {
+			return fmt.Sprintf("id:%v", id)
+		}
+	}
+	if parent != "" {
+		return fmt.Sprintf("%s/index:%d", parent, index)
+	}
+	return fmt.Sprintf("index:%d", index)
+}
+
+func panelID(sides ...side) *int64 {
+	for _, s := range sides {
+		if p, ok := s.v.(map[string]any); ok {
+			if id, ok := p["id"].(float64); ok {
+				i := int64(id)
+				return &i
+			}
+		}
+	}
+	return nil
+}
+
+func toPlainObject(data *simplejson.Json) (map[string]any, error) {
+	v, err := toPlainJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, ErrNotAnObject
+	}
+	return m, nil
+}
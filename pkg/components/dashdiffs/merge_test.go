@@ -0,0 +1,329 @@
+package dashdiffs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestMerge(t *testing.T) {
+	const base = `{
+		"id": 1,
+		"uid": "dash",
+		"version": 1,
+		"title": "Title",
+		"panels": [
+			{"id": 1, "title": "Panel 1"},
+			{"id": 2, "title": "Panel 2"},
+			{"id": 3, "title": "Panel 3"}
+		]
+	}`
+
+	merge := func(t *testing.T, ours, theirs string) *MergeResult {
+		t.Helper()
+		b, err := simplejson.NewJson([]byte(base))
+		require.NoError(t, err)
+		o, err := simplejson.NewJson([]byte(ours))
+		require.NoError(t, err)
+		th, err := simplejson.NewJson([]byte(theirs))
+		require.NoError(t, err)
+		result, err := Merge(b, o, th)
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("changes to different panels should be merged", func(t *testing.T) {
+		result := merge(t, `{
+			"id": 1, "uid": "dash", "version": 1, "title": "Title",
+			"panels": [
+				{"id": 1, "title": "Panel 1 changed by us"},
+				{"id": 2, "title": "Panel 2"},
+				{"id": 4, "title": "Panel 4 added by us"}
+			]
+		}`, `{
+			"id": 1, "uid": "dash", "version": 2, "title": "Title changed by them",
+			"panels": [
+				{"id": 1, "title": "Panel 1"},
+				{"id": 2, "title": "Panel 2 changed by them"},
+				{"id": 3, "title": "Panel 3"}
+			]
+		}`)
+		require.Empty(t, result.Conflicts)
+		require.JSONEq(t, `{
+			"id": 1, "uid": "dash", "version": 2, "title": "Title changed by them",
+			"panels": [
+				{"id": 1, "title": "Panel 1 changed by us"},
+				{"id": 2, "title": "Panel 2 changed by them"},
+				{"id": 4, "title": "Panel 4 added by us"}
+			]
+		}`, string(encode(t, result.Dashboard)))
+	})
+
+	t.Run("panels changed on both sides should conflict", func(t *testing.T) {
+		result := merge(t, `{
+			"id": 1, "uid": "dash", "version": 1, "title": "Title changed by us",
+			"panels": [
+				{"id": 1, "title": "Panel 1 changed by us"},
+				{"id": 2, "title": "Panel 2 changed by us"},
+				{"id": 3, "title": "Panel 3"}
+			]
+		}`, `{
+			"id": 1, "uid": "dash", "version": 2, "title": "Title changed by them",
+			"panels": [
+				{"id": 1, "title": "Panel 1 changed by them"},
+				{"id": 2, "title": "Panel 2 changed by us"}
+			]
+		}`)
+		panelID := int64(1)
+		require.Equal(t, []Conflict{
+			{
+				Path:   "/panels",
+				Base:   map[string]any{"id": float64(1), "title": "Panel 1"},
+				Ours:   map[string]any{"id": float64(1), "title": "Panel 1 changed by us"},
+				Theirs: map[string]any{"id": float64(1), "title": "Panel 1 changed by them"},
+			},
+			{Path: "/title", Base: "Title", Ours: "Title changed by us", Theirs: "Title changed by them"},
+		}, withoutPanelIDs(result.Conflicts))
+		require.Equal(t, &panelID, result.Conflicts[0].PanelID)
+		require.JSONEq(t, `{
+			"id": 1, "uid": "dash", "version": 2, "title": "Title changed by them",
+			"panels": [
+				{"id": 1, "title": "Panel 1 changed by them"},
+				{"id": 2, "title": "Panel 2 changed by us"}
+			]
+		}`, string(encode(t, result.Dashboard)))
+	})
+
+	t.Run("panel changed by us and removed by them should conflict", func(t *testing.T) {
+		result := merge(t, `{
+			"id": 1, "uid": "dash", "version": 1, "title": "Title",
+			"panels": [
+				{"id": 1, "title": "Panel 1"},
+				{"id": 2, "title": "Panel 2"},
+				{"id": 3, "title": "Panel 3 changed by us"}
+			]
+		}`, `{
+			"id": 1, "uid": "dash", "version": 2, "title": "Title",
+			"panels": [
+				{"id": 1, "title": "Panel 1"},
+				{"id": 2, "title": "Panel 2"}
+			]
+		}`)
+		require.Len(t, result.Conflicts, 1)
+		require.Nil(t, result.Conflicts[0].Theirs)
+		require.Len(t, result.Dashboard.Get("panels").MustArray(), 2)
+	})
+}
+
+func TestMergeCollapsedRows(t *testing.T) {
+	base := simplejson.MustJson([]byte(`{
+		"id": 1, "uid": "dash", "version": 1,
+		"panels": [
+			{"id": 1, "title": "Panel 1"},
+			{"id": 2, "type": "row", "title": "Row", "collapsed": true, "panels": [
+				{"id": 3, "title": "Panel 3"},
+				{"id": 4, "title": "Panel 4"}
+			]}
+		]
+	}`))
+
+	t.Run("changes to different panels in a collapsed row should be merged", func(t *testing.T) {
+		ours := simplejson.MustJson([]byte(`{
+			"id": 1, "uid": "dash", "version": 1,
+			"panels": [
+				{"id": 1, "title": "Panel 1"},
+				{"id": 2, "type": "row", "title": "Row", "collapsed": true, "panels": [
+					{"id": 3, "title": "Panel 3 changed by us"},
+					{"id": 4, "title": "Panel 4"}
+				]}
+			]
+		}`))
+		theirs := simplejson.MustJson([]byte(`{
+			"id": 1, "uid": "dash", "version": 2,
+			"panels": [
+				{"id": 1, "title": "Panel 1"},
+				{"id": 2, "type": "row", "title": "Row changed by them", "collapsed": true, "panels": [
+					{"id": 3, "title": "Panel 3"},
+					{"id": 4, "title": "Panel 4 changed by them"}
+				]}
+			]
+		}`))
+
+		result, err := Merge(base, ours, theirs)
+		require.NoError(t, err)
+		require.Empty(t, result.Conflicts)
+		require.JSONEq(t, `{
+			"id": 1, "uid": "dash", "version": 2,
+			"panels": [
+				{"id": 1, "title": "Panel 1"},
+				{"id": 2, "type": "row", "title": "Row changed by them", "collapsed": true, "panels": [
+					{"id": 3, "title": "Panel 3 changed by us"},
+					{"id": 4, "title": "Panel 4 changed by them"}
+				]}
+			]
+		}`, string(encode(t, result.Dashboard)))
+	})
+
+	t.Run("a panel in a collapsed row changed on both sides should conflict", func(t *testing.T) {
+		ours := simplejson.MustJson([]byte(`{
+			"id": 1, "uid": "dash", "version": 1,
+			"panels": [
+				{"id": 1, "title": "Panel 1"},
+				{"id": 2, "type": "row", "title": "Row", "collapsed": true, "panels": [
+					{"id": 3, "title": "Panel 3 changed by us"},
+					{"id": 4, "title": "Panel 4"}
+				]}
+			]
+		}`))
+		theirs := simplejson.MustJson([]byte(`{
+			"id": 1, "uid": "dash", "version": 2,
+			"panels": [
+				{"id": 1, "title": "Panel 1"},
+				{"id": 2, "type": "row", "title": "Row", "collapsed": true, "panels": [
+					{"id": 3, "title": "Panel 3 changed by them"},
+					{"id": 4, "title": "Panel 4"}
+				]}
+			]
+		}`))
+
+		result, err := Merge(base, ours, theirs)
+		require.NoError(t, err)
+		panelID := int64(3)
+		require.Len(t, result.Conflicts, 1)
+		require.Equal(t, &panelID, result.Conflicts[0].PanelID)
+		require.Equal(t, "Panel 3 changed by them", result.Dashboard.Get("panels").GetIndex(1).Get("panels").GetIndex(0).Get("title").MustString())
+	})
+
+	t.Run("a panel moved into a collapsed row by us should be moved", func(t *testing.T) {
+		ours := simplejson.MustJson([]byte(`{
+			"id": 1, "uid": "dash", "version": 1,
+			"panels": [
+				{"id": 2, "type": "row", "title": "Row", "collapsed": true, "panels": [
+					{"id": 3, "title": "Panel 3"},
+					{"id": 4, "title": "Panel 4"},
+					{"id": 1, "title": "Panel 1"}
+				]}
+			]
+		}`))
+		theirs := simplejson.MustJson([]byte(`{
+			"id": 1, "uid": "dash", "version": 2,
+			"panels": [
+				{"id": 1, "title": "Panel 1"},
+				{"id": 2, "type": "row", "title": "Row", "collapsed": true, "panels": [
+					{"id": 3, "title": "Panel 3 changed by them"},
+					{"id": 4, "title": "Panel 4"}
+				]}
+			]
+		}`))
+
+		result, err := Merge(base, ours, theirs)
+		require.NoError(t, err)
+		require.Empty(t, result.Conflicts)
+		require.JSONEq(t, `{
+			"id": 1, "uid": "dash", "version": 2,
+			"panels": [
+				{"id": 2, "type": "row", "title": "Row", "collapsed": true, "panels": [
+					{"id": 3, "title": "Panel 3 changed by them"},
+					{"id": 4, "title": "Panel 4"},
+					{"id": 1, "title": "Panel 1"}
+				]}
+			]
+		}`, string(encode(t, result.Dashboard)))
+	})
+
+	t.Run("a panel moved into a collapsed row by us and changed by them should conflict", func(t *testing.T) {
+		ours := simplejson.MustJson([]byte(`{
+			"id": 1, "uid": "dash", "version": 1,
+			"panels": [
+				{"id": 2, "type": "row", "title": "Row", "collapsed": true, "panels": [
+					{"id": 1, "title": "Panel 1"},
+					{"id": 3, "title": "Panel 3"},
+					{"id": 4, "title": "Panel 4"}
+				]}
+			]
+		}`))
+		theirs := simplejson.MustJson([]byte(`{
+			"id": 1, "uid": "dash", "version": 2,
+			"panels": [
+				{"id": 1, "title": "Panel 1 changed by them"},
+				{"id": 2, "type": "row", "title": "Row", "collapsed": true, "panels": [
+					{"id": 3, "title": "Panel 3"},
+					{"id": 4, "title": "Panel 4"}
+				]}
+			]
+		}`))
+
+		result, err := Merge(base, ours, theirs)
+		require.NoError(t, err)
+		panelID := int64(1)
+		require.Len(t, result.Conflicts, 1)
+		require.Equal(t, &panelID, result.Conflicts[0].PanelID)
+		require.JSONEq(t, `{
+			"id": 1, "uid": "dash", "version": 2,
+			"panels": [
+				{"id": 1, "title": "Panel 1 changed by them"},
+				{"id": 2, "type": "row", "title": "Row", "collapsed": true, "panels": [
+					{"id": 3, "title": "Panel 3"},
+					{"id": 4, "title": "Panel 4"}
+				]}
+			]
+		}`, string(encode(t, result.Dashboard)))
+	})
+
+	t.Run("a panel moved out of a collapsed row by them and changed by us should conflict", func(t *testing.T) {
+		ours := simplejson.MustJson([]byte(`{
+			"id": 1, "uid": "dash", "version": 1,
+			"panels": [
+				{"id": 1, "title": "Panel 1"},
+				{"id": 2, "type": "row", "title": "Row", "collapsed": true, "panels": [
+					{"id": 3, "title": "Panel 3"},
+					{"id": 4, "title": "Panel 4 changed by us"}
+				]}
+			]
+		}`))
+		theirs := simplejson.MustJson([]byte(`{
+			"id": 1, "uid": "dash", "version": 2,
+			"panels": [
+				{"id": 1, "title": "Panel 1"},
+				{"id": 4, "title": "Panel 4"},
+				{"id": 2, "type": "row", "title": "Row", "collapsed": true, "panels": [
+					{"id": 3, "title": "Panel 3"}
+				]}
+			]
+		}`))
+
+		result, err := Merge(base, ours, theirs)
+		require.NoError(t, err)
+		panelID := int64(4)
+		require.Len(t, result.Conflicts, 1)
+		require.Equal(t, &panelID, result.Conflicts[0].PanelID)
+		require.JSONEq(t, `{
+			"id": 1, "uid": "dash", "version": 2,
+			"panels": [
+				{"id": 1, "title": "Panel 1"},
+				{"id": 4, "title": "Panel 4"},
+				{"id": 2, "type": "row", "title": "Row", "collapsed": true, "panels": [
+					{"id": 3, "title": "Panel 3"}
+				]}
+			]
+		}`, string(encode(t, result.Dashboard)))
+	})
+}
+
+func encode(t *testing.T, data *simplejson.Json) []byte {
+	t.Helper()
+	b, err := data.Encode()
+	require.NoError(t, err)
+	return b
+}
+
+func withoutPanelIDs(conflicts []Conflict) []Conflict {
+	result := make([]Conflict, 0, len(conflicts))
+	for _, c := range conflicts {
+		c.PanelID = nil
+		result = append(result, c)
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+package dashdiffs
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// PatchOperation is an operation of a JSON patch, as defined in RFC 6902.
+type PatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// MarshalJSON omits the value of remove operations, but keeps null values of add and replace operations.
+func (o PatchOperation) MarshalJSON() ([]byte, error) {
+	if o.Op == PatchRemove {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+	type operation PatchOperation
+	return json.Marshal(operation(o))
+}
+
+const (
+	PatchAdd     = "add"
+	PatchRemove  = "remove"
+	PatchReplace = "replace"
+)
+
+// CalculatePatch computes the JSON patch that transforms baseData into newData.
+// Applying the operations in order to baseData results in newData.
+func CalculatePatch(baseData, newData *simplejson.Json) ([]PatchOperation, error) {
+	left, err := toPlainJSON(baseData)
+	if err != nil {
+		return nil, err
+	}
+	right, err := toPlainJSON(newData)
+	if err != nil {
+		return nil, err
+	}
+	return diffValues("", left, right, []PatchOperation{}), nil
+}
+
+// toPlainJSON converts a JSON document to maps, slices and values as they are decoded by encoding/json,
+// so that documents can be compared regardless of how they were built.
+func toPlainJSON(data *simplejson.Json) (any, error) {
+	b, err := data.Encode()
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func diffValues(path string, left, right any, ops []PatchOperation) []PatchOperation {
+	if reflect.DeepEqual(left, right) {
+		return ops
+	}
+	switch l := left.(type) {
+	case map[string]any:
+		if r, ok := right.(map[string]any); ok {
+			return diffObjects(path, l, r, ops)
+		}
+	case []any:
+		if r, ok := right.([]any); ok {
+			return diffArrays(path, l, r, ops)
+		}
+	}
+	return append(ops, PatchOperation{Op: PatchReplace, Path: path, Value: right})
+}
+
+func diffObjects(path string, left, right map[string]any, ops []PatchOperation) []PatchOperation {
+	for _, key := range sortedKeys(left) {
+		if _, ok := right[key]; !ok {
+			ops = append(ops, PatchOperation{Op: PatchRemove, Path: path + "/" + escapePointer(key)})
+		}
+	}
+	for _, key := range sortedKeys(right) {
+		l, ok := left[key]
+		if !ok {
+			ops = append(ops, PatchOperation{Op: PatchAdd, Path: path + "/" + escapePointer(key), Value: right[key]})
+			continue
+		}
+		ops = diffValues(path+"/"+escapePointer(key), l, right[key], ops)
+	}
+	return ops
+}
+
+func diffArrays(path string, left, right []any, ops []PatchOperation) []PatchOperation {
+	common := min(len(left), len(right))
+	for i := 0; i < common; i++ {
+		ops = diffValues(path+"/"+strconv.Itoa(i), left[i], right[i], ops)
+	}
+	// Remove from the end, so that the indexes of the remaining elements do not change.
+	for i := len(left) - 1; i >= common; i-- {
+		ops = append(ops, PatchOperation{Op: PatchRemove, Path: path + "/" + strconv.Itoa(i)})
+	}
+	for i := common; i < len(right); i++ {
+		ops = append(ops, PatchOperation{Op: PatchAdd, Path: path + "/" + strconv.Itoa(i), Value: right[i]})
+	}
+	return ops
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escapePointer escapes a key to be used as a reference token of a JSON pointer, as defined in RFC 6901.
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,61 @@
+package dashdiffs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestCalculatePatch(t *testing.T) {
+	base, err := simplejson.NewJson([]byte(`{
+		"title": "Old title",
+		"version": 1,
+		"tags": ["a", "b", "c"],
+		"time": {"from": "now-6h", "to": "now"},
+		"a/b": 1,
+		"removed": true
+	}`))
+	require.NoError(t, err)
+	updated, err := simplejson.NewJson([]byte(`{
+		"title": "New title",
+		"version": 2,
+		"tags": ["a"],
+		"time": {"from": "now-1h", "to": "now"},
+		"a/b": 2,
+		"description": null
+	}`))
+	require.NoError(t, err)
+
+	ops, err := CalculatePatch(base, updated)
+	require.NoError(t, err)
+	require.Equal(t, []PatchOperation{
+		{Op: PatchRemove, Path: "/removed"},
+		{Op: PatchReplace, Path: "/a~1b", Value: float64(2)},
+		{Op: PatchAdd, Path: "/description", Value: nil},
+		{Op: PatchRemove, Path: "/tags/2"},
+		{Op: PatchRemove, Path: "/tags/1"},
+		{Op: PatchReplace, Path: "/time/from", Value: "now-1h"},
+		{Op: PatchReplace, Path: "/title", Value: "New title"},
+		{Op: PatchReplace, Path: "/version", Value: float64(2)},
+	}, ops)
+
+	t.Run("remove operations should not have a value", func(t *testing.T) {
+		b, err := json.Marshal(ops[:3])
+		require.NoError(t, err)
+		require.JSONEq(t, `[
+			{"op": "remove", "path": "/removed"},
+			{"op": "replace", "path": "/a~1b", "value": 2},
+			{"op": "add", "path": "/description", "value": null}
+		]`, string(b))
+	})
+
+	t.Run("identical dashboards should have an empty patch", func(t *testing.T) {
+		ops, err := CalculatePatch(base, base)
+		require.NoError(t, err)
+		require.Empty(t, ops)
+		require.NotNil(t, ops)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -27,6 +28,8 @@ type MathCommand struct {
 	RawExpression string
 	Expression    *mathexp.Expr
 	refID         string
+	// offsetVars are the names of the nodes that provide the variables referenced with an offset.
+	offsetVars []string
 }
 
 // NewMathCommand creates a new MathCommand. It will return an error
@@ -64,7 +67,10 @@ func UnmarshalMathCommand(rn *rawNode) (*MathCommand, error) {
 // NeedsVars returns the variable names (refIds) that are dependencies
 // to execute the command and allows the command to fulfill the Command interface.
 func (gm *MathCommand) NeedsVars() []string {
-	return gm.Expression.VarNames
+	if len(gm.offsetVars) == 0 {
+		return gm.Expression.VarNames
+	}
+	return slices.Concat(gm.Expression.VarNames, gm.offsetVars)
 }
 
 // Execute runs the command and returns the results or an error if the command
@@ -73,7 +79,9 @@ func (gm *MathCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Va
 	_, span := tracer.Start(ctx, "SSE.ExecuteMath")
 	span.SetAttributes(attribute.String("expression", gm.RawExpression))
 	defer span.End()
-	return gm.Expression.Execute(gm.refID, vars, tracer)
+	res, drops, err := gm.Expression.ExecuteWithDrops(gm.refID, vars, tracer)
+	explanationFromContext(ctx).addDrops(gm.refID, drops)
+	return res, err
 }
 
 func (gm *MathCommand) Type() string {
@@ -124,12 +132,27 @@ func UnmarshalReduceCommand(rn *rawNode) (*ReduceCommand, error) {
 		return nil, fmt.Errorf("expected reducer to be a string, got %T", rawReducer)
 	}
 	redFunc := mathexp.ReducerID(strings.ToLower(redString))
+	var err error
 
 	var mapper mathexp.ReduceMapper = nil
 	settings, ok := rn.Query["settings"]
 	if ok {
 		switch s := settings.(type) {
 		case map[string]any:
+			if redFunc == mathexp.ReducerPercentile {
+				rawPercentile, ok := s["percentile"]
+				if !ok {
+					return nil, errors.New("setting percentile must be specified when reducer is 'percentile'")
+				}
+				percentile, ok := rawPercentile.(float64)
+				if !ok {
+					return nil, fmt.Errorf("setting percentile must be a number, got %T", rawPercentile)
+				}
+				redFunc, err = mathexp.PercentileReducer(percentile)
+				if err != nil {
+					return nil, err
+				}
+			}
 			mode, ok := s["mode"]
 			if ok && mode != "" {
 				switch mode {
@@ -217,7 +240,10 @@ type ResampleCommand struct {
 	Downsampler   mathexp.ReducerID
 	Upsampler     mathexp.Upsampler
 	TimeRange     TimeRange
-	refID         string
+	// AlignToWallClock aligns the resampled points to multiples of the window
+	// (e.g. every full 5 minutes) rather than to the start of the time range.
+	AlignToWallClock bool
+	refID            string
 }
 
 // NewResampleCommand creates a new ResampleCMD.
@@ -280,11 +306,24 @@ func UnmarshalResampleCommand(rn *rawNode) (*ResampleCommand, error) {
 		return nil, fmt.Errorf("expected resample downsampler to be a string, got type %T", upsampler)
 	}
 
-	return NewResampleCommand(rn.RefID, window,
+	alignToWallClock := false
+	if rawAlign, ok := rn.Query["alignToWallClock"]; ok {
+		alignToWallClock, ok = rawAlign.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected resample alignToWallClock to be a boolean, got type %T", rawAlign)
+		}
+	}
+
+	cmd, err := NewResampleCommand(rn.RefID, window,
 		varToResample,
 		mathexp.ReducerID(downsampler),
 		mathexp.Upsampler(upsampler),
 		rn.TimeRange)
+	if err != nil {
+		return nil, err
+	}
+	cmd.AlignToWallClock = alignToWallClock
+	return cmd, nil
 }
 
 // NeedsVars returns the variable names (refIds) that are dependencies
@@ -300,6 +339,9 @@ func (gr *ResampleCommand) Execute(ctx context.Context, now time.Time, vars math
 	defer span.End()
 	newRes := mathexp.Results{}
 	timeRange := gr.TimeRange.AbsoluteTime(now)
+	if gr.AlignToWallClock {
+		timeRange.From = alignToInterval(timeRange.From, gr.Window)
+	}
 	for _, val := range vars[gr.VarToResample].Values {
 		if val == nil {
 			continue
@@ -325,6 +367,15 @@ func (gr *ResampleCommand) Type() string {
 	return TypeResample.String()
 }
 
+// alignToInterval returns the first time at or after t that is a multiple of interval.
+func alignToInterval(t time.Time, interval time.Duration) time.Time {
+	aligned := t.Truncate(interval)
+	if aligned.Before(t) {
+		aligned = aligned.Add(interval)
+	}
+	return aligned
+}
+
 // CommandType is the type of the expression command.
 type CommandType int
 
@@ -343,6 +394,8 @@ const (
 	TypeThreshold
 	// TypeSQL is the CMDType for running SQL expressions
 	TypeSQL
+	// TypeForecast is the CMDType for projecting series into the future
+	TypeForecast
 )
 
 func (gt CommandType) String() string {
@@ -359,6 +412,8 @@ func (gt CommandType) String() string {
 		return "threshold"
 	case TypeSQL:
 		return "sql"
+	case TypeForecast:
+		return "forecast"
 	default:
 		return "unknown"
 	}
@@ -379,6 +434,8 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeThreshold, nil
 	case "sql":
 		return TypeSQL, nil
+	case "forecast":
+		return TypeForecast, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
@@ -99,6 +99,71 @@ func Test_UnmarshalReduceCommand_Settings(t *testing.T) {
 	}
 }
 
+func Test_UnmarshalReduceCommand_Percentile(t *testing.T) {
+	var tests = []struct {
+		name            string
+		query           string
+		isError         bool
+		expectedReducer mathexp.ReducerID
+	}{
+		{
+			name:            "percentile reducer from settings",
+			query:           `{ "expression" : "$A", "reducer": "percentile", "settings" : { "percentile": 95 } }`,
+			expectedReducer: "p95",
+		},
+		{
+			name:            "percentile reducer with fraction and mode",
+			query:           `{ "expression" : "$A", "reducer": "percentile", "settings" : { "mode": "dropNN", "percentile": 99.9 } }`,
+			expectedReducer: "p99.9",
+		},
+		{
+			name:            "percentile shorthand reducer",
+			query:           `{ "expression" : "$A", "reducer": "P90" }`,
+			expectedReducer: "p90",
+		},
+		{
+			name:    "error when percentile is not specified",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "settings" : { } }`,
+			isError: true,
+		},
+		{
+			name:    "error when settings is not specified",
+			query:   `{ "expression" : "$A", "reducer": "percentile" }`,
+			isError: true,
+		},
+		{
+			name:    "error when percentile is not a number",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "settings" : { "percentile": "95" } }`,
+			isError: true,
+		},
+		{
+			name:    "error when percentile is out of range",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "settings" : { "percentile": 120 } }`,
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var qmap = make(map[string]any)
+			require.NoError(t, json.Unmarshal([]byte(test.query), &qmap))
+
+			cmd, err := UnmarshalReduceCommand(&rawNode{
+				RefID: "A",
+				Query: qmap,
+			})
+
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedReducer, cmd.Reducer)
+		})
+	}
+}
+
 func TestReduceExecute(t *testing.T) {
 	varToReduce := util.GenerateShortUID()
 
@@ -273,3 +338,56 @@ func TestResampleCommand_Execute(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestResampleCommand_AlignToWallClock(t *testing.T) {
+	varToResample := util.GenerateShortUID()
+	tr := AbsoluteTimeRange{
+		From: time.Date(2024, 1, 1, 10, 2, 30, 0, time.UTC),
+		To:   time.Date(2024, 1, 1, 10, 20, 0, 0, time.UTC),
+	}
+	cmd, err := NewResampleCommand("B", "5m", varToResample, "mean", "pad", tr)
+	require.NoError(t, err)
+
+	vars := mathexp.Vars{
+		varToResample: mathexp.Results{Values: mathexp.Values{mathexp.NewSeries(varToResample, nil, 0)}},
+	}
+
+	result, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+	require.NoError(t, err)
+	require.Equal(t, tr.From, result.Values[0].(mathexp.Series).GetTime(0))
+
+	cmd.AlignToWallClock = true
+	result, err = cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+	require.NoError(t, err)
+	series := result.Values[0].(mathexp.Series)
+	require.Equal(t, 4, series.Len())
+	require.Equal(t, time.Date(2024, 1, 1, 10, 5, 0, 0, time.UTC), series.GetTime(0))
+	require.Equal(t, time.Date(2024, 1, 1, 10, 20, 0, 0, time.UTC), series.GetTime(3))
+}
+
+func Test_UnmarshalResampleCommand_AlignToWallClock(t *testing.T) {
+	q := map[string]any{
+		"expression":       "$A",
+		"window":           "5m",
+		"downsampler":      "time_weighted_mean",
+		"upsampler":        "linear",
+		"alignToWallClock": true,
+	}
+	cmd, err := UnmarshalResampleCommand(&rawNode{
+		RefID:     "B",
+		Query:     q,
+		TimeRange: RelativeTimeRange{From: -time.Hour},
+	})
+	require.NoError(t, err)
+	require.True(t, cmd.AlignToWallClock)
+	require.Equal(t, mathexp.DownsamplerTimeWeightedMean, cmd.Downsampler)
+	require.Equal(t, mathexp.UpsamplerLinear, cmd.Upsampler)
+
+	q["alignToWallClock"] = "yes"
+	_, err = UnmarshalResampleCommand(&rawNode{
+		RefID:     "B",
+		Query:     q,
+		TimeRange: RelativeTimeRange{From: -time.Hour},
+	})
+	require.Error(t, err)
+}
@@ -56,6 +56,27 @@ func MakeQueryError(refID, datasourceUID string, err error) error {
 	return QueryError.Build(data)
 }
 
+const (
+	sqlLimitReasonInputRows  = "input_rows"
+	sqlLimitReasonOutputRows = "output_rows"
+	sqlLimitReasonMemory     = "memory"
+	sqlLimitReasonTimeout    = "timeout"
+)
+
+var sqlLimitErrStr = "SQL expression [{{ .Public.refId }}] exceeded a limit: {{ .Public.error }}"
+
+var SQLLimitError = errutil.BadRequest("sse.sqlLimitExceeded").MustTemplate(
+	sqlLimitErrStr,
+	errutil.WithPublic(sqlLimitErrStr),
+)
+
+var sqlTimeoutErrStr = "SQL expression [{{ .Public.refId }}] did not complete within {{ .Public.timeout }}"
+
+var SQLTimeoutError = errutil.Timeout("sse.sqlTimeout").MustTemplate(
+	sqlTimeoutErrStr,
+	errutil.WithPublic(sqlTimeoutErrStr),
+)
+
 var depErrStr = "did not execute expression [{{ .Public.refId }}] due to a failure to of the dependent expression or query [{{.Public.depRefId}}]"
 
 var DependencyError = errutil.NewBase(
@@ -0,0 +1,214 @@
+package expr
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// ExplainRefID is the refID of the response that holds the explanation of the
+// pipeline when a request asks for it.
+const ExplainRefID = "__explain__"
+
+// Explanation describes how the nodes of a pipeline were executed.
+// It is collected when the context passed to the pipeline is created with WithExplanation.
+type Explanation struct {
+	mu    sync.Mutex
+	Nodes []*NodeExplanation `json:"nodes"`
+}
+
+// NodeExplanation describes the execution of a single node of the pipeline.
+type NodeExplanation struct {
+	RefID string `json:"refId"`
+	// NodeType is the type of the node, for example "Expression" or "Datasource".
+	NodeType string `json:"nodeType"`
+	// Command is the type of the expression command or of the data source.
+	Command string `json:"command,omitempty"`
+	// Inputs are the refIDs the node depends on.
+	Inputs []string `json:"inputs,omitempty"`
+	// Order is the position of the node in the execution order, starting at 0.
+	Order int `json:"order"`
+	// DurationMs is how long the node took to execute in milliseconds. Data source
+	// queries that are executed as a group share the duration of the group.
+	DurationMs float64 `json:"durationMs"`
+	// Series is the number of series in the result.
+	Series int `json:"series"`
+	// Numbers is the number of numbers in the result.
+	Numbers int `json:"numbers"`
+	// Rows is the number of rows of all frames of the result.
+	Rows int `json:"rows"`
+	// NoData is true if the result holds no data.
+	NoData bool `json:"noData"`
+	// Skipped is true if the node was not executed because an input failed.
+	Skipped bool `json:"skipped,omitempty"`
+	// Error is the error of the node, if any.
+	Error string `json:"error,omitempty"`
+	// Dropped is the number of items dropped from the unions of binary operations.
+	Dropped int `json:"dropped,omitempty"`
+	// LabelMismatches are the items dropped from the unions of binary operations
+	// because their labels did not match any item of the other side.
+	LabelMismatches []LabelMismatch `json:"labelMismatches,omitempty"`
+}
+
+// LabelMismatch is the set of items of one side of a binary operation that were
+// dropped because their labels did not match.
+type LabelMismatch struct {
+	// Operation is the binary operation, for example "$A + $B".
+	Operation string `json:"operation"`
+	// Input is the side of the operation the items belong to, for example "$A".
+	Input  string        `json:"input"`
+	Labels []data.Labels `json:"labels"`
+}
+
+type explanationKey struct{}
+
+// WithExplanation returns a context that collects an explanation of the pipeline executed with it.
+func WithExplanation(ctx context.Context) (context.Context, *Explanation) {
+	e := &Explanation{}
+	return context.WithValue(ctx, explanationKey{}, e), e
+}
+
+func explanationFromContext(ctx context.Context) *Explanation {
+	e, _ := ctx.Value(explanationKey{}).(*Explanation)
+	return e
+}
+
+// node returns the explanation of the node with the given refID, creating it if needed.
+func (e *Explanation) node(refID string) *NodeExplanation {
+	for _, n := range e.Nodes {
+		if n.RefID == refID {
+			return n
+		}
+	}
+	n := &NodeExplanation{RefID: refID, Order: len(e.Nodes)}
+	e.Nodes = append(e.Nodes, n)
+	return n
+}
+
+// add records the execution of the node.
+func (e *Explanation) add(node Node, res mathexp.Results, d time.Duration) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	n := e.node(node.RefID())
+	n.NodeType = node.NodeType().String()
+	n.Command = nodeCommandType(node)
+	n.Inputs = node.NeedsVars()
+	n.DurationMs = float64(d) / float64(time.Millisecond)
+	if res.Error != nil {
+		n.Error = res.Error.Error()
+	}
+	n.NoData = len(res.Values) == 0
+	for _, v := range res.Values {
+		switch v.(type) {
+		case mathexp.Series:
+			n.Series++
+		case mathexp.Number:
+			n.Numbers++
+		case mathexp.NoData:
+			n.NoData = true
+		}
+		if f := v.AsDataFrame(); f != nil {
+			n.Rows += f.Rows()
+		}
+	}
+}
+
+// skip records that the node was not executed.
+func (e *Explanation) skip(node Node, res mathexp.Results) {
+	if e == nil {
+		return
+	}
+	e.add(node, res, 0)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.node(node.RefID()).Skipped = true
+}
+
+// addDrops records the items dropped from the unions of the node.
+func (e *Explanation) addDrops(refID string, drops mathexp.Drops) {
+	if e == nil || len(drops) == 0 {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	n := e.node(refID)
+	operations := make([]string, 0, len(drops))
+	for op := range drops {
+		operations = append(operations, op)
+	}
+	sort.Strings(operations)
+	for _, op := range operations {
+		inputs := make([]string, 0, len(drops[op]))
+		for input := range drops[op] {
+			inputs = append(inputs, input)
+		}
+		sort.Strings(inputs)
+		for _, input := range inputs {
+			labels := drops[op][input]
+			n.Dropped += len(labels)
+			n.LabelMismatches = append(n.LabelMismatches, LabelMismatch{Operation: op, Input: input, Labels: labels})
+		}
+	}
+}
+
+// Frame returns the explanation as a table with a row per node, in execution order.
+func (e *Explanation) Frame() *data.Frame {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	nodes := make([]*NodeExplanation, len(e.Nodes))
+	copy(nodes, e.Nodes)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Order < nodes[j].Order })
+
+	f := data.NewFrame("explain",
+		data.NewField("order", nil, []int64{}),
+		data.NewField("refId", nil, []string{}),
+		data.NewField("nodeType", nil, []string{}),
+		data.NewField("command", nil, []string{}),
+		data.NewField("inputs", nil, []string{}),
+		data.NewField("duration", nil, []float64{}).SetConfig(&data.FieldConfig{Unit: "ms"}),
+		data.NewField("series", nil, []int64{}),
+		data.NewField("numbers", nil, []int64{}),
+		data.NewField("rows", nil, []int64{}),
+		data.NewField("noData", nil, []bool{}),
+		data.NewField("skipped", nil, []bool{}),
+		data.NewField("dropped", nil, []int64{}),
+		data.NewField("error", nil, []string{}),
+	)
+	f.RefID = ExplainRefID
+	f.Meta = &data.FrameMeta{Custom: nodes}
+	for _, n := range nodes {
+		f.AppendRow(int64(n.Order), n.RefID, n.NodeType, n.Command, strings.Join(n.Inputs, ","), n.DurationMs,
+			int64(n.Series), int64(n.Numbers), int64(n.Rows),
+			n.NoData, n.Skipped, int64(n.Dropped), n.Error)
+	}
+	return f
+}
+
+func nodeCommandType(node Node) string {
+	switch t := node.(type) {
+	case *CMDNode:
+		if t.Command != nil {
+			return t.Command.Type()
+		}
+	case *DSNode:
+		if t.datasource != nil {
+			return t.datasource.Type
+		}
+	case *MLNode:
+		if t.command != nil {
+			return t.command.Type()
+		}
+	case *InputNode:
+		return "input"
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	datafakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginconfig"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/plugincontext"
+	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestExplain(t *testing.T) {
+	series := func(labels data.Labels) *data.Frame {
+		return data.NewFrame("",
+			data.NewField("time", nil, []time.Time{time.Unix(1, 0), time.Unix(2, 0)}),
+			data.NewField("value", labels, []*float64{fp(1), fp(2)}))
+	}
+	me := &mockEndpoint{
+		Responses: map[string]backend.DataResponse{
+			"A": {Frames: data.Frames{series(data.Labels{"host": "a"}), series(data.Labels{"host": "b"})}},
+			"B": {Frames: data.Frames{series(data.Labels{"host": "a"})}},
+		},
+	}
+
+	cfg := setting.NewCfg()
+	cfg.ExpressionsEnabled = true
+	features := featuremgmt.WithFeatures()
+	s := Service{
+		cfg:         cfg,
+		dataService: me,
+		pCtxProvider: plugincontext.ProvideService(cfg, nil, &pluginstore.FakePluginStore{
+			PluginList: []pluginstore.Plugin{
+				{JSONData: plugins.JSONData{ID: "test"}},
+			},
+		}, &datafakes.FakeCacheService{}, &datafakes.FakeDataSourceService{}, nil, pluginconfig.NewFakePluginRequestConfigProvider()),
+		features: features,
+		tracer:   tracing.InitializeTracerForTest(),
+		metrics:  newMetrics(nil),
+		converter: &ResultConverter{
+			Features: features,
+			Tracer:   tracing.InitializeTracerForTest(),
+		},
+	}
+
+	dsQuery := func(refID string) Query {
+		return Query{
+			RefID:      refID,
+			DataSource: &datasources.DataSource{OrgID: 1, UID: "test", Type: "test"},
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "test" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+			TimeRange:  AbsoluteTimeRange{From: time.Unix(0, 0), To: time.Unix(10, 0)},
+		}
+	}
+	exprQuery := func(refID, model string) Query {
+		return Query{
+			RefID:      refID,
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(model),
+			TimeRange:  AbsoluteTimeRange{From: time.Unix(0, 0), To: time.Unix(10, 0)},
+		}
+	}
+	req := &Request{
+		User: &user.SignedInUser{},
+		Queries: []Query{
+			dsQuery("A"),
+			dsQuery("B"),
+			exprQuery("C", `{ "type": "math", "expression": "$A + $B" }`),
+			exprQuery("D", `{ "type": "reduce", "expression": "$C", "reducer": "last" }`),
+			exprQuery("E", `{ "type": "forecast", "expression": "$D", "horizon": "1m" }`),
+			exprQuery("F", `{ "type": "math", "expression": "$E * 2" }`),
+		},
+	}
+
+	t.Run("should explain each node", func(t *testing.T) {
+		pl, err := s.BuildPipeline(req)
+		require.NoError(t, err)
+
+		ctx, explanation := WithExplanation(context.Background())
+		_, err = s.ExecutePipeline(ctx, time.Now(), pl)
+		require.NoError(t, err)
+
+		nodes := map[string]*NodeExplanation{}
+		for i, n := range explanation.Nodes {
+			require.Equal(t, i, n.Order)
+			nodes[n.RefID] = n
+		}
+		require.Len(t, nodes, 6)
+
+		require.Equal(t, "Datasource", nodes["A"].NodeType)
+		require.Equal(t, "test", nodes["A"].Command)
+		require.Equal(t, 2, nodes["A"].Series)
+		require.Equal(t, 4, nodes["A"].Rows)
+
+		require.Equal(t, "math", nodes["C"].Command)
+		require.ElementsMatch(t, []string{"A", "B"}, nodes["C"].Inputs)
+		require.Equal(t, 1, nodes["C"].Series)
+		require.Equal(t, 1, nodes["C"].Dropped)
+		require.Equal(t, []LabelMismatch{{
+			Operation: "$A + $B",
+			Input:     "$A",
+			Labels:    []data.Labels{{"host": "b"}},
+		}}, nodes["C"].LabelMismatches)
+
+		require.Equal(t, 1, nodes["D"].Numbers)
+		require.Greater(t, nodes["D"].Order, nodes["C"].Order)
+
+		require.NotEmpty(t, nodes["E"].Error)
+		require.False(t, nodes["E"].Skipped)
+
+		require.True(t, nodes["F"].Skipped)
+		require.NotEmpty(t, nodes["F"].Error)
+	})
+
+	t.Run("should not collect anything without an explanation in the context", func(t *testing.T) {
+		pl, err := s.BuildPipeline(req)
+		require.NoError(t, err)
+		_, err = s.ExecutePipeline(context.Background(), time.Now(), pl)
+		require.NoError(t, err)
+	})
+
+	t.Run("should add the explanation to the response when requested", func(t *testing.T) {
+		res, err := s.TransformData(context.Background(), time.Now(), &Request{User: req.User, Queries: req.Queries, Explain: true})
+		require.NoError(t, err)
+		explain, ok := res.Responses[ExplainRefID]
+		require.True(t, ok)
+		require.Len(t, explain.Frames, 1)
+		require.Equal(t, 6, explain.Frames[0].Rows())
+		require.Equal(t, ExplainRefID, explain.Frames[0].RefID)
+
+		res, err = s.TransformData(context.Background(), time.Now(), req)
+		require.NoError(t, err)
+		require.NotContains(t, res.Responses, ExplainRefID)
+	})
+}
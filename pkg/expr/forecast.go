@@ -0,0 +1,122 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+// ForecastCommand is an expression command that projects each series of its input
+// into the future with a model fitted in-process.
+type ForecastCommand struct {
+	VarToForecast string
+	Options       mathexp.ForecastOptions
+	refID         string
+}
+
+// NewForecastCommand creates a new ForecastCommand from the forecast query.
+func NewForecastCommand(refID string, q ForecastQuery) (*ForecastCommand, error) {
+	varToForecast := strings.TrimPrefix(q.Expression, "$")
+	if varToForecast == "" {
+		return nil, fmt.Errorf("no variable specified to reference for refId %v", refID)
+	}
+
+	opts := mathexp.ForecastOptions{
+		Method:     q.Method,
+		Confidence: mathexp.DefaultForecastConfidence,
+		Alpha:      mathexp.DefaultForecastAlpha,
+		Beta:       mathexp.DefaultForecastBeta,
+		Gamma:      mathexp.DefaultForecastGamma,
+	}
+	if opts.Method == "" {
+		opts.Method = mathexp.ForecastMethodLinear
+	}
+
+	var err error
+	opts.Horizon, err = gtime.ParseDuration(q.Horizon)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to parse forecast "horizon" duration field %q: %w`, q.Horizon, err)
+	}
+	if q.Season != "" {
+		opts.Season, err = gtime.ParseDuration(q.Season)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse forecast "season" duration field %q: %w`, q.Season, err)
+		}
+	}
+	if q.Confidence != nil {
+		opts.Confidence = *q.Confidence
+	}
+	if q.Alpha != nil {
+		opts.Alpha = *q.Alpha
+	}
+	if q.Beta != nil {
+		opts.Beta = *q.Beta
+	}
+	if q.Gamma != nil {
+		opts.Gamma = *q.Gamma
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &ForecastCommand{
+		VarToForecast: varToForecast,
+		Options:       opts,
+		refID:         refID,
+	}, nil
+}
+
+// UnmarshalForecastCommand creates a ForecastCommand from Grafana's frontend query.
+func UnmarshalForecastCommand(rn *rawNode) (*ForecastCommand, error) {
+	q := ForecastQuery{}
+	if err := json.Unmarshal(rn.QueryRaw, &q); err != nil {
+		return nil, fmt.Errorf("failed to parse the forecast command: %w", err)
+	}
+	return NewForecastCommand(rn.RefID, q)
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (gr *ForecastCommand) NeedsVars() []string {
+	return []string{gr.VarToForecast}
+}
+
+// Execute runs the command and returns the results or an error if the command
+// failed to execute.
+func (gr *ForecastCommand) Execute(ctx context.Context, now time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteForecast")
+	defer span.End()
+	newRes := mathexp.Results{}
+	for _, val := range vars[gr.VarToForecast].Values {
+		if val == nil {
+			continue
+		}
+		switch v := val.(type) {
+		case mathexp.Series:
+			forecast, err := v.Forecast(gr.refID, gr.Options)
+			if err != nil {
+				return newRes, err
+			}
+			for _, s := range forecast {
+				newRes.Values = append(newRes.Values, s)
+			}
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v.New())
+			return newRes, nil
+		default:
+			return newRes, fmt.Errorf("can only forecast type series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+func (gr *ForecastCommand) Type() string {
+	return TypeForecast.String()
+}
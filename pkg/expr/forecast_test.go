@@ -0,0 +1,128 @@
+package expr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func Test_UnmarshalForecastCommand(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		errIs  require.ErrorAssertionFunc
+		expect mathexp.ForecastOptions
+	}{
+		{
+			name:  "defaults",
+			query: `{"expression": "$A", "horizon": "4h"}`,
+			errIs: require.NoError,
+			expect: mathexp.ForecastOptions{
+				Method:     mathexp.ForecastMethodLinear,
+				Horizon:    4 * time.Hour,
+				Confidence: mathexp.DefaultForecastConfidence,
+				Alpha:      mathexp.DefaultForecastAlpha,
+				Beta:       mathexp.DefaultForecastBeta,
+				Gamma:      mathexp.DefaultForecastGamma,
+			},
+		},
+		{
+			name:  "holt-winters with all settings",
+			query: `{"expression": "$A", "method": "holt_winters", "horizon": "30m", "season": "1d", "confidence": 0.8, "alpha": 0.3, "beta": 0.2, "gamma": 0.4}`,
+			errIs: require.NoError,
+			expect: mathexp.ForecastOptions{
+				Method:     mathexp.ForecastMethodHoltWinters,
+				Horizon:    30 * time.Minute,
+				Season:     24 * time.Hour,
+				Confidence: 0.8,
+				Alpha:      0.3,
+				Beta:       0.2,
+				Gamma:      0.4,
+			},
+		},
+		{
+			name:  "missing horizon - should error",
+			query: `{"expression": "$A"}`,
+			errIs: require.Error,
+		},
+		{
+			name:  "missing expression - should error",
+			query: `{"horizon": "1h"}`,
+			errIs: require.Error,
+		},
+		{
+			name:  "unknown method - should error",
+			query: `{"expression": "$A", "method": "arima", "horizon": "1h"}`,
+			errIs: require.Error,
+		},
+		{
+			name:  "smoothing factor out of range - should error",
+			query: `{"expression": "$A", "method": "holt_winters", "horizon": "1h", "alpha": 2}`,
+			errIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := UnmarshalForecastCommand(&rawNode{
+				RefID:    "B",
+				QueryRaw: []byte(tt.query),
+			})
+			tt.errIs(t, err)
+			if err != nil {
+				return
+			}
+			require.Equal(t, []string{"A"}, cmd.NeedsVars())
+			require.Equal(t, tt.expect, cmd.Options)
+		})
+	}
+}
+
+func TestForecastCommand_Execute(t *testing.T) {
+	cmd, err := NewForecastCommand("B", ForecastQuery{Expression: "$A", Horizon: "20s"})
+	require.NoError(t, err)
+
+	t.Run("should forecast each series", func(t *testing.T) {
+		var series []mathexp.Value
+		for _, host := range []string{"a", "b"} {
+			s := mathexp.NewSeries("A", data.Labels{"host": host}, 3)
+			for i := 0; i < 3; i++ {
+				v := float64(i)
+				s.SetPoint(i, time.Unix(int64(i)*10, 0), &v)
+			}
+			series = append(series, s)
+		}
+		vars := mathexp.Vars{"A": mathexp.Results{Values: series}}
+
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 6)
+		for _, v := range res.Values {
+			s, ok := v.(mathexp.Series)
+			require.True(t, ok)
+			require.Equal(t, 2, s.Len())
+			require.Contains(t, s.GetLabels(), mathexp.ForecastLabel)
+		}
+		require.Equal(t, data.Labels{"host": "b", mathexp.ForecastLabel: mathexp.ForecastPredicted}, res.Values[3].GetLabels())
+		require.Equal(t, 4.0, *res.Values[3].(mathexp.Series).GetValue(1))
+	})
+
+	t.Run("should pass through no data", func(t *testing.T) {
+		vars := mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}}
+		res, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		require.Len(t, res.Values, 1)
+		require.Equal(t, mathexp.NewNoData().Type(), res.Values[0].Type())
+	})
+
+	t.Run("should error on numbers", func(t *testing.T) {
+		vars := mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{mathexp.NewNumber("A", nil)}}}
+		_, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+		require.Error(t, err)
+	})
+}
@@ -1,6 +1,7 @@
 package expr
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -26,6 +27,8 @@ const (
 	TypeDatasourceNode
 	// TypeMLNode is a NodeType for Machine Learning queries.
 	TypeMLNode
+	// TypeInputNode is a NodeType for queries whose data is provided with the request.
+	TypeInputNode
 )
 
 func (nt NodeType) String() string {
@@ -36,6 +39,8 @@ func (nt NodeType) String() string {
 		return "Datasource"
 	case TypeMLNode:
 		return "Machine Learning"
+	case TypeInputNode:
+		return "Input"
 	default:
 		return "Unknown"
 	}
@@ -62,6 +67,7 @@ type DataPipeline []Node
 // map of the refId of the of each command
 func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (mathexp.Vars, error) {
 	vars := make(mathexp.Vars)
+	explain := explanationFromContext(c)
 
 	groupByDSFlag := s.features.IsEnabled(c, featuremgmt.FlagSseGroupByDatasource)
 	// Execute datasource nodes first, and grouped by datasource.
@@ -74,7 +80,11 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			dsNodes = append(dsNodes, node.(*DSNode))
 		}
 
+		start := time.Now()
 		executeDSNodesGrouped(c, now, vars, s, dsNodes)
+		for _, node := range dsNodes {
+			explain.add(node, vars[node.RefID()], time.Since(start))
+		}
 	}
 
 	s.allowLongFrames = hasSqlExpression(*dp)
@@ -93,6 +103,7 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 						Error: MakeDependencyError(node.RefID(), neededVar),
 					}
 					vars[node.RefID()] = errResult
+					explain.skip(node, errResult)
 					hasDepError = true
 					break
 				}
@@ -115,10 +126,12 @@ func (dp *DataPipeline) execute(c context.Context, now time.Time, s *Service) (m
 			return vars, makeUnexpectedNodeTypeError(node.RefID(), node.NodeType().String())
 		}
 
+		start := time.Now()
 		res, err := execNode.Execute(c, now, vars, s)
 		if err != nil {
 			res.Error = err
 		}
+		explain.add(node, res, time.Since(start))
 
 		vars[node.RefID()] = res
 	}
@@ -280,6 +293,15 @@ func (s *Service) buildGraph(req *Request) (*simple.DirectedGraph, error) {
 			node, err = s.buildDSNode(dp, rn, req)
 		case TypeCMDNode:
 			node, err = buildCMDNode(rn, s.features)
+			if cmdNode, ok := node.(*CMDNode); ok && err == nil {
+				if sqlCmd, ok := cmdNode.Command.(*SQLCommand); ok {
+					sqlCmd.limits = sqlLimitsFromCfg(s.cfg)
+					if s.cfg != nil {
+						sqlCmd.duckDBPath = s.cfg.SQLExpressionDuckDBPath
+					}
+					sqlCmd.metrics = s.metrics
+				}
+			}
 		case TypeMLNode:
 			if s.features.IsEnabledGlobally(featuremgmt.FlagMlExpressions) {
 				node, err = s.buildMLNode(dp, rn, req)
@@ -287,6 +309,11 @@ func (s *Service) buildGraph(req *Request) (*simple.DirectedGraph, error) {
 					err = fmt.Errorf("fail to parse expression with refID %v: %w", rn.RefID, err)
 				}
 			}
+		case TypeInputNode:
+			node, err = s.buildInputNode(dp, rn, req)
+			if err != nil {
+				err = fmt.Errorf("fail to parse input with refID %v: %w", rn.RefID, err)
+			}
 		}
 
 		if node == nil && err == nil {
@@ -299,9 +326,49 @@ func (s *Service) buildGraph(req *Request) (*simple.DirectedGraph, error) {
 
 		dp.AddNode(node)
 	}
+
+	addOffsetNodes(dp)
 	return dp, nil
 }
 
+// addOffsetNodes adds a data source node for each data source query that a math expression
+// references with an offset, e.g. "$A offset 1w". The node runs the query over the time range
+// shifted back by the offset, and the math expression shifts its series forward to align them.
+func addOffsetNodes(dp *simple.DirectedGraph) {
+	registry := buildNodeRegistry(dp)
+	nodes := maps.Values(registry)
+	slices.SortFunc(nodes, func(a, b Node) int { return cmp.Compare(a.ID(), b.ID()) })
+	for _, node := range nodes {
+		cmdNode, ok := node.(*CMDNode)
+		if !ok {
+			continue
+		}
+		mathCmd, ok := cmdNode.Command.(*MathCommand)
+		if !ok {
+			continue
+		}
+		for _, v := range mathCmd.Expression.OffsetVars {
+			dsNode, ok := registry[v.Name].(*DSNode)
+			if !ok || dsNode.offset != 0 {
+				// expressions are shifted in memory by the math expression itself
+				continue
+			}
+			name := v.OffsetName()
+			if _, ok := registry[name]; !ok {
+				shifted := *dsNode
+				shifted.baseNode = baseNode{id: dp.NewNode().ID(), refID: name}
+				shifted.timeRange = offsetTimeRange{TimeRange: dsNode.timeRange, offset: v.Offset}
+				shifted.offset = v.Offset
+				dp.AddNode(&shifted)
+				registry[name] = &shifted
+			}
+			if !slices.Contains(mathCmd.offsetVars, name) {
+				mathCmd.offsetVars = append(mathCmd.offsetVars, name)
+			}
+		}
+	}
+}
+
 // buildGraphEdges generates graph edges based on each node's dependencies.
 func buildGraphEdges(dp *simple.DirectedGraph, registry map[string]Node) error {
 	nodeIt := dp.Nodes()
@@ -404,3 +471,14 @@ func hasSqlExpression(dp DataPipeline) bool {
 // 	}
 // 	return false
 // }
+
+// isOffsetNode returns true if the node with the refID was added to the pipeline
+// to provide a variable that is referenced with an offset.
+func isOffsetNode(dp DataPipeline, refID string) bool {
+	for _, node := range dp {
+		if dsNode, ok := node.(*DSNode); ok && dsNode.RefID() == refID {
+			return dsNode.offset != 0
+		}
+	}
+	return false
+}
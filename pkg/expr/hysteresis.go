@@ -18,8 +18,12 @@ type Fingerprints map[data.Fingerprint]struct{}
 // - first threshold - "loading", is used when the metric is determined as not loaded, i.e. it does not exist in the data provided by the reader.
 // - second threshold - "unloading", is used when the metric is determined as loaded.
 // To determine whether a metric is loaded, the command uses LoadedDimensions that is supposed to contain data.Fingerprint of
-// the metrics that were loaded during the previous evaluation.
-// The result of the execution of the command is the same as ThresholdCommand: 0 or 1 for each metric.
+// the metrics that were loaded during the previous evaluation. If the context holds a LoadedDimensionsReader (see WithLoadedDimensionsReader),
+// the dimensions are read from it at execution time instead.
+// Both thresholds can be of any ThresholdType, including ranges.
+// For numbers, the result of the execution of the command is the same as ThresholdCommand: 0 or 1 for each metric.
+// For series, the points are evaluated in order and each point is evaluated against the threshold that matches the
+// result of the previous point, so the result shows when the metric would have been loaded and unloaded over time.
 type HysteresisCommand struct {
 	RefID                  string
 	ReferenceVar           string
@@ -28,6 +32,21 @@ type HysteresisCommand struct {
 	LoadedDimensions       Fingerprints
 }
 
+// LoadedDimensionsReader provides the fingerprints of the dimensions that were loaded
+// (for example, alerting) after the previous evaluation.
+type LoadedDimensionsReader interface {
+	Read() map[data.Fingerprint]struct{}
+}
+
+type loadedDimensionsReaderKey struct{}
+
+// WithLoadedDimensionsReader returns a context that makes hysteresis commands read the loaded dimensions
+// from the reader every time they are executed, so a pipeline can be evaluated many times in a row,
+// for example when backtesting, and each evaluation uses the state left by the previous one.
+func WithLoadedDimensionsReader(ctx context.Context, reader LoadedDimensionsReader) context.Context {
+	return context.WithValue(ctx, loadedDimensionsReaderKey{}, reader)
+}
+
 func (h *HysteresisCommand) NeedsVars() []string {
 	return []string{h.ReferenceVar}
 }
@@ -35,9 +54,14 @@ func (h *HysteresisCommand) NeedsVars() []string {
 func (h *HysteresisCommand) Execute(ctx context.Context, now time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
 	results := vars[h.ReferenceVar]
 
+	loadedDimensions := h.LoadedDimensions
+	if reader, ok := ctx.Value(loadedDimensionsReaderKey{}).(LoadedDimensionsReader); ok && reader != nil {
+		loadedDimensions = reader.Read()
+	}
+
 	logger := logger.FromContext(ctx)
-	traceCtx, span := tracer.Start(ctx, "SSE.ExecuteHysteresis")
-	span.SetAttributes(attribute.Int("previousLoadedDimensions", len(h.LoadedDimensions)))
+	_, span := tracer.Start(ctx, "SSE.ExecuteHysteresis")
+	span.SetAttributes(attribute.Int("previousLoadedDimensions", len(loadedDimensions)))
 	span.SetAttributes(attribute.Int("totalDimensions", len(results.Values)))
 	defer span.End()
 
@@ -45,46 +69,58 @@ func (h *HysteresisCommand) Execute(ctx context.Context, now time.Time, vars mat
 	if results.IsNoData() {
 		return mathexp.Results{Values: mathexp.Values{mathexp.NewNoData()}}, nil
 	}
-	if len(h.LoadedDimensions) == 0 {
-		return h.LoadingThresholdFunc.Execute(traceCtx, now, vars, tracer)
-	}
-	var loadedVals, unloadedVals mathexp.Values
-	for _, value := range results.Values {
-		_, ok := h.LoadedDimensions[value.GetLabels().Fingerprint()]
-		if ok {
-			loadedVals = append(loadedVals, value)
-		} else {
-			unloadedVals = append(unloadedVals, value)
+
+	matched := 0
+	newRes := mathexp.Results{Values: make(mathexp.Values, 0, len(results.Values))}
+	for _, val := range results.Values {
+		_, loaded := loadedDimensions[val.GetLabels().Fingerprint()]
+		if loaded {
+			matched++
+		}
+		switch v := val.(type) {
+		case mathexp.Series:
+			newRes.Values = append(newRes.Values, h.evalSeries(v, loaded))
+		case mathexp.Number:
+			n := mathexp.NewNumber(h.RefID, v.GetLabels())
+			n.SetValue(h.threshold(loaded).eval(v.GetFloat64Value()))
+			newRes.Values = append(newRes.Values, n)
+		case mathexp.Scalar:
+			newRes.Values = append(newRes.Values, mathexp.NewScalar(h.RefID, h.threshold(loaded).eval(v.GetFloat64Value())))
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, mathexp.NewNoData())
+		default:
+			return mathexp.Results{}, fmt.Errorf("unsupported format of the input data, got type %v", val.Type())
 		}
 	}
 
-	span.SetAttributes(attribute.Int("matchedLoadedDimensions", len(loadedVals)))
-
-	logger.Debug("Evaluating thresholds", "unloadingThresholdDimensions", len(loadedVals), "loadingThresholdDimensions", len(unloadedVals))
-	if len(loadedVals) == 0 { // if all values are unloaded
-		return h.LoadingThresholdFunc.Execute(traceCtx, now, vars, tracer)
-	}
-	if len(unloadedVals) == 0 { // if all values are loaded
-		return h.UnloadingThresholdFunc.Execute(traceCtx, now, vars, tracer)
-	}
+	span.SetAttributes(attribute.Int("matchedLoadedDimensions", matched))
+	logger.Debug("Evaluating thresholds", "unloadingThresholdDimensions", matched, "loadingThresholdDimensions", len(results.Values)-matched)
 
-	defer func() {
-		// return back the old values
-		vars[h.ReferenceVar] = results
-	}()
+	return newRes, nil
+}
 
-	vars[h.ReferenceVar] = mathexp.Results{Values: unloadedVals}
-	loadingResults, err := h.LoadingThresholdFunc.Execute(traceCtx, now, vars, tracer)
-	if err != nil {
-		return mathexp.Results{}, fmt.Errorf("failed to execute loading threshold: %w", err)
-	}
-	vars[h.ReferenceVar] = mathexp.Results{Values: loadedVals}
-	unloadingResults, err := h.UnloadingThresholdFunc.Execute(traceCtx, now, vars, tracer)
-	if err != nil {
-		return mathexp.Results{}, fmt.Errorf("failed to execute unloading threshold: %w", err)
+// threshold returns the threshold to evaluate a dimension with, depending on whether it is loaded.
+func (h *HysteresisCommand) threshold(loaded bool) *ThresholdCommand {
+	if loaded {
+		return &h.UnloadingThresholdFunc
 	}
+	return &h.LoadingThresholdFunc
+}
 
-	return mathexp.Results{Values: append(loadingResults.Values, unloadingResults.Values...)}, nil
+// evalSeries evaluates the points of the series in order, starting from the given loaded state.
+// A point with a result of 1 loads the dimension for the next point, a point with a result of 0 unloads it,
+// and a point without a value keeps the state.
+func (h *HysteresisCommand) evalSeries(s mathexp.Series, loaded bool) mathexp.Series {
+	result := mathexp.NewSeries(h.RefID, s.GetLabels(), s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, value := s.GetPoint(i)
+		r := h.threshold(loaded).eval(value)
+		if r != nil {
+			loaded = *r == 1
+		}
+		result.SetPoint(i, t, r)
+	}
+	return result
 }
 
 func (h HysteresisCommand) Type() string {
@@ -119,6 +119,97 @@ func TestHysteresisExecute(t *testing.T) {
 	}
 }
 
+func TestHysteresisExecute_Ranges(t *testing.T) {
+	tracer := tracing.InitializeTracerForTest()
+	labels := data.Labels{"label": "value"}
+
+	// load when the value is within (10, 20), recover when it leaves (5, 25)
+	newCommand := func(t *testing.T, loaded Fingerprints) *HysteresisCommand {
+		loading, err := NewThresholdCommand("B", "A", ThresholdIsWithinRange, []float64{10, 20})
+		require.NoError(t, err)
+		unloading, err := NewThresholdCommand("B", "A", ThresholdIsOutsideRange, []float64{5, 25})
+		require.NoError(t, err)
+		unloading.Invert = true
+		cmd, err := NewHysteresisCommand("B", "A", *loading, *unloading, loaded)
+		require.NoError(t, err)
+		return cmd
+	}
+
+	t.Run("should evaluate numbers against the range of their state", func(t *testing.T) {
+		for _, tc := range []struct {
+			value    float64
+			loaded   bool
+			expected float64
+		}{
+			{value: 15, loaded: false, expected: 1},
+			{value: 22, loaded: false, expected: 0},
+			{value: 22, loaded: true, expected: 1},
+			{value: 3, loaded: true, expected: 0},
+		} {
+			loaded := Fingerprints{}
+			if tc.loaded {
+				loaded[labels.Fingerprint()] = struct{}{}
+			}
+			n := mathexp.NewNumber("A", labels)
+			n.SetValue(&tc.value)
+			result, err := newCommand(t, loaded).Execute(context.Background(), time.Now(), mathexp.Vars{
+				"A": mathexp.Results{Values: mathexp.Values{n}},
+			}, tracer)
+			require.NoError(t, err)
+			require.Len(t, result.Values, 1)
+			require.Equalf(t, tc.expected, *result.Values[0].(mathexp.Number).GetFloat64Value(), "value %v, loaded %v", tc.value, tc.loaded)
+		}
+	})
+
+	t.Run("should evaluate points of a series in order", func(t *testing.T) {
+		values := []*float64{fp(0), fp(15), fp(22), nil, fp(7), fp(3), fp(22)}
+		expected := []*float64{fp(0), fp(1), fp(1), nil, fp(1), fp(0), fp(0)}
+
+		s := mathexp.NewSeries("A", labels, len(values))
+		for i, v := range values {
+			s.SetPoint(i, time.Unix(int64(i), 0), v)
+		}
+		result, err := newCommand(t, Fingerprints{}).Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: mathexp.Values{s}},
+		}, tracer)
+		require.NoError(t, err)
+		require.Len(t, result.Values, 1)
+		series := result.Values[0].(mathexp.Series)
+		require.Equal(t, labels, series.GetLabels())
+		for i := range expected {
+			require.Equalf(t, time.Unix(int64(i), 0), series.GetTime(i), "point %d", i)
+			require.Equalf(t, expected[i], series.GetValue(i), "point %d", i)
+		}
+	})
+
+	t.Run("should read loaded dimensions from the context", func(t *testing.T) {
+		n := mathexp.NewNumber("A", labels)
+		n.SetValue(fp(22))
+		vars := mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{n}}}
+		cmd := newCommand(t, Fingerprints{})
+
+		reader := &fakeLoadedDimensionsReader{}
+		ctx := WithLoadedDimensionsReader(context.Background(), reader)
+
+		result, err := cmd.Execute(ctx, time.Now(), vars, tracer)
+		require.NoError(t, err)
+		require.Equal(t, 0.0, *result.Values[0].(mathexp.Number).GetFloat64Value())
+
+		reader.loaded = Fingerprints{labels.Fingerprint(): {}}
+		result, err = cmd.Execute(ctx, time.Now(), vars, tracer)
+		require.NoError(t, err)
+		require.Equal(t, 1.0, *result.Values[0].(mathexp.Number).GetFloat64Value())
+	})
+}
+
+type fakeLoadedDimensionsReader struct {
+	loaded Fingerprints
+}
+
+func (f *fakeLoadedDimensionsReader) Read() map[data.Fingerprint]struct{} {
+	return f.loaded
+}
+
 func TestLoadedDimensionsFromFrame(t *testing.T) {
 	correctType := &data.FrameMeta{Type: "fingerprints", TypeVersion: data.FrameTypeVersion{1, 0}}
 	testCases := []struct {
@@ -0,0 +1,80 @@
+package expr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"gonum.org/v1/gonum/graph/simple"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+const (
+	// inputDatasourceID is similar to a fake ID for CMDNode. There is no specific reason for the selection of this value.
+	inputDatasourceID = -300
+
+	// InputDatasourceUID is the string constant used as the datasource name in requests
+	// to identify a query whose data is not queried but provided with the request, see Request.Inputs.
+	InputDatasourceUID = "__input__"
+
+	// inputDatasourceType is the type of the data source model of input queries.
+	inputDatasourceType = "__input__"
+)
+
+// InputNode is a node of expression tree whose data is provided by the caller of the request in Request.Inputs
+// under the name set in the field "input" of the query, for example the results of a pipeline executed before.
+type InputNode struct {
+	baseNode
+	name   string
+	frames data.Frames
+	found  bool
+}
+
+// NodeType returns the data pipeline node type.
+func (in *InputNode) NodeType() NodeType {
+	return TypeInputNode
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the node.
+func (in *InputNode) NeedsVars() []string {
+	return []string{}
+}
+
+// String returns the name of the input.
+func (in *InputNode) String() string {
+	return in.name
+}
+
+// Execute converts the frames of the input to mathexp.Results the same way as the response of a data source query.
+// Returns QueryError if the input was not provided with the request.
+func (in *InputNode) Execute(ctx context.Context, _ time.Time, _ mathexp.Vars, s *Service) (mathexp.Results, error) {
+	if !in.found {
+		return mathexp.Results{}, MakeQueryError(in.refID, InputDatasourceUID, fmt.Errorf("input %q is not provided", in.name))
+	}
+	_, result, err := s.converter.Convert(ctx, inputDatasourceType, in.frames, s.allowLongFrames)
+	if err != nil {
+		err = makeConversionError(in.refID, err)
+	}
+	return result, err
+}
+
+func (s *Service) buildInputNode(_ *simple.DirectedGraph, rn *rawNode, req *Request) (Node, error) {
+	name, ok := rn.Query["input"].(string)
+	if !ok || name == "" {
+		return nil, errors.New("input query must have the name of the input in the field 'input'")
+	}
+	frames, found := req.Inputs[name]
+	return &InputNode{
+		baseNode: baseNode{
+			id:    rn.idx,
+			refID: rn.RefID,
+		},
+		name:   name,
+		frames: frames,
+		found:  found,
+	}, nil
+}
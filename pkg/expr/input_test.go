@@ -0,0 +1,82 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestInputNode(t *testing.T) {
+	features := featuremgmt.WithFeatures()
+	s := Service{
+		cfg:      setting.NewCfg(),
+		features: features,
+		tracer:   tracing.InitializeTracerForTest(),
+		metrics:  newMetrics(nil),
+		converter: &ResultConverter{
+			Features: features,
+			Tracer:   tracing.InitializeTracerForTest(),
+		},
+	}
+	inputDS, err := DataSourceModelFromNodeType(TypeInputNode)
+	require.NoError(t, err)
+
+	queries := func(input string) []Query {
+		return []Query{
+			{
+				RefID:      "A",
+				DataSource: inputDS,
+				JSON:       json.RawMessage(`{ "datasource": { "uid": "__input__" }, "input": "` + input + `" }`),
+			},
+			{
+				RefID:      "B",
+				DataSource: dataSourceModel(),
+				JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$A * 2" }`),
+			},
+		}
+	}
+	number := mathexp.NewNumber("A", data.Labels{"instance": "a"})
+	number.SetValue(fp(2))
+	inputs := map[string]data.Frames{"recorded": {number.AsDataFrame()}}
+
+	t.Run("uses the frames of the input", func(t *testing.T) {
+		req := &Request{Queries: queries("recorded"), Inputs: inputs, User: &user.SignedInUser{}}
+		pl, err := s.BuildPipeline(req)
+		require.NoError(t, err)
+
+		res, err := s.ExecutePipeline(context.Background(), time.Now(), pl)
+		require.NoError(t, err)
+		require.NoError(t, res.Responses["B"].Error)
+		require.Len(t, res.Responses["B"].Frames, 1)
+		frame := res.Responses["B"].Frames[0]
+		require.Equal(t, data.Labels{"instance": "a"}, frame.Fields[0].Labels)
+		require.Equal(t, 4.0, *frame.Fields[0].At(0).(*float64))
+	})
+
+	t.Run("fails if the input is not provided", func(t *testing.T) {
+		req := &Request{Queries: queries("unknown"), Inputs: inputs, User: &user.SignedInUser{}}
+		pl, err := s.BuildPipeline(req)
+		require.NoError(t, err)
+
+		res, err := s.ExecutePipeline(context.Background(), time.Now(), pl)
+		require.NoError(t, err)
+		require.ErrorContains(t, res.Responses["A"].Error, `input "unknown" is not provided`)
+		require.ErrorIs(t, res.Responses["B"].Error, DependencyError)
+	})
+
+	t.Run("fails to build if the query has no input", func(t *testing.T) {
+		req := &Request{Queries: queries(""), User: &user.SignedInUser{}}
+		_, err := s.BuildPipeline(req)
+		require.ErrorContains(t, err, "input query must have the name of the input")
+	})
+}
@@ -27,7 +27,7 @@ type State struct {
 	//  - Unions (How many result A and many Result B in case A + B are joined)
 	//  - NaN/Null behavior
 	RefID     string
-	Drops     map[string]map[string][]data.Labels // binary node text -> LH/RH -> Drop Labels
+	Drops     Drops
 	DropCount int64
 
 	tracer tracing.Tracer
@@ -36,6 +36,11 @@ type State struct {
 // Vars holds the results of datasource queries or other expression commands.
 type Vars map[string]Results
 
+// Drops holds the labels of the items that were dropped from the unions of binary
+// operations because they did not match any item of the other side.
+// It is keyed by the text of the binary operation and then by the text of the side.
+type Drops map[string]map[string][]data.Labels
+
 // New creates a new expression tree
 func New(expr string, funcs ...map[string]parse.Func) (*Expr, error) {
 	funcs = append(funcs, builtins)
@@ -61,6 +66,20 @@ func (e *Expr) Execute(refID string, vars Vars, tracer tracing.Tracer) (r Result
 	return e.executeState(s)
 }
 
+// ExecuteWithDrops is like Execute and also returns the items that were dropped
+// from the unions of binary operations.
+func (e *Expr) ExecuteWithDrops(refID string, vars Vars, tracer tracing.Tracer) (Results, Drops, error) {
+	s := &State{
+		Expr:  e,
+		Vars:  vars,
+		RefID: refID,
+
+		tracer: tracer,
+	}
+	r, err := e.executeState(s)
+	return r, s.Drops, err
+}
+
 func (e *Expr) executeState(s *State) (r Results, err error) {
 	defer errRecover(&err, s)
 	r, err = s.walk(e.Tree.Root)
@@ -91,7 +110,7 @@ func (e *State) walk(node parse.Node) (res Results, err error) {
 	case *parse.ScalarNode:
 		res = NewScalarResults(e.RefID, &node.Float64)
 	case *parse.VarNode:
-		res = e.Vars[node.Name]
+		res = e.walkVar(node)
 	case *parse.BinaryNode:
 		res, err = e.walkBinary(node)
 	case *parse.UnaryNode:
@@ -104,6 +123,31 @@ func (e *State) walk(node parse.Node) (res Results, err error) {
 	return
 }
 
+// walkVar returns the results of the variable. If the variable has an offset, the results
+// provided under the offset name of the variable are used, or the results of the variable itself
+// if there are none, and their series are shifted forward by the offset so they align with the
+// series of the current time range.
+func (e *State) walkVar(node *parse.VarNode) Results {
+	if node.Offset == 0 {
+		return e.Vars[node.Name]
+	}
+	res, ok := e.Vars[node.OffsetName()]
+	if !ok {
+		res = e.Vars[node.Name]
+	}
+	if len(res.Values) == 0 {
+		return res
+	}
+	shifted := Results{Values: make(Values, 0, len(res.Values)), Error: res.Error}
+	for _, v := range res.Values {
+		if s, ok := v.(Series); ok {
+			v = s.Shift(node.Offset)
+		}
+		shifted.Values = append(shifted.Values, v)
+	}
+	return shifted
+}
+
 func (e *State) walkUnary(node *parse.UnaryNode) (Results, error) {
 	a, err := e.walk(node.Arg)
 	if err != nil {
@@ -220,7 +264,7 @@ func (e *State) union(aResults, bResults Results, biNode *parse.BinaryNode) []*U
 					continue
 				}
 				if e.Drops == nil {
-					e.Drops = make(map[string]map[string][]data.Labels)
+					e.Drops = make(Drops)
 				}
 				if e.Drops[biNode.String()] == nil {
 					e.Drops[biNode.String()] = make(map[string][]data.Labels)
@@ -566,7 +610,7 @@ func (e *State) walkFunc(node *parse.FuncNode) (Results, error) {
 		case *parse.StringNode:
 			v = t.Text
 		case *parse.VarNode:
-			v = e.Vars[t.Name]
+			v = e.walkVar(t)
 		case *parse.ScalarNode:
 			v = NewScalarResults(e.RefID, &t.Float64)
 		case *parse.FuncNode:
@@ -8,6 +8,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSeriesExpr(t *testing.T) {
@@ -168,3 +169,51 @@ func TestSeriesExpr(t *testing.T) {
 		})
 	}
 }
+
+func TestSeriesExprOffset(t *testing.T) {
+	current := makeSeries("", data.Labels{"host": "a"},
+		tp{time.Unix(100, 0), float64Pointer(4)},
+		tp{time.Unix(110, 0), float64Pointer(9)})
+	previous := makeSeries("", data.Labels{"host": "a"},
+		tp{time.Unix(90, 0), float64Pointer(2)},
+		tp{time.Unix(100, 0), float64Pointer(3)})
+
+	t.Run("should use the shifted results of the variable", func(t *testing.T) {
+		e, err := New("$A / $A offset 10s")
+		require.NoError(t, err)
+		require.Len(t, e.OffsetVars, 1)
+		require.Equal(t, "A offset 10s", e.OffsetVars[0].OffsetName())
+		require.Equal(t, 10*time.Second, e.OffsetVars[0].Offset)
+
+		res, err := e.Execute("", Vars{
+			"A":            resultValuesNoErr(current),
+			"A offset 10s": resultValuesNoErr(previous),
+		}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		expected := resultValuesNoErr(makeSeries("", data.Labels{"host": "a"},
+			tp{time.Unix(100, 0), float64Pointer(2)},
+			tp{time.Unix(110, 0), float64Pointer(3)}))
+		if diff := cmp.Diff(expected, res, data.FrameTestCompareOptions()...); diff != "" {
+			t.Errorf("Result mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should shift the variable itself without shifted results", func(t *testing.T) {
+		e, err := New("$A - ($A offset 10s)")
+		require.NoError(t, err)
+		res, err := e.Execute("", Vars{"A": resultValuesNoErr(current)}, tracing.InitializeTracerForTest())
+		require.NoError(t, err)
+		expected := resultValuesNoErr(makeSeries("", data.Labels{"host": "a"},
+			tp{time.Unix(110, 0), float64Pointer(5)}))
+		if diff := cmp.Diff(expected, res, data.FrameTestCompareOptions()...); diff != "" {
+			t.Errorf("Result mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should reject invalid offsets", func(t *testing.T) {
+		for _, expr := range []string{"$A offset", "$A offset 10", "$A offset 1x", "($A) offset 1w"} {
+			_, err := New(expr)
+			require.Errorf(t, err, expr)
+		}
+	})
+}
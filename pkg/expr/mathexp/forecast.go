@@ -0,0 +1,318 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// The forecast model
+// +enum
+type ForecastMethod string
+
+const (
+	// Fit a straight line with least squares
+	ForecastMethodLinear ForecastMethod = "linear"
+
+	// Additive Holt-Winters (triple exponential smoothing), or Holt's linear trend
+	// method when no season is set
+	ForecastMethodHoltWinters ForecastMethod = "holt_winters"
+)
+
+// ForecastLabel is the label added to the forecast series to tell the projected
+// values apart from their confidence bounds.
+const ForecastLabel = "forecast"
+
+// Values of the ForecastLabel.
+const (
+	ForecastPredicted = "predicted"
+	ForecastLower     = "lower"
+	ForecastUpper     = "upper"
+)
+
+// Default parameters of the forecast.
+const (
+	DefaultForecastConfidence = 0.95
+	DefaultForecastAlpha      = 0.5
+	DefaultForecastBeta       = 0.1
+	DefaultForecastGamma      = 0.1
+)
+
+// MaxForecastPoints is the maximum number of points of each forecast series. The points are spaced by the
+// interval of the input series, so a long horizon over a series with a short interval is rejected.
+const MaxForecastPoints = 10000
+
+// ForecastOptions are the parameters of Series.Forecast.
+type ForecastOptions struct {
+	Method ForecastMethod
+	// Horizon is how far past the last point of the series to project.
+	Horizon time.Duration
+	// Confidence is the confidence level of the bounds, between 0 and 1 (exclusive).
+	Confidence float64
+	// Season is the length of the seasonal cycle. Only used by Holt-Winters, 0 disables seasonality.
+	Season time.Duration
+	// Alpha, Beta and Gamma are the level, trend and seasonal smoothing factors of Holt-Winters.
+	Alpha, Beta, Gamma float64
+}
+
+// Validate returns an error if the options cannot be used to forecast.
+func (o ForecastOptions) Validate() error {
+	switch o.Method {
+	case ForecastMethodLinear, ForecastMethodHoltWinters:
+	default:
+		return fmt.Errorf("forecast method %q not implemented", o.Method)
+	}
+	if o.Horizon <= 0 {
+		return fmt.Errorf("forecast horizon must be greater than zero")
+	}
+	if o.Confidence <= 0 || o.Confidence >= 1 {
+		return fmt.Errorf("forecast confidence must be between 0 and 1, got %v", o.Confidence)
+	}
+	if o.Season < 0 {
+		return fmt.Errorf("forecast season must not be negative")
+	}
+	for _, p := range []struct {
+		name  string
+		value float64
+	}{{"alpha", o.Alpha}, {"beta", o.Beta}, {"gamma", o.Gamma}} {
+		if p.value < 0 || p.value > 1 {
+			return fmt.Errorf("forecast %s must be between 0 and 1, got %v", p.name, p.value)
+		}
+	}
+	return nil
+}
+
+// Forecast fits the model to the non-null values of the series and projects it up to
+// the horizon past the last point, with the same step as the median interval of the series.
+// It returns three series, the projected values and the lower and upper confidence bounds,
+// told apart by the ForecastLabel. The series are empty if there are fewer than two values.
+// It returns an error if the series would have more than MaxForecastPoints points.
+func (s Series) Forecast(refID string, opts ForecastOptions) ([]Series, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	times, values := forecastInput(s)
+
+	var model forecastModel
+	var step time.Duration
+	if len(values) >= 2 {
+		step = medianStep(times)
+		if step <= 0 {
+			return nil, fmt.Errorf("cannot forecast a series with duplicate timestamps only")
+		}
+		if points := opts.Horizon / step; points > MaxForecastPoints {
+			return nil, fmt.Errorf("forecast horizon %s is %d intervals of %s of the series, the limit is %d points", opts.Horizon, points, step, MaxForecastPoints)
+		}
+		var err error
+		switch opts.Method {
+		case ForecastMethodLinear:
+			model = fitLinear(times, values)
+		case ForecastMethodHoltWinters:
+			model, err = fitHoltWinters(times, values, step, opts)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]Series, 0, 3)
+	for _, kind := range []string{ForecastPredicted, ForecastLower, ForecastUpper} {
+		labels := s.GetLabels().Copy()
+		if labels == nil {
+			labels = data.Labels{}
+		}
+		labels[ForecastLabel] = kind
+		out = append(out, NewSeries(refID, labels, 0))
+	}
+	if model == nil {
+		return out, nil
+	}
+
+	z := math.Sqrt2 * math.Erfinv(opts.Confidence)
+	last := times[len(times)-1]
+	for ahead := step; ; ahead += step {
+		if ahead > opts.Horizon {
+			ahead = opts.Horizon
+		}
+		t := last.Add(ahead)
+		predicted, stdErr := model.predict(t)
+		lower, upper := predicted-z*stdErr, predicted+z*stdErr
+		out[0].AppendPoint(t, &predicted)
+		out[1].AppendPoint(t, &lower)
+		out[2].AppendPoint(t, &upper)
+		if ahead == opts.Horizon {
+			break
+		}
+	}
+	return out, nil
+}
+
+// forecastModel is a fitted model that returns the predicted value at a time along with
+// the standard error of the prediction.
+type forecastModel interface {
+	predict(t time.Time) (value float64, stdErr float64)
+}
+
+// forecastInput returns the times and values of the series ordered by time, leaving
+// out null, NaN and infinite values.
+func forecastInput(s Series) ([]time.Time, []float64) {
+	times := make([]time.Time, 0, s.Len())
+	values := make([]float64, 0, s.Len())
+	for _, p := range sortedPoints(s) {
+		if p.f == nil || math.IsNaN(*p.f) || math.IsInf(*p.f, 0) {
+			continue
+		}
+		times = append(times, p.t)
+		values = append(values, *p.f)
+	}
+	return times, values
+}
+
+// medianStep returns the median of the intervals between consecutive times.
+func medianStep(times []time.Time) time.Duration {
+	steps := make([]time.Duration, 0, len(times)-1)
+	for i := 1; i < len(times); i++ {
+		if d := times[i].Sub(times[i-1]); d > 0 {
+			steps = append(steps, d)
+		}
+	}
+	if len(steps) == 0 {
+		return 0
+	}
+	sort.Slice(steps, func(i, j int) bool { return steps[i] < steps[j] })
+	return steps[len(steps)/2]
+}
+
+// linearModel is a least squares fit of value = intercept + slope * seconds since start.
+type linearModel struct {
+	start            time.Time
+	intercept, slope float64
+	n                float64
+	meanX, sxx       float64
+	residualStdErr   float64
+}
+
+func fitLinear(times []time.Time, values []float64) *linearModel {
+	m := &linearModel{start: times[0], n: float64(len(values))}
+	xs := make([]float64, len(times))
+	var meanY float64
+	for i, t := range times {
+		xs[i] = t.Sub(m.start).Seconds()
+		m.meanX += xs[i]
+		meanY += values[i]
+	}
+	m.meanX /= m.n
+	meanY /= m.n
+
+	var sxy float64
+	for i, x := range xs {
+		m.sxx += (x - m.meanX) * (x - m.meanX)
+		sxy += (x - m.meanX) * (values[i] - meanY)
+	}
+	m.slope = sxy / m.sxx
+	m.intercept = meanY - m.slope*m.meanX
+
+	if len(values) > 2 {
+		var sse float64
+		for i, x := range xs {
+			r := values[i] - (m.intercept + m.slope*x)
+			sse += r * r
+		}
+		m.residualStdErr = math.Sqrt(sse / (m.n - 2))
+	}
+	return m
+}
+
+func (m *linearModel) predict(t time.Time) (float64, float64) {
+	x := t.Sub(m.start).Seconds()
+	stdErr := m.residualStdErr * math.Sqrt(1+1/m.n+(x-m.meanX)*(x-m.meanX)/m.sxx)
+	return m.intercept + m.slope*x, stdErr
+}
+
+// holtWintersModel is the state of additive Holt-Winters after smoothing all values.
+type holtWintersModel struct {
+	last         time.Time
+	step         time.Duration
+	level, trend float64
+	// season holds the seasonal components, indexed by position in the cycle.
+	season []float64
+	// next is the position in the cycle of the first projected step.
+	next int
+	// sigma is the standard deviation of the one step ahead errors.
+	sigma float64
+}
+
+func fitHoltWinters(times []time.Time, values []float64, step time.Duration, opts ForecastOptions) (*holtWintersModel, error) {
+	m := &holtWintersModel{last: times[len(times)-1], step: step}
+	period := int(math.Round(float64(opts.Season) / float64(step)))
+	if opts.Season > 0 && period < 2 {
+		return nil, fmt.Errorf("forecast season %v must be at least two times the interval of the series %v", opts.Season, step)
+	}
+
+	start := 1
+	if period > 0 {
+		if len(values) < 2*period {
+			return nil, fmt.Errorf("forecast with a season of %v needs at least %d values, got %d", opts.Season, 2*period, len(values))
+		}
+		first, second := mean(values[:period]), mean(values[period:2*period])
+		m.level = first
+		m.trend = (second - first) / float64(period)
+		m.season = make([]float64, period)
+		for i := range m.season {
+			m.season[i] = values[i] - first
+		}
+		start = period
+	} else {
+		m.level = values[0]
+		m.trend = values[1] - values[0]
+	}
+
+	var sse float64
+	for i := start; i < len(values); i++ {
+		seasonal := 0.0
+		if period > 0 {
+			seasonal = m.season[i%period]
+		}
+		err := values[i] - (m.level + m.trend + seasonal)
+		sse += err * err
+
+		level := opts.Alpha*(values[i]-seasonal) + (1-opts.Alpha)*(m.level+m.trend)
+		m.trend = opts.Beta*(level-m.level) + (1-opts.Beta)*m.trend
+		if period > 0 {
+			m.season[i%period] = opts.Gamma*(values[i]-level) + (1-opts.Gamma)*seasonal
+		}
+		m.level = level
+	}
+	if n := len(values) - start; n > 0 {
+		m.sigma = math.Sqrt(sse / float64(n))
+	}
+	if period > 0 {
+		m.next = len(values) % period
+	}
+	return m, nil
+}
+
+func (m *holtWintersModel) predict(t time.Time) (float64, float64) {
+	steps := float64(t.Sub(m.last)) / float64(m.step)
+	value := m.level + steps*m.trend
+	if len(m.season) > 0 {
+		k := int(math.Round(steps))
+		if k < 1 {
+			k = 1
+		}
+		value += m.season[(m.next+k-1)%len(m.season)]
+	}
+	return value, m.sigma * math.Sqrt(steps)
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
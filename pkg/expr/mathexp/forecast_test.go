@@ -0,0 +1,178 @@
+package mathexp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesForecast(t *testing.T) {
+	defaults := ForecastOptions{
+		Confidence: DefaultForecastConfidence,
+		Alpha:      DefaultForecastAlpha,
+		Beta:       DefaultForecastBeta,
+		Gamma:      DefaultForecastGamma,
+	}
+	withOpts := func(f func(o *ForecastOptions)) ForecastOptions {
+		o := defaults
+		f(&o)
+		return o
+	}
+	valuesOf := func(s Series) []float64 {
+		values := make([]float64, 0, s.Len())
+		for i := 0; i < s.Len(); i++ {
+			values = append(values, math.Round(*s.GetValue(i)*1e9)/1e9)
+		}
+		return values
+	}
+
+	tests := []struct {
+		name      string
+		series    Series
+		opts      ForecastOptions
+		errIs     require.ErrorAssertionFunc
+		times     []time.Time
+		predicted []float64
+	}{
+		{
+			name: "linear regression on a straight line",
+			series: makeSeries("", data.Labels{"host": "a"},
+				tp{time.Unix(30, 0), float64Pointer(60)},
+				tp{time.Unix(0, 0), float64Pointer(0)},
+				tp{time.Unix(10, 0), float64Pointer(20)},
+				tp{time.Unix(20, 0), nil},
+				tp{time.Unix(20, 0), float64Pointer(40)}),
+			opts: withOpts(func(o *ForecastOptions) {
+				o.Method = ForecastMethodLinear
+				o.Horizon = 25 * time.Second
+			}),
+			errIs:     require.NoError,
+			times:     []time.Time{time.Unix(40, 0), time.Unix(50, 0), time.Unix(55, 0)},
+			predicted: []float64{80, 100, 110},
+		},
+		{
+			name: "holt's linear trend",
+			series: makeSeries("", nil,
+				tp{time.Unix(0, 0), float64Pointer(0)},
+				tp{time.Unix(10, 0), float64Pointer(1)},
+				tp{time.Unix(20, 0), float64Pointer(2)},
+				tp{time.Unix(30, 0), float64Pointer(3)}),
+			opts: withOpts(func(o *ForecastOptions) {
+				o.Method = ForecastMethodHoltWinters
+				o.Horizon = 20 * time.Second
+			}),
+			errIs:     require.NoError,
+			times:     []time.Time{time.Unix(40, 0), time.Unix(50, 0)},
+			predicted: []float64{4, 5},
+		},
+		{
+			name: "holt-winters with a season",
+			series: makeSeries("", nil,
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(10, 0), float64Pointer(3)},
+				tp{time.Unix(20, 0), float64Pointer(1)},
+				tp{time.Unix(30, 0), float64Pointer(3)},
+				tp{time.Unix(40, 0), float64Pointer(1)},
+				tp{time.Unix(50, 0), float64Pointer(3)}),
+			opts: withOpts(func(o *ForecastOptions) {
+				o.Method = ForecastMethodHoltWinters
+				o.Horizon = 30 * time.Second
+				o.Season = 20 * time.Second
+			}),
+			errIs:     require.NoError,
+			times:     []time.Time{time.Unix(60, 0), time.Unix(70, 0), time.Unix(80, 0)},
+			predicted: []float64{1, 3, 1},
+		},
+		{
+			name: "not enough values for the season - should error",
+			series: makeSeries("", nil,
+				tp{time.Unix(0, 0), float64Pointer(1)},
+				tp{time.Unix(10, 0), float64Pointer(3)},
+				tp{time.Unix(20, 0), float64Pointer(1)}),
+			opts: withOpts(func(o *ForecastOptions) {
+				o.Method = ForecastMethodHoltWinters
+				o.Horizon = time.Minute
+				o.Season = 20 * time.Second
+			}),
+			errIs: require.Error,
+		},
+		{
+			name:   "single value returns empty series",
+			series: makeSeries("", nil, tp{time.Unix(0, 0), float64Pointer(1)}),
+			opts: withOpts(func(o *ForecastOptions) {
+				o.Method = ForecastMethodLinear
+				o.Horizon = time.Minute
+			}),
+			errIs: require.NoError,
+		},
+		{
+			name: "too many points up to the horizon - should error",
+			series: makeSeries("", nil,
+				tp{time.Unix(0, 0), float64Pointer(0)},
+				tp{time.Unix(1, 0), float64Pointer(1)}),
+			opts: withOpts(func(o *ForecastOptions) {
+				o.Method = ForecastMethodLinear
+				o.Horizon = (MaxForecastPoints + 1) * time.Second
+			}),
+			errIs: require.Error,
+		},
+		{
+			name:   "invalid confidence - should error",
+			series: makeSeries("", nil),
+			opts: withOpts(func(o *ForecastOptions) {
+				o.Method = ForecastMethodLinear
+				o.Horizon = time.Minute
+				o.Confidence = 1
+			}),
+			errIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.series.Forecast("B", tt.opts)
+			tt.errIs(t, err)
+			if err != nil {
+				return
+			}
+			require.Len(t, res, 3)
+			for i, kind := range []string{ForecastPredicted, ForecastLower, ForecastUpper} {
+				require.Equal(t, kind, res[i].GetLabels()[ForecastLabel])
+				require.Equal(t, len(tt.times), res[i].Len())
+			}
+			for i, ts := range tt.times {
+				require.Equal(t, ts, res[0].GetTime(i))
+			}
+			if len(tt.predicted) > 0 {
+				require.Equal(t, tt.predicted, valuesOf(res[0]))
+				// the models fit the values exactly, so the bounds collapse onto the prediction
+				require.Equal(t, tt.predicted, valuesOf(res[1]))
+				require.Equal(t, tt.predicted, valuesOf(res[2]))
+			}
+		})
+	}
+}
+
+func TestSeriesForecast_ConfidenceBounds(t *testing.T) {
+	s := makeSeries("", nil,
+		tp{time.Unix(0, 0), float64Pointer(0)},
+		tp{time.Unix(10, 0), float64Pointer(12)},
+		tp{time.Unix(20, 0), float64Pointer(18)},
+		tp{time.Unix(30, 0), float64Pointer(32)})
+	res, err := s.Forecast("B", ForecastOptions{
+		Method:     ForecastMethodLinear,
+		Horizon:    20 * time.Second,
+		Confidence: 0.9,
+	})
+	require.NoError(t, err)
+	for i := 0; i < res[0].Len(); i++ {
+		predicted, lower, upper := *res[0].GetValue(i), *res[1].GetValue(i), *res[2].GetValue(i)
+		require.Less(t, lower, predicted)
+		require.Greater(t, upper, predicted)
+		require.InDelta(t, predicted-lower, upper-predicted, 1e-9)
+	}
+	// the interval widens further from the data
+	require.Greater(t, *res[2].GetValue(1)-*res[1].GetValue(1), *res[2].GetValue(0)-*res[1].GetValue(0))
+}
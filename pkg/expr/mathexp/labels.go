@@ -0,0 +1,276 @@
+package mathexp
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// label_replace($A, "dst", "replacement", "src", "regex")
+func checkLabelReplace(t *parse.Tree, f *parse.FuncNode) error {
+	if err := checkLabelName(f.Name, stringArg(f, 1)); err != nil {
+		return err
+	}
+	if _, err := compileLabelRegex(stringArg(f, 4)); err != nil {
+		return fmt.Errorf("parse: %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// label_join($A, "dst", "separator", "src1,src2")
+func checkLabelJoin(t *parse.Tree, f *parse.FuncNode) error {
+	if err := checkLabelName(f.Name, stringArg(f, 1)); err != nil {
+		return err
+	}
+	return checkLabelNamesArg(3)(t, f)
+}
+
+// group_by($A, "label1,label2", "reducer")
+func checkGroupBy(t *parse.Tree, f *parse.FuncNode) error {
+	if err := checkLabelNamesArg(1)(t, f); err != nil {
+		return err
+	}
+	if _, err := GetReduceFunc(ReducerID(stringArg(f, 2))); err != nil {
+		return fmt.Errorf("parse: %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// checkLabelNamesArg returns a parse time check that the argument at idx is a comma separated list of valid label names.
+func checkLabelNamesArg(idx int) func(t *parse.Tree, f *parse.FuncNode) error {
+	return func(t *parse.Tree, f *parse.FuncNode) error {
+		for _, name := range splitLabelNames(stringArg(f, idx)) {
+			if err := checkLabelName(f.Name, name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func checkLabelName(funcName, name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("parse: %s: label name must not be empty", funcName)
+	}
+	return nil
+}
+
+// stringArg returns the text of the string argument at idx, the type of which is checked by the parser.
+func stringArg(f *parse.FuncNode, idx int) string {
+	if sn, ok := f.Args[idx].(*parse.StringNode); ok {
+		return sn.Text
+	}
+	return ""
+}
+
+func splitLabelNames(s string) []string {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// compileLabelRegex compiles the regex anchored at both ends so it has to match the whole label value.
+func compileLabelRegex(s string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("^(?:" + s + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression %q: %w", s, err)
+	}
+	return re, nil
+}
+
+// labelReplace sets the label dst to the replacement for each item where the value of the label src matches the regex.
+// The replacement can refer to the capture groups of the regex, e.g. $1. If the replacement is empty the label dst is removed.
+// Items where the regex does not match are not changed.
+func labelReplace(e *State, varSet Results, dst, replacement, src, rawRegex string) (Results, error) {
+	re, err := compileLabelRegex(rawRegex)
+	if err != nil {
+		return Results{}, err
+	}
+	return e.relabel("label_replace", varSet, func(labels data.Labels) data.Labels {
+		value := labels[src]
+		match := re.FindStringSubmatchIndex(value)
+		if match == nil {
+			return labels
+		}
+		newValue := string(re.ExpandString(nil, replacement, value, match))
+		if newValue == "" {
+			delete(labels, dst)
+			return labels
+		}
+		labels[dst] = newValue
+		return labels
+	})
+}
+
+// labelDrop removes the labels in the comma separated list of names from each item.
+func labelDrop(e *State, varSet Results, names string) (Results, error) {
+	drop := splitLabelNames(names)
+	return e.relabel("label_drop", varSet, func(labels data.Labels) data.Labels {
+		for _, name := range drop {
+			delete(labels, name)
+		}
+		return labels
+	})
+}
+
+// labelKeep removes all labels but the ones in the comma separated list of names from each item.
+func labelKeep(e *State, varSet Results, names string) (Results, error) {
+	return e.relabel("label_keep", varSet, func(labels data.Labels) data.Labels {
+		return keepLabels(labels, splitLabelNames(names))
+	})
+}
+
+// labelJoin sets the label dst to the values of the labels in the comma separated list of srcs joined with the separator.
+// If the joined value is empty the label dst is removed.
+func labelJoin(e *State, varSet Results, dst, separator, srcs string) (Results, error) {
+	names := splitLabelNames(srcs)
+	return e.relabel("label_join", varSet, func(labels data.Labels) data.Labels {
+		values := make([]string, 0, len(names))
+		for _, name := range names {
+			values = append(values, labels[name])
+		}
+		newValue := strings.Join(values, separator)
+		if newValue == "" {
+			delete(labels, dst)
+			return labels
+		}
+		labels[dst] = newValue
+		return labels
+	})
+}
+
+func keepLabels(labels data.Labels, names []string) data.Labels {
+	kept := data.Labels{}
+	for _, name := range names {
+		if v, ok := labels[name]; ok {
+			kept[name] = v
+		}
+	}
+	return kept
+}
+
+// relabel returns a copy of each item of varSet with the labels changed by labelsF, which gets a copy of the labels.
+// It is an error if two items end up with the same labels, since they can no longer be told apart by the unions.
+func (e *State) relabel(name string, varSet Results, labelsF func(labels data.Labels) data.Labels) (Results, error) {
+	newRes := Results{}
+	seen := map[data.Fingerprint]struct{}{}
+	for _, val := range varSet.Values {
+		var newVal Value
+		switch v := val.(type) {
+		case Series:
+			labels := labelsF(v.GetLabels().Copy())
+			s := NewSeries(e.RefID, labels, v.Len())
+			for i := 0; i < v.Len(); i++ {
+				t, f := v.GetPoint(i)
+				s.SetPoint(i, t, f)
+			}
+			newVal = s
+		case Number:
+			labels := labelsF(v.GetLabels().Copy())
+			n := NewNumber(e.RefID, labels)
+			n.SetValue(v.GetFloat64Value())
+			newVal = n
+		case Scalar, NoData:
+			newRes.Values = append(newRes.Values, val)
+			continue
+		default:
+			return newRes, fmt.Errorf("%s: expected a number or a series but got %s", name, val.Type())
+		}
+		fp := newVal.GetLabels().Fingerprint()
+		if _, ok := seen[fp]; ok {
+			return newRes, fmt.Errorf("%s: more than one item has the labels %s after the change, use group_by to aggregate them", name, newVal.GetLabels())
+		}
+		seen[fp] = struct{}{}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// groupBy aggregates the items that have the same values of the labels in the comma separated list of names
+// with the reducer. The result has an item per group that only has these labels.
+// Numbers are reduced to a number and series are reduced, at each time of any of their points,
+// to a series of the values the series of the group have at that time.
+func groupBy(e *State, varSet Results, names, reducer string) (Results, error) {
+	reduceFunc, err := GetReduceFunc(ReducerID(reducer))
+	if err != nil {
+		return Results{}, err
+	}
+	labelNames := splitLabelNames(names)
+
+	type group struct {
+		labels data.Labels
+		values []Value
+	}
+	var groups []*group
+	byFingerprint := map[data.Fingerprint]*group{}
+	newRes := Results{}
+	for _, val := range varSet.Values {
+		switch val.(type) {
+		case Series, Number:
+		case Scalar, NoData:
+			newRes.Values = append(newRes.Values, val)
+			continue
+		default:
+			return newRes, fmt.Errorf("group_by: expected a number or a series but got %s", val.Type())
+		}
+		if len(groups) > 0 && groups[0].values[0].Type() != val.Type() {
+			return newRes, fmt.Errorf("group_by: expected all items to be of type %s but got %s", groups[0].values[0].Type(), val.Type())
+		}
+		labels := keepLabels(val.GetLabels(), labelNames)
+		fp := labels.Fingerprint()
+		g, ok := byFingerprint[fp]
+		if !ok {
+			g = &group{labels: labels}
+			byFingerprint[fp] = g
+			groups = append(groups, g)
+		}
+		g.values = append(g.values, val)
+	}
+
+	reduce := func(values []*float64) *float64 {
+		ff := Float64Field(*data.NewField("", nil, values))
+		return reduceFunc(&ff)
+	}
+	for _, g := range groups {
+		if _, ok := g.values[0].(Number); ok {
+			values := make([]*float64, 0, len(g.values))
+			for _, v := range g.values {
+				values = append(values, v.(Number).GetFloat64Value())
+			}
+			n := NewNumber(e.RefID, g.labels)
+			n.SetValue(reduce(values))
+			newRes.Values = append(newRes.Values, n)
+			continue
+		}
+
+		byTime := map[int64][]*float64{}
+		var times []time.Time
+		for _, v := range g.values {
+			s := v.(Series)
+			for i := 0; i < s.Len(); i++ {
+				t, f := s.GetPoint(i)
+				if _, ok := byTime[t.UnixNano()]; !ok {
+					times = append(times, t)
+				}
+				byTime[t.UnixNano()] = append(byTime[t.UnixNano()], f)
+			}
+		}
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+		s := NewSeries(e.RefID, g.labels, len(times))
+		for i, t := range times {
+			s.SetPoint(i, t, reduce(byTime[t.UnixNano()]))
+		}
+		newRes.Values = append(newRes.Values, s)
+	}
+	return newRes, nil
+}
@@ -0,0 +1,154 @@
+package mathexp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestLabelFuncs(t *testing.T) {
+	hosts := Vars{
+		"A": resultValuesNoErr(
+			makeNumber("", data.Labels{"instance": "web01:9100", "job": "node"}, float64Pointer(1)),
+			makeNumber("", data.Labels{"instance": "web02:9100", "job": "node"}, float64Pointer(2)),
+		),
+		"B": resultValuesNoErr(
+			makeNumber("", data.Labels{"host": "web01", "dc": "eu"}, float64Pointer(10)),
+			makeNumber("", data.Labels{"host": "web02", "dc": "us"}, float64Pointer(20)),
+		),
+	}
+
+	tests := []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name:      "label_replace normalises dimensions for unions",
+			expr:      `label_keep(label_replace($A, "host", "$1", "instance", "(.*):.*"), "host") + $B`,
+			vars:      hosts,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"host": "web01", "dc": "eu"}, float64Pointer(11)),
+				makeNumber("", data.Labels{"host": "web02", "dc": "us"}, float64Pointer(22)),
+			),
+		},
+		{
+			name:      "label_replace does not change items that do not match",
+			expr:      `label_replace($B, "region", "europe", "dc", "eu")`,
+			vars:      hosts,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"host": "web01", "dc": "eu", "region": "europe"}, float64Pointer(10)),
+				makeNumber("", data.Labels{"host": "web02", "dc": "us"}, float64Pointer(20)),
+			),
+		},
+		{
+			name:      "label_drop",
+			expr:      `label_drop($B, "dc, unknown")`,
+			vars:      hosts,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"host": "web01"}, float64Pointer(10)),
+				makeNumber("", data.Labels{"host": "web02"}, float64Pointer(20)),
+			),
+		},
+		{
+			name:      "label_join",
+			expr:      `label_join($B, "id", "/", "dc,host")`,
+			vars:      hosts,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"host": "web01", "dc": "eu", "id": "eu/web01"}, float64Pointer(10)),
+				makeNumber("", data.Labels{"host": "web02", "dc": "us", "id": "us/web02"}, float64Pointer(20)),
+			),
+		},
+		{
+			name:      "label_keep that makes items identical should error",
+			expr:      `label_keep($A, "job")`,
+			vars:      hosts,
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:      "group_by numbers",
+			expr:      `group_by($A, "job", "sum")`,
+			vars:      hosts,
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeNumber("", data.Labels{"job": "node"}, float64Pointer(3)),
+			),
+		},
+		{
+			name: "group_by series aligns points by time",
+			expr: `group_by($A, "dc", "max")`,
+			vars: Vars{
+				"A": resultValuesNoErr(
+					makeSeries("", data.Labels{"host": "a", "dc": "eu"},
+						tp{time.Unix(10, 0), float64Pointer(1)},
+						tp{time.Unix(5, 0), float64Pointer(4)}),
+					makeSeries("", data.Labels{"host": "b", "dc": "eu"},
+						tp{time.Unix(10, 0), float64Pointer(3)},
+						tp{time.Unix(15, 0), float64Pointer(2)}),
+					makeSeries("", data.Labels{"host": "c", "dc": "us"},
+						tp{time.Unix(10, 0), float64Pointer(7)}),
+				),
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: resultValuesNoErr(
+				makeSeries("", data.Labels{"dc": "eu"},
+					tp{time.Unix(5, 0), float64Pointer(4)},
+					tp{time.Unix(10, 0), float64Pointer(3)},
+					tp{time.Unix(15, 0), float64Pointer(2)}),
+				makeSeries("", data.Labels{"dc": "us"},
+					tp{time.Unix(10, 0), float64Pointer(7)}),
+			),
+		},
+		{
+			name:     "invalid regex should error",
+			expr:     `label_replace($A, "host", "$1", "instance", "(")`,
+			newErrIs: require.Error,
+		},
+		{
+			name:     "empty destination label should error",
+			expr:     `label_join($A, "", "/", "dc,host")`,
+			newErrIs: require.Error,
+		},
+		{
+			name:     "unknown reducer should error",
+			expr:     `group_by($A, "dc", "avg")`,
+			newErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if err != nil {
+				return
+			}
+			res, err := e.Execute("", tt.vars, tracing.InitializeTracerForTest())
+			tt.execErrIs(t, err)
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.results, res, data.FrameTestCompareOptions()...); diff != "" {
+				t.Errorf("Result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -33,6 +33,7 @@ var itemName = map[itemType]string{
 	itemRightParen: ")",
 	itemString:     "string",
 	itemFunc:       "func",
+	itemDuration:   "duration",
 }
 
 func (i itemType) String() string {
@@ -113,6 +114,18 @@ var lexTests = []lexTest{
 		{itemVar, 0, "$A"},
 		tEOF,
 	}},
+	{"var with offset", "$A / $A offset 1w", []item{
+		{itemVar, 0, "$A"},
+		tDiv,
+		{itemVar, 0, "$A"},
+		{itemFunc, 0, "offset"},
+		{itemDuration, 0, "1w"},
+		tEOF,
+	}},
+	{"compound duration", "1h30m", []item{
+		{itemDuration, 0, "1h30m"},
+		tEOF,
+	}},
 	// errors
 	{"unclosed quote", "\"", []item{
 		{itemError, 0, "unterminated string"},
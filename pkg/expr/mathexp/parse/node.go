@@ -9,6 +9,7 @@ package parse
 import (
 	"fmt"
 	"strconv"
+	"time"
 )
 
 // A Node is an element in the parse tree. The interface is trivial.
@@ -92,6 +93,10 @@ type VarNode struct {
 	Pos
 	Name string // Without the $ or {}
 	Text string // Raw
+
+	// Offset is the duration the variable is shifted back in time by, e.g. 1w in "$A offset 1w".
+	Offset     time.Duration
+	OffsetText string // Raw duration of the offset
 }
 
 func newVar(pos Pos, name, text string) *VarNode {
@@ -102,7 +107,21 @@ func newVar(pos Pos, name, text string) *VarNode {
 func (n *VarNode) Type() NodeType { return NodeVar }
 
 // String returns the string representation of the VarNode so it fulfills the Node interface.
-func (n *VarNode) String() string { return n.Text }
+func (n *VarNode) String() string {
+	if n.Offset != 0 {
+		return n.Text + " offset " + n.OffsetText
+	}
+	return n.Text
+}
+
+// OffsetName returns the name under which the results of the variable shifted by the offset
+// can be provided, e.g. "A offset 1w". It returns the name of the variable if there is no offset.
+func (n *VarNode) OffsetName() string {
+	if n.Offset != 0 {
+		return n.Name + " offset " + n.OffsetText
+	}
+	return n.Name
+}
 
 // StringAST returns the string representation of abstract syntax tree of the VarNode so it fulfills the Node interface.
 func (n *VarNode) StringAST() string { return n.String() }
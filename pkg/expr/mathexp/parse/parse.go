@@ -12,6 +12,8 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
 )
 
 // Tree is the representation of a single parsed expression.
@@ -19,6 +21,8 @@ type Tree struct {
 	Text     string // text parsed to create the expression.
 	Root     Node   // top-level root of the tree, returns a number.
 	VarNames []string
+	// OffsetVars are the variables that are referenced with an offset, e.g. "$A offset 1w".
+	OffsetVars []*VarNode
 
 	funcs []map[string]Func
 
@@ -174,7 +178,8 @@ E -> F {( "**" ) F}
 F -> v | "(" O ")" | "!" O | "-" O
 v -> number | func(..) | queryVar
 Func -> name "(" param {"," param} ")"
-param -> number | "string" | queryVar
+param -> number | "string" | duration | queryVar
+queryVar -> var ["offset" duration]
 */
 
 // expr:
@@ -302,7 +307,22 @@ func (t *Tree) Var() (v *VarNode) {
 	varNoPrefix := strings.TrimPrefix(token.val, "$")
 	varNoBraces := strings.TrimSuffix(strings.TrimPrefix(varNoPrefix, "{"), "}")
 	t.VarNames = append(t.VarNames, varNoBraces)
-	return newVar(token.pos, varNoBraces, token.val)
+	v = newVar(token.pos, varNoBraces, token.val)
+	if next := t.peek(); next.typ == itemFunc && next.val == "offset" {
+		t.next()
+		d := t.expect(itemDuration, "offset")
+		offset, err := gtime.ParseDuration(d.val)
+		if err != nil {
+			t.errorf("invalid offset %s: %s", d.val, err)
+		}
+		if offset <= 0 {
+			t.errorf("offset must be positive, got %s", d.val)
+		}
+		v.Offset = offset
+		v.OffsetText = d.val
+		t.OffsetVars = append(t.OffsetVars, v)
+	}
+	return v
 }
 
 // Func parses a FuncNode.
@@ -329,6 +349,13 @@ func (t *Tree) Func() (f *FuncNode) {
 				t.errorf("Unquoting error: %s", err)
 			}
 			f.append(newString(token.pos, token.val, s))
+		case itemDuration:
+			// a bare duration such as 5m is passed to the function like the string "5m"
+			f.append(newString(token.pos, token.val, token.val))
+		case itemComma:
+			if len(f.Args) == 0 {
+				t.unexpected(token, "func")
+			}
 		case itemRightParen:
 			return
 		}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 )
@@ -22,11 +24,47 @@ const (
 	ReducerCount  ReducerID = "count"
 	ReducerLast   ReducerID = "last"
 	ReducerMedian ReducerID = "median"
+	ReducerFirst  ReducerID = "first"
+	ReducerRange  ReducerID = "range"
+	ReducerDiff   ReducerID = "diff"
+	// Population standard deviation
+	ReducerStdDev ReducerID = "stddev"
+	// Population variance
+	ReducerVariance     ReducerID = "variance"
+	ReducerCountNonNull ReducerID = "count_non_null"
+	// Requires the percentile setting, also available as p<percentile> (e.g. p95)
+	ReducerPercentile ReducerID = "percentile"
 )
 
-// GetSupportedReduceFuncs returns collection of supported function names
+// percentileReducerPrefix is the prefix of parameterised percentile reducers, e.g. p95 or p99.9
+const percentileReducerPrefix = "p"
+
+// GetSupportedReduceFuncs returns collection of supported function names.
+// Parameterised reducers such as percentiles are not included, see PercentileReducer.
 func GetSupportedReduceFuncs() []ReducerID {
-	return []ReducerID{ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerCount, ReducerLast, ReducerMedian}
+	return []ReducerID{ReducerSum, ReducerMean, ReducerMin, ReducerMax, ReducerCount, ReducerLast, ReducerMedian,
+		ReducerFirst, ReducerRange, ReducerDiff, ReducerStdDev, ReducerVariance, ReducerCountNonNull}
+}
+
+// PercentileReducer returns the ReducerID of the percentile reducer for the given percentile, e.g. p95.
+func PercentileReducer(percentile float64) (ReducerID, error) {
+	if math.IsNaN(percentile) || percentile < 0 || percentile > 100 {
+		return "", fmt.Errorf("percentile must be between 0 and 100, got %v", percentile)
+	}
+	return ReducerID(percentileReducerPrefix + strconv.FormatFloat(percentile, 'f', -1, 64)), nil
+}
+
+// parsePercentileReducer returns the percentile of a parameterised percentile reducer such as p95.
+func parsePercentileReducer(rFunc ReducerID) (float64, bool) {
+	raw, ok := strings.CutPrefix(string(rFunc), percentileReducerPrefix)
+	if !ok {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(p) || p < 0 || p > 100 {
+		return 0, false
+	}
+	return p, true
 }
 
 func Sum(fv *Float64Field) *float64 {
@@ -126,6 +164,95 @@ func Median(fv *Float64Field) *float64 {
 	}
 }
 
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+// Range returns the difference between the largest and smallest value.
+func Range(fv *Float64Field) *float64 {
+	f := *Max(fv) - *Min(fv)
+	return &f
+}
+
+// Diff returns the difference between the last and the first value.
+func Diff(fv *Float64Field) *float64 {
+	f := math.NaN()
+	if fv.Len() == 0 {
+		return &f
+	}
+	first, last := First(fv), Last(fv)
+	if first != nil && last != nil {
+		f = *last - *first
+	}
+	return &f
+}
+
+func CountNonNull(fv *Float64Field) *float64 {
+	var f float64
+	for i := 0; i < fv.Len(); i++ {
+		if fv.GetValue(i) != nil {
+			f++
+		}
+	}
+	return &f
+}
+
+func Variance(fv *Float64Field) *float64 {
+	if fv.Len() == 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	mean := *Avg(fv)
+	if math.IsNaN(mean) {
+		return &mean
+	}
+	var sum float64
+	for i := 0; i < fv.Len(); i++ {
+		d := *fv.GetValue(i) - mean
+		sum += d * d
+	}
+	f := sum / float64(fv.Len())
+	return &f
+}
+
+func StdDev(fv *Float64Field) *float64 {
+	f := math.Sqrt(*Variance(fv))
+	return &f
+}
+
+// Percentile returns a ReducerFunc that calculates the given percentile (0-100)
+// by linear interpolation between the closest ranks.
+func Percentile(percentile float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		values := make([]float64, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			v := fv.GetValue(i)
+			if v == nil || math.IsNaN(*v) {
+				nan := math.NaN()
+				return &nan
+			}
+			values = append(values, *v)
+		}
+
+		if len(values) == 0 {
+			nan := math.NaN()
+			return &nan
+		}
+
+		sort.Float64s(values)
+		rank := percentile / 100 * float64(len(values)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		f := values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+		return &f
+	}
+}
+
 func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 	switch rFunc {
 	case ReducerSum:
@@ -142,7 +269,24 @@ func GetReduceFunc(rFunc ReducerID) (ReducerFunc, error) {
 		return Last, nil
 	case ReducerMedian:
 		return Median, nil
+	case ReducerFirst:
+		return First, nil
+	case ReducerRange:
+		return Range, nil
+	case ReducerDiff:
+		return Diff, nil
+	case ReducerStdDev:
+		return StdDev, nil
+	case ReducerVariance:
+		return Variance, nil
+	case ReducerCountNonNull:
+		return CountNonNull, nil
+	case ReducerPercentile:
+		return nil, fmt.Errorf("reduction %v requires a percentile", rFunc)
 	default:
+		if p, ok := parsePercentileReducer(rFunc); ok {
+			return Percentile(p), nil
+		}
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
 }
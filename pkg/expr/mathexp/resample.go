@@ -2,6 +2,7 @@ package mathexp
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -20,20 +21,37 @@ const (
 
 	// Do not fill values (nill)
 	UpsamplerFillNA Upsampler = "fillna"
+
+	// Interpolate linearly between the last seen and the next value
+	UpsamplerLinear Upsampler = "linear"
 )
 
+// Weight each value by how long it was the current value within the window.
+// Only valid as a downsampler.
+const DownsamplerTimeWeightedMean ReducerID = "time_weighted_mean"
+
 // Resample turns the Series into a Number based on the given reduction function
 func (s Series) Resample(refID string, interval time.Duration, downsampler ReducerID, upsampler Upsampler, from, to time.Time) (Series, error) {
 	newSeriesLength := int(float64(to.Sub(from).Nanoseconds()) / float64(interval.Nanoseconds()))
 	if newSeriesLength <= 0 {
 		return s, fmt.Errorf("the series cannot be sampled further; the time range is shorter than the interval")
 	}
+	var reduceFunc ReducerFunc
+	if downsampler != DownsamplerTimeWeightedMean {
+		var err error
+		if reduceFunc, err = GetReduceFunc(downsampler); err != nil {
+			return s, fmt.Errorf("downsampling %v not implemented", downsampler)
+		}
+	}
 	resampled := NewSeries(refID, s.GetLabels(), newSeriesLength+1)
 	bookmark := 0
 	var lastSeen *float64
+	var lastSeenTime time.Time
 	idx := 0
 	t := from
 	for !t.After(to) && idx <= newSeriesLength {
+		carried := lastSeen
+		points := make([]windowPoint, 0)
 		vals := make([]*float64, 0)
 		sIdx := bookmark
 		for {
@@ -47,7 +65,9 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 			bookmark++
 			sIdx++
 			lastSeen = v
+			lastSeenTime = st
 			vals = append(vals, v)
+			points = append(points, windowPoint{t: st, f: v})
 		}
 		var value *float64
 		if len(vals) == 0 { // upsampling
@@ -66,30 +86,22 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 				}
 			case UpsamplerFillNA:
 				value = nil
+			case UpsamplerLinear:
+				if lastSeen != nil && sIdx < s.Len() {
+					nextTime, next := s.GetPoint(sIdx)
+					value = interpolate(lastSeenTime, *lastSeen, nextTime, next, t)
+				}
 			default:
 				return s, fmt.Errorf("upsampling %v not implemented", upsampler)
 			}
-		} else if len(vals) == 1 {
-			value = vals[0]
+		} else if downsampler == DownsamplerTimeWeightedMean {
+			value = timeWeightedMean(carried, points, t.Add(-interval), t)
+		} else if len(vals) == 1 && vals[0] == nil {
+			value = nil
 		} else { // downsampling
 			fVec := data.NewField("", s.GetLabels(), vals)
 			ff := Float64Field(*fVec)
-			var tmp *float64
-			switch downsampler {
-			case ReducerSum:
-				tmp = Sum(&ff)
-			case ReducerMean:
-				tmp = Avg(&ff)
-			case ReducerMin:
-				tmp = Min(&ff)
-			case ReducerMax:
-				tmp = Max(&ff)
-			case ReducerLast:
-				tmp = Last(&ff)
-			default:
-				return s, fmt.Errorf("downsampling %v not implemented", downsampler)
-			}
-			value = tmp
+			value = reduceFunc(&ff)
 		}
 		resampled.SetPoint(idx, t, value)
 		t = t.Add(interval)
@@ -97,3 +109,50 @@ func (s Series) Resample(refID string, interval time.Duration, downsampler Reduc
 	}
 	return resampled, nil
 }
+
+// interpolate returns the value at t on the line between the points (prevTime, prev) and (nextTime, next).
+// If next is null, the result is null.
+func interpolate(prevTime time.Time, prev float64, nextTime time.Time, next *float64, t time.Time) *float64 {
+	if next == nil {
+		return nil
+	}
+	span := nextTime.Sub(prevTime).Seconds()
+	if span == 0 {
+		return next
+	}
+	f := prev + (*next-prev)*t.Sub(prevTime).Seconds()/span
+	return &f
+}
+
+// timeWeightedMean returns the mean of the values in the window (start, end] where each value is
+// weighted by the time until the next point. The carried value is the last value before the window
+// and holds from the start of the window until the first point. Null and NaN values are not counted.
+func timeWeightedMean(carried *float64, points []windowPoint, start, end time.Time) *float64 {
+	var weighted, total float64
+	add := func(f *float64, from, until time.Time) {
+		if f == nil || math.IsNaN(*f) {
+			return
+		}
+		if from.Before(start) {
+			from = start
+		}
+		d := until.Sub(from).Seconds()
+		if d <= 0 {
+			return
+		}
+		weighted += *f * d
+		total += d
+	}
+	cur, curStart := carried, start
+	for _, p := range points {
+		add(cur, curStart, p.t)
+		cur, curStart = p.f, p.t
+	}
+	add(cur, curStart, end)
+	if total == 0 {
+		// all the points of the window are at its end
+		return cur
+	}
+	f := weighted / total
+	return &f
+}
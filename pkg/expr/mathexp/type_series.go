@@ -246,6 +246,16 @@ func (s Series) GetValue(pointIdx int) *float64 {
 	return s.Frame.Fields[seriesTypeValIdx].At(pointIdx).(*float64)
 }
 
+// Shift returns a copy of the series with the time of each point moved by the duration.
+func (s Series) Shift(d time.Duration) Series {
+	frame := s.Frame.EmptyCopy()
+	for i := 0; i < s.Len(); i++ {
+		t, v := s.GetPoint(i)
+		frame.AppendRow(t.Add(d), v)
+	}
+	return Series{Frame: frame}
+}
+
 // SortByTime sorts the series by the time from oldest to newest.
 // If desc is true, it will sort from newest to oldest.
 // If any time values are nil, it will panic.
@@ -0,0 +1,179 @@
+package mathexp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+)
+
+// windowPoint is a single point of a Series used by the window functions.
+type windowPoint struct {
+	t time.Time
+	f *float64
+}
+
+// sortedPoints returns the points of the series ordered from oldest to newest.
+// The series itself is not modified.
+func sortedPoints(s Series) []windowPoint {
+	points := make([]windowPoint, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, f := s.GetPoint(i)
+		points[i] = windowPoint{t: t, f: f}
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].t.Before(points[j].t)
+	})
+	return points
+}
+
+// checkWindowDuration is a parse time check that the second argument of a
+// window function is a valid positive duration such as 5m or "5m".
+func checkWindowDuration(t *parse.Tree, f *parse.FuncNode) error {
+	sn, ok := f.Args[1].(*parse.StringNode)
+	if !ok {
+		return fmt.Errorf("parse: expected a duration for the window argument of %s", f.Name)
+	}
+	if _, err := parseWindow(sn.Text); err != nil {
+		return fmt.Errorf("parse: %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+func parseWindow(s string) (time.Duration, error) {
+	d, err := gtime.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid window %q: %w", s, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid window %q: must be greater than zero", s)
+	}
+	return d, nil
+}
+
+// perSeries passes each Series in varSet to seriesF and collects the results.
+// NoData values are passed through. Any other value type is an error since window
+// functions need a time index to operate on.
+func perSeries(e *State, name string, varSet Results, seriesF func(s Series) Series) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		switch v := res.(type) {
+		case Series:
+			newRes.Values = append(newRes.Values, seriesF(v))
+		case NoData:
+			newRes.Values = append(newRes.Values, NewNoData())
+		default:
+			return newRes, fmt.Errorf("%s: expected a series but got %s", name, res.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// delta returns the difference between each point and the previous point of each Series.
+// The first point of a series has no previous point and is omitted from the result.
+// If either point is null the result for that point is null.
+func delta(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "delta", varSet, func(s Series) Series {
+		return e.consecutive(s, func(prev, cur windowPoint) *float64 {
+			nF := *cur.f - *prev.f
+			return &nF
+		})
+	})
+}
+
+// rate returns the per-second rate of increase between each point and the previous point of each Series.
+// Each Series is treated as a counter: as in Prometheus, a value lower than the previous value is a
+// counter reset, and the value itself is the increase since the reset.
+// The first point of a series has no previous point and is omitted from the result.
+// If either point is null, or the point has the same time as the previous point, the result for that point is null.
+func rate(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "rate", varSet, func(s Series) Series {
+		return e.consecutive(s, func(prev, cur windowPoint) *float64 {
+			seconds := cur.t.Sub(prev.t).Seconds()
+			if seconds <= 0 {
+				return nil
+			}
+			increase := *cur.f - *prev.f
+			if *cur.f < *prev.f {
+				increase = *cur.f
+			}
+			nF := increase / seconds
+			return &nF
+		})
+	})
+}
+
+// consecutive builds a new Series by calling pairF with each point and the point before it.
+// If pairF returns nil the result for that point is null.
+func (e *State) consecutive(s Series, pairF func(prev, cur windowPoint) *float64) Series {
+	points := sortedPoints(s)
+	newSeries := NewSeries(e.RefID, s.GetLabels(), 0)
+	for i := 1; i < len(points); i++ {
+		prev, cur := points[i-1], points[i]
+		if prev.f == nil || cur.f == nil {
+			newSeries.AppendPoint(cur.t, nil)
+			continue
+		}
+		newSeries.AppendPoint(cur.t, pairF(prev, cur))
+	}
+	return newSeries
+}
+
+// movingAvg returns, for each point of each Series, the mean of the values that fall
+// within the window ending at (and including) the point's time.
+// Null and NaN values are ignored. If the window has no values then the result is null.
+func movingAvg(e *State, varSet Results, rawWindow string) (Results, error) {
+	window, err := parseWindow(rawWindow)
+	if err != nil {
+		return Results{}, err
+	}
+	return perSeries(e, "moving_avg", varSet, func(s Series) Series {
+		points := sortedPoints(s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), len(points))
+		start := 0
+		sum, count := 0.0, 0
+		for i, p := range points {
+			if p.f != nil && !math.IsNaN(*p.f) {
+				sum += *p.f
+				count++
+			}
+			for ; !points[start].t.After(p.t.Add(-window)); start++ {
+				if f := points[start].f; f != nil && !math.IsNaN(*f) {
+					sum -= *f
+					count--
+				}
+			}
+			if count == 0 {
+				newSeries.SetPoint(i, p.t, nil)
+				continue
+			}
+			avg := sum / float64(count)
+			newSeries.SetPoint(i, p.t, &avg)
+		}
+		return newSeries
+	})
+}
+
+// cumsum returns the running total of each Series.
+// Null values are returned as null and do not contribute to the total.
+func cumsum(e *State, varSet Results) (Results, error) {
+	return perSeries(e, "cumsum", varSet, func(s Series) Series {
+		points := sortedPoints(s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), len(points))
+		total := 0.0
+		for i, p := range points {
+			if p.f == nil {
+				newSeries.SetPoint(i, p.t, nil)
+				continue
+			}
+			total += *p.f
+			nF := total
+			newSeries.SetPoint(i, p.t, &nF)
+		}
+		return newSeries
+	})
+}
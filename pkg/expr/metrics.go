@@ -12,6 +12,8 @@ const (
 type metrics struct {
 	dsRequests *prometheus.CounterVec
 
+	sqlCommandsRejected *prometheus.CounterVec
+
 	// older metric
 	expressionsQuerySummary *prometheus.SummaryVec
 }
@@ -25,6 +27,13 @@ func newMetrics(reg prometheus.Registerer) *metrics {
 			Help:      "Number of datasource queries made via server side expression requests",
 		}, []string{"error", "dataplane", "datasource_type"}),
 
+		sqlCommandsRejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: metricsSubSystem,
+			Name:      "sql_command_rejected_total",
+			Help:      "Number of SQL expressions that were stopped because they exceeded a configured limit",
+		}, []string{"reason"}),
+
 		// older (No Namespace or Subsystem)
 		expressionsQuerySummary: prometheus.NewSummaryVec(
 			prometheus.SummaryOpts{
@@ -39,6 +48,7 @@ func newMetrics(reg prometheus.Registerer) *metrics {
 	if reg != nil {
 		reg.MustRegister(
 			m.dsRequests,
+			m.sqlCommandsRejected,
 			m.expressionsQuerySummary,
 		)
 	}
@@ -29,6 +29,9 @@ const (
 
 	// SQL query via DuckDB
 	QueryTypeSQL QueryType = "sql"
+
+	// Project query results into the future
+	QueryTypeForecast QueryType = "forecast"
 )
 
 type MathQuery struct {
@@ -60,6 +63,9 @@ type ResampleQuery struct {
 
 	// The upsample function
 	Upsampler mathexp.Upsampler `json:"upsampler"`
+
+	// Align the resampled points to multiples of the window rather than to the start of the time range
+	AlignToWallClock bool `json:"alignToWallClock,omitempty"`
 }
 
 type ThresholdQuery struct {
@@ -79,6 +85,33 @@ type SQLExpression struct {
 	Expression string `json:"expression" jsonschema:"minLength=1,example=SELECT * FROM A LIMIT 1"`
 }
 
+// QueryType = forecast
+type ForecastQuery struct {
+	// Reference to the series to forecast
+	Expression string `json:"expression" jsonschema:"minLength=1,example=$A"`
+
+	// The forecast model, defaults to linear
+	Method mathexp.ForecastMethod `json:"method,omitempty"`
+
+	// How far past the last point to project
+	Horizon string `json:"horizon" jsonschema:"minLength=1,example=4h,example=30m"`
+
+	// The length of the seasonal cycle, only used by holt_winters
+	Season string `json:"season,omitempty" jsonschema:"example=1d"`
+
+	// The confidence level of the bounds between 0 and 1, defaults to 0.95
+	Confidence *float64 `json:"confidence,omitempty"`
+
+	// Level smoothing factor of holt_winters, defaults to 0.5
+	Alpha *float64 `json:"alpha,omitempty"`
+
+	// Trend smoothing factor of holt_winters, defaults to 0.1
+	Beta *float64 `json:"beta,omitempty"`
+
+	// Seasonal smoothing factor of holt_winters, defaults to 0.1
+	Gamma *float64 `json:"gamma,omitempty"`
+}
+
 //-------------------------------
 // Non-query commands
 //-------------------------------
@@ -89,6 +122,9 @@ type ReduceSettings struct {
 
 	// Only valid when mode is replace
 	ReplaceWithValue *float64 `json:"replaceWithValue,omitempty"`
+
+	// The percentile (0-100), only valid when the reducer is percentile
+	Percentile *float64 `json:"percentile,omitempty"`
 }
 
 // Non-Number behavior mode
@@ -72,8 +72,18 @@ func (h *ExpressionQueryReader) ReadQuery(
 			referenceVar, err = getReferenceVar(q.Expression, common.RefID)
 			eq.Properties = q
 		}
+		reducer := q.Reducer
+		if err == nil && reducer == mathexp.ReducerPercentile {
+			if q.Settings == nil || q.Settings.Percentile == nil {
+				err = fmt.Errorf("setting percentile must be specified when reducer is '%s'", reducer)
+			} else {
+				reducer, err = mathexp.PercentileReducer(*q.Settings.Percentile)
+			}
+		}
 		if err == nil && q.Settings != nil {
 			switch q.Settings.Mode {
+			case "":
+				// strict mode, the settings only hold reducer parameters
 			case ReduceModeDrop:
 				mapper = mathexp.DropNonNumber{}
 			case ReduceModeReplace:
@@ -88,7 +98,7 @@ func (h *ExpressionQueryReader) ReadQuery(
 		if err == nil {
 			eq.Properties = q
 			eq.Command, err = NewReduceCommand(common.RefID,
-				q.Reducer, referenceVar, mapper)
+				reducer, referenceVar, mapper)
 		}
 
 	case QueryTypeResample:
@@ -103,7 +113,8 @@ func (h *ExpressionQueryReader) ReadQuery(
 		if err == nil {
 			tr := gtime.NewTimeRange(common.TimeRange.From, common.TimeRange.To)
 			eq.Properties = q
-			eq.Command, err = NewResampleCommand(common.RefID,
+			var cmd *ResampleCommand
+			cmd, err = NewResampleCommand(common.RefID,
 				q.Window,
 				referenceVar,
 				q.Downsampler,
@@ -113,6 +124,10 @@ func (h *ExpressionQueryReader) ReadQuery(
 					To:   tr.GetToAsTimeUTC(),
 				},
 			)
+			if err == nil {
+				cmd.AlignToWallClock = q.AlignToWallClock
+				eq.Command = cmd
+			}
 		}
 
 	case QueryTypeClassic:
@@ -131,6 +146,14 @@ func (h *ExpressionQueryReader) ReadQuery(
 			eq.Command, err = NewSQLCommand(common.RefID, q.Expression)
 		}
 
+	case QueryTypeForecast:
+		q := &ForecastQuery{}
+		err = iter.ReadVal(q)
+		if err == nil {
+			eq.Properties = q
+			eq.Command, err = NewForecastCommand(common.RefID, *q)
+		}
+
 	case QueryTypeThreshold:
 		q := &ThresholdQuery{}
 		err = iter.ReadVal(q)
@@ -153,10 +176,10 @@ func (h *ExpressionQueryReader) ReadQuery(
 
 			if firstCondition.UnloadEvaluator != nil && h.features.IsEnabledGlobally(featuremgmt.FlagRecoveryThreshold) {
 				unloading, err := NewThresholdCommand(common.RefID, referenceVar, firstCondition.UnloadEvaluator.Type, firstCondition.UnloadEvaluator.Params)
-				unloading.Invert = true
 				if err != nil {
 					return eq, fmt.Errorf("invalid unloadCondition: %w", err)
 				}
+				unloading.Invert = true
 				var d Fingerprints
 				if firstCondition.LoadedDimensions != nil {
 					d, err = FingerprintsFromFrame(firstCondition.LoadedDimensions)
@@ -51,6 +51,9 @@ func NodeTypeFromDatasourceUID(uid string) NodeType {
 	if uid == MLDatasourceUID {
 		return TypeMLNode
 	}
+	if uid == InputDatasourceUID {
+		return TypeInputNode
+	}
 	return TypeDatasourceNode
 }
 
@@ -113,6 +116,9 @@ func (s *Service) ExecutePipeline(ctx context.Context, now time.Time, pipeline D
 		return nil, err
 	}
 	for refID, val := range vars {
+		if isOffsetNode(pipeline, refID) {
+			continue
+		}
 		res.Responses[refID] = backend.DataResponse{
 			Frames: val.Values.AsDataFrames(refID),
 			Error:  val.Error,
@@ -142,6 +148,15 @@ func DataSourceModelFromNodeType(kind NodeType) (*datasources.DataSource, error)
 			JsonData:       simplejson.New(),
 			SecureJsonData: make(map[string][]byte),
 		}, nil
+	case TypeInputNode:
+		return &datasources.DataSource{
+			ID:             inputDatasourceID,
+			UID:            InputDatasourceUID,
+			Name:           InputDatasourceUID,
+			Type:           inputDatasourceType,
+			JsonData:       simplejson.New(),
+			SecureJsonData: make(map[string][]byte),
+		}, nil
 	case TypeDatasourceNode:
 		return nil, errors.New("cannot create expression data source for data source kind")
 	default:
@@ -120,6 +120,85 @@ func TestService(t *testing.T) {
 	}
 }
 
+func TestServiceOffset(t *testing.T) {
+	// returns a point at the start of the time range with its unix time as value
+	endpoint := &rangeEndpoint{}
+
+	features := featuremgmt.WithFeatures()
+	s := Service{
+		cfg:         setting.NewCfg(),
+		dataService: endpoint,
+		pCtxProvider: plugincontext.ProvideService(setting.NewCfg(), nil, &pluginstore.FakePluginStore{
+			PluginList: []pluginstore.Plugin{
+				{JSONData: plugins.JSONData{ID: "test"}},
+			},
+		}, &datafakes.FakeCacheService{}, &datafakes.FakeDataSourceService{}, nil, pluginconfig.NewFakePluginRequestConfigProvider()),
+		features: features,
+		tracer:   tracing.InitializeTracerForTest(),
+		metrics:  newMetrics(nil),
+		converter: &ResultConverter{
+			Features: features,
+			Tracer:   tracing.InitializeTracerForTest(),
+		},
+	}
+
+	queries := []Query{
+		{
+			RefID:      "A",
+			DataSource: &datasources.DataSource{OrgID: 1, UID: "test", Type: "test"},
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "test" }, "intervalMs": 1000, "maxDataPoints": 1000 }`),
+			TimeRange:  RelativeTimeRange{From: -time.Hour, To: 0},
+		},
+		{
+			RefID:      "B",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "type": "math", "expression": "$A - $A offset 1d" }`),
+		},
+		{
+			RefID:      "C",
+			DataSource: dataSourceModel(),
+			JSON:       json.RawMessage(`{ "type": "math", "expression": "$B offset 1d" }`),
+		},
+	}
+
+	pl, err := s.BuildPipeline(&Request{Queries: queries, User: &user.SignedInUser{}})
+	require.NoError(t, err)
+	require.Len(t, pl, 4)
+
+	now := time.Unix(1000000, 0)
+	res, err := s.ExecutePipeline(context.Background(), now, pl)
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, []string{"A", "A offset 1d"}, endpoint.refIDs)
+	require.NotContains(t, res.Responses, "A offset 1d")
+	require.Len(t, res.Responses, 3)
+
+	// the shifted query ran a day earlier and its points were moved forward by a day
+	b := res.Responses["B"].Frames[0]
+	require.Equal(t, now.Add(-time.Hour), b.Fields[0].At(0))
+	require.Equal(t, fp((24 * time.Hour).Seconds()), b.Fields[1].At(0))
+
+	// the offset of an expression shifts its series in memory
+	c := res.Responses["C"].Frames[0]
+	require.Equal(t, now.Add(23*time.Hour), c.Fields[0].At(0))
+	require.Equal(t, fp((24 * time.Hour).Seconds()), c.Fields[1].At(0))
+}
+
+type rangeEndpoint struct {
+	refIDs []string
+}
+
+func (e *rangeEndpoint) QueryData(_ context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	resp := backend.NewQueryDataResponse()
+	for _, q := range req.Queries {
+		e.refIDs = append(e.refIDs, q.RefID)
+		resp.Responses[q.RefID] = backend.DataResponse{Frames: data.Frames{data.NewFrame("",
+			data.NewField("time", nil, []time.Time{q.TimeRange.From}),
+			data.NewField("value", nil, []*float64{fp(float64(q.TimeRange.From.Unix()))}))}}
+	}
+	return resp, nil
+}
+
 func TestDSQueryError(t *testing.T) {
 	me := &mockEndpoint{
 		Responses: map[string]backend.DataResponse{
@@ -0,0 +1,186 @@
+package sql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana-plugin-sdk-go/data/framestruct"
+	duckdata "github.com/scottlepp/go-duck/duck/data"
+)
+
+// outOfMemoryMessage is the error type DuckDB prints when a query exceeds the memory_limit setting.
+// The command line interface exits with the same status for every error, so the message is the
+// only way to tell an out of memory error apart from other errors.
+const outOfMemoryMessage = "Out of Memory Error"
+
+// ErrOutOfMemory is returned when a query exceeds the memory limit of the database.
+var ErrOutOfMemory = errors.New("out of memory")
+
+// ErrDuckDBNotFound is returned when the DuckDB command line interface cannot be found.
+var ErrDuckDBNotFound = errors.New("the DuckDB command line interface was not found, install it or set sql_expression_duckdb_path in the [expressions] section")
+
+// DefaultDuckDBPath is the name of the DuckDB command line interface, which is looked up in the PATH.
+const DefaultDuckDBPath = "duckdb"
+
+// DB runs SQL queries over data frames with the DuckDB command line interface.
+// Every query runs in a new in-memory database.
+type DB struct {
+	exe           string
+	memoryLimitMB int64
+}
+
+// NewInMemoryDB returns a DB that runs the DuckDB command line interface at duckDBPath, and limits
+// the memory of each query to memoryLimitMB megabytes. A duckDBPath without a slash is looked up in
+// the PATH, and an empty one defaults to DefaultDuckDBPath. A memoryLimitMB of 0 means no limit.
+func NewInMemoryDB(duckDBPath string, memoryLimitMB int64) (*DB, error) {
+	if duckDBPath == "" {
+		duckDBPath = DefaultDuckDBPath
+	}
+	exe, err := exec.LookPath(duckDBPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrDuckDBNotFound, err)
+	}
+	return &DB{exe: exe, memoryLimitMB: memoryLimitMB}, nil
+}
+
+// QueryFrames creates a view for the frames of each RefID, runs the query and returns the result
+// as a frame called name. The database process is killed when ctx is done.
+func (db *DB) QueryFrames(ctx context.Context, name, query string, frames []*data.Frame) (*data.Frame, error) {
+	dirs, err := duckdata.ToParquet(frames, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write input tables: %w", err)
+	}
+	defer func() {
+		for _, dir := range dirs {
+			if err := os.RemoveAll(dir); err != nil {
+				logger.Warn("Failed to remove input table files", "dir", dir, "error", err)
+			}
+		}
+	}()
+
+	var commands bytes.Buffer
+	commands.WriteString(".mode json\n")
+	if db.memoryLimitMB > 0 {
+		fmt.Fprintf(&commands, "SET memory_limit='%dMB';\n", db.memoryLimitMB)
+	}
+	refIDs := make([]string, 0, len(dirs))
+	for refID := range dirs {
+		refIDs = append(refIDs, refID)
+	}
+	sort.Strings(refIDs)
+	for _, refID := range refIDs {
+		fmt.Fprintf(&commands, "CREATE VIEW %s AS (SELECT * FROM '%s/*.parquet');\n", refID, dirs[refID])
+	}
+	commands.WriteString(query)
+	commands.WriteString("\n")
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, db.exe)
+	cmd.Stdin = &commands
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// do not wait for output that outlives the killed process
+	cmd.WaitDelay = time.Second
+	runErr := cmd.Run()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err := queryError(runErr, stderr.String()); err != nil {
+		return nil, err
+	}
+
+	return resultToFrame(name, stdout.Bytes(), frames)
+}
+
+// queryError returns the error of a run of the command line interface, which reports query
+// errors on stderr.
+func queryError(runErr error, stderr string) error {
+	stderr = strings.TrimSpace(stderr)
+	if runErr == nil && stderr == "" {
+		return nil
+	}
+	if strings.Contains(stderr, outOfMemoryMessage) {
+		return fmt.Errorf("%w: %s", ErrOutOfMemory, stderr)
+	}
+	if stderr == "" {
+		return runErr
+	}
+	return errors.New(stderr)
+}
+
+// resultToFrame converts the JSON rows printed by the command line interface to a frame, with
+// the fields in the order of the columns of the query.
+func resultToFrame(name string, result []byte, frames []*data.Frame) (*data.Frame, error) {
+	frame := data.NewFrame(name)
+	if len(bytes.TrimSpace(result)) == 0 {
+		return frame, nil
+	}
+
+	rows := []map[string]any{}
+	if err := json.Unmarshal(result, &rows); err != nil {
+		return nil, fmt.Errorf("failed to read query result: %w", err)
+	}
+	if len(rows) == 0 {
+		return frame, nil
+	}
+	columns, err := columnOrder(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query result: %w", err)
+	}
+
+	resultFrame, err := framestruct.ToDataFrame(name, rows, duckdata.Converters(frames)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert query result: %w", err)
+	}
+	fields := make(map[string]*data.Field, len(resultFrame.Fields))
+	for _, f := range resultFrame.Fields {
+		fields[f.Name] = f
+	}
+	for _, c := range columns {
+		if f, ok := fields[c]; ok {
+			frame.Fields = append(frame.Fields, f)
+		}
+	}
+	return frame, nil
+}
+
+// columnOrder returns the keys of the first object of a JSON array, in the order they appear.
+func columnOrder(result []byte) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(result))
+	for _, want := range []json.Delim{'[', '{'} {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if t != want {
+			return nil, fmt.Errorf("expected %s but got %v", want, t)
+		}
+	}
+
+	columns := []string{}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := t.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a column name but got %v", t)
+		}
+		columns = append(columns, key)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+	}
+	return columns, nil
+}
@@ -0,0 +1,92 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDuckDB writes a shell script that stands in for the duckdb command line interface.
+func fakeDuckDB(t *testing.T, script string) *DB {
+	t.Helper()
+	exe := filepath.Join(t.TempDir(), "duckdb")
+	require.NoError(t, os.WriteFile(exe, []byte("#!/bin/sh\ncat > /dev/null\n"+script+"\n"), 0o700))
+	return &DB{exe: exe}
+}
+
+func TestDB_QueryFrames(t *testing.T) {
+	input := data.NewFrame("", data.NewField("value", nil, []float64{1, 2}))
+	input.RefID = "A"
+
+	t.Run("result keeps the column order", func(t *testing.T) {
+		db := fakeDuckDB(t, `echo '[{"value":2,"host":"a"},{"value":3,"host":"b"}]'`)
+		frame, err := db.QueryFrames(context.Background(), "B", "SELECT value + 1 AS value, host FROM A", []*data.Frame{input})
+		require.NoError(t, err)
+		require.Equal(t, "B", frame.Name)
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, "value", frame.Fields[0].Name)
+		require.Equal(t, "host", frame.Fields[1].Name)
+		require.Equal(t, 2, frame.Rows())
+	})
+
+	t.Run("empty result", func(t *testing.T) {
+		db := fakeDuckDB(t, "")
+		frame, err := db.QueryFrames(context.Background(), "B", "SELECT * FROM A WHERE false", []*data.Frame{input})
+		require.NoError(t, err)
+		require.Equal(t, 0, frame.Rows())
+	})
+
+	t.Run("errors are read from stderr", func(t *testing.T) {
+		db := fakeDuckDB(t, `echo 'Parser Error: syntax error at or near "SELEC"' >&2; exit 1`)
+		_, err := db.QueryFrames(context.Background(), "B", "SELEC 1", []*data.Frame{input})
+		require.ErrorContains(t, err, "Parser Error")
+		require.False(t, errors.Is(err, ErrOutOfMemory))
+	})
+
+	t.Run("process is killed when the context is done", func(t *testing.T) {
+		db := fakeDuckDB(t, "exec sleep 30")
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := db.QueryFrames(ctx, "B", "SELECT * FROM A", []*data.Frame{input})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(start), 10*time.Second)
+	})
+}
+
+func TestNewInMemoryDB(t *testing.T) {
+	exe := fakeDuckDB(t, "").exe
+
+	db, err := NewInMemoryDB(exe, 10)
+	require.NoError(t, err)
+	require.Equal(t, exe, db.exe)
+	require.Equal(t, int64(10), db.memoryLimitMB)
+
+	_, err = NewInMemoryDB(filepath.Join(t.TempDir(), "duckdb"), 0)
+	require.ErrorIs(t, err, ErrDuckDBNotFound)
+
+	t.Setenv("PATH", t.TempDir())
+	_, err = NewInMemoryDB("", 0)
+	require.ErrorIs(t, err, ErrDuckDBNotFound)
+}
+
+func TestQueryError(t *testing.T) {
+	require.NoError(t, queryError(nil, ""))
+	require.NoError(t, queryError(nil, "\n"))
+
+	err := queryError(errors.New("exit status 1"), "Error: Out of Memory Error: failed to allocate data of size 16.0 MiB (95.3 MiB/95.3 MiB used)\n")
+	require.ErrorIs(t, err, ErrOutOfMemory)
+	require.ErrorContains(t, err, "failed to allocate data")
+
+	err = queryError(errors.New("exit status 1"), "")
+	require.EqualError(t, err, "exit status 1")
+
+	err = queryError(nil, "Catalog Error: Table with name C does not exist!")
+	require.EqualError(t, err, "Catalog Error: Table with name C does not exist!")
+}
@@ -0,0 +1,97 @@
+package sql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Statement is a single statement of a (possibly multi-statement) SQL expression.
+type Statement struct {
+	// Name is the name of the output table of the statement. It is empty
+	// unless the statement is of the form `CREATE VIEW <name> AS <query>`.
+	Name string
+	// Query is the SELECT query of the statement.
+	Query string
+}
+
+var namedStatementRegexp = regexp.MustCompile(`(?is)^CREATE\s+(?:OR\s+REPLACE\s+)?(?:TEMP\s+|TEMPORARY\s+)?(?:VIEW|TABLE)\s+("[^"]+"|[A-Za-z_][A-Za-z0-9_]*)\s+AS\s+(.+)$`)
+
+// Statements splits the raw SQL into its statements. Statements are separated by
+// semicolons outside of quotes and comments. Statements that create a view or table,
+// such as `CREATE VIEW totals AS SELECT ...`, are named so their output can be
+// referenced by the following statements.
+func Statements(rawSQL string) ([]Statement, error) {
+	statements := []Statement{}
+	names := map[string]bool{}
+	for _, raw := range splitStatements(rawSQL) {
+		stmt := Statement{Query: raw}
+		if m := namedStatementRegexp.FindStringSubmatch(raw); m != nil {
+			stmt.Name = strings.Trim(m[1], `"`)
+			stmt.Query = strings.TrimSpace(m[2])
+			if names[stmt.Name] {
+				return nil, fmt.Errorf("table %s is created more than once", stmt.Name)
+			}
+			names[stmt.Name] = true
+		}
+		statements = append(statements, stmt)
+	}
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("no statements found in sql")
+	}
+	return statements, nil
+}
+
+// splitStatements splits the raw SQL on semicolons that are not quoted, strips
+// comments and drops empty statements.
+func splitStatements(rawSQL string) []string {
+	statements := []string{}
+	var b strings.Builder
+	var quote rune
+	lineComment, blockComment := false, false
+	flush := func() {
+		if s := strings.TrimSpace(b.String()); s != "" {
+			statements = append(statements, s)
+		}
+		b.Reset()
+	}
+	runes := []rune(rawSQL)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+		switch {
+		case lineComment:
+			if r == '\n' {
+				lineComment = false
+				b.WriteRune(r)
+			}
+		case blockComment:
+			if r == '*' && next == '/' {
+				blockComment = false
+				b.WriteRune(' ')
+				i++
+			}
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+			b.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote = r
+			b.WriteRune(r)
+		case r == '-' && next == '-':
+			lineComment = true
+		case r == '/' && next == '*':
+			blockComment = true
+		case r == ';':
+			flush()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	flush()
+	return statements
+}
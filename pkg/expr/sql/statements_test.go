@@ -0,0 +1,68 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		sql      string
+		expected []Statement
+		isError  bool
+	}{
+		{
+			name:     "single statement",
+			sql:      "SELECT * FROM A",
+			expected: []Statement{{Query: "SELECT * FROM A"}},
+		},
+		{
+			name:     "trailing semicolon and comment",
+			sql:      "SELECT * FROM A; -- the end",
+			expected: []Statement{{Query: "SELECT * FROM A"}},
+		},
+		{
+			name: "named statements",
+			sql: `CREATE VIEW joined AS SELECT * FROM A JOIN B ON A.host = B.host;
+			create or replace temp table "totals" as
+			  SELECT host, sum(value) AS value FROM joined GROUP BY host;
+			SELECT * FROM totals`,
+			expected: []Statement{
+				{Name: "joined", Query: "SELECT * FROM A JOIN B ON A.host = B.host"},
+				{Name: "totals", Query: "SELECT host, sum(value) AS value FROM joined GROUP BY host"},
+				{Query: "SELECT * FROM totals"},
+			},
+		},
+		{
+			name: "semicolons in quotes and comments",
+			sql:  `SELECT 'a;b' AS "c;d" FROM A /* ; */; SELECT 1`,
+			expected: []Statement{
+				{Query: `SELECT 'a;b' AS "c;d" FROM A`},
+				{Query: "SELECT 1"},
+			},
+		},
+		{
+			name:    "duplicate names",
+			sql:     "CREATE VIEW x AS SELECT 1; CREATE VIEW x AS SELECT 2",
+			isError: true,
+		},
+		{
+			name:    "only comments",
+			sql:     "-- nothing ;",
+			isError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statements, err := Statements(tt.sql)
+			if tt.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, statements)
+		})
+	}
+}
@@ -4,45 +4,116 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
-	"github.com/scottlepp/go-duck/duck"
 
 	"github.com/grafana/grafana/pkg/apimachinery/errutil"
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 	"github.com/grafana/grafana/pkg/expr/sql"
 	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 // SQLCommand is an expression to run SQL over results
 type SQLCommand struct {
 	query       string
+	statements  []sql.Statement
 	varsToQuery []string
 	refID       string
+
+	limits SQLLimits
+	// duckDBPath is the path of the DuckDB command line interface that runs the statements.
+	duckDBPath string
+	metrics    *metrics
+	// db runs the statements. It is nil unless replaced in tests.
+	db sqlDB
+}
+
+// sqlDB runs a SQL query over a set of frames.
+type sqlDB interface {
+	QueryFrames(ctx context.Context, name, query string, frames []*data.Frame) (*data.Frame, error)
+}
+
+// SQLLimits bounds the resources a SQL expression may use. A zero value means no limit.
+type SQLLimits struct {
+	// MaxInputRows is the maximum number of rows of each input table.
+	MaxInputRows int64
+	// MaxOutputRows is the maximum number of rows returned by each statement.
+	MaxOutputRows int64
+	// MemoryLimitMB is the memory limit of the database in megabytes.
+	MemoryLimitMB int64
+	// Timeout is the maximum duration of the whole SQL expression.
+	Timeout time.Duration
+}
+
+// sqlLimitsFromCfg returns the SQL expression limits configured in the [expressions] section.
+func sqlLimitsFromCfg(cfg *setting.Cfg) SQLLimits {
+	if cfg == nil {
+		return SQLLimits{}
+	}
+	return SQLLimits{
+		MaxInputRows:  cfg.SQLExpressionMaxInputRows,
+		MaxOutputRows: cfg.SQLExpressionMaxOutputRows,
+		MemoryLimitMB: cfg.SQLExpressionMemoryLimitMB,
+		Timeout:       cfg.SQLExpressionTimeout,
+	}
 }
 
 // NewSQLCommand creates a new SQLCommand.
+// The SQL may hold several statements separated by semicolons. Statements of the form
+// `CREATE VIEW <name> AS <query>` produce a named output frame that following
+// statements can query like any other input. The output of every statement is returned
+// as its own frame, named after its view or, if it has none, after its position.
 func NewSQLCommand(refID, rawSQL string) (*SQLCommand, error) {
 	if rawSQL == "" {
 		return nil, errutil.BadRequest("sql-missing-query",
 			errutil.WithPublicMessage("missing SQL query"))
 	}
-	tables, err := sql.TablesList(rawSQL)
+	statements, err := sql.Statements(rawSQL)
 	if err != nil {
 		logger.Warn("invalid sql query", "sql", rawSQL, "error", err)
 		return nil, errutil.BadRequest("sql-invalid-sql",
-			errutil.WithPublicMessage("error reading SQL command"),
+			errutil.WithPublicMessage(fmt.Sprintf("error reading SQL command: %s", err)),
 		)
 	}
+
+	tables := []string{}
+	named := map[string]bool{}
+	for _, stmt := range statements {
+		stmtTables, err := sql.TablesList(stmt.Query)
+		if err != nil {
+			logger.Warn("invalid sql query", "sql", stmt.Query, "error", err)
+			return nil, errutil.BadRequest("sql-invalid-sql",
+				errutil.WithPublicMessage("error reading SQL command"),
+			)
+		}
+		for _, t := range stmtTables {
+			if !named[t] && !slices.Contains(tables, t) {
+				tables = append(tables, t)
+			}
+		}
+		if stmt.Name != "" {
+			if slices.Contains(tables, stmt.Name) {
+				return nil, errutil.BadRequest("sql-invalid-sql",
+					errutil.WithPublicMessage(fmt.Sprintf("table %s is used before it is created", stmt.Name)),
+				)
+			}
+			named[stmt.Name] = true
+		}
+	}
+	sort.Strings(tables)
+
 	if len(tables) == 0 {
 		logger.Warn("no tables found in SQL query", "sql", rawSQL)
-	}
-	if tables != nil {
+	} else {
 		logger.Debug("REF tables", "tables", tables, "sql", rawSQL)
 	}
 	return &SQLCommand{
 		query:       rawSQL,
+		statements:  statements,
 		varsToQuery: tables,
 		refID:       refID,
 	}, nil
@@ -78,9 +149,15 @@ func (gr *SQLCommand) NeedsVars() []string {
 // Execute runs the command and returns the results or an error if the command
 // failed to execute.
 func (gr *SQLCommand) Execute(ctx context.Context, now time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
-	_, span := tracer.Start(ctx, "SSE.ExecuteSQL")
+	ctx, span := tracer.Start(ctx, "SSE.ExecuteSQL")
 	defer span.End()
 
+	if gr.limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, gr.limits.Timeout)
+		defer cancel()
+	}
+
 	allFrames := []*data.Frame{}
 	for _, ref := range gr.varsToQuery {
 		results, ok := vars[ref]
@@ -94,33 +171,171 @@ func (gr *SQLCommand) Execute(ctx context.Context, now time.Time, vars mathexp.V
 
 	rsp := mathexp.Results{}
 
-	duckDB := duck.NewInMemoryDB()
-	var frame = &data.Frame{}
-
-	logger.Debug("Executing query", "query", gr.query, "frames", len(allFrames))
-	err := duckDB.QueryFramesInto(gr.refID, gr.query, allFrames, frame)
-	if err != nil {
-		logger.Error("Failed to query frames", "error", err.Error())
+	if err := gr.checkInputRows(allFrames); err != nil {
 		rsp.Error = err
 		return rsp, nil
 	}
-	logger.Debug("Done Executing query", "query", gr.query, "rows", frame.Rows())
-
-	frame.RefID = gr.refID
 
-	if frame.Rows() == 0 {
-		rsp.Values = mathexp.Values{
-			mathexp.NoData{Frame: frame},
+	db := gr.db
+	if db == nil {
+		inMemoryDB, err := sql.NewInMemoryDB(gr.duckDBPath, gr.limits.MemoryLimitMB)
+		if err != nil {
+			logger.Error("Failed to start the SQL expression database", "error", err)
+			rsp.Error = err
+			return rsp, nil
 		}
+		db = inMemoryDB
 	}
 
-	rsp.Values = mathexp.Values{
-		mathexp.TableData{Frame: frame},
+	for i, stmt := range gr.statements {
+		name := gr.statementName(i)
+		logger.Debug("Executing query", "query", stmt.Query, "frames", len(allFrames))
+		frame, err := gr.queryFrames(ctx, db, name, stmt.Query, allFrames)
+		if err != nil {
+			logger.Error("Failed to query frames", "error", err.Error())
+			rsp.Error = err
+			return rsp, nil
+		}
+		logger.Debug("Done Executing query", "query", stmt.Query, "rows", frame.Rows())
+
+		if limit := gr.limits.MaxOutputRows; limit > 0 && int64(frame.Rows()) > limit {
+			rsp.Error = gr.limitError(sqlLimitReasonOutputRows, fmt.Sprintf("the output of the query has %d rows, the limit is %d", frame.Rows(), limit))
+			return rsp, nil
+		}
+
+		if stmt.Name != "" {
+			// make the output available to the following statements
+			input := *frame
+			input.RefID = stmt.Name
+			allFrames = append(allFrames, &input)
+		}
+		frame.Name = name
+		frame.RefID = gr.refID
+
+		values, err := sqlFrameToValues(frame)
+		if err != nil {
+			rsp.Error = err
+			return rsp, nil
+		}
+		rsp.Values = append(rsp.Values, values...)
 	}
 
 	return rsp, nil
 }
 
+// statementName returns the name of the output frame of the i-th statement: the name of its
+// view, or its 1-based position if it does not create a view. The output of a single unnamed
+// statement is named after the command.
+func (gr *SQLCommand) statementName(i int) string {
+	if name := gr.statements[i].Name; name != "" {
+		return name
+	}
+	if len(gr.statements) == 1 {
+		return gr.refID
+	}
+	return fmt.Sprintf("statement_%d", i+1)
+}
+
+// checkInputRows returns an error if any input table has more rows than allowed.
+func (gr *SQLCommand) checkInputRows(frames []*data.Frame) error {
+	limit := gr.limits.MaxInputRows
+	if limit <= 0 {
+		return nil
+	}
+	rows := map[string]int64{}
+	for _, f := range frames {
+		rows[f.RefID] += int64(f.Rows())
+		if rows[f.RefID] > limit {
+			return gr.limitError(sqlLimitReasonInputRows, fmt.Sprintf("input table %s has more than %d rows", f.RefID, limit))
+		}
+	}
+	return nil
+}
+
+// queryFrames runs the query and translates the errors of the database to the errors of the
+// SQL expression. The database stops when the context is canceled or the timeout is reached.
+func (gr *SQLCommand) queryFrames(ctx context.Context, db sqlDB, name, query string, frames []*data.Frame) (*data.Frame, error) {
+	frame, err := db.QueryFrames(ctx, name, query, frames)
+	if err == nil {
+		return frame, nil
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		gr.countRejected(sqlLimitReasonTimeout)
+		return nil, SQLTimeoutError.Build(errutil.TemplateData{
+			Public: map[string]any{"refId": gr.refID, "timeout": gr.limits.Timeout.String()},
+			Error:  err,
+		})
+	case errors.Is(err, context.Canceled):
+		// the caller went away, which is not a limit of the expression
+		return nil, err
+	case errors.Is(err, sql.ErrOutOfMemory):
+		return nil, gr.limitError(sqlLimitReasonMemory, fmt.Sprintf("the query exceeded the memory limit of %dMB", gr.limits.MemoryLimitMB))
+	}
+	return nil, err
+}
+
+func (gr *SQLCommand) limitError(reason, msg string) error {
+	gr.countRejected(reason)
+	return SQLLimitError.Build(errutil.TemplateData{
+		Public: map[string]any{"refId": gr.refID, "reason": reason, "error": msg},
+	})
+}
+
+func (gr *SQLCommand) countRejected(reason string) {
+	if gr.metrics != nil {
+		gr.metrics.sqlCommandsRejected.WithLabelValues(reason).Inc()
+	}
+}
+
+// sqlFrameToValues converts the output frame of a SQL statement to numeric values when it
+// has the shape of a time series or of a number set, so it can be the input of other
+// expressions. Any other frame is returned as table data.
+func sqlFrameToValues(frame *data.Frame) (mathexp.Values, error) {
+	if frame.Rows() == 0 {
+		return mathexp.Values{mathexp.NoData{Frame: frame}}, nil
+	}
+
+	switch frame.TimeSeriesSchema().Type {
+	case data.TimeSeriesTypeLong:
+		wide, err := data.LongToWide(frame, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert SQL output %q from long to wide format: %w", frame.Name, err)
+		}
+		wide.Name, wide.RefID = frame.Name, frame.RefID
+		return sqlWideToValues(wide)
+	case data.TimeSeriesTypeWide:
+		return sqlWideToValues(frame)
+	case data.TimeSeriesTypeNot:
+		if isNumberTable(frame) {
+			numbers, err := extractNumberSet(frame)
+			if err != nil {
+				return nil, err
+			}
+			values := make(mathexp.Values, 0, len(numbers))
+			for _, n := range numbers {
+				n.Frame.Name, n.Frame.RefID = frame.Name, frame.RefID
+				values = append(values, n)
+			}
+			return values, nil
+		}
+	}
+	return mathexp.Values{mathexp.TableData{Frame: frame}}, nil
+}
+
+func sqlWideToValues(frame *data.Frame) (mathexp.Values, error) {
+	series, err := WideToMany(frame, nil)
+	if err != nil {
+		return nil, err
+	}
+	values := make(mathexp.Values, 0, len(series))
+	for _, s := range series {
+		s.Frame.RefID = frame.RefID
+		values = append(values, s)
+	}
+	return values, nil
+}
+
 func (gr *SQLCommand) Type() string {
 	return TypeSQL.String()
 }
@@ -1,8 +1,22 @@
 package expr
 
 import (
+	"context"
+	"fmt"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
+	"github.com/grafana/grafana/pkg/expr/sql"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 func TestNewCommand(t *testing.T) {
@@ -25,3 +39,259 @@ func TestNewCommand(t *testing.T) {
 		return
 	}
 }
+
+func TestSQLFrameToValues(t *testing.T) {
+	t.Run("long frame is converted to series", func(t *testing.T) {
+		frame := data.NewFrame("",
+			data.NewField("time", nil, []time.Time{time.Unix(1, 0), time.Unix(1, 0), time.Unix(2, 0), time.Unix(2, 0)}),
+			data.NewField("host", nil, []string{"a", "b", "a", "b"}),
+			data.NewField("value", nil, []float64{1, 2, 3, 4}),
+		)
+		frame.RefID = "B"
+
+		values, err := sqlFrameToValues(frame)
+		require.NoError(t, err)
+		require.Len(t, values, 2)
+		for _, v := range values {
+			require.Equal(t, parse.TypeSeriesSet, v.Type())
+			require.Equal(t, "B", v.AsDataFrame().RefID)
+		}
+		require.Equal(t, data.Labels{"host": "a"}, values[0].GetLabels())
+		require.Equal(t, data.Labels{"host": "b"}, values[1].GetLabels())
+	})
+
+	t.Run("number table is converted to numbers", func(t *testing.T) {
+		frame := data.NewFrame("",
+			data.NewField("host", nil, []string{"a", "b"}),
+			data.NewField("value", nil, []float64{1, 2}),
+		)
+		frame.RefID = "B"
+
+		values, err := sqlFrameToValues(frame)
+		require.NoError(t, err)
+		require.Len(t, values, 2)
+		require.Equal(t, parse.TypeNumberSet, values[0].Type())
+		require.Equal(t, data.Labels{"host": "a"}, values[0].GetLabels())
+		require.Equal(t, "B", values[0].AsDataFrame().RefID)
+	})
+
+	t.Run("other tables are kept as table data", func(t *testing.T) {
+		frame := data.NewFrame("",
+			data.NewField("host", nil, []string{"a", "b"}),
+			data.NewField("min", nil, []float64{1, 2}),
+			data.NewField("max", nil, []float64{3, 4}),
+		)
+
+		values, err := sqlFrameToValues(frame)
+		require.NoError(t, err)
+		require.Equal(t, mathexp.Values{mathexp.TableData{Frame: frame}}, values)
+	})
+
+	t.Run("empty frame is no data", func(t *testing.T) {
+		frame := data.NewFrame("")
+
+		values, err := sqlFrameToValues(frame)
+		require.NoError(t, err)
+		require.Len(t, values, 1)
+		require.Equal(t, parse.TypeNoData, values[0].Type())
+	})
+}
+
+func TestSQLCommand_InputRowsLimit(t *testing.T) {
+	frame := func(refID string, rows int) *data.Frame {
+		f := data.NewFrame("", data.NewField("value", nil, make([]float64, rows)))
+		f.RefID = refID
+		return f
+	}
+
+	t.Run("no limit", func(t *testing.T) {
+		cmd := &SQLCommand{refID: "B"}
+		require.NoError(t, cmd.checkInputRows([]*data.Frame{frame("A", 1000)}))
+	})
+
+	t.Run("within the limit", func(t *testing.T) {
+		cmd := &SQLCommand{refID: "B", limits: SQLLimits{MaxInputRows: 10}}
+		require.NoError(t, cmd.checkInputRows([]*data.Frame{frame("A", 10), frame("C", 10)}))
+	})
+
+	t.Run("frames of the same table are added up", func(t *testing.T) {
+		m := newMetrics(nil)
+		cmd := &SQLCommand{refID: "B", limits: SQLLimits{MaxInputRows: 10}, metrics: m}
+		err := cmd.checkInputRows([]*data.Frame{frame("A", 6), frame("A", 6)})
+		require.ErrorContains(t, err, "input table A has more than 10 rows")
+		require.Equal(t, 1.0, testutil.ToFloat64(m.sqlCommandsRejected.WithLabelValues(sqlLimitReasonInputRows)))
+	})
+}
+
+// fakeSQLDB returns the frame or error set for each query, and records the
+// tables that were available to each query.
+type fakeSQLDB struct {
+	frames map[string]*data.Frame
+	err    error
+	tables [][]string
+}
+
+func (db *fakeSQLDB) QueryFrames(ctx context.Context, name, query string, frames []*data.Frame) (*data.Frame, error) {
+	tables := []string{}
+	for _, f := range frames {
+		tables = append(tables, f.RefID)
+	}
+	db.tables = append(db.tables, tables)
+	if db.err != nil {
+		return nil, db.err
+	}
+	f := *db.frames[query]
+	f.Name = name
+	return &f, nil
+}
+
+func TestSQLCommand_MultipleStatements(t *testing.T) {
+	input := data.NewFrame("", data.NewField("value", nil, []float64{1}))
+	input.RefID = "A"
+	db := &fakeSQLDB{frames: map[string]*data.Frame{
+		"SELECT * FROM A": data.NewFrame("",
+			data.NewField("host", nil, []string{"a", "b", "c"}),
+			data.NewField("value", nil, []float64{1, 2, 3}),
+		),
+		"SELECT host, value FROM totals WHERE value > 1": data.NewFrame("",
+			data.NewField("host", nil, []string{"b", "c"}),
+			data.NewField("value", nil, []float64{2, 3}),
+		),
+	}}
+	cmd := &SQLCommand{
+		refID: "B",
+		statements: []sql.Statement{
+			{Name: "totals", Query: "SELECT * FROM A"},
+			{Query: "SELECT host, value FROM totals WHERE value > 1"},
+		},
+		varsToQuery: []string{"A"},
+		db:          db,
+	}
+	vars := mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: input}}}}
+
+	rsp, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+	require.NoError(t, err)
+	require.NoError(t, rsp.Error)
+	require.Equal(t, [][]string{{"A"}, {"A", "totals"}}, db.tables)
+
+	// the output of every statement is returned and converted to numbers
+	require.Len(t, rsp.Values, 5)
+	names := []string{}
+	for _, v := range rsp.Values {
+		require.Equal(t, parse.TypeNumberSet, v.Type())
+		require.Equal(t, "B", v.AsDataFrame().RefID)
+		names = append(names, v.AsDataFrame().Name)
+	}
+	require.Equal(t, []string{"totals", "totals", "totals", "statement_2", "statement_2"}, names)
+	require.Equal(t, data.Labels{"host": "b"}, rsp.Values[3].GetLabels())
+	require.Equal(t, data.Labels{"host": "c"}, rsp.Values[4].GetLabels())
+}
+
+func TestSQLCommand_UnnamedStatements(t *testing.T) {
+	input := data.NewFrame("", data.NewField("value", nil, []float64{1}))
+	input.RefID = "A"
+	db := &fakeSQLDB{frames: map[string]*data.Frame{
+		"SELECT min(value) AS value FROM A": data.NewFrame("", data.NewField("value", nil, []float64{1})),
+		"SELECT max(value) AS value FROM A": data.NewFrame("", data.NewField("value", nil, []float64{3})),
+	}}
+	cmd := &SQLCommand{
+		refID: "B",
+		statements: []sql.Statement{
+			{Query: "SELECT min(value) AS value FROM A"},
+			{Query: "SELECT max(value) AS value FROM A"},
+		},
+		varsToQuery: []string{"A"},
+		db:          db,
+	}
+	vars := mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{mathexp.TableData{Frame: input}}}}
+
+	rsp, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.InitializeTracerForTest())
+	require.NoError(t, err)
+	require.NoError(t, rsp.Error)
+	require.Len(t, rsp.Values, 2)
+	for i, v := range rsp.Values {
+		require.Equal(t, parse.TypeNumberSet, v.Type())
+		require.Equal(t, fmt.Sprintf("statement_%d", i+1), v.AsDataFrame().Name)
+	}
+	require.Equal(t, 1.0, *rsp.Values[0].(mathexp.Number).GetFloat64Value())
+	require.Equal(t, 3.0, *rsp.Values[1].(mathexp.Number).GetFloat64Value())
+}
+
+func TestSQLCommand_QueryErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		reason string
+		check  func(t *testing.T, err error)
+	}{
+		{
+			name:   "timeout",
+			err:    context.DeadlineExceeded,
+			reason: sqlLimitReasonTimeout,
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, SQLTimeoutError.Base)
+			},
+		},
+		{
+			// a canceled query is not rejected by a limit, so it is not counted
+			name: "canceled",
+			err:  context.Canceled,
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, context.Canceled)
+			},
+		},
+		{
+			name:   "out of memory",
+			err:    fmt.Errorf("%w: Out of Memory Error: failed to allocate", sql.ErrOutOfMemory),
+			reason: sqlLimitReasonMemory,
+			check: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, SQLLimitError.Base)
+				require.ErrorContains(t, err, "memory limit of 1MB")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMetrics(nil)
+			cmd := &SQLCommand{
+				refID:      "B",
+				statements: []sql.Statement{{Query: "SELECT * FROM A"}},
+				limits:     SQLLimits{MemoryLimitMB: 1, Timeout: time.Second},
+				metrics:    m,
+				db:         &fakeSQLDB{err: tt.err},
+			}
+
+			rsp, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{}, tracing.InitializeTracerForTest())
+			require.NoError(t, err)
+			tt.check(t, rsp.Error)
+			if tt.reason == "" {
+				require.Equal(t, 0, testutil.CollectAndCount(m.sqlCommandsRejected))
+				return
+			}
+			require.Equal(t, 1.0, testutil.ToFloat64(m.sqlCommandsRejected.WithLabelValues(tt.reason)))
+		})
+	}
+}
+
+func TestSQLCommand_DuckDBNotFound(t *testing.T) {
+	cmd := &SQLCommand{
+		refID:      "B",
+		statements: []sql.Statement{{Query: "SELECT 1"}},
+		duckDBPath: filepath.Join(t.TempDir(), "duckdb"),
+	}
+
+	rsp, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{}, tracing.InitializeTracerForTest())
+	require.NoError(t, err)
+	require.ErrorIs(t, rsp.Error, sql.ErrDuckDBNotFound)
+}
+
+func TestSQLLimitsFromCfg(t *testing.T) {
+	require.Equal(t, SQLLimits{}, sqlLimitsFromCfg(nil))
+
+	cfg := setting.NewCfg()
+	cfg.SQLExpressionMaxInputRows = 1
+	cfg.SQLExpressionMaxOutputRows = 2
+	cfg.SQLExpressionMemoryLimitMB = 3
+	cfg.SQLExpressionTimeout = time.Second
+	require.Equal(t, SQLLimits{MaxInputRows: 1, MaxOutputRows: 2, MemoryLimitMB: 3, Timeout: time.Second}, sqlLimitsFromCfg(cfg))
+}
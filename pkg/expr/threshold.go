@@ -133,20 +133,6 @@ func (tc *ThresholdCommand) NeedsVars() []string {
 }
 
 func (tc *ThresholdCommand) Execute(_ context.Context, _ time.Time, vars mathexp.Vars, _ tracing.Tracer) (mathexp.Results, error) {
-	eval := func(maybeValue *float64) *float64 {
-		if maybeValue == nil {
-			return nil
-		}
-		result := tc.predicate.Eval(*maybeValue)
-		if tc.Invert {
-			result = !result
-		}
-		if result {
-			return util.Pointer(float64(1))
-		}
-		return util.Pointer(float64(0))
-	}
-
 	refVarResult := vars[tc.ReferenceVar]
 	newRes := mathexp.Results{Values: make(mathexp.Values, 0, len(refVarResult.Values))}
 	for _, val := range refVarResult.Values {
@@ -155,15 +141,15 @@ func (tc *ThresholdCommand) Execute(_ context.Context, _ time.Time, vars mathexp
 			s := mathexp.NewSeries(tc.RefID, v.GetLabels(), v.Len())
 			for i := 0; i < v.Len(); i++ {
 				t, value := v.GetPoint(i)
-				s.SetPoint(i, t, eval(value))
+				s.SetPoint(i, t, tc.eval(value))
 			}
 			newRes.Values = append(newRes.Values, s)
 		case mathexp.Number:
 			copyV := mathexp.NewNumber(tc.RefID, v.GetLabels())
-			copyV.SetValue(eval(v.GetFloat64Value()))
+			copyV.SetValue(tc.eval(v.GetFloat64Value()))
 			newRes.Values = append(newRes.Values, copyV)
 		case mathexp.Scalar:
-			copyV := mathexp.NewScalar(tc.RefID, eval(v.GetFloat64Value()))
+			copyV := mathexp.NewScalar(tc.RefID, tc.eval(v.GetFloat64Value()))
 			newRes.Values = append(newRes.Values, copyV)
 		case mathexp.NoData:
 			newRes.Values = append(newRes.Values, mathexp.NewNoData())
@@ -174,6 +160,22 @@ func (tc *ThresholdCommand) Execute(_ context.Context, _ time.Time, vars mathexp
 	return newRes, nil
 }
 
+// eval returns 1 if the value matches the threshold (or does not match when the command is inverted),
+// 0 if it does not, and nil if there is no value.
+func (tc *ThresholdCommand) eval(maybeValue *float64) *float64 {
+	if maybeValue == nil {
+		return nil
+	}
+	result := tc.predicate.Eval(*maybeValue)
+	if tc.Invert {
+		result = !result
+	}
+	if result {
+		return util.Pointer(float64(1))
+	}
+	return util.Pointer(float64(0))
+}
+
 func (tc *ThresholdCommand) Type() string {
 	return TypeThreshold.String()
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -16,9 +17,13 @@ import (
 type Request struct {
 	Headers map[string]string
 	Debug   bool
+	// Explain adds an explanation of the execution of each node to the response, see ExplainRefID.
+	Explain bool
 	OrgId   int64
 	Queries []Query
 	User    identity.Requester
+	// Inputs are the frames of the queries of the data source InputDatasourceUID by the name of the input.
+	Inputs map[string]data.Frames
 }
 
 // Query is like plugins.DataSubQuery, but with a a time range, and only the UID
@@ -63,6 +68,20 @@ func (r RelativeTimeRange) AbsoluteTime(t time.Time) backend.TimeRange {
 	}
 }
 
+// offsetTimeRange is a time range shifted back in time by the offset.
+type offsetTimeRange struct {
+	TimeRange
+	offset time.Duration
+}
+
+func (r offsetTimeRange) AbsoluteTime(now time.Time) backend.TimeRange {
+	tr := r.TimeRange.AbsoluteTime(now)
+	return backend.TimeRange{
+		From: tr.From.Add(-r.offset),
+		To:   tr.To.Add(-r.offset),
+	}
+}
+
 // TransformData takes Queries which are either expressions nodes
 // or are datasource requests.
 func (s *Service) TransformData(ctx context.Context, now time.Time, req *Request) (r *backend.QueryDataResponse, err error) {
@@ -93,6 +112,11 @@ func (s *Service) TransformData(ctx context.Context, now time.Time, req *Request
 		return nil, err
 	}
 
+	var explanation *Explanation
+	if req.Explain {
+		ctx, explanation = WithExplanation(ctx)
+	}
+
 	// Execute the pipeline
 	responses, err := s.ExecutePipeline(ctx, now, pipeline)
 	if err != nil {
@@ -116,6 +140,10 @@ func (s *Service) TransformData(ctx context.Context, now time.Time, req *Request
 		responses = filteredRes
 	}
 
+	if explanation != nil {
+		responses.Responses[ExplainRefID] = backend.DataResponse{Frames: data.Frames{explanation.Frame()}}
+	}
+
 	return responses, nil
 }
 
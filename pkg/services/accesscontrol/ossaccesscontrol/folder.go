@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/libraryelements"
 	"github.com/grafana/grafana/pkg/services/licensing"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
@@ -127,6 +128,7 @@ func ProvideFolderPermissions(
 		ReaderRoleName: "Folder permission reader",
 		WriterRoleName: "Folder permission writer",
 		RoleGroup:      "Folders",
+		WriteMW:        provenance.Middleware(provenance.ProvideService(sql), provenance.KindFolderPermissions, ":resourceID"),
 	}
 	srv, err := resourcepermissions.New(cfg, options, features, router, license, accesscontrol, service, sql, teamService, userService, actionSetService)
 	if err != nil {
@@ -42,4 +42,6 @@ type Options struct {
 	InheritedScopesSolver InheritedScopesSolver
 	// LicenseMV if configured is applied to endpoints that can modify permissions
 	LicenseMW web.Handler
+	// WriteMW if configured is applied to endpoints that can modify permissions after the request has been authorized
+	WriteMW web.Handler
 }
@@ -221,6 +221,9 @@ type DashboardProvisioning struct {
 	ExternalID  string `xorm:"external_id"`
 	CheckSum    string
 	Updated     int64
+	// CommitSHA is the commit of the git repository that the dashboard was last provisioned from.
+	// It is empty for dashboards that are not provisioned from git.
+	CommitSHA string `xorm:"commit_sha"`
 }
 
 type DeleteDashboardCommand struct {
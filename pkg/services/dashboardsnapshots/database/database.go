@@ -61,6 +61,9 @@ func (d *DashboardSnapshotStore) CreateDashboardSnapshot(ctx context.Context, cm
 			External:           cmd.External,
 			ExternalURL:        cmd.ExternalURL,
 			ExternalDeleteURL:  cmd.ExternalDeleteURL,
+			DashboardUID:       cmd.DashboardUID,
+			TimeFrom:           cmd.TimeFrom,
+			TimeTo:             cmd.TimeTo,
 			Dashboard:          simplejson.New(),
 			DashboardEncrypted: cmd.DashboardEncrypted,
 			Expires:            expires,
@@ -86,6 +89,24 @@ func (d *DashboardSnapshotStore) DeleteDashboardSnapshot(ctx context.Context, cm
 	})
 }
 
+// UpdateDashboardSnapshot replaces the dashboard of a snapshot.
+func (d *DashboardSnapshotStore) UpdateDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.UpdateDashboardSnapshotCommand) error {
+	return d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		snapshot := &dashboardsnapshots.DashboardSnapshot{
+			DashboardEncrypted: cmd.DashboardEncrypted,
+			Updated:            time.Now(),
+		}
+		affected, err := sess.Where(d.store.GetDialect().Quote("key")+" = ?", cmd.Key).Cols("dashboard_encrypted", "updated").Update(snapshot)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return dashboardsnapshots.ErrBaseNotFound.Errorf("dashboard snapshot not found")
+		}
+		return nil
+	})
+}
+
 func (d *DashboardSnapshotStore) GetDashboardSnapshot(ctx context.Context, query *dashboardsnapshots.GetDashboardSnapshotQuery) (*dashboardsnapshots.DashboardSnapshot, error) {
 	var queryResult *dashboardsnapshots.DashboardSnapshot
 	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
@@ -199,6 +199,148 @@ func TestIntegrationDeleteExpiredSnapshots(t *testing.T) {
 	})
 }
 
+func TestIntegrationUpdateDashboardSnapshot(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlstore := db.InitTestDB(t)
+	dashStore := NewStore(sqlstore)
+
+	t.Run("Should replace the dashboard of the snapshot", func(t *testing.T) {
+		snapshot := createTestSnapshot(t, dashStore, "key1", 0)
+
+		err := dashStore.UpdateDashboardSnapshot(context.Background(), &dashboardsnapshots.UpdateDashboardSnapshotCommand{
+			Key:                snapshot.Key,
+			DashboardEncrypted: []byte("updated"),
+		})
+		require.NoError(t, err)
+
+		result, err := dashStore.GetDashboardSnapshot(context.Background(), &dashboardsnapshots.GetDashboardSnapshotQuery{Key: snapshot.Key})
+		require.NoError(t, err)
+		require.Equal(t, []byte("updated"), result.DashboardEncrypted)
+		require.Equal(t, snapshot.DeleteKey, result.DeleteKey)
+	})
+
+	t.Run("Should return not found for a missing snapshot", func(t *testing.T) {
+		err := dashStore.UpdateDashboardSnapshot(context.Background(), &dashboardsnapshots.UpdateDashboardSnapshotCommand{Key: "missing"})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrBaseNotFound)
+	})
+}
+
+func TestIntegrationSnapshotSchedules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlstore := db.InitTestDB(t)
+	dashStore := NewStore(sqlstore)
+	ctx := context.Background()
+	now := time.Now()
+
+	createSchedule := func(t *testing.T, uid string, orgID, userID int64, nextRun time.Time) *dashboardsnapshots.DashboardSnapshotSchedule {
+		t.Helper()
+		schedule, err := dashStore.CreateSnapshotSchedule(ctx, &dashboardsnapshots.CreateSnapshotScheduleCommand{
+			UID:          uid,
+			DashboardUID: "dash",
+			Name:         uid,
+			Schedule:     "@weekly",
+			From:         "now-7d",
+			To:           "now",
+			OrgID:        orgID,
+			UserID:       userID,
+			NextRun:      nextRun,
+		})
+		require.NoError(t, err)
+		return schedule
+	}
+
+	due := createSchedule(t, "due", 1, 1, now.Add(-time.Minute))
+	createSchedule(t, "later", 1, 2, now.Add(time.Hour))
+	createSchedule(t, "other-org", 2, 1, now.Add(-time.Hour))
+
+	t.Run("Should get a schedule by its UID in the org", func(t *testing.T) {
+		schedule, err := dashStore.GetSnapshotSchedule(ctx, &dashboardsnapshots.GetSnapshotScheduleQuery{UID: "due", OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, "now-7d", schedule.TimeFrom)
+
+		_, err = dashStore.GetSnapshotSchedule(ctx, &dashboardsnapshots.GetSnapshotScheduleQuery{UID: "due", OrgID: 2})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrScheduleNotFound)
+	})
+
+	t.Run("Should search the schedules of an org or a user", func(t *testing.T) {
+		schedules, err := dashStore.SearchSnapshotSchedules(ctx, &dashboardsnapshots.SearchSnapshotSchedulesQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, schedules, 2)
+
+		schedules, err = dashStore.SearchSnapshotSchedules(ctx, &dashboardsnapshots.SearchSnapshotSchedulesQuery{OrgID: 1, UserID: 2})
+		require.NoError(t, err)
+		require.Len(t, schedules, 1)
+		require.Equal(t, "later", schedules[0].UID)
+	})
+
+	t.Run("Should get the due schedules of all orgs", func(t *testing.T) {
+		schedules, err := dashStore.GetDueSnapshotSchedules(ctx, now)
+		require.NoError(t, err)
+		require.Len(t, schedules, 2)
+		require.Equal(t, "other-org", schedules[0].UID)
+		require.Equal(t, "due", schedules[1].UID)
+	})
+
+	t.Run("Should update the run of a schedule", func(t *testing.T) {
+		err := dashStore.UpdateSnapshotScheduleRun(ctx, &dashboardsnapshots.UpdateSnapshotScheduleRunCommand{
+			ID:        due.ID,
+			NextRun:   now.Add(-time.Minute),
+			LastError: "failed",
+		})
+		require.NoError(t, err)
+
+		err = dashStore.UpdateSnapshotScheduleRun(ctx, &dashboardsnapshots.UpdateSnapshotScheduleRunCommand{
+			ID:              due.ID,
+			NextRun:         now.Add(7 * 24 * time.Hour),
+			LastSnapshotKey: "key",
+		})
+		require.NoError(t, err)
+
+		schedules, err := dashStore.GetDueSnapshotSchedules(ctx, now)
+		require.NoError(t, err)
+		require.Len(t, schedules, 1)
+
+		schedule, err := dashStore.GetSnapshotSchedule(ctx, &dashboardsnapshots.GetSnapshotScheduleQuery{UID: "due", OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, "key", schedule.LastSnapshotKey)
+		require.Empty(t, schedule.LastError)
+	})
+
+	t.Run("Should claim the run of a due schedule once", func(t *testing.T) {
+		err := dashStore.UpdateSnapshotScheduleRun(ctx, &dashboardsnapshots.UpdateSnapshotScheduleRunCommand{
+			ID:      due.ID,
+			NextRun: now.Add(-time.Minute),
+		})
+		require.NoError(t, err)
+
+		cmd := &dashboardsnapshots.ClaimSnapshotScheduleRunCommand{ID: due.ID, Now: now, NextRun: now.Add(time.Hour)}
+		claimed, err := dashStore.ClaimSnapshotScheduleRun(ctx, cmd)
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		claimed, err = dashStore.ClaimSnapshotScheduleRun(ctx, cmd)
+		require.NoError(t, err)
+		require.False(t, claimed)
+
+		schedules, err := dashStore.GetDueSnapshotSchedules(ctx, now)
+		require.NoError(t, err)
+		require.Len(t, schedules, 1)
+		require.Equal(t, "other-org", schedules[0].UID)
+	})
+
+	t.Run("Should delete a schedule", func(t *testing.T) {
+		err := dashStore.DeleteSnapshotSchedule(ctx, &dashboardsnapshots.DeleteSnapshotScheduleCommand{UID: "due", OrgID: 1})
+		require.NoError(t, err)
+
+		err = dashStore.DeleteSnapshotSchedule(ctx, &dashboardsnapshots.DeleteSnapshotScheduleCommand{UID: "due", OrgID: 1})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrScheduleNotFound)
+	})
+}
+
 func createTestSnapshot(t *testing.T, dashStore *DashboardSnapshotStore, key string, expires int64) *dashboardsnapshots.DashboardSnapshot {
 	cmd := dashboardsnapshots.CreateDashboardSnapshotCommand{
 		Key:       key,
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+)
+
+func (d *DashboardSnapshotStore) CreateSnapshotSchedule(ctx context.Context, cmd *dashboardsnapshots.CreateSnapshotScheduleCommand) (*dashboardsnapshots.DashboardSnapshotSchedule, error) {
+	schedule := &dashboardsnapshots.DashboardSnapshotSchedule{
+		UID:          cmd.UID,
+		OrgID:        cmd.OrgID,
+		UserID:       cmd.UserID,
+		DashboardUID: cmd.DashboardUID,
+		Name:         cmd.Name,
+		Schedule:     cmd.Schedule,
+		TimeFrom:     cmd.From,
+		TimeTo:       cmd.To,
+		Expires:      cmd.Expires,
+		NextRun:      cmd.NextRun,
+		Created:      time.Now(),
+		Updated:      time.Now(),
+	}
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(schedule)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (d *DashboardSnapshotStore) DeleteSnapshotSchedule(ctx context.Context, cmd *dashboardsnapshots.DeleteSnapshotScheduleCommand) error {
+	return d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec("DELETE FROM dashboard_snapshot_schedule WHERE org_id = ? AND uid = ?", cmd.OrgID, cmd.UID)
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err == nil && affected == 0 {
+			return dashboardsnapshots.ErrScheduleNotFound.Errorf("snapshot schedule not found")
+		}
+		return nil
+	})
+}
+
+func (d *DashboardSnapshotStore) GetSnapshotSchedule(ctx context.Context, query *dashboardsnapshots.GetSnapshotScheduleQuery) (*dashboardsnapshots.DashboardSnapshotSchedule, error) {
+	schedule := &dashboardsnapshots.DashboardSnapshotSchedule{}
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("org_id = ? AND uid = ?", query.OrgID, query.UID).Get(schedule)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return dashboardsnapshots.ErrScheduleNotFound.Errorf("snapshot schedule not found")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (d *DashboardSnapshotStore) SearchSnapshotSchedules(ctx context.Context, query *dashboardsnapshots.SearchSnapshotSchedulesQuery) ([]*dashboardsnapshots.DashboardSnapshotSchedule, error) {
+	schedules := make([]*dashboardsnapshots.DashboardSnapshotSchedule, 0)
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		sess.Where("org_id = ?", query.OrgID)
+		if query.UserID != 0 {
+			sess.Where("user_id = ?", query.UserID)
+		}
+		return sess.Asc("name", "id").Find(&schedules)
+	})
+	return schedules, err
+}
+
+func (d *DashboardSnapshotStore) GetDueSnapshotSchedules(ctx context.Context, now time.Time) ([]*dashboardsnapshots.DashboardSnapshotSchedule, error) {
+	schedules := make([]*dashboardsnapshots.DashboardSnapshotSchedule, 0)
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("next_run <= ?", now).Asc("next_run", "id").Find(&schedules)
+	})
+	return schedules, err
+}
+
+func (d *DashboardSnapshotStore) ClaimSnapshotScheduleRun(ctx context.Context, cmd *dashboardsnapshots.ClaimSnapshotScheduleRunCommand) (bool, error) {
+	var claimed bool
+	err := d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		schedule := &dashboardsnapshots.DashboardSnapshotSchedule{
+			NextRun: cmd.NextRun,
+			Updated: time.Now(),
+		}
+		affected, err := sess.ID(cmd.ID).Where("next_run <= ?", cmd.Now).Cols("next_run", "updated").Update(schedule)
+		claimed = affected > 0
+		return err
+	})
+	return claimed, err
+}
+
+// UpdateSnapshotScheduleRun records the result of a run of a schedule and when it runs next.
+func (d *DashboardSnapshotStore) UpdateSnapshotScheduleRun(ctx context.Context, cmd *dashboardsnapshots.UpdateSnapshotScheduleRunCommand) error {
+	return d.store.WithDbSession(ctx, func(sess *db.Session) error {
+		schedule := &dashboardsnapshots.DashboardSnapshotSchedule{
+			NextRun:         cmd.NextRun,
+			LastSnapshotKey: cmd.LastSnapshotKey,
+			LastError:       cmd.LastError,
+			Updated:         time.Now(),
+		}
+		_, err := sess.ID(cmd.ID).Cols("next_run", "last_snapshot_key", "last_error", "updated").Update(schedule)
+		return err
+	})
+}
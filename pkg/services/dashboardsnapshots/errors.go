@@ -5,3 +5,18 @@ import (
 )
 
 var ErrBaseNotFound = errutil.NotFound("dashboardsnapshots.not-found", errutil.WithPublicMessage("Snapshot not found"))
+
+var (
+	ErrCannotRefresh = errutil.BadRequest("dashboardsnapshots.cannot-refresh",
+		errutil.WithPublicMessage("Only snapshots that were built on the server can be refreshed"))
+	ErrInvalidTimeRange = errutil.BadRequest("dashboardsnapshots.invalid-time-range",
+		errutil.WithPublicMessage("Invalid time range"))
+	ErrPanelQueryFailed = errutil.BadRequest("dashboardsnapshots.panel-query-failed",
+		errutil.WithPublicMessage("Failed to query the data of a panel"))
+	ErrInvalidSchedule = errutil.BadRequest("dashboardsnapshots.invalid-schedule",
+		errutil.WithPublicMessage("Invalid cron expression"))
+	ErrScheduleNotFound = errutil.NotFound("dashboardsnapshots.schedule-not-found",
+		errutil.WithPublicMessage("Snapshot schedule not found"))
+	ErrScheduleForbidden = errutil.Forbidden("dashboardsnapshots.schedule-forbidden",
+		errutil.WithPublicMessage("The user of the schedule cannot save the dashboard"))
+)
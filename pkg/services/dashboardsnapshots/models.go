@@ -20,6 +20,12 @@ type DashboardSnapshot struct {
 	ExternalURL       string `xorm:"external_url"`
 	ExternalDeleteURL string `xorm:"external_delete_url"`
 
+	// DashboardUID, TimeFrom and TimeTo are set for snapshots that were built on the server,
+	// so that they can be refreshed with the queries of the dashboard.
+	DashboardUID string `xorm:"dashboard_uid"`
+	TimeFrom     string `xorm:"time_from"`
+	TimeTo       string `xorm:"time_to"`
+
 	Expires time.Time
 	Created time.Time
 	Updated time.Time
@@ -66,9 +72,54 @@ type CreateDashboardSnapshotCommand struct {
 	OrgID  int64 `json:"-"`
 	UserID int64 `json:"-"`
 
+	DashboardUID string `json:"-"`
+	TimeFrom     string `json:"-"`
+	TimeTo       string `json:"-"`
+
 	DashboardEncrypted []byte `json:"-"`
 }
 
+// BuildDashboardSnapshotCommand creates a snapshot of a saved dashboard by running the queries of its panels on the server.
+// swagger:model
+type BuildDashboardSnapshotCommand struct {
+	// The UID of the dashboard.
+	// required:true
+	DashboardUID string `json:"dashboardUid" binding:"Required"`
+
+	// Snapshot name. Default is the title of the dashboard.
+	// required:false
+	Name string `json:"name"`
+
+	// The start of the time range, as an absolute or relative time. Default is the time range of the dashboard.
+	// required:false
+	From string `json:"from"`
+
+	// The end of the time range, as an absolute or relative time. Default is the time range of the dashboard.
+	// required:false
+	To string `json:"to"`
+
+	// When the snapshot should expire in seconds. Default is never to expire.
+	// required:false
+	// default:0
+	Expires int64 `json:"expires"`
+
+	Key          string             `json:"-"`
+	DeleteKey    string             `json:"-"`
+	SignedInUser identity.Requester `json:"-"`
+}
+
+// RefreshDashboardSnapshotCommand runs the queries of a snapshot that was built on the server again,
+// and replaces the data of the snapshot.
+type RefreshDashboardSnapshotCommand struct {
+	Key          string
+	SignedInUser identity.Requester
+}
+
+type UpdateDashboardSnapshotCommand struct {
+	Key                string
+	DashboardEncrypted []byte
+}
+
 type DeleteDashboardSnapshotCommand struct {
 	DeleteKey string `json:"-"`
 }
@@ -97,3 +148,89 @@ type CreateExternalSnapshotResponse struct {
 	Url       string `json:"url"`
 	DeleteUrl string `json:"deleteUrl"`
 }
+
+// DashboardSnapshotSchedule builds a snapshot of a dashboard on a cron schedule.
+type DashboardSnapshotSchedule struct {
+	ID           int64  `json:"-" xorm:"pk autoincr 'id'"`
+	UID          string `json:"uid" xorm:"uid"`
+	OrgID        int64  `json:"-" xorm:"org_id"`
+	UserID       int64  `json:"userId" xorm:"user_id"`
+	DashboardUID string `json:"dashboardUid" xorm:"dashboard_uid"`
+	Name         string `json:"name"`
+	Schedule     string `json:"schedule"`
+	TimeFrom     string `json:"from" xorm:"time_from"`
+	TimeTo       string `json:"to" xorm:"time_to"`
+	Expires      int64  `json:"expires"`
+
+	NextRun         time.Time `json:"nextRun"`
+	LastSnapshotKey string    `json:"lastSnapshotKey"`
+	LastError       string    `json:"lastError"`
+
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// swagger:model
+type CreateSnapshotScheduleCommand struct {
+	// The UID of the dashboard.
+	// required:true
+	DashboardUID string `json:"dashboardUid" binding:"Required"`
+
+	// Name of the snapshots. Default is the title of the dashboard.
+	// required:false
+	Name string `json:"name"`
+
+	// Cron expression of the schedule, such as `0 9 * * 1` for every Monday at 9:00.
+	// The time zone of the server is used, unless the expression starts with `CRON_TZ=<time zone>`.
+	// required:true
+	Schedule string `json:"schedule" binding:"Required"`
+
+	// The start of the time range of every snapshot. Default is the time range of the dashboard.
+	// required:false
+	From string `json:"from"`
+
+	// The end of the time range of every snapshot. Default is the time range of the dashboard.
+	// required:false
+	To string `json:"to"`
+
+	// When the snapshots should expire in seconds. Default is never to expire.
+	// required:false
+	// default:0
+	Expires int64 `json:"expires"`
+
+	UID     string    `json:"-"`
+	OrgID   int64     `json:"-"`
+	UserID  int64     `json:"-"`
+	NextRun time.Time `json:"-"`
+}
+
+type DeleteSnapshotScheduleCommand struct {
+	UID   string
+	OrgID int64
+}
+
+type GetSnapshotScheduleQuery struct {
+	UID   string
+	OrgID int64
+}
+
+type SearchSnapshotSchedulesQuery struct {
+	OrgID int64
+	// UserID limits the schedules to the schedules of a user, if it is set.
+	UserID int64
+}
+
+// ClaimSnapshotScheduleRunCommand sets the next run of a schedule if the schedule is due at Now, so that a run is
+// started by only one instance.
+type ClaimSnapshotScheduleRunCommand struct {
+	ID      int64
+	Now     time.Time
+	NextRun time.Time
+}
+
+type UpdateSnapshotScheduleRunCommand struct {
+	ID              int64
+	NextRun         time.Time
+	LastSnapshotKey string
+	LastError       string
+}
@@ -29,6 +29,13 @@ type Service interface {
 	GetDashboardSnapshot(context.Context, *GetDashboardSnapshotQuery) (*DashboardSnapshot, error)
 	SearchDashboardSnapshots(context.Context, *GetDashboardSnapshotsQuery) (DashboardSnapshotsList, error)
 	ValidateDashboardExists(context.Context, int64, string) error
+	BuildDashboardSnapshot(context.Context, *BuildDashboardSnapshotCommand) (*DashboardSnapshot, error)
+	RefreshDashboardSnapshot(context.Context, *RefreshDashboardSnapshotCommand) (*DashboardSnapshot, error)
+
+	CreateSnapshotSchedule(context.Context, *CreateSnapshotScheduleCommand) (*DashboardSnapshotSchedule, error)
+	DeleteSnapshotSchedule(context.Context, *DeleteSnapshotScheduleCommand) error
+	GetSnapshotSchedule(context.Context, *GetSnapshotScheduleQuery) (*DashboardSnapshotSchedule, error)
+	SearchSnapshotSchedules(context.Context, *SearchSnapshotSchedulesQuery) ([]*DashboardSnapshotSchedule, error)
 }
 
 var client = &http.Client{
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	common "github.com/grafana/grafana/pkg/apimachinery/apis/common/v0alpha1"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	dashboardsnapshot "github.com/grafana/grafana/pkg/apis/dashboardsnapshot/v0alpha1"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/tsdb/grafanads"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// defaultMaxDataPoints is used for panels that do not set maxDataPoints, it is about the width of a panel in pixels.
+	defaultMaxDataPoints = 1000
+	defaultTimeFrom      = "now-6h"
+	defaultTimeTo        = "now"
+)
+
+// BuildDashboardSnapshot creates a snapshot of a saved dashboard without a browser. The queries of every panel are run
+// as the user of the command, and the data frames are embedded in the panels the same way as in snapshots that are
+// created in the dashboard. Callers check that the user can save the dashboard.
+func (s *ServiceImpl) BuildDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.BuildDashboardSnapshotCommand) (*dashboardsnapshots.DashboardSnapshot, error) {
+	dashboard, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: cmd.DashboardUID, OrgID: cmd.SignedInUser.GetOrgID()})
+	if err != nil {
+		return nil, err
+	}
+
+	from, to := timeRangeOrDefault(dashboard.Data, cmd.From, cmd.To)
+	name := cmd.Name
+	if name == "" {
+		name = dashboard.Title
+	}
+
+	snapshotDashboard, err := s.buildSnapshotDashboard(ctx, cmd.SignedInUser, dashboard, name, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.Key == "" {
+		if cmd.Key, err = util.GetRandomString(32); err != nil {
+			return nil, err
+		}
+	}
+	if cmd.DeleteKey == "" {
+		if cmd.DeleteKey, err = util.GetRandomString(32); err != nil {
+			return nil, err
+		}
+	}
+
+	userID, _ := identity.UserIdentifier(cmd.SignedInUser.GetID())
+	return s.CreateDashboardSnapshot(ctx, &dashboardsnapshots.CreateDashboardSnapshotCommand{
+		DashboardCreateCommand: dashboardsnapshot.DashboardCreateCommand{
+			Name:      name,
+			Dashboard: snapshotDashboard,
+			Expires:   cmd.Expires,
+		},
+		Key:          cmd.Key,
+		DeleteKey:    cmd.DeleteKey,
+		OrgID:        cmd.SignedInUser.GetOrgID(),
+		UserID:       userID,
+		DashboardUID: dashboard.UID,
+		TimeFrom:     from,
+		TimeTo:       to,
+	})
+}
+
+// RefreshDashboardSnapshot runs the queries of the dashboard of a snapshot again for the time range that the snapshot was
+// built with, and replaces the data of the snapshot. The key, name and expiry of the snapshot do not change.
+func (s *ServiceImpl) RefreshDashboardSnapshot(ctx context.Context, cmd *dashboardsnapshots.RefreshDashboardSnapshotCommand) (*dashboardsnapshots.DashboardSnapshot, error) {
+	snapshot, err := s.store.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: cmd.Key})
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.External || snapshot.DashboardUID == "" {
+		return nil, dashboardsnapshots.ErrCannotRefresh.Errorf("snapshot %s was not built on the server", snapshot.Key)
+	}
+
+	dashboard, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: snapshot.DashboardUID, OrgID: snapshot.OrgID})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotDashboard, err := s.buildSnapshotDashboard(ctx, cmd.SignedInUser, dashboard, snapshot.Name, snapshot.TimeFrom, snapshot.TimeTo)
+	if err != nil {
+		return nil, err
+	}
+	marshalledData, err := snapshotDashboard.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	encryptedDashboard, err := s.secretsService.Encrypt(ctx, marshalledData, secrets.WithoutScope())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.store.UpdateDashboardSnapshot(ctx, &dashboardsnapshots.UpdateDashboardSnapshotCommand{
+		Key:                snapshot.Key,
+		DashboardEncrypted: encryptedDashboard,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: snapshot.Key})
+}
+
+// buildSnapshotDashboard returns the model of a snapshot of the dashboard. Like the snapshots that are created in the
+// dashboard, the targets of every panel are replaced with a snapshot query of the Grafana data source that contains
+// the data frames of the panel, and links, annotation queries and variable queries are removed.
+func (s *ServiceImpl) buildSnapshotDashboard(ctx context.Context, user identity.Requester, dashboard *dashboards.Dashboard, name, from, to string) (*common.Unstructured, error) {
+	now := time.Now()
+	timeRange := gtime.NewTimeRange(from, to)
+	fromTime, err := timeRange.ParseFrom(gtime.WithNow(now))
+	if err != nil {
+		return nil, dashboardsnapshots.ErrInvalidTimeRange.Errorf("invalid from %q: %w", from, err)
+	}
+	toTime, err := timeRange.ParseTo(gtime.WithNow(now))
+	if err != nil {
+		return nil, dashboardsnapshots.ErrInvalidTimeRange.Errorf("invalid to %q: %w", to, err)
+	}
+	if !fromTime.Before(toTime) {
+		return nil, dashboardsnapshots.ErrInvalidTimeRange.Errorf("from %q is not before to %q", from, to)
+	}
+
+	// Copy the dashboard, so that the dashboard of the caller is not changed.
+	raw, err := dashboard.Data.Encode()
+	if err != nil {
+		return nil, err
+	}
+	model, err := simplejson.NewJson(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.snapshotPanels(ctx, user, model.Get("panels").MustArray(), fromTime, toTime); err != nil {
+		return nil, err
+	}
+	model.Set("title", name)
+	model.Set("time", map[string]any{
+		"from": fromTime.UTC().Format(time.RFC3339Nano),
+		"to":   toTime.UTC().Format(time.RFC3339Nano),
+	})
+	model.Set("links", []any{})
+	trimAnnotations(model)
+	trimVariables(model)
+	model.SetPath([]string{"snapshot", "originalUrl"}, fmt.Sprintf("/d/%v", dashboard.UID))
+
+	b, err := model.Encode()
+	if err != nil {
+		return nil, err
+	}
+	result := &common.Unstructured{}
+	if err := json.Unmarshal(b, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *ServiceImpl) snapshotPanels(ctx context.Context, user identity.Requester, panels []any, from, to time.Time) error {
+	for _, panelObj := range panels {
+		panel := simplejson.NewFromAny(panelObj)
+		if _, ok := panel.CheckGet("links"); ok {
+			panel.Set("links", []any{})
+		}
+
+		// collapsed rows contain their panels, the panels of expanded rows follow the row
+		if panel.Get("type").MustString() == "row" {
+			if err := s.snapshotPanels(ctx, user, panel.Get("panels").MustArray(), from, to); err != nil {
+				return err
+			}
+			continue
+		}
+
+		targets := panel.Get("targets").MustArray()
+		if len(targets) == 0 {
+			continue
+		}
+
+		frames, err := s.queryPanel(ctx, user, panel, targets, from, to)
+		if err != nil {
+			return dashboardsnapshots.ErrPanelQueryFailed.Errorf("panel %d: %w", panel.Get("id").MustInt64(), err)
+		}
+
+		datasource := map[string]any{"type": "grafana", "uid": grafanads.DatasourceUID}
+		panel.Set("datasource", datasource)
+		panel.Set("targets", []any{
+			map[string]any{
+				"refId":      "A",
+				"datasource": datasource,
+				"queryType":  "snapshot",
+				"snapshot":   frames,
+			},
+		})
+	}
+	return nil
+}
+
+// queryPanel runs the targets of a panel through the query service and returns the data frames in the order of the
+// targets, serialized the same way as the frames of snapshots that are created in the dashboard.
+func (s *ServiceImpl) queryPanel(ctx context.Context, user identity.Requester, panel *simplejson.Json, targets []any, from, to time.Time) ([]json.RawMessage, error) {
+	maxDataPoints := panel.Get("maxDataPoints").MustInt64(defaultMaxDataPoints)
+	interval := gtime.RoundInterval(to.Sub(from) / time.Duration(maxDataPoints))
+	if minInterval, err := gtime.ParseIntervalStringToTimeDuration(panel.Get("interval").MustString()); err == nil && minInterval > interval {
+		interval = minInterval
+	}
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+
+	hasExpression := false
+	for _, targetObj := range targets {
+		uid := simplejson.NewFromAny(targetObj).Get("datasource").Get("uid").MustString()
+		if expr.NodeTypeFromDatasourceUID(uid) == expr.TypeCMDNode {
+			hasExpression = true
+		}
+	}
+
+	queries := make([]*simplejson.Json, 0, len(targets))
+	refIDs := make([]string, 0, len(targets))
+	for _, targetObj := range targets {
+		query := simplejson.NewFromAny(targetObj)
+		// hidden queries are only run as the input of expressions, the query service removes their results
+		if !hasExpression && query.Get("hide").MustBool() {
+			continue
+		}
+		if _, ok := query.CheckGet("datasource"); !ok {
+			query.Set("datasource", panel.Get("datasource").Interface())
+		}
+		query.Set("intervalMs", interval.Milliseconds())
+		query.Set("maxDataPoints", maxDataPoints)
+		queries = append(queries, query)
+		refIDs = append(refIDs, query.Get("refId").MustString())
+	}
+
+	frames := []json.RawMessage{}
+	if len(queries) == 0 {
+		return frames, nil
+	}
+
+	resp, err := s.queryDataService.QueryData(ctx, user, false, dtos.MetricRequest{
+		From:    strconv.FormatInt(from.UnixMilli(), 10),
+		To:      strconv.FormatInt(to.UnixMilli(), 10),
+		Queries: queries,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, refID := range refIDs {
+		res, ok := resp.Responses[refID]
+		if !ok {
+			continue
+		}
+		if res.Error != nil {
+			return nil, fmt.Errorf("query %s: %w", refID, res.Error)
+		}
+		for _, frame := range res.Frames {
+			if frame.RefID == "" {
+				frame.RefID = refID
+			}
+			b, err := data.FrameToJSON(frame, data.IncludeAll)
+			if err != nil {
+				return nil, err
+			}
+			frames = append(frames, b)
+		}
+	}
+	return frames, nil
+}
+
+// trimAnnotations keeps the enabled annotations without their queries, because annotations are not part of the data
+// of the panels.
+func trimAnnotations(model *simplejson.Json) {
+	annotations := []any{}
+	for _, annotationObj := range model.Get("annotations").Get("list").MustArray() {
+		annotation := simplejson.NewFromAny(annotationObj)
+		if !annotation.Get("enable").MustBool() {
+			continue
+		}
+		trimmed := map[string]any{"snapshotData": []any{}}
+		for _, key := range []string{"name", "enable", "iconColor", "type", "builtIn", "hide"} {
+			if v, ok := annotation.CheckGet(key); ok {
+				trimmed[key] = v.Interface()
+			}
+		}
+		annotations = append(annotations, trimmed)
+	}
+	model.SetPath([]string{"annotations", "list"}, annotations)
+}
+
+// trimVariables keeps the saved value of every variable as its only option, and removes the variable queries.
+func trimVariables(model *simplejson.Json) {
+	for _, variableObj := range model.Get("templating").Get("list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		if _, ok := variable.CheckGet("query"); ok {
+			variable.Set("query", "")
+		}
+		if _, ok := variable.CheckGet("options"); ok {
+			current := variable.Get("current").MustMap()
+			if len(current) == 0 {
+				variable.Set("options", []any{})
+			} else {
+				variable.Set("options", []any{current})
+			}
+		}
+		if _, ok := variable.CheckGet("refresh"); ok {
+			// never refresh
+			variable.Set("refresh", 0)
+		}
+	}
+}
+
+// timeRangeOrDefault returns the time range, or the time range of the dashboard if the range is not set.
+func timeRangeOrDefault(dashboard *simplejson.Json, from, to string) (string, string) {
+	if from == "" {
+		from = dashboard.Get("time").Get("from").MustString(defaultTimeFrom)
+	}
+	if to == "" {
+		to = dashboard.Get("time").Get("to").MustString(defaultTimeTo)
+	}
+	return from, to
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	dashsnapdb "github.com/grafana/grafana/pkg/services/dashboardsnapshots/database"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const snapshotTestDashboard = `{
+	"uid": "dash",
+	"title": "Weekly report",
+	"time": {"from": "now-7d", "to": "now"},
+	"links": [{"title": "link"}],
+	"annotations": {"list": [
+		{"name": "Annotations & Alerts", "enable": true, "builtIn": 1, "target": {"limit": 100}},
+		{"name": "Disabled", "enable": false}
+	]},
+	"templating": {"list": [
+		{"name": "host", "type": "query", "query": "hosts", "refresh": 1, "current": {"text": "a", "value": "a"}, "options": [{"text": "a"}, {"text": "b"}]}
+	]},
+	"panels": [
+		{"id": 1, "type": "timeseries", "links": [{"title": "link"}], "datasource": {"type": "prometheus", "uid": "prom"}, "targets": [
+			{"refId": "A", "expr": "up"},
+			{"refId": "B", "expr": "hidden", "hide": true}
+		]},
+		{"id": 2, "type": "text"},
+		{"id": 3, "type": "row", "collapsed": true, "panels": [
+			{"id": 4, "type": "stat", "datasource": {"type": "loki", "uid": "loki"}, "targets": [{"refId": "A", "expr": "rate"}]}
+		]}
+	]
+}`
+
+type fakeQueryService struct {
+	requests []dtos.MetricRequest
+	value    float64
+	err      error
+}
+
+func (f *fakeQueryService) Run(ctx context.Context) error {
+	return nil
+}
+
+func (f *fakeQueryService) QueryData(ctx context.Context, user identity.Requester, skipDSCache bool, reqDTO dtos.MetricRequest) (*backend.QueryDataResponse, error) {
+	f.requests = append(f.requests, reqDTO)
+	if f.err != nil {
+		return nil, f.err
+	}
+	resp := backend.NewQueryDataResponse()
+	for _, q := range reqDTO.Queries {
+		refID := q.Get("refId").MustString()
+		resp.Responses[refID] = backend.DataResponse{Frames: data.Frames{
+			data.NewFrame(refID, data.NewField("value", nil, []float64{f.value})),
+		}}
+	}
+	return resp, nil
+}
+
+func setupBuildTest(t *testing.T) (*ServiceImpl, *fakeQueryService) {
+	t.Helper()
+	sqlStore := db.InitTestDB(t)
+	dsStore := dashsnapdb.ProvideStore(sqlStore, setting.NewCfg())
+	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+
+	dashboardData, err := simplejson.NewJson([]byte(snapshotTestDashboard))
+	require.NoError(t, err)
+	dashboardService := &dashboards.FakeDashboardService{}
+	dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(func(ctx context.Context, query *dashboards.GetDashboardQuery) (*dashboards.Dashboard, error) {
+		if query.UID != "dash" {
+			return nil, dashboards.ErrDashboardNotFound
+		}
+		return &dashboards.Dashboard{ID: 1, UID: "dash", OrgID: query.OrgID, Title: "Weekly report", Data: dashboardData}, nil
+	})
+
+	queryService := &fakeQueryService{value: 1}
+	return ProvideService(dsStore, secretsService, dashboardService, queryService), queryService
+}
+
+func TestBuildDashboardSnapshot(t *testing.T) {
+	s, queryService := setupBuildTest(t)
+	ctx := context.Background()
+	usr := &user.SignedInUser{UserID: 10, OrgID: 1}
+
+	var key string
+	t.Run("should embed the data of every panel", func(t *testing.T) {
+		before := time.Now()
+		result, err := s.BuildDashboardSnapshot(ctx, &dashboardsnapshots.BuildDashboardSnapshotCommand{
+			DashboardUID: "dash",
+			From:         "now-1h",
+			SignedInUser: usr,
+		})
+		require.NoError(t, err)
+		key = result.Key
+		require.Len(t, key, 32)
+		require.Equal(t, "Weekly report", result.Name)
+		require.Equal(t, "dash", result.DashboardUID)
+		require.Equal(t, "now-1h", result.TimeFrom)
+		require.Equal(t, "now", result.TimeTo)
+		require.Equal(t, int64(10), result.UserID)
+
+		// the hidden query is not run, and the panels in the collapsed row are queried
+		require.Len(t, queryService.requests, 2)
+		require.Len(t, queryService.requests[0].Queries, 1)
+		require.Equal(t, "up", queryService.requests[0].Queries[0].Get("expr").MustString())
+		require.Equal(t, "prom", queryService.requests[0].Queries[0].Get("datasource").Get("uid").MustString())
+		require.Equal(t, "loki", queryService.requests[1].Queries[0].Get("datasource").Get("uid").MustString())
+
+		snapshot, err := s.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: key})
+		require.NoError(t, err)
+		dash := snapshot.Dashboard
+
+		from, err := time.Parse(time.RFC3339Nano, dash.GetPath("time", "from").MustString())
+		require.NoError(t, err)
+		require.WithinDuration(t, before.Add(-time.Hour), from, time.Minute)
+		require.Equal(t, "/d/dash", dash.GetPath("snapshot", "originalUrl").MustString())
+		require.Empty(t, dash.Get("links").MustArray())
+
+		panel := dash.Get("panels").GetIndex(0)
+		require.Equal(t, "grafana", panel.Get("datasource").Get("uid").MustString())
+		require.Empty(t, panel.Get("links").MustArray())
+		target := panel.Get("targets").GetIndex(0)
+		require.Equal(t, "snapshot", target.Get("queryType").MustString())
+		frames := target.Get("snapshot").MustArray()
+		require.Len(t, frames, 1)
+		require.Equal(t, "A", target.Get("snapshot").GetIndex(0).GetPath("schema", "refId").MustString())
+		require.Equal(t, float64(1), target.Get("snapshot").GetIndex(0).GetPath("data", "values").GetIndex(0).GetIndex(0).MustFloat64())
+
+		require.Equal(t, "text", dash.Get("panels").GetIndex(1).Get("type").MustString())
+		require.Nil(t, dash.Get("panels").GetIndex(1).Get("targets").Interface())
+
+		rowPanel := dash.Get("panels").GetIndex(2).Get("panels").GetIndex(0)
+		require.Equal(t, "snapshot", rowPanel.Get("targets").GetIndex(0).Get("queryType").MustString())
+
+		annotations := dash.GetPath("annotations", "list").MustArray()
+		require.Len(t, annotations, 1)
+		require.Nil(t, dash.GetPath("annotations", "list").GetIndex(0).Get("target").Interface())
+
+		variable := dash.GetPath("templating", "list").GetIndex(0)
+		require.Equal(t, "", variable.Get("query").MustString())
+		require.Equal(t, 0, variable.Get("refresh").MustInt())
+		require.Len(t, variable.Get("options").MustArray(), 1)
+	})
+
+	t.Run("refresh should replace the data and keep the key", func(t *testing.T) {
+		queryService.value = 2
+		queryService.requests = nil
+
+		result, err := s.RefreshDashboardSnapshot(ctx, &dashboardsnapshots.RefreshDashboardSnapshotCommand{Key: key, SignedInUser: usr})
+		require.NoError(t, err)
+		require.Equal(t, key, result.Key)
+		require.Len(t, queryService.requests, 2)
+
+		target := result.Dashboard.Get("panels").GetIndex(0).Get("targets").GetIndex(0)
+		require.Equal(t, float64(2), target.Get("snapshot").GetIndex(0).GetPath("data", "values").GetIndex(0).GetIndex(0).MustFloat64())
+	})
+
+	t.Run("failed queries should fail the snapshot", func(t *testing.T) {
+		queryService.err = errors.New("boom")
+		t.Cleanup(func() { queryService.err = nil })
+
+		_, err := s.BuildDashboardSnapshot(ctx, &dashboardsnapshots.BuildDashboardSnapshotCommand{DashboardUID: "dash", SignedInUser: usr})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrPanelQueryFailed)
+	})
+
+	t.Run("invalid time range should fail", func(t *testing.T) {
+		_, err := s.BuildDashboardSnapshot(ctx, &dashboardsnapshots.BuildDashboardSnapshotCommand{DashboardUID: "dash", From: "now", To: "now-1h", SignedInUser: usr})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrInvalidTimeRange)
+	})
+
+	t.Run("snapshots that were created in the dashboard cannot be refreshed", func(t *testing.T) {
+		created, err := s.store.CreateDashboardSnapshot(ctx, &dashboardsnapshots.CreateDashboardSnapshotCommand{Key: "browser", DeleteKey: "browser-delete", OrgID: 1})
+		require.NoError(t, err)
+
+		_, err = s.RefreshDashboardSnapshot(ctx, &dashboardsnapshots.RefreshDashboardSnapshotCommand{Key: created.Key, SignedInUser: usr})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrCannotRefresh)
+	})
+}
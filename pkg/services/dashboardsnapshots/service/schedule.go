@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// CreateSnapshotSchedule validates the cron expression and time range of the schedule, and creates the schedule
+// with its first run.
+func (s *ServiceImpl) CreateSnapshotSchedule(ctx context.Context, cmd *dashboardsnapshots.CreateSnapshotScheduleCommand) (*dashboardsnapshots.DashboardSnapshotSchedule, error) {
+	schedule, err := cron.ParseStandard(cmd.Schedule)
+	if err != nil {
+		return nil, dashboardsnapshots.ErrInvalidSchedule.Errorf("invalid schedule %q: %w", cmd.Schedule, err)
+	}
+	if err := validateTimeRange(cmd.From, cmd.To); err != nil {
+		return nil, err
+	}
+	if err := s.ValidateDashboardExists(ctx, cmd.OrgID, cmd.DashboardUID); err != nil {
+		return nil, err
+	}
+
+	if cmd.UID == "" {
+		cmd.UID = util.GenerateShortUID()
+	}
+	cmd.NextRun = schedule.Next(time.Now())
+	return s.store.CreateSnapshotSchedule(ctx, cmd)
+}
+
+func (s *ServiceImpl) DeleteSnapshotSchedule(ctx context.Context, cmd *dashboardsnapshots.DeleteSnapshotScheduleCommand) error {
+	return s.store.DeleteSnapshotSchedule(ctx, cmd)
+}
+
+func (s *ServiceImpl) GetSnapshotSchedule(ctx context.Context, query *dashboardsnapshots.GetSnapshotScheduleQuery) (*dashboardsnapshots.DashboardSnapshotSchedule, error) {
+	return s.store.GetSnapshotSchedule(ctx, query)
+}
+
+func (s *ServiceImpl) SearchSnapshotSchedules(ctx context.Context, query *dashboardsnapshots.SearchSnapshotSchedulesQuery) ([]*dashboardsnapshots.DashboardSnapshotSchedule, error) {
+	return s.store.SearchSnapshotSchedules(ctx, query)
+}
+
+// validateTimeRange checks that the times can be parsed. Times that are not set are taken from the dashboard
+// when the snapshot is built.
+func validateTimeRange(from, to string) error {
+	if from != "" {
+		if _, err := gtime.NewTimeRange(from, defaultTimeTo).ParseFrom(); err != nil {
+			return dashboardsnapshots.ErrInvalidTimeRange.Errorf("invalid from %q: %w", from, err)
+		}
+	}
+	if to != "" {
+		if _, err := gtime.NewTimeRange(defaultTimeFrom, to).ParseTo(); err != nil {
+			return dashboardsnapshots.ErrInvalidTimeRange.Errorf("invalid to %q: %w", to, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	scheduleInterval   = time.Minute
+	scheduleLockAction = "dashboard snapshot schedules"
+)
+
+// ScheduleRunner builds the snapshots of the snapshot schedules that are due. The schedules are run by one instance
+// at a time, and the queries of a schedule are run as the user who created it, as long as the user can save the
+// dashboard.
+type ScheduleRunner struct {
+	cfg               *setting.Cfg
+	log               log.Logger
+	store             dashboardsnapshots.Store
+	service           *ServiceImpl
+	userService       user.Service
+	accessControl     accesscontrol.AccessControl
+	acService         accesscontrol.Service
+	serverLockService *serverlock.ServerLockService
+}
+
+func ProvideScheduleRunner(cfg *setting.Cfg, store dashboardsnapshots.Store, service *ServiceImpl, userService user.Service,
+	accessControl accesscontrol.AccessControl, acService accesscontrol.Service, serverLockService *serverlock.ServerLockService) *ScheduleRunner {
+	return &ScheduleRunner{
+		cfg:               cfg,
+		log:               log.New("dashboardsnapshots.schedules"),
+		store:             store,
+		service:           service,
+		userService:       userService,
+		accessControl:     accessControl,
+		acService:         acService,
+		serverLockService: serverLockService,
+	}
+}
+
+func (r *ScheduleRunner) IsDisabled() bool {
+	return !r.cfg.SnapshotEnabled
+}
+
+func (r *ScheduleRunner) Run(ctx context.Context) error {
+	ticker := time.NewTicker(scheduleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			// the lock expires before the next tick, so that a schedule is never skipped
+			err := r.serverLockService.LockAndExecute(ctx, scheduleLockAction, scheduleInterval-10*time.Second, r.runDueSchedules)
+			if err != nil {
+				r.log.Error("Failed to run snapshot schedules", "error", err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (r *ScheduleRunner) runDueSchedules(ctx context.Context) {
+	now := time.Now()
+	schedules, err := r.store.GetDueSnapshotSchedules(ctx, now)
+	if err != nil {
+		r.log.Error("Failed to get due snapshot schedules", "error", err)
+		return
+	}
+	for _, schedule := range schedules {
+		if ctx.Err() != nil {
+			return
+		}
+		r.runSchedule(ctx, schedule, now)
+	}
+}
+
+// runSchedule builds the snapshot of a schedule and records the result. Runs that were missed while no instance
+// was running are not made up for, the schedule runs once and continues with its next run after now.
+func (r *ScheduleRunner) runSchedule(ctx context.Context, schedule *dashboardsnapshots.DashboardSnapshotSchedule, now time.Time) {
+	logger := r.log.FromContext(ctx).New("scheduleUid", schedule.UID, "orgId", schedule.OrgID, "dashboardUid", schedule.DashboardUID)
+	cmd := &dashboardsnapshots.UpdateSnapshotScheduleRunCommand{
+		ID:              schedule.ID,
+		LastSnapshotKey: schedule.LastSnapshotKey,
+	}
+
+	cronSchedule, parseErr := cron.ParseStandard(schedule.Schedule)
+	if parseErr != nil {
+		// schedules are validated when they are created, retry once a day in case the time zone is not available
+		cmd.NextRun = now.Add(24 * time.Hour)
+	} else {
+		cmd.NextRun = cronSchedule.Next(now)
+	}
+
+	// the lock of the runner can expire during a long run, so the run is claimed before the snapshot is built
+	claimed, err := r.store.ClaimSnapshotScheduleRun(ctx, &dashboardsnapshots.ClaimSnapshotScheduleRunCommand{
+		ID:      schedule.ID,
+		Now:     now,
+		NextRun: cmd.NextRun,
+	})
+	if err != nil {
+		logger.Error("Failed to claim snapshot schedule", "error", err)
+		return
+	}
+	if !claimed {
+		logger.Debug("Snapshot schedule was run by another instance")
+		return
+	}
+
+	if parseErr != nil {
+		logger.Error("Invalid snapshot schedule", "schedule", schedule.Schedule, "error", parseErr)
+		cmd.LastError = parseErr.Error()
+	} else {
+		snapshot, err := r.buildSnapshot(ctx, schedule)
+		if err != nil {
+			logger.Error("Failed to build scheduled snapshot", "error", err)
+			cmd.LastError = err.Error()
+		} else {
+			logger.Info("Built scheduled snapshot", "key", snapshot.Key)
+			cmd.LastSnapshotKey = snapshot.Key
+		}
+	}
+
+	if err := r.store.UpdateSnapshotScheduleRun(ctx, cmd); err != nil {
+		logger.Error("Failed to update snapshot schedule", "error", err)
+	}
+}
+
+func (r *ScheduleRunner) buildSnapshot(ctx context.Context, schedule *dashboardsnapshots.DashboardSnapshotSchedule) (*dashboardsnapshots.DashboardSnapshot, error) {
+	usr, err := r.userService.GetSignedInUser(ctx, &user.GetSignedInUserQuery{UserID: schedule.UserID, OrgID: schedule.OrgID})
+	if err != nil {
+		return nil, err
+	}
+	if usr.Permissions == nil {
+		usr.Permissions = make(map[int64]map[string][]string)
+	}
+	if _, ok := usr.Permissions[schedule.OrgID]; !ok {
+		permissions, err := r.acService.GetUserPermissions(ctx, usr, accesscontrol.Options{ReloadCache: false})
+		if err != nil {
+			return nil, err
+		}
+		usr.Permissions[schedule.OrgID] = accesscontrol.GroupScopesByActionContext(ctx, permissions)
+	}
+
+	// the permissions of the user can change after the schedule was created with them
+	evaluator := accesscontrol.EvalPermission(dashboards.ActionDashboardsWrite, dashboards.ScopeDashboardsProvider.GetResourceScopeUID(schedule.DashboardUID))
+	canSave, err := r.accessControl.Evaluate(ctx, usr, evaluator)
+	if err != nil {
+		return nil, err
+	}
+	if !canSave {
+		return nil, dashboardsnapshots.ErrScheduleForbidden.Errorf("user %d cannot save dashboard %s", schedule.UserID, schedule.DashboardUID)
+	}
+
+	return r.service.BuildDashboardSnapshot(ctx, &dashboardsnapshots.BuildDashboardSnapshotCommand{
+		DashboardUID: schedule.DashboardUID,
+		Name:         schedule.Name,
+		From:         schedule.TimeFrom,
+		To:           schedule.TimeTo,
+		Expires:      schedule.Expires,
+		SignedInUser: usr,
+	})
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestCreateSnapshotSchedule(t *testing.T) {
+	s, _ := setupBuildTest(t)
+	ctx := context.Background()
+
+	t.Run("should create the schedule with its next run", func(t *testing.T) {
+		schedule, err := s.CreateSnapshotSchedule(ctx, &dashboardsnapshots.CreateSnapshotScheduleCommand{
+			DashboardUID: "dash",
+			Schedule:     "CRON_TZ=UTC 0 9 * * 1",
+			From:         "now-7d",
+			OrgID:        1,
+			UserID:       10,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, schedule.UID)
+		require.Equal(t, time.Monday, schedule.NextRun.UTC().Weekday())
+		require.Equal(t, 9, schedule.NextRun.UTC().Hour())
+		require.True(t, schedule.NextRun.After(time.Now()))
+	})
+
+	t.Run("should not create a schedule with an invalid cron expression", func(t *testing.T) {
+		_, err := s.CreateSnapshotSchedule(ctx, &dashboardsnapshots.CreateSnapshotScheduleCommand{DashboardUID: "dash", Schedule: "every monday", OrgID: 1})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrInvalidSchedule)
+	})
+
+	t.Run("should not create a schedule with an invalid time range", func(t *testing.T) {
+		_, err := s.CreateSnapshotSchedule(ctx, &dashboardsnapshots.CreateSnapshotScheduleCommand{DashboardUID: "dash", Schedule: "@weekly", From: "last week", OrgID: 1})
+		require.ErrorIs(t, err, dashboardsnapshots.ErrInvalidTimeRange)
+	})
+
+	t.Run("should not create a schedule of a dashboard that does not exist", func(t *testing.T) {
+		_, err := s.CreateSnapshotSchedule(ctx, &dashboardsnapshots.CreateSnapshotScheduleCommand{DashboardUID: "missing", Schedule: "@weekly", OrgID: 1})
+		require.ErrorIs(t, err, dashboards.ErrDashboardNotFound)
+	})
+}
+
+func TestScheduleRunner(t *testing.T) {
+	s, queryService := setupBuildTest(t)
+	ctx := context.Background()
+	userService := &usertest.FakeUserService{ExpectedSignedInUser: &user.SignedInUser{UserID: 10, OrgID: 1}}
+	runner := ProvideScheduleRunner(setting.NewCfg(), s.store, s, userService, actest.FakeAccessControl{ExpectedEvaluate: true}, actest.FakeService{}, nil)
+
+	// makeDue sets the next run of the schedule to the past and returns the schedule as it is read by the runner
+	makeDue := func(t *testing.T, uid string) *dashboardsnapshots.DashboardSnapshotSchedule {
+		t.Helper()
+		previous, err := s.GetSnapshotSchedule(ctx, &dashboardsnapshots.GetSnapshotScheduleQuery{UID: uid, OrgID: 1})
+		require.NoError(t, err)
+		err = s.store.UpdateSnapshotScheduleRun(ctx, &dashboardsnapshots.UpdateSnapshotScheduleRunCommand{
+			ID:              previous.ID,
+			NextRun:         time.Now().Add(-time.Minute),
+			LastSnapshotKey: previous.LastSnapshotKey,
+		})
+		require.NoError(t, err)
+		due, err := s.GetSnapshotSchedule(ctx, &dashboardsnapshots.GetSnapshotScheduleQuery{UID: uid, OrgID: 1})
+		require.NoError(t, err)
+		return due
+	}
+
+	schedule, err := s.store.CreateSnapshotSchedule(ctx, &dashboardsnapshots.CreateSnapshotScheduleCommand{
+		UID:          "weekly",
+		DashboardUID: "dash",
+		Schedule:     "@weekly",
+		OrgID:        1,
+		UserID:       10,
+		NextRun:      time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+	_, err = s.store.CreateSnapshotSchedule(ctx, &dashboardsnapshots.CreateSnapshotScheduleCommand{
+		UID:          "later",
+		DashboardUID: "dash",
+		Schedule:     "@weekly",
+		OrgID:        1,
+		UserID:       10,
+		NextRun:      time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	t.Run("should build the snapshots of due schedules", func(t *testing.T) {
+		runner.runDueSchedules(ctx)
+
+		require.Len(t, queryService.requests, 2)
+		updated, err := s.GetSnapshotSchedule(ctx, &dashboardsnapshots.GetSnapshotScheduleQuery{UID: schedule.UID, OrgID: 1})
+		require.NoError(t, err)
+		require.NotEmpty(t, updated.LastSnapshotKey)
+		require.Empty(t, updated.LastError)
+		require.True(t, updated.NextRun.After(time.Now()))
+
+		snapshot, err := s.GetDashboardSnapshot(ctx, &dashboardsnapshots.GetDashboardSnapshotQuery{Key: updated.LastSnapshotKey})
+		require.NoError(t, err)
+		require.Equal(t, int64(10), snapshot.UserID)
+		require.Equal(t, "dash", snapshot.DashboardUID)
+	})
+
+	t.Run("should record errors and keep the last snapshot", func(t *testing.T) {
+		previous := makeDue(t, schedule.UID)
+		queryService.err = errors.New("boom")
+		t.Cleanup(func() { queryService.err = nil })
+
+		runner.runSchedule(ctx, previous, time.Now())
+
+		updated, err := s.GetSnapshotSchedule(ctx, &dashboardsnapshots.GetSnapshotScheduleQuery{UID: schedule.UID, OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, previous.LastSnapshotKey, updated.LastSnapshotKey)
+		require.Contains(t, updated.LastError, "boom")
+	})
+
+	t.Run("should not run a schedule that was claimed by another instance", func(t *testing.T) {
+		due := makeDue(t, schedule.UID)
+		now := time.Now()
+		runner.runSchedule(ctx, due, now)
+		requests := len(queryService.requests)
+		ran, err := s.GetSnapshotSchedule(ctx, &dashboardsnapshots.GetSnapshotScheduleQuery{UID: schedule.UID, OrgID: 1})
+		require.NoError(t, err)
+
+		// another instance read the schedule before it was claimed
+		runner.runSchedule(ctx, due, now)
+
+		require.Len(t, queryService.requests, requests)
+		updated, err := s.GetSnapshotSchedule(ctx, &dashboardsnapshots.GetSnapshotScheduleQuery{UID: schedule.UID, OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, ran.LastSnapshotKey, updated.LastSnapshotKey)
+	})
+
+	t.Run("should not build the snapshot if the user cannot save the dashboard anymore", func(t *testing.T) {
+		denied := ProvideScheduleRunner(setting.NewCfg(), s.store, s, userService, actest.FakeAccessControl{ExpectedEvaluate: false}, actest.FakeService{}, nil)
+		previous := makeDue(t, schedule.UID)
+		requests := len(queryService.requests)
+
+		denied.runSchedule(ctx, previous, time.Now())
+
+		require.Len(t, queryService.requests, requests)
+		updated, err := s.GetSnapshotSchedule(ctx, &dashboardsnapshots.GetSnapshotScheduleQuery{UID: schedule.UID, OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, previous.LastSnapshotKey, updated.LastSnapshotKey)
+		require.Contains(t, updated.LastError, "cannot save dashboard dash")
+		require.True(t, updated.NextRun.After(time.Now()))
+	})
+}
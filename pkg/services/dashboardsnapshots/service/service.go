@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/query"
 	"github.com/grafana/grafana/pkg/services/secrets"
 )
 
@@ -13,16 +14,18 @@ type ServiceImpl struct {
 	store            dashboardsnapshots.Store
 	secretsService   secrets.Service
 	dashboardService dashboards.DashboardService
+	queryDataService query.Service
 }
 
 // ServiceImpl implements the dashboardsnapshots Service interface
 var _ dashboardsnapshots.Service = (*ServiceImpl)(nil)
 
-func ProvideService(store dashboardsnapshots.Store, secretsService secrets.Service, dashboardService dashboards.DashboardService) *ServiceImpl {
+func ProvideService(store dashboardsnapshots.Store, secretsService secrets.Service, dashboardService dashboards.DashboardService, queryDataService query.Service) *ServiceImpl {
 	s := &ServiceImpl{
 		store:            store,
 		secretsService:   secretsService,
 		dashboardService: dashboardService,
+		queryDataService: queryDataService,
 	}
 
 	return s
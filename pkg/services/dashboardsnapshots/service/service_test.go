@@ -39,7 +39,7 @@ func TestDashboardSnapshotsService(t *testing.T) {
 	dsStore := dashsnapdb.ProvideStore(sqlStore, cfg)
 	fakeDashboardService := &dashboards.FakeDashboardService{}
 	secretsService := secretsManager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
-	s := ProvideService(dsStore, secretsService, fakeDashboardService)
+	s := ProvideService(dsStore, secretsService, fakeDashboardService, &fakeQueryService{})
 
 	origSecret := cfg.SecretKey
 	cfg.SecretKey = "dashboard_snapshot_service_test"
@@ -101,7 +101,7 @@ func TestValidateDashboardExists(t *testing.T) {
 	require.NoError(t, err)
 	dashSvc, err := dashsvc.ProvideDashboardServiceImpl(cfg, dashboardStore, folderimpl.ProvideDashboardFolderStore(sqlStore), nil, nil, nil, acmock.New(), foldertest.NewFakeService(), folder.NewFakeStore(), nil)
 	require.NoError(t, err)
-	s := ProvideService(dsStore, secretsService, dashSvc)
+	s := ProvideService(dsStore, secretsService, dashSvc, &fakeQueryService{})
 	ctx := context.Background()
 
 	t.Run("returns false when dashboard does not exist", func(t *testing.T) {
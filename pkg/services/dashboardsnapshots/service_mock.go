@@ -13,6 +13,32 @@ type MockService struct {
 	mock.Mock
 }
 
+// BuildDashboardSnapshot provides a mock function with given fields: _a0, _a1
+func (_m *MockService) BuildDashboardSnapshot(_a0 context.Context, _a1 *BuildDashboardSnapshotCommand) (*DashboardSnapshot, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *DashboardSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *BuildDashboardSnapshotCommand) (*DashboardSnapshot, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *BuildDashboardSnapshotCommand) *DashboardSnapshot); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DashboardSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *BuildDashboardSnapshotCommand) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateDashboardSnapshot provides a mock function with given fields: _a0, _a1
 func (_m *MockService) CreateDashboardSnapshot(_a0 context.Context, _a1 *CreateDashboardSnapshotCommand) (*DashboardSnapshot, error) {
 	ret := _m.Called(_a0, _a1)
@@ -39,6 +65,32 @@ func (_m *MockService) CreateDashboardSnapshot(_a0 context.Context, _a1 *CreateD
 	return r0, r1
 }
 
+// CreateSnapshotSchedule provides a mock function with given fields: _a0, _a1
+func (_m *MockService) CreateSnapshotSchedule(_a0 context.Context, _a1 *CreateSnapshotScheduleCommand) (*DashboardSnapshotSchedule, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *DashboardSnapshotSchedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *CreateSnapshotScheduleCommand) (*DashboardSnapshotSchedule, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *CreateSnapshotScheduleCommand) *DashboardSnapshotSchedule); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DashboardSnapshotSchedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *CreateSnapshotScheduleCommand) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteDashboardSnapshot provides a mock function with given fields: _a0, _a1
 func (_m *MockService) DeleteDashboardSnapshot(_a0 context.Context, _a1 *DeleteDashboardSnapshotCommand) error {
 	ret := _m.Called(_a0, _a1)
@@ -67,6 +119,20 @@ func (_m *MockService) DeleteExpiredSnapshots(_a0 context.Context, _a1 *DeleteEx
 	return r0
 }
 
+// DeleteSnapshotSchedule provides a mock function with given fields: _a0, _a1
+func (_m *MockService) DeleteSnapshotSchedule(_a0 context.Context, _a1 *DeleteSnapshotScheduleCommand) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *DeleteSnapshotScheduleCommand) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetDashboardSnapshot provides a mock function with given fields: _a0, _a1
 func (_m *MockService) GetDashboardSnapshot(_a0 context.Context, _a1 *GetDashboardSnapshotQuery) (*DashboardSnapshot, error) {
 	ret := _m.Called(_a0, _a1)
@@ -93,6 +159,58 @@ func (_m *MockService) GetDashboardSnapshot(_a0 context.Context, _a1 *GetDashboa
 	return r0, r1
 }
 
+// GetSnapshotSchedule provides a mock function with given fields: _a0, _a1
+func (_m *MockService) GetSnapshotSchedule(_a0 context.Context, _a1 *GetSnapshotScheduleQuery) (*DashboardSnapshotSchedule, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *DashboardSnapshotSchedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *GetSnapshotScheduleQuery) (*DashboardSnapshotSchedule, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *GetSnapshotScheduleQuery) *DashboardSnapshotSchedule); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DashboardSnapshotSchedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *GetSnapshotScheduleQuery) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshDashboardSnapshot provides a mock function with given fields: _a0, _a1
+func (_m *MockService) RefreshDashboardSnapshot(_a0 context.Context, _a1 *RefreshDashboardSnapshotCommand) (*DashboardSnapshot, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *DashboardSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *RefreshDashboardSnapshotCommand) (*DashboardSnapshot, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *RefreshDashboardSnapshotCommand) *DashboardSnapshot); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*DashboardSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *RefreshDashboardSnapshotCommand) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SearchDashboardSnapshots provides a mock function with given fields: _a0, _a1
 func (_m *MockService) SearchDashboardSnapshots(_a0 context.Context, _a1 *GetDashboardSnapshotsQuery) (DashboardSnapshotsList, error) {
 	ret := _m.Called(_a0, _a1)
@@ -119,6 +237,32 @@ func (_m *MockService) SearchDashboardSnapshots(_a0 context.Context, _a1 *GetDas
 	return r0, r1
 }
 
+// SearchSnapshotSchedules provides a mock function with given fields: _a0, _a1
+func (_m *MockService) SearchSnapshotSchedules(_a0 context.Context, _a1 *SearchSnapshotSchedulesQuery) ([]*DashboardSnapshotSchedule, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []*DashboardSnapshotSchedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *SearchSnapshotSchedulesQuery) ([]*DashboardSnapshotSchedule, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *SearchSnapshotSchedulesQuery) []*DashboardSnapshotSchedule); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*DashboardSnapshotSchedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *SearchSnapshotSchedulesQuery) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ValidateDashboardExists provides a mock function with given fields: _a0, _a1, _a2
 func (_m *MockService) ValidateDashboardExists(_a0 context.Context, _a1 int64, _a2 string) error {
 	ret := _m.Called(_a0, _a1, _a2)
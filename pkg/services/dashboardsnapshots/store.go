@@ -2,6 +2,7 @@ package dashboardsnapshots
 
 import (
 	"context"
+	"time"
 )
 
 type Store interface {
@@ -10,4 +11,16 @@ type Store interface {
 	DeleteExpiredSnapshots(context.Context, *DeleteExpiredSnapshotsCommand) error
 	GetDashboardSnapshot(context.Context, *GetDashboardSnapshotQuery) (*DashboardSnapshot, error)
 	SearchDashboardSnapshots(context.Context, *GetDashboardSnapshotsQuery) (DashboardSnapshotsList, error)
+	UpdateDashboardSnapshot(context.Context, *UpdateDashboardSnapshotCommand) error
+
+	CreateSnapshotSchedule(context.Context, *CreateSnapshotScheduleCommand) (*DashboardSnapshotSchedule, error)
+	DeleteSnapshotSchedule(context.Context, *DeleteSnapshotScheduleCommand) error
+	GetSnapshotSchedule(context.Context, *GetSnapshotScheduleQuery) (*DashboardSnapshotSchedule, error)
+	SearchSnapshotSchedules(context.Context, *SearchSnapshotSchedulesQuery) ([]*DashboardSnapshotSchedule, error)
+	// GetDueSnapshotSchedules returns the schedules of all organizations whose next run is not after now.
+	GetDueSnapshotSchedules(ctx context.Context, now time.Time) ([]*DashboardSnapshotSchedule, error)
+	// ClaimSnapshotScheduleRun sets the next run of a schedule that is due and returns false if the schedule is not
+	// due anymore, because its run was claimed by another instance.
+	ClaimSnapshotScheduleRun(context.Context, *ClaimSnapshotScheduleRunCommand) (bool, error)
+	UpdateSnapshotScheduleRun(context.Context, *UpdateSnapshotScheduleRunCommand) error
 }
@@ -489,6 +489,9 @@ func toRuleGroup(log log.Logger, manager state.AlertInstanceManager, sr StatusRe
 			if alertState.Error != nil && rule.ExecErrState != ngmodels.ErrorErrState {
 				totals["error"] += 1
 			}
+			if alertState.StateReason == ngmodels.StateReasonSuppressed {
+				totals["suppressed"] += 1
+			}
 			alert := apimodels.Alert{
 				Labels:      apimodels.LabelsFromMap(alertState.GetLabels(labelOptions...)),
 				Annotations: apimodels.LabelsFromMap(alertState.Annotations),
@@ -530,6 +533,9 @@ func toRuleGroup(log log.Logger, manager state.AlertInstanceManager, sr StatusRe
 			if alertState.Error != nil && rule.ExecErrState != ngmodels.ErrorErrState {
 				totalsFiltered["error"] += 1
 			}
+			if alertState.StateReason == ngmodels.StateReasonSuppressed {
+				totalsFiltered["suppressed"] += 1
+			}
 
 			alertingRule.Alerts = append(alertingRule.Alerts, alert)
 		}
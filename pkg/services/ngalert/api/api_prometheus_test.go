@@ -265,6 +265,13 @@ func withErrorState() forEachState {
 	}
 }
 
+func withSuppressedState() forEachState {
+	return func(s *state.State) *state.State {
+		s.SetNormal(ngmodels.StateReasonSuppressed, timeNow(), timeNow())
+		return s
+	}
+}
+
 func withLabels(labels data.Labels) forEachState {
 	return func(s *state.State) *state.State {
 		for k, v := range labels {
@@ -765,6 +772,37 @@ func TestRouteGetRuleStatuses(t *testing.T) {
 		require.Len(t, r3.Alerts, 1)
 	})
 
+	t.Run("test totals count suppressed alerts", func(t *testing.T) {
+		fakeStore, fakeAIM, api := setupAPI(t)
+		rule := gen.With(gen.WithOrgID(orgID)).GenerateRef()
+		fakeStore.PutRule(context.Background(), rule)
+
+		fakeAIM.GenerateAlertInstances(orgID, rule.UID, 1)
+		fakeAIM.GenerateAlertInstances(orgID, rule.UID, 1, withSuppressedState())
+
+		r, err := http.NewRequest("GET", "/api/v1/rules", nil)
+		require.NoError(t, err)
+		c := &contextmodel.ReqContext{
+			Context: &web.Context{Req: r},
+			SignedInUser: &user.SignedInUser{
+				OrgID:       orgID,
+				Permissions: queryPermissions,
+			},
+		}
+		resp := api.RouteGetRuleStatuses(c)
+		require.Equal(t, http.StatusOK, resp.Status())
+		var res apimodels.RuleResponse
+		require.NoError(t, json.Unmarshal(resp.Body(), &res))
+
+		require.Len(t, res.Data.RuleGroups, 1)
+		require.Len(t, res.Data.RuleGroups[0].Rules, 1)
+		r1 := res.Data.RuleGroups[0].Rules[0]
+		require.Equal(t, "inactive", r1.State)
+		require.Equal(t, map[string]int64{"normal": 2, "suppressed": 1}, r1.Totals)
+		require.Equal(t, map[string]int64{"normal": 2, "suppressed": 1}, r1.TotalsFiltered)
+		require.ElementsMatch(t, []string{"Normal", "Normal (Suppressed)"}, []string{r1.Alerts[0].State, r1.Alerts[1].State})
+	})
+
 	t.Run("test time of first firing alert", func(t *testing.T) {
 		fakeStore, fakeAIM, api := setupAPI(t)
 		// Create rules in the same Rule Group to keep assertions simple
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/folder/folderimpl"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/hcl"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// ruleGroupResourceType is the type of the Terraform resource of rule groups, the same as in the HCL export.
+const ruleGroupResourceType = "grafana_rule_group"
+
+// RoutePostAlertRulesImport imports rule groups in the format of the export, either provisioning file format or HCL.
+// The format is determined by the Content-Type header. Groups replace the groups with the same name in the folder.
+// If query parameter dryRun is true, the changes are calculated and verified but not applied.
+// The response contains the changes of every group that has any.
+func (srv *ProvisioningSrv) RoutePostAlertRulesImport(c *contextmodel.ReqContext) response.Response {
+	body, err := io.ReadAll(c.Req.Body)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to read the request body")
+	}
+	file, err := parseAlertRulesImport(c.Req.Header.Get("Content-Type"), body)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse the file")
+	}
+	if len(file.ContactPoints) > 0 || len(file.Policies) > 0 || len(file.MuteTimings) > 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("only rule groups can be imported"), "")
+	}
+	if len(file.Groups) == 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("file does not contain rule groups"), "")
+	}
+
+	folders := make(map[string]string)
+	groups := make([]alerting_models.AlertRuleGroup, 0, len(file.Groups))
+	for _, g := range file.Groups {
+		group, err := AlertRuleGroupFromAlertRuleGroupExport(g)
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, err, "rule group '%s' failed to parse", g.Name)
+		}
+		if group.FolderUID == "" {
+			uid, ok := folders[g.Folder]
+			if !ok {
+				uid, err = srv.getFolderUIDByFullpath(c, g.Folder)
+				if err != nil {
+					return ErrResp(http.StatusBadRequest, err, "rule group '%s'", g.Name)
+				}
+				folders[g.Folder] = uid
+			}
+			group.FolderUID = uid
+			for i := range group.Rules {
+				group.Rules[i].NamespaceUID = uid
+			}
+		}
+		groups = append(groups, group)
+	}
+
+	dryRun := c.QueryBool("dryRun")
+	provenance := determineProvenance(c)
+	deltas, err := srv.alertRules.ImportRuleGroups(c.Req.Context(), c.SignedInUser, groups, alerting_models.Provenance(provenance), dryRun)
+	if errors.Is(err, alerting_models.ErrAlertRuleUniqueConstraintViolation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, store.ErrOptimisticLock) {
+		return ErrResp(http.StatusConflict, err, "")
+	}
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to import alert rules", err)
+	}
+
+	result := definitions.AlertRulesImportResponse{
+		DryRun: dryRun,
+		Groups: make([]definitions.AlertRuleGroupImportChanges, 0, len(deltas)),
+	}
+	for _, delta := range deltas {
+		changes := alertRuleGroupImportChangesFromDelta(delta)
+		if len(changes.Created)+len(changes.Updated)+len(changes.Deleted) == 0 {
+			continue
+		}
+		result.Groups = append(result.Groups, changes)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// parseAlertRulesImport parses the file as HCL if the content type is HCL, and as YAML otherwise, which includes JSON.
+func parseAlertRulesImport(contentType string, body []byte) (definitions.AlertingFileExport, error) {
+	if !strings.Contains(contentType, "hcl") {
+		var file definitions.AlertingFileExport
+		decoder := yaml.NewDecoder(bytes.NewReader(body))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&file); err != nil {
+			return definitions.AlertingFileExport{}, err
+		}
+		return file, nil
+	}
+
+	resources, err := hcl.Decode(body, "import.tf", map[string]func() any{
+		ruleGroupResourceType: func() any { return &definitions.AlertRuleGroupExport{} },
+	})
+	if err != nil {
+		return definitions.AlertingFileExport{}, err
+	}
+	file := definitions.AlertingFileExport{APIVersion: 1}
+	for _, resource := range resources {
+		group := resource.Body.(*definitions.AlertRuleGroupExport)
+		if group.FolderUID == "" {
+			return definitions.AlertingFileExport{}, fmt.Errorf("resource %s.%s: folder_uid must be set", resource.Type, resource.Name)
+		}
+		file.Groups = append(file.Groups, *group)
+	}
+	return file, nil
+}
+
+// getFolderUIDByFullpath returns the UID of the folder with the full path, as in the export. The folder must exist.
+func (srv *ProvisioningSrv) getFolderUIDByFullpath(c *contextmodel.ReqContext, fullpath string) (string, error) {
+	titles := folderimpl.SplitFullpath(fullpath)
+	if len(titles) == 0 {
+		return "", fmt.Errorf("%w: folder must be set", alerting_models.ErrAlertRuleFailedValidation)
+	}
+	var parentUID *string
+	for _, title := range titles {
+		f, err := srv.folderSvc.Get(c.Req.Context(), &folder.GetFolderQuery{
+			Title:        &title,
+			ParentUID:    parentUID,
+			OrgID:        c.SignedInUser.GetOrgID(),
+			SignedInUser: c.SignedInUser,
+		})
+		if errors.Is(err, dashboards.ErrFolderNotFound) {
+			return "", fmt.Errorf("%w: folder '%s' does not exist", alerting_models.ErrAlertRuleFailedValidation, fullpath)
+		}
+		if err != nil {
+			return "", err
+		}
+		parentUID = &f.UID
+	}
+	return *parentUID, nil
+}
+
+func alertRuleGroupImportChangesFromDelta(delta *store.GroupDelta) definitions.AlertRuleGroupImportChanges {
+	result := definitions.AlertRuleGroupImportChanges{
+		FolderUID: delta.GroupKey.NamespaceUID,
+		Title:     delta.GroupKey.RuleGroup,
+		Created:   make([]definitions.AlertRuleImportChange, 0, len(delta.New)),
+		Updated:   make([]definitions.AlertRuleImportChange, 0, len(delta.Update)),
+		Deleted:   make([]definitions.AlertRuleImportChange, 0, len(delta.Delete)),
+	}
+	for _, rule := range delta.New {
+		result.Created = append(result.Created, definitions.AlertRuleImportChange{UID: rule.UID, Title: rule.Title})
+	}
+	for _, update := range delta.Update {
+		// Rules that are updated only because of the recalculation of the fields of the group are not reported.
+		if len(update.Diff) == 0 {
+			continue
+		}
+		result.Updated = append(result.Updated, definitions.AlertRuleImportChange{
+			UID:    update.Existing.UID,
+			Title:  update.New.Title,
+			Fields: update.Diff.Paths(),
+		})
+	}
+	for _, rule := range delta.Delete {
+		result.Deleted = append(result.Deleted, definitions.AlertRuleImportChange{UID: rule.UID, Title: rule.Title})
+	}
+	return result
+}
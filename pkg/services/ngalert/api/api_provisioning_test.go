@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -1475,6 +1476,141 @@ func TestProvisioningApi(t *testing.T) {
 	})
 }
 
+func TestProvisioningApiAlertRulesImport(t *testing.T) {
+	export := func(t *testing.T, sut ProvisioningSrv, format string) string {
+		t.Helper()
+		rc := createTestRequestCtx()
+		rc.Context.Req.Form.Set("format", format)
+		response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+		require.Equal(t, 200, response.Status())
+		return string(response.Body())
+	}
+	importRules := func(t *testing.T, sut ProvisioningSrv, contentType string, body string, dryRun bool) (int, definitions.AlertRulesImportResponse) {
+		t.Helper()
+		rc := createTestRequestCtx()
+		rc.Context.Req.Header.Set("Content-Type", contentType)
+		rc.Context.Req.Body = io.NopCloser(strings.NewReader(body))
+		rc.Context.Req.Form.Set("dryRun", fmt.Sprintf("%t", dryRun))
+		response := sut.RoutePostAlertRulesImport(&rc)
+		var result definitions.AlertRulesImportResponse
+		if response.Status() == 200 {
+			require.NoError(t, json.Unmarshal(response.Body(), &result))
+		}
+		return response.Status(), result
+	}
+	// Durations of the test rule are too short to survive the export.
+	createRule := func(title string) definitions.ProvisionedAlertRule {
+		rule := createTestAlertRule(title, 1)
+		rule.For = model.Duration(time.Minute)
+		rule.Data[0].RelativeTimeRange.From = definitions.Duration(10 * time.Minute)
+		return rule
+	}
+
+	t.Run("yaml export with changes, POST returns 200 and updates the rule", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		insertRule(t, sut, createRule("rule"))
+		file := strings.Replace(export(t, sut, "yaml"), "title: rule", "title: renamed rule", 1)
+
+		status, result := importRules(t, sut, "application/yaml", file, false)
+
+		require.Equal(t, 200, status)
+		require.False(t, result.DryRun)
+		require.Len(t, result.Groups, 1)
+		require.Equal(t, "folder-uid", result.Groups[0].FolderUID)
+		require.Equal(t, "my-cool-group", result.Groups[0].Title)
+		require.Empty(t, result.Groups[0].Created)
+		require.Empty(t, result.Groups[0].Deleted)
+		require.Len(t, result.Groups[0].Updated, 1)
+		require.Equal(t, "rule", result.Groups[0].Updated[0].UID)
+		require.Equal(t, []string{"Title"}, result.Groups[0].Updated[0].Fields)
+
+		rc := createTestRequestCtx()
+		response := sut.RouteRouteGetAlertRule(&rc, "rule")
+		require.Equal(t, 200, response.Status())
+		require.Equal(t, "renamed rule", deserializeRule(t, response.Body()).Title)
+	})
+
+	t.Run("unchanged json export, POST returns 200 without changes", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		insertRule(t, sut, createRule("rule"))
+
+		status, result := importRules(t, sut, "application/json", export(t, sut, "json"), false)
+
+		require.Equal(t, 200, status)
+		require.Empty(t, result.Groups)
+	})
+
+	t.Run("hcl export with changes and dry run, POST returns 200 and does not apply changes", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		insertRule(t, sut, createRule("rule"))
+		file := export(t, sut, "hcl")
+		require.Contains(t, file, `is_paused      = false`)
+		file = strings.Replace(file, `is_paused      = false`, `is_paused      = true`, 1)
+
+		status, result := importRules(t, sut, "text/hcl", file, true)
+
+		require.Equal(t, 200, status)
+		require.True(t, result.DryRun)
+		require.Len(t, result.Groups, 1)
+		require.Len(t, result.Groups[0].Updated, 1)
+		require.Equal(t, "rule", result.Groups[0].Updated[0].UID)
+		require.Equal(t, []string{"IsPaused"}, result.Groups[0].Updated[0].Fields)
+
+		rc := createTestRequestCtx()
+		response := sut.RouteRouteGetAlertRule(&rc, "rule")
+		require.Equal(t, 200, response.Status())
+		require.False(t, deserializeRule(t, response.Body()).IsPaused)
+	})
+
+	t.Run("new group in yaml, POST returns 200 and creates the rules", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		insertRule(t, sut, createRule("rule"))
+		file := strings.Replace(export(t, sut, "yaml"), "my-cool-group", "new-group", 1)
+		file = strings.Replace(file, "uid: rule", "uid: new-rule", 1)
+		file = strings.Replace(file, "title: rule", "title: new rule", 1)
+
+		status, result := importRules(t, sut, "application/yaml", file, false)
+
+		require.Equal(t, 200, status)
+		require.Len(t, result.Groups, 1)
+		require.Equal(t, "new-group", result.Groups[0].Title)
+		require.Len(t, result.Groups[0].Created, 1)
+		require.Equal(t, "new-rule", result.Groups[0].Created[0].UID)
+	})
+
+	t.Run("folder does not exist, POST returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		insertRule(t, sut, createRule("rule"))
+		file := strings.Replace(export(t, sut, "yaml"), "folder: Folder Title", "folder: Unknown Folder", 1)
+
+		status, _ := importRules(t, sut, "application/yaml", file, false)
+
+		require.Equal(t, 400, status)
+	})
+
+	t.Run("file contains contact points, POST returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		file := `
+apiVersion: 1
+contactPoints:
+  - orgId: 1
+    name: cp
+`
+
+		status, _ := importRules(t, sut, "application/yaml", file, false)
+
+		require.Equal(t, 400, status)
+	})
+
+	t.Run("invalid hcl, POST returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+
+		status, _ := importRules(t, sut, "text/hcl", `resource "grafana_rule_group" {`, false)
+
+		require.Equal(t, 400, status)
+	})
+}
+
 func TestProvisioningApiContactPointExport(t *testing.T) {
 	createTestEnv := func(t *testing.T, testConfig string) testEnvironment {
 		env := createTestEnv(t, testConfig)
@@ -0,0 +1,307 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"sort"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	authz "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// RouteBulkUpdateRules applies the action of the command to all rules that match its selector and the user is authorized to access.
+// Every affected rule group is updated in the same way as by RoutePostNameRulesConfig and all groups are updated in a single transaction,
+// so the request fails without changes if the user is not authorized to change any of the groups or any of the groups is provisioned.
+// If the command is a dry run, the changes are calculated and verified but not stored.
+func (srv RulerSrv) RouteBulkUpdateRules(c *contextmodel.ReqContext, cmd apimodels.PostableBulkRulesCommand) response.Response {
+	if err := validateBulkRulesCommand(cmd); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	var targetFolderUID string
+	if cmd.Action == apimodels.BulkRulesActionMove {
+		namespace, err := srv.store.GetNamespaceByUID(c.Req.Context(), cmd.TargetFolderUID, c.SignedInUser.GetOrgID(), c.SignedInUser)
+		if err != nil {
+			return toNamespaceErrorResponse(err)
+		}
+		targetFolderUID = namespace.UID
+	}
+
+	result := apimodels.BulkRulesResponse{
+		DryRun:  cmd.DryRun,
+		Updated: make([]string, 0),
+		Deleted: make([]string, 0),
+	}
+	var dbConfig *ngmodels.AlertConfiguration
+	err := srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
+		groups, selected, err := srv.selectBulkRules(tranCtx, c.SignedInUser, cmd.Selector)
+		if err != nil {
+			return err
+		}
+
+		for _, change := range bulkRuleGroupChanges(groups, selected, cmd, targetFolderUID) {
+			submitted := change.rules
+			if change.target != nil {
+				// Rules are moved to the end of the group in the target folder.
+				target, err := srv.getAuthorizedRuleGroup(tranCtx, c, *change.target)
+				if err != nil {
+					return err
+				}
+				submitted = moveRulesToGroup(target, *change.target, change.rules)
+			}
+
+			groupKey := change.key
+			if change.target != nil {
+				groupKey = *change.target
+			}
+			delta, config, err := srv.applyRuleGroupChanges(tranCtx, c, groupKey, submitted, cmd.DryRun)
+			if err != nil {
+				return err
+			}
+			if config != nil {
+				dbConfig = config
+			}
+			result.Updated, result.Deleted = appendBulkChanges(result.Updated, result.Deleted, delta, selected)
+		}
+		return nil
+	})
+	if err != nil {
+		return updateRuleGroupErrorResponse(err)
+	}
+
+	if !cmd.DryRun {
+		srv.refreshAlertmanagerConfig(c, c.SignedInUser.GetOrgID(), dbConfig)
+	}
+
+	switch {
+	case len(result.Updated) == 0 && len(result.Deleted) == 0:
+		result.Message = "no rules were changed"
+	case cmd.DryRun:
+		result.Message = "rules would be changed"
+	default:
+		result.Message = "rules changed successfully"
+	}
+	return response.JSON(http.StatusAccepted, result)
+}
+
+func validateBulkRulesCommand(cmd apimodels.PostableBulkRulesCommand) error {
+	s := cmd.Selector
+	if len(s.FolderUIDs) == 0 && len(s.Groups) == 0 && len(s.Matchers) == 0 && len(s.DatasourceUIDs) == 0 {
+		return errors.New("selector must specify at least one of folders, groups, matchers or data sources")
+	}
+	switch cmd.Action {
+	case apimodels.BulkRulesActionPause, apimodels.BulkRulesActionUnpause, apimodels.BulkRulesActionDelete:
+	case apimodels.BulkRulesActionPatch:
+		if len(cmd.Labels) == 0 && len(cmd.Annotations) == 0 {
+			return errors.New("patch action requires labels or annotations")
+		}
+		if err := validateLabels(cmd.Labels); err != nil {
+			return err
+		}
+	case apimodels.BulkRulesActionMove:
+		if cmd.TargetFolderUID == "" {
+			return errors.New("move action requires the target folder")
+		}
+	default:
+		return fmt.Errorf("unknown action %q, must be one of pause, unpause, patch, move or delete", cmd.Action)
+	}
+	return nil
+}
+
+// selectBulkRules returns the groups of the rules that match the selector and the UIDs of the matching rules.
+// Groups that the user is not authorized to access are skipped. Returns an authorization error if there are matching rules
+// but the user cannot access any of their groups.
+func (srv RulerSrv) selectBulkRules(ctx context.Context, user identity.Requester, selector apimodels.BulkRulesSelector) (map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup, map[string]struct{}, error) {
+	rules, err := srv.store.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
+		OrgID:         user.GetOrgID(),
+		NamespaceUIDs: selector.FolderUIDs,
+		RuleGroups:    selector.Groups,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	datasources := make(map[string]struct{}, len(selector.DatasourceUIDs))
+	for _, uid := range selector.DatasourceUIDs {
+		datasources[uid] = struct{}{}
+	}
+	matchers := labels.Matchers(selector.Matchers)
+
+	groups := make(map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup)
+	selected := make(map[string]struct{})
+	for groupKey, group := range ngmodels.GroupByAlertRuleGroupKey(rules) {
+		for _, rule := range group {
+			if !matchersMatch(matchers, rule.Labels) || !queriesDatasource(rule, datasources) {
+				continue
+			}
+			groups[groupKey] = group
+			selected[rule.UID] = struct{}{}
+		}
+	}
+
+	total := len(groups)
+	for groupKey, group := range groups {
+		ok, err := srv.authz.HasAccessToRuleGroup(ctx, user, group)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			for _, rule := range group {
+				delete(selected, rule.UID)
+			}
+			delete(groups, groupKey)
+		}
+	}
+	if total > 0 && len(groups) == 0 {
+		return nil, nil, authz.NewAuthorizationErrorGeneric("access any of the selected rules")
+	}
+	return groups, selected, nil
+}
+
+// queriesDatasource returns true if the rule queries any of the data sources or if no data sources are given.
+func queriesDatasource(rule *ngmodels.AlertRule, datasources map[string]struct{}) bool {
+	if len(datasources) == 0 {
+		return true
+	}
+	for _, q := range rule.Data {
+		if _, ok := datasources[q.DatasourceUID]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+type bulkRuleGroupChange struct {
+	key ngmodels.AlertRuleGroupKey
+	// target is the group the rules are moved to, if set.
+	target *ngmodels.AlertRuleGroupKey
+	// rules are the rules to submit to the group. If the rules are moved, rules contains only the moved rules.
+	rules []*ngmodels.AlertRuleWithOptionals
+}
+
+// bulkRuleGroupChanges returns the rules to submit to every affected group to apply the action to the selected rules.
+// The changes are sorted by group to update the groups in a stable order.
+func bulkRuleGroupChanges(groups map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup, selected map[string]struct{}, cmd apimodels.PostableBulkRulesCommand, targetFolderUID string) []bulkRuleGroupChange {
+	keys := make([]ngmodels.AlertRuleGroupKey, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+
+	changes := make([]bulkRuleGroupChange, 0, len(keys))
+	for _, key := range keys {
+		group := groups[key]
+		group.SortByGroupIndex()
+		change := bulkRuleGroupChange{key: key}
+		if cmd.Action == apimodels.BulkRulesActionMove {
+			if key.NamespaceUID == targetFolderUID {
+				continue
+			}
+			change.target = &ngmodels.AlertRuleGroupKey{
+				OrgID:        key.OrgID,
+				NamespaceUID: targetFolderUID,
+				RuleGroup:    key.RuleGroup,
+			}
+		}
+
+		for _, rule := range group {
+			_, isSelected := selected[rule.UID]
+			if change.target != nil {
+				if isSelected {
+					change.rules = append(change.rules, &ngmodels.AlertRuleWithOptionals{AlertRule: *rule})
+				}
+				continue
+			}
+			// Rules are submitted as stored, so unless the action pauses or unpauses them they keep their paused state.
+			r := &ngmodels.AlertRuleWithOptionals{AlertRule: *rule}
+			if isSelected {
+				if cmd.Action == apimodels.BulkRulesActionDelete {
+					continue
+				}
+				applyBulkAction(r, cmd)
+			}
+			change.rules = append(change.rules, r)
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func applyBulkAction(rule *ngmodels.AlertRuleWithOptionals, cmd apimodels.PostableBulkRulesCommand) {
+	switch cmd.Action {
+	case apimodels.BulkRulesActionPause:
+		rule.IsPaused = true
+		rule.HasPause = true
+	case apimodels.BulkRulesActionUnpause:
+		rule.IsPaused = false
+		rule.HasPause = true
+	case apimodels.BulkRulesActionPatch:
+		rule.Labels = patchMap(rule.Labels, cmd.Labels)
+		rule.Annotations = patchMap(rule.Annotations, cmd.Annotations)
+	}
+}
+
+// patchMap returns a copy of m with the values of the patch set and the keys with empty values removed.
+func patchMap(m map[string]string, patch map[string]string) map[string]string {
+	if len(patch) == 0 {
+		return m
+	}
+	result := make(map[string]string, len(m)+len(patch))
+	maps.Copy(result, m)
+	for k, v := range patch {
+		if v == "" {
+			delete(result, k)
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// moveRulesToGroup returns the rules of the target group followed by the moved rules that get the folder and interval of the target group.
+func moveRulesToGroup(target ngmodels.RulesGroup, targetKey ngmodels.AlertRuleGroupKey, moved []*ngmodels.AlertRuleWithOptionals) []*ngmodels.AlertRuleWithOptionals {
+	target.SortByGroupIndex()
+	result := make([]*ngmodels.AlertRuleWithOptionals, 0, len(target)+len(moved))
+	for _, rule := range target {
+		result = append(result, &ngmodels.AlertRuleWithOptionals{AlertRule: *rule})
+	}
+	for _, rule := range moved {
+		rule.NamespaceUID = targetKey.NamespaceUID
+		if len(target) > 0 {
+			rule.IntervalSeconds = target[0].IntervalSeconds
+		}
+		result = append(result, rule)
+	}
+	for i, rule := range result {
+		rule.RuleGroupIndex = i + 1
+	}
+	return result
+}
+
+// appendBulkChanges appends UIDs of the selected rules that are updated or deleted by the changes of a group.
+// Rules that are updated only because other rules of their group changed are not included.
+func appendBulkChanges(updated, deleted []string, delta *store.GroupDelta, selected map[string]struct{}) ([]string, []string) {
+	for _, rule := range delta.Delete {
+		if _, ok := selected[rule.UID]; ok {
+			deleted = append(deleted, rule.UID)
+		}
+	}
+	for _, update := range delta.Update {
+		if _, ok := selected[update.Existing.UID]; ok && len(update.Diff) > 0 {
+			updated = append(updated, update.Existing.UID)
+		}
+	}
+	return updated, deleted
+}
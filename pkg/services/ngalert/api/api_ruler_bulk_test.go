@@ -0,0 +1,307 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+func TestRouteBulkUpdateRules(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	targetFolder := randFolder()
+	groupKey := models.GenerateGroupKey(orgID)
+	groupKey.NamespaceUID = folder.UID
+	gen := models.RuleGen
+	groupGen := gen.With(gen.WithGroupKey(groupKey), gen.WithUniqueGroupIndex(), gen.WithUniqueID(), gen.WithIsPaused(false))
+
+	setup := func(t *testing.T) (*fakes.RuleStore, []*models.AlertRule) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder, targetFolder)
+		teamA := groupGen.With(gen.WithLabels(data.Labels{"team": "a", "env": "prod"})).GenerateManyRef(2)
+		teamB := groupGen.With(gen.WithLabels(data.Labels{"team": "b"})).GenerateRef()
+		group := append(teamA, teamB)
+		ruleStore.PutRule(context.Background(), group...)
+		return ruleStore, group
+	}
+	permissions := func(actions ...string) map[int64]map[string][]string {
+		perms := map[string][]string{
+			datasources.ActionQuery:      {datasources.ScopeAll},
+			dashboards.ActionFoldersRead: {dashboards.ScopeFoldersAll},
+			ac.ActionAlertingRuleRead:    {dashboards.ScopeFoldersAll},
+		}
+		for _, action := range actions {
+			perms[action] = []string{dashboards.ScopeFoldersAll}
+		}
+		return map[int64]map[string][]string{orgID: perms}
+	}
+	teamMatcher := func(t *testing.T, team string) apimodels.ObjectMatchers {
+		m, err := labels.NewMatcher(labels.MatchEqual, "team", team)
+		require.NoError(t, err)
+		return apimodels.ObjectMatchers{m}
+	}
+	updatedRules := func(ruleStore *fakes.RuleStore) map[string]models.AlertRule {
+		result := map[string]models.AlertRule{}
+		for _, cmd := range ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			u, ok := cmd.([]models.UpdateRule)
+			return u, ok
+		}) {
+			for _, u := range cmd.([]models.UpdateRule) {
+				result[u.New.UID] = u.New
+			}
+		}
+		return result
+	}
+	parse := func(t *testing.T, body []byte) apimodels.BulkRulesResponse {
+		var result apimodels.BulkRulesResponse
+		require.NoError(t, json.Unmarshal(body, &result))
+		return result
+	}
+
+	t.Run("should pause rules selected by label matchers", func(t *testing.T) {
+		ruleStore, group := setup(t)
+		svc := createService(ruleStore)
+		svc.conditionValidator = &recordingConditionValidator{}
+		req := createRequestContextWithPerms(orgID, permissions(ac.ActionAlertingRuleUpdate), nil)
+
+		response := svc.RouteBulkUpdateRules(req, apimodels.PostableBulkRulesCommand{
+			Selector: apimodels.BulkRulesSelector{Matchers: teamMatcher(t, "a")},
+			Action:   apimodels.BulkRulesActionPause,
+		})
+
+		require.Equal(t, http.StatusAccepted, response.Status())
+		result := parse(t, response.Body())
+		require.ElementsMatch(t, []string{group[0].UID, group[1].UID}, result.Updated)
+		require.Empty(t, result.Deleted)
+		require.False(t, result.DryRun)
+
+		updated := updatedRules(ruleStore)
+		require.True(t, updated[group[0].UID].IsPaused)
+		require.True(t, updated[group[1].UID].IsPaused)
+		require.False(t, updated[group[2].UID].IsPaused)
+	})
+
+	t.Run("should patch labels and annotations", func(t *testing.T) {
+		ruleStore, group := setup(t)
+		svc := createService(ruleStore)
+		svc.conditionValidator = &recordingConditionValidator{}
+		req := createRequestContextWithPerms(orgID, permissions(ac.ActionAlertingRuleUpdate), nil)
+
+		response := svc.RouteBulkUpdateRules(req, apimodels.PostableBulkRulesCommand{
+			Selector:    apimodels.BulkRulesSelector{FolderUIDs: []string{folder.UID}, Groups: []string{groupKey.RuleGroup}},
+			Action:      apimodels.BulkRulesActionPatch,
+			Labels:      map[string]string{"owner": "sre", "env": ""},
+			Annotations: map[string]string{"runbook_url": "https://example.com"},
+		})
+
+		require.Equal(t, http.StatusAccepted, response.Status())
+		require.Len(t, parse(t, response.Body()).Updated, len(group))
+		updated := updatedRules(ruleStore)
+		require.Equal(t, map[string]string{"team": "a", "owner": "sre"}, updated[group[0].UID].Labels)
+		require.Equal(t, map[string]string{"team": "b", "owner": "sre"}, updated[group[2].UID].Labels)
+		require.Equal(t, "https://example.com", updated[group[2].UID].Annotations["runbook_url"])
+	})
+
+	t.Run("should not change rules in dry run", func(t *testing.T) {
+		ruleStore, group := setup(t)
+		svc := createService(ruleStore)
+		svc.conditionValidator = &recordingConditionValidator{}
+		req := createRequestContextWithPerms(orgID, permissions(ac.ActionAlertingRuleDelete), nil)
+
+		response := svc.RouteBulkUpdateRules(req, apimodels.PostableBulkRulesCommand{
+			Selector: apimodels.BulkRulesSelector{Matchers: teamMatcher(t, "b")},
+			Action:   apimodels.BulkRulesActionDelete,
+			DryRun:   true,
+		})
+
+		require.Equal(t, http.StatusAccepted, response.Status())
+		result := parse(t, response.Body())
+		require.True(t, result.DryRun)
+		require.Equal(t, []string{group[2].UID}, result.Deleted)
+		require.Empty(t, updatedRules(ruleStore))
+		require.Empty(t, ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			q, ok := cmd.(fakes.GenericRecordedQuery)
+			return q, ok && q.Name == "DeleteAlertRulesByUID"
+		}))
+	})
+
+	t.Run("should delete rules that query the data source", func(t *testing.T) {
+		ruleStore, group := setup(t)
+		group[1].Data[0].DatasourceUID = "selected-datasource"
+		svc := createService(ruleStore)
+		svc.conditionValidator = &recordingConditionValidator{}
+		req := createRequestContextWithPerms(orgID, permissions(ac.ActionAlertingRuleDelete), nil)
+
+		response := svc.RouteBulkUpdateRules(req, apimodels.PostableBulkRulesCommand{
+			Selector: apimodels.BulkRulesSelector{DatasourceUIDs: []string{"selected-datasource"}},
+			Action:   apimodels.BulkRulesActionDelete,
+		})
+
+		require.Equal(t, http.StatusAccepted, response.Status())
+		require.Equal(t, []string{group[1].UID}, parse(t, response.Body()).Deleted)
+		rules, err := ruleStore.ListAlertRules(context.Background(), &models.ListAlertRulesQuery{OrgID: orgID})
+		require.NoError(t, err)
+		require.Len(t, rules, len(group)-1)
+	})
+
+	t.Run("should move rules to the target folder", func(t *testing.T) {
+		ruleStore, group := setup(t)
+		svc := createService(ruleStore)
+		svc.conditionValidator = &recordingConditionValidator{}
+		req := createRequestContextWithPerms(orgID, permissions(ac.ActionAlertingRuleUpdate, ac.ActionAlertingRuleCreate, ac.ActionAlertingRuleDelete), nil)
+
+		response := svc.RouteBulkUpdateRules(req, apimodels.PostableBulkRulesCommand{
+			Selector:        apimodels.BulkRulesSelector{Matchers: teamMatcher(t, "a")},
+			Action:          apimodels.BulkRulesActionMove,
+			TargetFolderUID: targetFolder.UID,
+		})
+
+		require.Equal(t, http.StatusAccepted, response.Status())
+		require.ElementsMatch(t, []string{group[0].UID, group[1].UID}, parse(t, response.Body()).Updated)
+		updated := updatedRules(ruleStore)
+		for _, rule := range group[:2] {
+			require.Equal(t, targetFolder.UID, updated[rule.UID].NamespaceUID)
+			require.Equal(t, groupKey.RuleGroup, updated[rule.UID].RuleGroup)
+		}
+		require.Equal(t, 1, updated[group[2].UID].RuleGroupIndex)
+	})
+
+	t.Run("should keep paused rules paused", func(t *testing.T) {
+		// group[0] is selected by the matcher and group[2] is not, both are paused
+		pausedSetup := func(t *testing.T) (*fakes.RuleStore, []*models.AlertRule) {
+			ruleStore, group := setup(t)
+			group[0].IsPaused = true
+			group[2].IsPaused = true
+			return ruleStore, group
+		}
+		perms := permissions(ac.ActionAlertingRuleUpdate, ac.ActionAlertingRuleCreate, ac.ActionAlertingRuleDelete)
+
+		t.Run("when patching rules", func(t *testing.T) {
+			ruleStore, group := pausedSetup(t)
+			svc := createService(ruleStore)
+			svc.conditionValidator = &recordingConditionValidator{}
+
+			response := svc.RouteBulkUpdateRules(createRequestContextWithPerms(orgID, perms, nil), apimodels.PostableBulkRulesCommand{
+				Selector: apimodels.BulkRulesSelector{Matchers: teamMatcher(t, "a")},
+				Action:   apimodels.BulkRulesActionPatch,
+				Labels:   map[string]string{"owner": "sre"},
+			})
+
+			require.Equal(t, http.StatusAccepted, response.Status())
+			updated := updatedRules(ruleStore)
+			require.True(t, updated[group[0].UID].IsPaused)
+			require.False(t, updated[group[1].UID].IsPaused)
+			require.True(t, updated[group[2].UID].IsPaused)
+			require.Equal(t, map[string]string{"team": "a", "env": "prod"}, group[0].Labels, "stored rules must not be modified")
+		})
+
+		t.Run("when moving rules", func(t *testing.T) {
+			ruleStore, group := pausedSetup(t)
+			targetRule := gen.With(gen.WithOrgID(orgID), gen.WithNamespaceUID(targetFolder.UID), gen.WithGroupName(groupKey.RuleGroup), gen.WithIsPaused(true)).GenerateRef()
+			ruleStore.PutRule(context.Background(), targetRule)
+			svc := createService(ruleStore)
+			svc.conditionValidator = &recordingConditionValidator{}
+
+			response := svc.RouteBulkUpdateRules(createRequestContextWithPerms(orgID, perms, nil), apimodels.PostableBulkRulesCommand{
+				Selector:        apimodels.BulkRulesSelector{Matchers: teamMatcher(t, "a")},
+				Action:          apimodels.BulkRulesActionMove,
+				TargetFolderUID: targetFolder.UID,
+			})
+
+			require.Equal(t, http.StatusAccepted, response.Status())
+			updated := updatedRules(ruleStore)
+			require.Equal(t, targetFolder.UID, updated[group[0].UID].NamespaceUID)
+			require.True(t, updated[group[0].UID].IsPaused)
+			require.False(t, updated[group[1].UID].IsPaused)
+			require.True(t, updated[group[2].UID].IsPaused)
+			require.True(t, updated[targetRule.UID].IsPaused)
+		})
+
+		t.Run("when unpausing other rules", func(t *testing.T) {
+			ruleStore, group := pausedSetup(t)
+			svc := createService(ruleStore)
+			svc.conditionValidator = &recordingConditionValidator{}
+
+			response := svc.RouteBulkUpdateRules(createRequestContextWithPerms(orgID, perms, nil), apimodels.PostableBulkRulesCommand{
+				Selector: apimodels.BulkRulesSelector{Matchers: teamMatcher(t, "a")},
+				Action:   apimodels.BulkRulesActionUnpause,
+			})
+
+			require.Equal(t, http.StatusAccepted, response.Status())
+			require.Equal(t, []string{group[0].UID}, parse(t, response.Body()).Updated)
+			updated := updatedRules(ruleStore)
+			require.False(t, updated[group[0].UID].IsPaused)
+			require.True(t, updated[group[2].UID].IsPaused)
+		})
+	})
+
+	t.Run("should fail without changes if rules are provisioned", func(t *testing.T) {
+		ruleStore, group := setup(t)
+		provenanceStore := fakes.NewFakeProvisioningStore()
+		require.NoError(t, provenanceStore.SetProvenance(context.Background(), group[2], orgID, models.ProvenanceAPI))
+		svc := createServiceWithProvenanceStore(ruleStore, provenanceStore)
+		svc.conditionValidator = &recordingConditionValidator{}
+		req := createRequestContextWithPerms(orgID, permissions(ac.ActionAlertingRuleUpdate), nil)
+
+		response := svc.RouteBulkUpdateRules(req, apimodels.PostableBulkRulesCommand{
+			Selector: apimodels.BulkRulesSelector{Matchers: teamMatcher(t, "a")},
+			Action:   apimodels.BulkRulesActionPause,
+		})
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+		require.Empty(t, updatedRules(ruleStore))
+	})
+
+	t.Run("should return 403 if user cannot change the rules", func(t *testing.T) {
+		ruleStore, _ := setup(t)
+		svc := createService(ruleStore)
+		svc.conditionValidator = &recordingConditionValidator{}
+		req := createRequestContextWithPerms(orgID, permissions(), nil)
+
+		response := svc.RouteBulkUpdateRules(req, apimodels.PostableBulkRulesCommand{
+			Selector: apimodels.BulkRulesSelector{FolderUIDs: []string{folder.UID}},
+			Action:   apimodels.BulkRulesActionUnpause,
+		})
+
+		require.Equal(t, http.StatusAccepted, response.Status(), "rules that are not paused do not change")
+
+		response = svc.RouteBulkUpdateRules(req, apimodels.PostableBulkRulesCommand{
+			Selector: apimodels.BulkRulesSelector{FolderUIDs: []string{folder.UID}},
+			Action:   apimodels.BulkRulesActionPause,
+		})
+		require.Equal(t, http.StatusForbidden, response.Status())
+		require.Empty(t, updatedRules(ruleStore))
+	})
+
+	t.Run("should return 400 if command is invalid", func(t *testing.T) {
+		ruleStore, _ := setup(t)
+		svc := createService(ruleStore)
+		req := createRequestContextWithPerms(orgID, permissions(ac.ActionAlertingRuleUpdate), nil)
+
+		testCases := map[string]apimodels.PostableBulkRulesCommand{
+			"empty selector": {Action: apimodels.BulkRulesActionPause},
+			"unknown action": {Selector: apimodels.BulkRulesSelector{FolderUIDs: []string{folder.UID}}, Action: "archive"},
+			"empty patch":    {Selector: apimodels.BulkRulesSelector{FolderUIDs: []string{folder.UID}}, Action: apimodels.BulkRulesActionPatch},
+			"reserved label": {Selector: apimodels.BulkRulesSelector{FolderUIDs: []string{folder.UID}}, Action: apimodels.BulkRulesActionPatch, Labels: map[string]string{models.AutogeneratedRouteLabel: "test"}},
+			"no target":      {Selector: apimodels.BulkRulesSelector{FolderUIDs: []string{folder.UID}}, Action: apimodels.BulkRulesActionMove},
+		}
+		for name, cmd := range testCases {
+			t.Run(name, func(t *testing.T) {
+				require.Equal(t, http.StatusBadRequest, svc.RouteBulkUpdateRules(req, cmd).Status())
+			})
+		}
+	})
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
 	"github.com/grafana/grafana/pkg/util/cmputil"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -379,6 +380,177 @@ func TestRouteGetRuleByUID(t *testing.T) {
 	})
 }
 
+func TestRuleVersions(t *testing.T) {
+	orgID := rand.Int63()
+	folder := randFolder()
+	groupKey := models.GenerateGroupKey(orgID)
+	groupKey.NamespaceUID = folder.UID
+	gen := models.RuleGen.With(models.RuleGen.WithGroupKey(groupKey), models.RuleGen.WithUniqueGroupIndex(), models.RuleGen.WithUniqueID())
+
+	setup := func(t *testing.T) (*fakes.RuleStore, *models.AlertRule, []*models.AlertRule) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+		group := gen.GenerateManyRef(2)
+		current := group[0]
+		current.Version = 3
+		current.Title = "current"
+		current.UpdatedBy = util.Pointer(models.UserUID("editor"))
+		ruleStore.PutRule(context.Background(), group...)
+
+		versions := make([]*models.AlertRule, 0, 2)
+		for i, title := range []string{"first", "second"} {
+			v := models.CopyRule(current)
+			v.ID = 0
+			v.Version = int64(i + 1)
+			v.Title = title
+			v.UpdatedBy = util.Pointer(models.UserUID(fmt.Sprintf("user-%d", i+1)))
+			versions = append(versions, v)
+		}
+		ruleStore.Versions[current.GetKey()] = versions
+		return ruleStore, current, group
+	}
+
+	t.Run("should list versions newest first with author", func(t *testing.T) {
+		ruleStore, current, group := setup(t)
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules(group, orgID), nil)
+
+		response := createService(ruleStore).RouteGetRuleVersionsByUID(req, current.UID)
+
+		require.Equal(t, http.StatusOK, response.Status())
+		var result apimodels.GettableRuleVersions
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result, 3)
+		for i, expected := range []struct {
+			version   int64
+			title     string
+			updatedBy string
+		}{{3, "current", "editor"}, {2, "second", "user-2"}, {1, "first", "user-1"}} {
+			require.Equal(t, expected.version, result[i].GrafanaManagedAlert.Version)
+			require.Equal(t, expected.title, result[i].GrafanaManagedAlert.Title)
+			require.Equal(t, expected.updatedBy, *result[i].GrafanaManagedAlert.UpdatedBy)
+			require.Equal(t, current.ID, result[i].GrafanaManagedAlert.ID)
+		}
+	})
+
+	t.Run("should return 404 when listing versions of an unknown rule", func(t *testing.T) {
+		ruleStore, _, group := setup(t)
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules(group, orgID), nil)
+
+		response := createService(ruleStore).RouteGetRuleVersionsByUID(req, "foobar")
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should diff two versions", func(t *testing.T) {
+		ruleStore, current, group := setup(t)
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules(group, orgID), nil)
+		req.Req.Form.Set("from", "1")
+		req.Req.Form.Set("to", "3")
+
+		response := createService(ruleStore).RouteGetRuleVersionsDiff(req, current.UID)
+
+		require.Equal(t, http.StatusOK, response.Status())
+		var result apimodels.GettableRuleVersionsDiff
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, int64(1), result.From)
+		require.Equal(t, int64(3), result.To)
+		require.Equal(t, []apimodels.RuleVersionChange{{Path: "Title", From: "first", To: "current"}}, result.Changes)
+	})
+
+	t.Run("should return 400 if diff versions are missing", func(t *testing.T) {
+		ruleStore, current, group := setup(t)
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules(group, orgID), nil)
+		req.Req.Form.Set("from", "1")
+
+		response := createService(ruleStore).RouteGetRuleVersionsDiff(req, current.UID)
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return 404 if diff version does not exist", func(t *testing.T) {
+		ruleStore, current, group := setup(t)
+		req := createRequestContextWithPerms(orgID, createPermissionsForRules(group, orgID), nil)
+		req.Req.Form.Set("from", "1")
+		req.Req.Form.Set("to", "10")
+
+		response := createService(ruleStore).RouteGetRuleVersionsDiff(req, current.UID)
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	restorePermissions := func(group []*models.AlertRule) map[int64]map[string][]string {
+		perms := createPermissionsForRules(group, orgID)
+		perms[orgID][ac.ActionAlertingRuleUpdate] = []string{dashboards.ScopeFoldersProvider.GetResourceScopeUID(folder.UID)}
+		return perms
+	}
+
+	t.Run("should restore version and keep the rule in its group", func(t *testing.T) {
+		ruleStore, current, group := setup(t)
+		req := createRequestContextWithPerms(orgID, restorePermissions(group), nil)
+		req.SignedInUser.UserID = 1
+		req.SignedInUser.UserUID = "restorer"
+		svc := createService(ruleStore)
+		validator := &recordingConditionValidator{}
+		svc.conditionValidator = validator
+
+		response := svc.RouteRestoreRuleVersion(req, current.UID, "1")
+
+		require.Equal(t, http.StatusAccepted, response.Status())
+		updates := ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			u, ok := cmd.([]models.UpdateRule)
+			return u, ok
+		})
+		require.Len(t, updates, 1)
+		var restored *models.AlertRule
+		for _, u := range updates[0].([]models.UpdateRule) {
+			if u.New.UID == current.UID {
+				restored = &u.New
+				continue
+			}
+			require.Nil(t, u.New.UpdatedBy, "rules of the group that did not change should keep their author")
+		}
+		require.NotNil(t, restored)
+		require.Equal(t, current.UID, restored.UID)
+		require.Equal(t, current.ID, restored.ID)
+		require.Equal(t, "first", restored.Title)
+		require.Equal(t, current.GetGroupKey(), restored.GetGroupKey())
+		require.Equal(t, current.RuleGroupIndex, restored.RuleGroupIndex)
+		require.Equal(t, models.UserUID("restorer"), *restored.UpdatedBy)
+		require.Len(t, validator.recorded, 1)
+	})
+
+	t.Run("should not restore version of provisioned rule", func(t *testing.T) {
+		ruleStore, current, group := setup(t)
+		provenanceStore := fakes.NewFakeProvisioningStore()
+		require.NoError(t, provenanceStore.SetProvenance(context.Background(), current, orgID, models.ProvenanceAPI))
+		req := createRequestContextWithPerms(orgID, restorePermissions(group), nil)
+		svc := createServiceWithProvenanceStore(ruleStore, provenanceStore)
+		svc.conditionValidator = &recordingConditionValidator{}
+
+		response := svc.RouteRestoreRuleVersion(req, current.UID, "1")
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return 404 if version to restore does not exist", func(t *testing.T) {
+		ruleStore, current, group := setup(t)
+		req := createRequestContextWithPerms(orgID, restorePermissions(group), nil)
+
+		response := createService(ruleStore).RouteRestoreRuleVersion(req, current.UID, "10")
+
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	t.Run("should return 400 if version to restore is invalid", func(t *testing.T) {
+		ruleStore, current, group := setup(t)
+		req := createRequestContextWithPerms(orgID, restorePermissions(group), nil)
+
+		response := createService(ruleStore).RouteRestoreRuleVersion(req, current.UID, "latest")
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+}
+
 func TestRouteGetRulesConfig(t *testing.T) {
 	gen := models.RuleGen
 	t.Run("fine-grained access is enabled", func(t *testing.T) {
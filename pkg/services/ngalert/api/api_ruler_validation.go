@@ -144,6 +144,8 @@ func validateAlertingRuleFields(in *apimodels.PostableExtendedRuleNode, newRule
 		}
 	}
 
+	newRule.SuppressedBy = SuppressingRulesFromApiSuppressingRules(in.GrafanaManagedAlert.SuppressedBy)
+
 	if in.GrafanaManagedAlert.Metadata != nil {
 		newRule.Metadata.EditorSettings = ngmodels.EditorSettings{
 			SimplifiedQueryAndExpressionsSection: in.GrafanaManagedAlert.Metadata.EditorSettings.SimplifiedQueryAndExpressionsSection,
@@ -330,6 +332,14 @@ func ValidateRuleGroup(
 
 		result = append(result, &ruleWithOptionals)
 	}
+
+	rules := make([]*ngmodels.AlertRule, 0, len(result))
+	for _, rule := range result {
+		rules = append(rules, &rule.AlertRule)
+	}
+	if err := ngmodels.ValidateRuleGroupInputs(rules); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
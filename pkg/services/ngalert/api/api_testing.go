@@ -10,6 +10,7 @@ import (
 
 	"github.com/benbjohnson/clock"
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/timeinterval"
 
 	"github.com/grafana/alerting/models"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -18,6 +19,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
@@ -51,6 +53,11 @@ type TestingApiSrv struct {
 	appUrl          *url.URL
 	tracer          tracing.Tracer
 	folderService   folderService
+	amConfigs       alertmanagerConfigProvider
+}
+
+type alertmanagerConfigProvider interface {
+	GetAlertmanagerConfiguration(ctx context.Context, org int64, withAutogen bool) (apimodels.GettableUserConfig, error)
 }
 
 // RouteTestGrafanaRuleConfig returns a list of potential alerts for a given rule configuration. This is intended to be
@@ -195,13 +202,22 @@ func (srv TestingApiSrv) RouteEvalQueries(c *contextmodel.ReqContext, cmd apimod
 		now = timeNow()
 	}
 
-	evalResults, err := evaluator.EvaluateRaw(c.Req.Context(), now)
+	ctx := c.Req.Context()
+	var explanation *expr.Explanation
+	if cmd.Explain {
+		ctx, explanation = expr.WithExplanation(ctx)
+	}
+
+	evalResults, err := evaluator.EvaluateRaw(ctx, now)
 
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "Failed to evaluate queries and expressions")
 	}
 
 	addOptimizedQueryWarnings(evalResults, optimizations)
+	if explanation != nil {
+		evalResults.Responses[expr.ExplainRefID] = backend.DataResponse{Frames: data.Frames{explanation.Frame()}}
+	}
 	return response.JSONStreaming(http.StatusOK, evalResults)
 }
 
@@ -221,35 +237,86 @@ func addOptimizedQueryWarnings(evalResults *backend.QueryDataResponse, optimizat
 }
 
 func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimodels.BacktestConfig) response.Response {
+	rule, errResp := srv.backtestingRule(c, cmd)
+	if errResp != nil {
+		return errResp
+	}
+
+	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(400, err, "Failed to evaluate")
+		}
+		return ErrResp(500, err, "Failed to evaluate")
+	}
+
+	body, err := data.FrameToJSON(result, data.IncludeAll)
+	if err != nil {
+		return ErrResp(500, err, "Failed to convert frame to JSON")
+	}
+	return response.JSON(http.StatusOK, body)
+}
+
+// BacktestSimulation evaluates the rule over the historical data and simulates the notifications of its alerts
+// using the notification policies and time intervals of the Grafana Alertmanager of the organization.
+func (srv TestingApiSrv) BacktestSimulation(c *contextmodel.ReqContext, cmd apimodels.BacktestConfig) response.Response {
+	rule, errResp := srv.backtestingRule(c, cmd)
+	if errResp != nil {
+		return errResp
+	}
+
+	amConfig, err := srv.amConfigs.GetAlertmanagerConfiguration(c.Req.Context(), c.SignedInUser.GetOrgID(), true)
+	if err != nil {
+		return ErrResp(500, err, "Failed to get the Alertmanager configuration")
+	}
+
+	result, err := srv.backtesting.Simulate(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To, notificationPoliciesFromConfig(amConfig))
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(400, err, "Failed to evaluate")
+		}
+		return ErrResp(500, err, "Failed to evaluate")
+	}
+	return response.JSON(http.StatusOK, backtestSimulationResultToApi(result))
+}
+
+// backtestingRule validates the backtesting request and creates a rule from it.
+func (srv TestingApiSrv) backtestingRule(c *contextmodel.ReqContext, cmd apimodels.BacktestConfig) (*ngmodels.AlertRule, response.Response) {
 	if !srv.featureManager.IsEnabled(c.Req.Context(), featuremgmt.FlagAlertingBacktesting) {
-		return ErrResp(http.StatusNotFound, nil, "Backgtesting API is not enabled")
+		return nil, ErrResp(http.StatusNotFound, nil, "Backgtesting API is not enabled")
 	}
 
 	if cmd.From.After(cmd.To) {
-		return ErrResp(400, nil, "From cannot be greater than To")
+		return nil, ErrResp(400, nil, "From cannot be greater than To")
 	}
 
 	noDataState, err := ngmodels.NoDataStateFromString(string(cmd.NoDataState))
-
 	if err != nil {
-		return ErrResp(400, err, "")
+		return nil, ErrResp(400, err, "")
+	}
+	execErrState := ngmodels.ErrorErrState
+	if cmd.ExecErrState != "" {
+		execErrState, err = ngmodels.ErrStateFromString(string(cmd.ExecErrState))
+		if err != nil {
+			return nil, ErrResp(400, err, "")
+		}
 	}
 	forInterval := time.Duration(cmd.For)
 	if forInterval < 0 {
-		return ErrResp(400, nil, "Bad For interval")
+		return nil, ErrResp(400, nil, "Bad For interval")
 	}
 
 	intervalSeconds, err := validateInterval(time.Duration(cmd.Interval), srv.cfg.BaseInterval)
 	if err != nil {
-		return ErrResp(400, err, "")
+		return nil, ErrResp(400, err, "")
 	}
 
 	queries := AlertQueriesFromApiAlertQueries(cmd.Data)
 	if err := srv.authz.AuthorizeDatasourceAccessForRule(c.Req.Context(), c.SignedInUser, &ngmodels.AlertRule{Data: queries}); err != nil {
-		return errorToResponse(err)
+		return nil, errorToResponse(err)
 	}
 
-	rule := &ngmodels.AlertRule{
+	return &ngmodels.AlertRule{
 		// ID:             0,
 		// Updated:        time.Time{},
 		// Version:        0,
@@ -258,7 +325,6 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		// PanelID:        nil,
 		// RuleGroup:      "",
 		// RuleGroupIndex: 0,
-		// ExecErrState:   "",
 		Title: cmd.Title,
 		// prefix backtesting- is to distinguish between executions of regular rule and backtesting in logs (like expression engine, evaluator, state manager etc)
 		UID:             "backtesting-" + util.GenerateShortUID(),
@@ -267,22 +333,67 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 		Data:            queries,
 		IntervalSeconds: intervalSeconds,
 		NoDataState:     noDataState,
+		ExecErrState:    execErrState,
 		For:             forInterval,
 		Annotations:     cmd.Annotations,
 		Labels:          cmd.Labels,
+	}, nil
+}
+
+// notificationPoliciesFromConfig returns the notification policies and all named time intervals of the Alertmanager configuration.
+func notificationPoliciesFromConfig(cfg apimodels.GettableUserConfig) backtesting.NotificationPolicies {
+	amConfig := cfg.AlertmanagerConfig
+	policies := backtesting.NotificationPolicies{
+		TimeIntervals: make(map[string][]timeinterval.TimeInterval, len(amConfig.MuteTimeIntervals)+len(amConfig.TimeIntervals)),
+	}
+	if amConfig.Route != nil {
+		policies.Route = amConfig.Route.AsAMRoute()
+	}
+	for _, ti := range amConfig.MuteTimeIntervals {
+		policies.TimeIntervals[ti.Name] = ti.TimeIntervals
+	}
+	for _, ti := range amConfig.TimeIntervals {
+		policies.TimeIntervals[ti.Name] = ti.TimeIntervals
 	}
+	return policies
+}
 
-	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To)
-	if err != nil {
-		if errors.Is(err, backtesting.ErrInvalidInputData) {
-			return ErrResp(400, err, "Failed to evaluate")
+func backtestSimulationResultToApi(s *backtesting.Simulation) apimodels.BacktestSimulationResult {
+	result := apimodels.BacktestSimulationResult{
+		Timeline:      make([]apimodels.BacktestInstanceTimeline, 0, len(s.Timeline)),
+		Notifications: make([]apimodels.BacktestNotification, 0, len(s.Notifications)),
+	}
+	for _, instance := range s.Timeline {
+		states := make([]apimodels.BacktestStatePeriod, 0, len(instance.States))
+		for _, p := range instance.States {
+			states = append(states, apimodels.BacktestStatePeriod{
+				State:  p.State.String(),
+				Reason: p.Reason,
+				From:   p.From,
+				To:     p.To,
+			})
 		}
-		return ErrResp(500, err, "Failed to evaluate")
+		result.Timeline = append(result.Timeline, apimodels.BacktestInstanceTimeline{
+			Labels: instance.Labels,
+			States: states,
+		})
 	}
-
-	body, err := data.FrameToJSON(result, data.IncludeAll)
-	if err != nil {
-		return ErrResp(500, err, "Failed to convert frame to JSON")
+	toMaps := func(labels []data.Labels) []map[string]string {
+		maps := make([]map[string]string, 0, len(labels))
+		for _, l := range labels {
+			maps = append(maps, l)
+		}
+		return maps
 	}
-	return response.JSON(http.StatusOK, body)
+	for _, n := range s.Notifications {
+		result.Notifications = append(result.Notifications, apimodels.BacktestNotification{
+			Time:        n.Time,
+			Receiver:    n.Receiver,
+			GroupLabels: n.GroupLabels,
+			Firing:      toMaps(n.Firing),
+			Resolved:    toMaps(n.Resolved),
+			Muted:       n.Muted,
+		})
+	}
+	return result
 }
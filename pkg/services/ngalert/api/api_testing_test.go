@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
 	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
@@ -314,6 +315,37 @@ func TestRouteEvalQueries(t *testing.T) {
 
 			evaluator.AssertCalled(t, "EvaluateRaw", mock.Anything, currentTime)
 		})
+
+		t.Run("should add the explanation of the expressions if requested", func(t *testing.T) {
+			data1 := models.GenerateAlertQuery()
+
+			ac := acMock.New().WithPermissions([]ac.Permission{
+				{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(data1.DatasourceUID)},
+			})
+
+			ds := &fakes.FakeCacheService{DataSources: []*datasources.DataSource{
+				{UID: data1.DatasourceUID},
+			}}
+
+			evaluator := &eval_mocks.ConditionEvaluatorMock{}
+			result := &backend.QueryDataResponse{
+				Responses: map[string]backend.DataResponse{
+					"test": {},
+				},
+			}
+			evaluator.EXPECT().EvaluateRaw(mock.Anything, mock.Anything).Return(result, nil)
+
+			srv := createTestingApiSrv(t, ds, ac, eval_mocks.NewEvaluatorFactory(evaluator), featuremgmt.WithFeatures(), fakes2.NewRuleStore(t))
+
+			response := srv.RouteEvalQueries(rc, definitions.EvalQueriesPayload{
+				Data:    ApiAlertQueriesFromAlertQueries([]models.AlertQuery{data1}),
+				Explain: true,
+			})
+
+			require.Equal(t, http.StatusOK, response.Status())
+			require.Contains(t, result.Responses, expr.ExplainRefID)
+			require.Len(t, result.Responses[expr.ExplainRefID].Frames, 1)
+		})
 	})
 
 	t.Run("when query is optimizable", func(t *testing.T) {
@@ -406,3 +438,28 @@ func createTestingApiSrv(t *testing.T, ds *fakes.FakeCacheService, ac *acMock.Mo
 		folderService:   ruleStore,
 	}
 }
+
+func TestNotificationPoliciesFromConfig(t *testing.T) {
+	cfg := definitions.GettableUserConfig{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"alertmanager_config": {
+			"route": {
+				"receiver": "default",
+				"routes": [{"receiver": "team-b", "object_matchers": [["team", "=", "b"]], "mute_time_intervals": ["weekends"], "active_time_intervals": ["work-hours"]}]
+			},
+			"mute_time_intervals": [{"name": "weekends", "time_intervals": [{"weekdays": ["saturday", "sunday"]}]}],
+			"time_intervals": [{"name": "work-hours", "time_intervals": [{"times": [{"start_time": "09:00", "end_time": "17:00"}]}]}],
+			"receivers": [{"name": "default"}, {"name": "team-b"}]
+		}
+	}`), &cfg))
+
+	policies := notificationPoliciesFromConfig(cfg)
+	require.Equal(t, "default", policies.Route.Receiver)
+	require.Len(t, policies.Route.Routes, 1)
+	require.Len(t, policies.Route.Routes[0].Matchers, 1)
+	require.Equal(t, []string{"weekends"}, policies.Route.Routes[0].MuteTimeIntervals)
+	require.Len(t, policies.TimeIntervals["weekends"], 1)
+	require.Len(t, policies.TimeIntervals["work-hours"], 1)
+
+	require.Nil(t, notificationPoliciesFromConfig(definitions.GettableUserConfig{}).Route)
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
@@ -34,6 +35,7 @@ func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (mode
 		IsPaused:             a.IsPaused,
 		NotificationSettings: NotificationSettingsFromAlertRuleNotificationSettings(a.NotificationSettings),
 		Record:               ModelRecordFromApiRecord(a.Record),
+		SuppressedBy:         SuppressingRulesFromApiSuppressingRules(a.SuppressedBy),
 	}, nil
 }
 
@@ -58,6 +60,7 @@ func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: AlertRuleNotificationSettingsFromNotificationSettings(rule.NotificationSettings),
 		Record:               ApiRecordFromModelRecord(rule.Record),
+		SuppressedBy:         ApiSuppressingRulesFromSuppressingRules(rule.SuppressedBy),
 	}
 }
 
@@ -209,6 +212,7 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: AlertRuleNotificationSettingsExportFromNotificationSettings(rule.NotificationSettings),
 		Record:               AlertRuleRecordExportFromRecord(rule.Record),
+		SuppressedBy:         AlertRuleSuppressingRulesExportFromSuppressingRules(rule.SuppressedBy),
 	}
 	if rule.For.Seconds() > 0 {
 		result.ForString = util.Pointer(model.Duration(rule.For).String())
@@ -264,6 +268,114 @@ func AlertQueryExportFromAlertQuery(query models.AlertQuery) (definitions.AlertQ
 	}, nil
 }
 
+// AlertRuleGroupFromAlertRuleGroupExport converts definitions.AlertRuleGroupExport to models.AlertRuleGroup.
+// The folder UID is set only if the export contains it, for example, if it was parsed from HCL.
+func AlertRuleGroupFromAlertRuleGroupExport(d definitions.AlertRuleGroupExport) (models.AlertRuleGroup, error) {
+	interval := d.IntervalSeconds
+	if interval == 0 {
+		interval = int64(time.Duration(d.Interval).Seconds())
+	}
+	group := models.AlertRuleGroup{
+		Title:     d.Name,
+		FolderUID: d.FolderUID,
+		Interval:  interval,
+		Rules:     make([]models.AlertRule, 0, len(d.Rules)),
+	}
+	for _, r := range d.Rules {
+		rule, err := AlertRuleFromAlertRuleExport(r)
+		if err != nil {
+			return models.AlertRuleGroup{}, fmt.Errorf("rule '%s' failed to parse: %w", r.Title, err)
+		}
+		rule.NamespaceUID = d.FolderUID
+		rule.RuleGroup = d.Name
+		rule.IntervalSeconds = interval
+		group.Rules = append(group.Rules, rule)
+	}
+	return group, nil
+}
+
+// AlertRuleFromAlertRuleExport converts definitions.AlertRuleExport to models.AlertRule.
+// Empty no data and error states of alerting rules get the same defaults as in file provisioning.
+func AlertRuleFromAlertRuleExport(r definitions.AlertRuleExport) (models.AlertRule, error) {
+	rule := models.AlertRule{
+		UID:          r.UID,
+		Title:        r.Title,
+		For:          time.Duration(r.For),
+		DashboardUID: r.DashboardUID,
+		PanelID:      r.PanelID,
+		IsPaused:     r.IsPaused,
+		Record:       ModelRecordFromAlertRuleRecordExport(r.Record),
+		SuppressedBy: SuppressingRulesFromAlertRuleSuppressingRulesExport(r.SuppressedBy),
+	}
+	if r.ForString != nil {
+		d, err := model.ParseDuration(*r.ForString)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("failed to parse 'for' field: %w", err)
+		}
+		rule.For = time.Duration(d)
+	}
+	if r.Condition != nil {
+		rule.Condition = *r.Condition
+	}
+	if r.NoDataState != nil {
+		rule.NoDataState = models.NoDataState(*r.NoDataState)
+	} else if r.Record == nil {
+		rule.NoDataState = models.NoData
+	}
+	if r.ExecErrState != nil {
+		rule.ExecErrState = models.ExecutionErrorState(*r.ExecErrState)
+	} else if r.Record == nil {
+		rule.ExecErrState = models.AlertingErrState
+	}
+	if r.Annotations != nil {
+		rule.Annotations = *r.Annotations
+	}
+	if r.Labels != nil {
+		rule.Labels = *r.Labels
+	}
+	for _, q := range r.Data {
+		query, err := AlertQueryFromAlertQueryExport(q)
+		if err != nil {
+			return models.AlertRule{}, err
+		}
+		rule.Data = append(rule.Data, query)
+	}
+	ns, err := NotificationSettingsFromAlertRuleNotificationSettingsExport(r.NotificationSettings)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	rule.NotificationSettings = ns
+	return rule, nil
+}
+
+// AlertQueryFromAlertQueryExport converts definitions.AlertQueryExport to models.AlertQuery.
+// The model is taken from the JSON string of the HCL export if it is set.
+func AlertQueryFromAlertQueryExport(q definitions.AlertQueryExport) (models.AlertQuery, error) {
+	mdl := json.RawMessage(q.ModelString)
+	if q.ModelString == "" {
+		var err error
+		mdl, err = json.Marshal(q.Model)
+		if err != nil {
+			return models.AlertQuery{}, fmt.Errorf("failed to encode model of query %s: %w", q.RefID, err)
+		}
+	} else if !json.Valid(mdl) {
+		return models.AlertQuery{}, fmt.Errorf("model of query %s is not valid JSON", q.RefID)
+	}
+	query := models.AlertQuery{
+		RefID: q.RefID,
+		RelativeTimeRange: models.RelativeTimeRange{
+			From: models.Duration(time.Duration(q.RelativeTimeRange.FromSeconds) * time.Second),
+			To:   models.Duration(time.Duration(q.RelativeTimeRange.ToSeconds) * time.Second),
+		},
+		DatasourceUID: q.DatasourceUID,
+		Model:         mdl,
+	}
+	if q.QueryType != nil {
+		query.QueryType = *q.QueryType
+	}
+	return query, nil
+}
+
 // AlertingFileExportFromEmbeddedContactPoints creates a definitions.AlertingFileExport DTO from []definitions.EmbeddedContactPoint.
 func AlertingFileExportFromEmbeddedContactPoints(orgID int64, ecps []definitions.EmbeddedContactPoint) (definitions.AlertingFileExport, error) {
 	f := definitions.AlertingFileExport{APIVersion: 1}
@@ -470,6 +582,66 @@ func AlertRuleNotificationSettingsExportFromNotificationSettings(ns []models.Not
 	}
 }
 
+// SuppressingRulesFromApiSuppressingRules converts []definitions.SuppressingRule to []models.SuppressingRule
+func SuppressingRulesFromApiSuppressingRules(rules []definitions.SuppressingRule) []models.SuppressingRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	result := make([]models.SuppressingRule, 0, len(rules))
+	for _, r := range rules {
+		result = append(result, models.SuppressingRule{
+			RuleUID: r.RuleUID,
+			Equal:   r.Equal,
+		})
+	}
+	return result
+}
+
+// ApiSuppressingRulesFromSuppressingRules converts []models.SuppressingRule to []definitions.SuppressingRule
+func ApiSuppressingRulesFromSuppressingRules(rules []models.SuppressingRule) []definitions.SuppressingRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	result := make([]definitions.SuppressingRule, 0, len(rules))
+	for _, r := range rules {
+		result = append(result, definitions.SuppressingRule{
+			RuleUID: r.RuleUID,
+			Equal:   r.Equal,
+		})
+	}
+	return result
+}
+
+// AlertRuleSuppressingRulesExportFromSuppressingRules converts []models.SuppressingRule to []definitions.AlertRuleSuppressingRuleExport
+func AlertRuleSuppressingRulesExportFromSuppressingRules(rules []models.SuppressingRule) []definitions.AlertRuleSuppressingRuleExport {
+	if len(rules) == 0 {
+		return nil
+	}
+	result := make([]definitions.AlertRuleSuppressingRuleExport, 0, len(rules))
+	for _, r := range rules {
+		result = append(result, definitions.AlertRuleSuppressingRuleExport{
+			RuleUID: r.RuleUID,
+			Equal:   r.Equal,
+		})
+	}
+	return result
+}
+
+// SuppressingRulesFromAlertRuleSuppressingRulesExport converts []definitions.AlertRuleSuppressingRuleExport to []models.SuppressingRule
+func SuppressingRulesFromAlertRuleSuppressingRulesExport(rules []definitions.AlertRuleSuppressingRuleExport) []models.SuppressingRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	result := make([]models.SuppressingRule, 0, len(rules))
+	for _, r := range rules {
+		result = append(result, models.SuppressingRule{
+			RuleUID: r.RuleUID,
+			Equal:   r.Equal,
+		})
+	}
+	return result
+}
+
 // NotificationSettingsFromAlertRuleNotificationSettings converts definitions.AlertRuleNotificationSettings to []models.NotificationSettings
 func NotificationSettingsFromAlertRuleNotificationSettings(ns *definitions.AlertRuleNotificationSettings) []models.NotificationSettings {
 	if ns == nil {
@@ -487,6 +659,39 @@ func NotificationSettingsFromAlertRuleNotificationSettings(ns *definitions.Alert
 	}
 }
 
+// NotificationSettingsFromAlertRuleNotificationSettingsExport converts definitions.AlertRuleNotificationSettingsExport to []models.NotificationSettings
+func NotificationSettingsFromAlertRuleNotificationSettingsExport(ns *definitions.AlertRuleNotificationSettingsExport) ([]models.NotificationSettings, error) {
+	if ns == nil {
+		return nil, nil
+	}
+	parse := func(name string, s *string) (*model.Duration, error) {
+		if s == nil {
+			return nil, nil
+		}
+		d, err := model.ParseDuration(*s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse '%s' field of notification settings: %w", name, err)
+		}
+		return &d, nil
+	}
+	result := models.NotificationSettings{
+		Receiver:          ns.Receiver,
+		GroupBy:           ns.GroupBy,
+		MuteTimeIntervals: ns.MuteTimeIntervals,
+	}
+	var err error
+	if result.GroupWait, err = parse("group_wait", ns.GroupWait); err != nil {
+		return nil, err
+	}
+	if result.GroupInterval, err = parse("group_interval", ns.GroupInterval); err != nil {
+		return nil, err
+	}
+	if result.RepeatInterval, err = parse("repeat_interval", ns.RepeatInterval); err != nil {
+		return nil, err
+	}
+	return []models.NotificationSettings{result}, nil
+}
+
 func AlertRuleRecordExportFromRecord(r *models.Record) *definitions.AlertRuleRecordExport {
 	if r == nil {
 		return nil
@@ -497,6 +702,16 @@ func AlertRuleRecordExportFromRecord(r *models.Record) *definitions.AlertRuleRec
 	}
 }
 
+func ModelRecordFromAlertRuleRecordExport(r *definitions.AlertRuleRecordExport) *models.Record {
+	if r == nil {
+		return nil
+	}
+	return &models.Record{
+		Metric: r.Metric,
+		From:   r.From,
+	}
+}
+
 func ModelRecordFromApiRecord(r *definitions.Record) *models.Record {
 	if r == nil {
 		return nil
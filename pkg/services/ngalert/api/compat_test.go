@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
 func TestToModel(t *testing.T) {
@@ -35,3 +36,33 @@ func TestToModel(t *testing.T) {
 		require.Len(t, tm.Rules, 1)
 	})
 }
+
+func TestSuppressedByConversion(t *testing.T) {
+	suppressedBy := []models.SuppressingRule{
+		{RuleUID: "node-down", Equal: []string{"instance"}},
+		{RuleUID: "maintenance"},
+	}
+	rule := models.RuleGen.With(models.RuleGen.WithSuppressedBy(suppressedBy...)).Generate()
+
+	t.Run("provisioned alert rule", func(t *testing.T) {
+		provisioned := ProvisionedAlertRuleFromAlertRule(rule, models.ProvenanceNone)
+		require.Len(t, provisioned.SuppressedBy, 2)
+
+		converted, err := AlertRuleFromProvisionedAlertRule(provisioned)
+		require.NoError(t, err)
+		require.Equal(t, suppressedBy, converted.SuppressedBy)
+	})
+
+	t.Run("exported alert rule", func(t *testing.T) {
+		exported, err := AlertRuleExportFromAlertRule(rule)
+		require.NoError(t, err)
+		require.Equal(t, []definitions.AlertRuleSuppressingRuleExport{
+			{RuleUID: "node-down", Equal: []string{"instance"}},
+			{RuleUID: "maintenance"},
+		}, exported.SuppressedBy)
+
+		converted, err := AlertRuleFromAlertRuleExport(exported)
+		require.NoError(t, err)
+		require.Equal(t, suppressedBy, converted.SuppressedBy)
+	})
+}
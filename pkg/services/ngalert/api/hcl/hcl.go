@@ -1,10 +1,15 @@
 package hcl
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
 )
 
 type Resource struct {
@@ -28,3 +33,45 @@ func Encode(resources ...Resource) (data []byte, err error) {
 	}
 	return f.Bytes(), nil
 }
+
+var resourcesSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "resource", LabelNames: []string{"type", "name"}},
+	},
+}
+
+// evalContext contains the functions that are commonly used in resources, for example, to encode the model of a query.
+var evalContext = &hcl.EvalContext{
+	Functions: map[string]function.Function{
+		"jsondecode": stdlib.JSONDecodeFunc,
+		"jsonencode": stdlib.JSONEncodeFunc,
+	},
+}
+
+// Decode parses the resources of the HCL file and decodes their bodies to the values created by the function of the resource type.
+// Blocks other than resources are ignored. Returns an error if the file contains resources of types that are not in the map.
+func Decode(data []byte, filename string, bodies map[string]func() any) ([]Resource, error) {
+	file, diags := hclsyntax.ParseConfig(data, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, errors.New(diags.Error())
+	}
+	content, _, diags := file.Body.PartialContent(resourcesSchema)
+	if diags.HasErrors() {
+		return nil, errors.New(diags.Error())
+	}
+
+	resources := make([]Resource, 0, len(content.Blocks))
+	for _, block := range content.Blocks {
+		resource := Resource{Type: block.Labels[0], Name: block.Labels[1]}
+		newBody, ok := bodies[resource.Type]
+		if !ok {
+			return nil, fmt.Errorf("resource %s.%s: unsupported resource type", resource.Type, resource.Name)
+		}
+		resource.Body = newBody()
+		if diags := gohcl.DecodeBody(block.Body, evalContext, resource.Body); diags.HasErrors() {
+			return nil, errors.New(diags.Error())
+		}
+		resources = append(resources, resource)
+	}
+	return resources, nil
+}
@@ -73,3 +73,86 @@ func TestEncode(t *testing.T) {
 }
 `, string(encoded))
 }
+
+func TestDecode(t *testing.T) {
+	type query struct {
+		RefID string `hcl:"ref_id"`
+		Model string `hcl:"model"`
+	}
+	type data struct {
+		Name    string            `hcl:"name"`
+		Number  *float64          `hcl:"number,optional"`
+		Labels  map[string]string `hcl:"labels,optional"`
+		Queries []query           `hcl:"query,block"`
+	}
+	newData := map[string]func() any{
+		"grafana_test": func() any { return &data{} },
+	}
+
+	t.Run("decodes resources", func(t *testing.T) {
+		resources, err := Decode([]byte(`
+terraform {
+  required_providers {
+    grafana = {
+      source = "grafana/grafana"
+    }
+  }
+}
+
+resource "grafana_test" "test-01" {
+  name   = "test"
+  labels = { team = "a" }
+
+  query {
+    ref_id = "A"
+    model  = jsonencode({ expr = "up" })
+  }
+}
+
+resource "grafana_test" "test-02" {
+  name   = "test-2"
+  number = 2
+}
+`), "test.tf", newData)
+		require.NoError(t, err)
+		require.Equal(t, []Resource{
+			{
+				Type: "grafana_test",
+				Name: "test-01",
+				Body: &data{Name: "test", Labels: map[string]string{"team": "a"}, Queries: []query{{RefID: "A", Model: `{"expr":"up"}`}}},
+			},
+			{
+				Type: "grafana_test",
+				Name: "test-02",
+				Body: &data{Name: "test-2", Number: func(f float64) *float64 { return &f }(2)},
+			},
+		}, resources)
+	})
+
+	t.Run("decodes encoded resources", func(t *testing.T) {
+		body := &data{Name: "test", Labels: map[string]string{"team": "a"}, Queries: []query{{RefID: "A", Model: `{"expr":"up"}`}}}
+		encoded, err := Encode(Resource{Type: "grafana_test", Name: "test-01", Body: body})
+		require.NoError(t, err)
+
+		resources, err := Decode(encoded, "test.tf", newData)
+		require.NoError(t, err)
+		require.Equal(t, []Resource{{Type: "grafana_test", Name: "test-01", Body: body}}, resources)
+	})
+
+	t.Run("fails on unsupported resource types", func(t *testing.T) {
+		_, err := Decode([]byte(`resource "grafana_folder" "folder" {
+  title = "test"
+}`), "test.tf", newData)
+		require.ErrorContains(t, err, "grafana_folder.folder: unsupported resource type")
+	})
+
+	t.Run("fails on invalid resources", func(t *testing.T) {
+		_, err := Decode([]byte(`resource "grafana_test" "test-01" {
+  number = 1
+}`), "test.tf", newData)
+		require.ErrorContains(t, err, `The argument "name" is required`)
+
+		_, err = Decode([]byte(`resource "grafana_test" "test-01" {`), "test.tf", newData)
+		require.Error(t, err)
+	})
+}
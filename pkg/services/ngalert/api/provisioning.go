@@ -104,6 +104,10 @@ func (f *ProvisioningApiHandler) handleRoutePostAlertRule(ctx *contextmodel.ReqC
 	return f.svc.RoutePostAlertRule(ctx, ar)
 }
 
+func (f *ProvisioningApiHandler) handleRoutePostAlertRulesImport(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RoutePostAlertRulesImport(ctx)
+}
+
 func (f *ProvisioningApiHandler) handleRoutePutAlertRule(ctx *contextmodel.ReqContext, ar apimodels.ProvisionedAlertRule, UID string) response.Response {
 	return f.svc.RoutePutAlertRule(ctx, ar, UID)
 }
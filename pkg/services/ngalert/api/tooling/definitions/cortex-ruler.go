@@ -20,6 +20,61 @@ import (
 //       403: ForbiddenError
 //       404: description: Not found.
 
+// swagger:route Get /ruler/grafana/api/v1/rule/{RuleUID}/versions ruler RouteGetRuleVersionsByUID
+//
+// List the stored versions of a rule, newest first
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: GettableRuleVersions
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route Get /ruler/grafana/api/v1/rule/{RuleUID}/versions/diff ruler RouteGetRuleVersionsDiff
+//
+// Compare two versions of a rule
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: GettableRuleVersionsDiff
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route POST /ruler/grafana/api/v1/rule/{RuleUID}/versions/{Version}/restore ruler RouteRestoreRuleVersion
+//
+// Restore a version of a rule. The rule keeps its group, and the group is updated as if it was submitted by the user.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       202: UpdateRuleGroupResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:route POST /ruler/grafana/api/v1/bulk/rules ruler RouteBulkUpdateRules
+//
+// Apply an action to all rules that match the selector. All affected rule groups are updated in a single transaction
+// with the same validation, authorization and provenance checks as if they were submitted by the user.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       202: BulkRulesResponse
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+
 // swagger:route Get /ruler/grafana/api/v1/rules ruler RouteGetGrafanaRulesConfig
 //
 // List rule groups
@@ -218,12 +273,109 @@ type PathGetRulesParams struct {
 	PanelID int64
 }
 
-// swagger:parameters RouteGetRuleByUID
+// swagger:parameters RouteGetRuleByUID RouteGetRuleVersionsByUID
 type PathGetRuleByUIDParams struct {
 	// in: path
 	RuleUID string
 }
 
+// swagger:parameters RouteGetRuleVersionsDiff
+type PathGetRuleVersionsDiffParams struct {
+	// in: path
+	RuleUID string
+	// Version to compare from
+	// in: query
+	// required: true
+	From int64 `json:"from"`
+	// Version to compare to
+	// in: query
+	// required: true
+	To int64 `json:"to"`
+}
+
+// swagger:parameters RouteRestoreRuleVersion
+type PathRestoreRuleVersionParams struct {
+	// in: path
+	RuleUID string
+	// in: path
+	Version int64
+}
+
+// swagger:parameters RouteBulkUpdateRules
+type BulkRulesParams struct {
+	// in:body
+	Body PostableBulkRulesCommand
+}
+
+// swagger:model
+type GettableRuleVersions []GettableExtendedRuleNode
+
+// swagger:model
+type GettableRuleVersionsDiff struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+	// Changes are the fields that differ between the versions. Fields that change with every version, such as the version itself, are not compared.
+	Changes []RuleVersionChange `json:"changes"`
+}
+
+type RuleVersionChange struct {
+	// Path is the path to the field that changed, for example Labels[team] or Data[0].Model.
+	Path string `json:"path"`
+	// From is the value of the field in the version compared from. It is missing if the field was added.
+	From any `json:"from,omitempty"`
+	// To is the value of the field in the version compared to. It is missing if the field was removed.
+	To any `json:"to,omitempty"`
+}
+
+// BulkRulesAction is the action that is applied to the rules selected by a bulk request.
+// swagger:enum BulkRulesAction
+type BulkRulesAction string
+
+const (
+	BulkRulesActionPause   BulkRulesAction = "pause"
+	BulkRulesActionUnpause BulkRulesAction = "unpause"
+	BulkRulesActionPatch   BulkRulesAction = "patch"
+	BulkRulesActionMove    BulkRulesAction = "move"
+	BulkRulesActionDelete  BulkRulesAction = "delete"
+)
+
+// swagger:model
+type PostableBulkRulesCommand struct {
+	// required: true
+	Selector BulkRulesSelector `json:"selector"`
+	// required: true
+	// enum: pause,unpause,patch,move,delete
+	Action BulkRulesAction `json:"action"`
+	// Labels are added to the rules by the patch action. A label with an empty value is removed from the rules.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are added to the rules by the patch action. An annotation with an empty value is removed from the rules.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// TargetFolderUID is the folder the move action moves the rules to. The rules keep their group names.
+	TargetFolderUID string `json:"targetFolderUid,omitempty"`
+	// DryRun returns the rules that would be changed by the action without changing them.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// BulkRulesSelector selects the rules that match all of the specified fields. At least one field must be specified.
+type BulkRulesSelector struct {
+	FolderUIDs []string `json:"folderUids,omitempty"`
+	Groups     []string `json:"groups,omitempty"`
+	// Matchers select rules by their labels.
+	Matchers ObjectMatchers `json:"matchers,omitempty"`
+	// DatasourceUIDs select rules that query any of the data sources.
+	DatasourceUIDs []string `json:"datasourceUids,omitempty"`
+}
+
+// swagger:model
+type BulkRulesResponse struct {
+	Message string `json:"message"`
+	DryRun  bool   `json:"dryRun"`
+	// Updated contains UIDs of the selected rules that are changed by the action.
+	Updated []string `json:"updated"`
+	// Deleted contains UIDs of the selected rules that are deleted by the action.
+	Deleted []string `json:"deleted"`
+}
+
 // swagger:model
 type RuleGroupConfigResponse struct {
 	GettableRuleGroupConfig
@@ -506,6 +658,21 @@ type AlertRuleNotificationSettings struct {
 	MuteTimeIntervals []string `json:"mute_time_intervals,omitempty"`
 }
 
+// SuppressingRule declares that the alerts of a rule are suppressed while another rule has firing alerts with the same
+// values of the labels in Equal. Suppressed alerts are Normal with the reason Suppressed.
+// swagger:model
+type SuppressingRule struct {
+	// UID of the rule whose firing alerts suppress the alerts of this rule.
+	// required: true
+	// example: node-down
+	RuleUID string `json:"rule_uid" yaml:"rule_uid"`
+
+	// Labels that must have the same value in the firing alert and in the suppressed one.
+	// If empty, any firing alert of the rule suppresses all alerts of this rule.
+	// example: ["instance"]
+	Equal []string `json:"equal,omitempty" yaml:"equal,omitempty"`
+}
+
 // swagger:model
 type Record struct {
 	// Name of the recorded metric.
@@ -530,6 +697,7 @@ type PostableGrafanaRule struct {
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings" yaml:"notification_settings"`
 	Record               *Record                        `json:"record" yaml:"record"`
 	Metadata             *AlertRuleMetadata             `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	SuppressedBy         []SuppressingRule              `json:"suppressed_by,omitempty" yaml:"suppressed_by,omitempty"`
 }
 
 // swagger:model
@@ -552,6 +720,8 @@ type GettableGrafanaRule struct {
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty"`
 	Record               *Record                        `json:"record,omitempty" yaml:"record,omitempty"`
 	Metadata             *AlertRuleMetadata             `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	UpdatedBy            *string                        `json:"updated_by,omitempty" yaml:"updated_by,omitempty"`
+	SuppressedBy         []SuppressingRule              `json:"suppressed_by,omitempty" yaml:"suppressed_by,omitempty"`
 }
 
 // AlertQuery represents a single query associated with an alert definition.
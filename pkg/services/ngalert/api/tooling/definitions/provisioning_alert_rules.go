@@ -80,6 +80,23 @@ import (
 //     Responses:
 //       204: description: The alert rule was deleted successfully.
 
+// swagger:route POST /v1/provisioning/alert-rules/import provisioning stable RoutePostAlertRulesImport
+//
+// Import alert rule groups in provisioning file format or HCL. Every group in the file replaces the group with the same name
+// in the folder. All groups are imported in a single transaction. Rules without UID are matched to the existing rules in the folder by title,
+// and rules with UID that does not exist are created with this UID.
+//
+//     Consumes:
+//     - application/json
+//     - application/yaml
+//     - text/yaml
+//     - application/terraform+hcl
+//     - text/hcl
+//
+//     Responses:
+//       200: AlertRulesImportResponse
+//       400: ValidationError
+
 // swagger:parameters RouteGetAlertRulesExport RouteGetRulesForExport
 type AlertRulesExportParameters struct {
 	ExportQueryParams
@@ -112,12 +129,26 @@ type AlertRulePayload struct {
 	Body ProvisionedAlertRule
 }
 
-// swagger:parameters RoutePostAlertRule RoutePutAlertRule RouteDeleteAlertRule RoutePutAlertRuleGroup
+// swagger:parameters RoutePostAlertRule RoutePutAlertRule RouteDeleteAlertRule RoutePutAlertRuleGroup RoutePostAlertRulesImport
 type AlertRuleHeaders struct {
 	// in:header
 	XDisableProvenance string `json:"X-Disable-Provenance"`
 }
 
+// swagger:parameters RoutePostAlertRulesImport
+type AlertRulesImportParameters struct {
+	// Calculate the changes without applying them.
+	// in:query
+	// required:false
+	// default:false
+	DryRun bool `json:"dryRun"`
+
+	// The file in provisioning file format, as returned by the export, or HCL with grafana_rule_group resources.
+	// Groups in provisioning file format are imported to the folder with the title in field folder.
+	// in:body
+	Body AlertingFileExport
+}
+
 // swagger:model
 type ProvisionedAlertRules []ProvisionedAlertRule
 
@@ -170,6 +201,8 @@ type ProvisionedAlertRule struct {
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings"`
 	//example: {"metric":"grafana_alerts_ratio", "from":"A"}
 	Record *Record `json:"record"`
+	// example: [{"rule_uid":"node-down","equal":["instance"]}]
+	SuppressedBy []SuppressingRule `json:"suppressed_by,omitempty"`
 }
 
 // swagger:route GET /v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteGetAlertRuleGroup
@@ -248,7 +281,7 @@ type AlertRuleGroup struct {
 
 // AlertRuleGroupExport is the provisioned file export of AlertRuleGroupV1.
 type AlertRuleGroupExport struct {
-	OrgID           int64             `json:"orgId" yaml:"orgId" hcl:"org_id"`
+	OrgID           int64             `json:"orgId" yaml:"orgId" hcl:"org_id,optional"`
 	Name            string            `json:"name" yaml:"name" hcl:"name"`
 	Folder          string            `json:"folder" yaml:"folder"`
 	FolderUID       string            `json:"-" yaml:"-" hcl:"folder_uid"`
@@ -261,28 +294,29 @@ type AlertRuleGroupExport struct {
 type AlertRuleExport struct {
 	UID          string               `json:"uid,omitempty" yaml:"uid,omitempty"`
 	Title        string               `json:"title" yaml:"title" hcl:"name"`
-	Condition    *string              `json:"condition,omitempty" yaml:"condition,omitempty" hcl:"condition"`
+	Condition    *string              `json:"condition,omitempty" yaml:"condition,omitempty" hcl:"condition,optional"`
 	Data         []AlertQueryExport   `json:"data" yaml:"data" hcl:"data,block"`
 	DashboardUID *string              `json:"dashboardUid,omitempty" yaml:"dashboardUid,omitempty"`
 	PanelID      *int64               `json:"panelId,omitempty" yaml:"panelId,omitempty"`
-	NoDataState  *NoDataState         `json:"noDataState,omitempty" yaml:"noDataState,omitempty" hcl:"no_data_state"`
-	ExecErrState *ExecutionErrorState `json:"execErrState,omitempty" yaml:"execErrState,omitempty" hcl:"exec_err_state"`
+	NoDataState  *NoDataState         `json:"noDataState,omitempty" yaml:"noDataState,omitempty" hcl:"no_data_state,optional"`
+	ExecErrState *ExecutionErrorState `json:"execErrState,omitempty" yaml:"execErrState,omitempty" hcl:"exec_err_state,optional"`
 	For          model.Duration       `json:"for,omitempty" yaml:"for,omitempty"`
 	// ForString is used to:
 	// - Only export the for field for HCL if it is non-zero.
 	// - Format the Prometheus model.Duration type properly for HCL.
-	ForString            *string                              `json:"-" yaml:"-" hcl:"for"`
-	Annotations          *map[string]string                   `json:"annotations,omitempty" yaml:"annotations,omitempty" hcl:"annotations"`
-	Labels               *map[string]string                   `json:"labels,omitempty" yaml:"labels,omitempty" hcl:"labels"`
-	IsPaused             bool                                 `json:"isPaused" yaml:"isPaused" hcl:"is_paused"`
+	ForString            *string                              `json:"-" yaml:"-" hcl:"for,optional"`
+	Annotations          *map[string]string                   `json:"annotations,omitempty" yaml:"annotations,omitempty" hcl:"annotations,optional"`
+	Labels               *map[string]string                   `json:"labels,omitempty" yaml:"labels,omitempty" hcl:"labels,optional"`
+	IsPaused             bool                                 `json:"isPaused" yaml:"isPaused" hcl:"is_paused,optional"`
 	NotificationSettings *AlertRuleNotificationSettingsExport `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty" hcl:"notification_settings,block"`
 	Record               *AlertRuleRecordExport               `json:"record,omitempty" yaml:"record,omitempty" hcl:"record,block"`
+	SuppressedBy         []AlertRuleSuppressingRuleExport     `json:"suppressed_by,omitempty" yaml:"suppressed_by,omitempty" hcl:"suppressed_by,block"`
 }
 
 // AlertQueryExport is the provisioned export of models.AlertQuery.
 type AlertQueryExport struct {
 	RefID             string                  `json:"refId" yaml:"refId" hcl:"ref_id"`
-	QueryType         *string                 `json:"queryType,omitempty" yaml:"queryType,omitempty" hcl:"query_type"`
+	QueryType         *string                 `json:"queryType,omitempty" yaml:"queryType,omitempty" hcl:"query_type,optional"`
 	RelativeTimeRange RelativeTimeRangeExport `json:"relativeTimeRange,omitempty" yaml:"relativeTimeRange,omitempty" hcl:"relative_time_range,block"`
 	DatasourceUID     string                  `json:"datasourceUid" yaml:"datasourceUid" hcl:"datasource_uid"`
 	Model             map[string]any          `json:"model" yaml:"model"`
@@ -299,11 +333,11 @@ type AlertRuleNotificationSettingsExport struct {
 	// Field name mismatches with Terraform provider schema are noted where applicable.
 
 	Receiver          string   `yaml:"receiver,omitempty" json:"receiver,omitempty" hcl:"contact_point"` // TF -> `contact_point`
-	GroupBy           []string `yaml:"group_by,omitempty" json:"group_by,omitempty" hcl:"group_by"`
+	GroupBy           []string `yaml:"group_by,omitempty" json:"group_by,omitempty" hcl:"group_by,optional"`
 	GroupWait         *string  `yaml:"group_wait,omitempty" json:"group_wait,omitempty" hcl:"group_wait,optional"`
 	GroupInterval     *string  `yaml:"group_interval,omitempty" json:"group_interval,omitempty" hcl:"group_interval,optional"`
 	RepeatInterval    *string  `yaml:"repeat_interval,omitempty" json:"repeat_interval,omitempty" hcl:"repeat_interval,optional"`
-	MuteTimeIntervals []string `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty" hcl:"mute_timings"` // TF -> `mute_timings`
+	MuteTimeIntervals []string `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty" hcl:"mute_timings,optional"` // TF -> `mute_timings`
 }
 
 // Record is the provisioned export of models.Record.
@@ -311,3 +345,32 @@ type AlertRuleRecordExport struct {
 	Metric string `json:"metric" yaml:"metric" hcl:"metric"`
 	From   string `json:"from" yaml:"from" hcl:"from"`
 }
+
+// AlertRuleSuppressingRuleExport is the provisioned export of models.SuppressingRule.
+type AlertRuleSuppressingRuleExport struct {
+	RuleUID string   `json:"rule_uid" yaml:"rule_uid" hcl:"rule_uid"`
+	Equal   []string `json:"equal,omitempty" yaml:"equal,omitempty" hcl:"equal,optional"`
+}
+
+// swagger:model
+type AlertRulesImportResponse struct {
+	DryRun bool                          `json:"dryRun"`
+	Groups []AlertRuleGroupImportChanges `json:"groups"`
+}
+
+// AlertRuleGroupImportChanges are the changes to a rule group made by an import.
+type AlertRuleGroupImportChanges struct {
+	FolderUID string                  `json:"folderUid"`
+	Title     string                  `json:"title"`
+	Created   []AlertRuleImportChange `json:"created"`
+	Updated   []AlertRuleImportChange `json:"updated"`
+	Deleted   []AlertRuleImportChange `json:"deleted"`
+}
+
+type AlertRuleImportChange struct {
+	// UID is empty for the rules that would be created in dry run.
+	UID   string `json:"uid,omitempty"`
+	Title string `json:"title"`
+	// Fields are the names of the changed fields of the updated rules.
+	Fields []string `json:"fields,omitempty"`
+}
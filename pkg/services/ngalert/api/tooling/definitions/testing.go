@@ -68,6 +68,23 @@ import (
 //     Responses:
 //       200: BacktestResult
 
+// swagger:route Post /v1/rule/backtest/simulate testing BacktestSimulation
+//
+// Test rule and simulate its notifications
+//
+// Evaluates the rule over the historical data and returns the state timeline of every alert instance and the notifications
+// that the notification policies and mute timings of the Grafana Alertmanager of the organization would have sent.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: BacktestSimulationResult
+//       400: ValidationError
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -131,6 +148,7 @@ type EvalQueriesPayload struct {
 	Condition string       `json:"condition"`
 	Data      []AlertQuery `json:"data"`
 	Now       time.Time    `json:"now"`
+	Explain   bool         `json:"explain"`
 }
 
 func (p *TestRulePayload) UnmarshalJSON(b []byte) error {
@@ -209,7 +227,7 @@ type ResponseDetails struct {
 	Msg string `json:"msg"`
 }
 
-// swagger:parameters BacktestConfig
+// swagger:parameters BacktestConfig BacktestSimulation
 type BacktestConfigRequest struct {
 	// in:body
 	Body BacktestConfig
@@ -229,8 +247,44 @@ type BacktestConfig struct {
 	Labels      map[string]string `json:"labels,omitempty"`
 	Annotations map[string]string `json:"annotations,omitempty"`
 
-	NoDataState NoDataState `json:"no_data_state"`
+	NoDataState  NoDataState         `json:"no_data_state"`
+	ExecErrState ExecutionErrorState `json:"exec_err_state,omitempty"`
 }
 
 // swagger:model
 type BacktestResult data.Frame
+
+// swagger:model
+type BacktestSimulationResult struct {
+	// Timeline contains the periods of states of every alert instance of the rule.
+	Timeline []BacktestInstanceTimeline `json:"timeline"`
+	// Notifications contains the notifications in the order they would have been sent.
+	Notifications []BacktestNotification `json:"notifications"`
+}
+
+// swagger:model
+type BacktestInstanceTimeline struct {
+	Labels map[string]string     `json:"labels"`
+	States []BacktestStatePeriod `json:"states"`
+}
+
+// swagger:model
+type BacktestStatePeriod struct {
+	// example: Alerting
+	State string `json:"state"`
+	// example: NoData
+	Reason string    `json:"reason,omitempty"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+}
+
+// swagger:model
+type BacktestNotification struct {
+	Time        time.Time           `json:"time"`
+	Receiver    string              `json:"receiver"`
+	GroupLabels map[string]string   `json:"groupLabels"`
+	Firing      []map[string]string `json:"firing"`
+	Resolved    []map[string]string `json:"resolved"`
+	// Muted is true if the notification would not have been sent because of a mute timing or an active time interval.
+	Muted bool `json:"muted"`
+}
@@ -64,14 +64,76 @@ func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory, tracer tracin
 }
 
 func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time) (*data.Frame, error) {
+	var tsField *data.Field
+	valueFields := make(map[data.Fingerprint]*data.Field)
+
+	err := e.run(ctx, user, rule, from, to, nil, func(length int) {
+		tsField = data.NewField("Time", nil, make([]time.Time, length))
+	}, func(idx int, length int, currentTime time.Time, states state.StateTransitions) {
+		tsField.Set(idx, currentTime)
+		for _, s := range states {
+			field, ok := valueFields[s.CacheID]
+			if !ok {
+				field = data.NewField("", s.Labels, make([]*string, length))
+				valueFields[s.CacheID] = field
+			}
+			if s.State.State != eval.NoData { // set nil if NoData
+				value := s.State.State.String()
+				if s.StateReason != "" {
+					value += " (" + s.StateReason + ")"
+				}
+				field.Set(idx, &value)
+				continue
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]*data.Field, 0, len(valueFields)+1)
+	fields = append(fields, tsField)
+	for _, f := range valueFields {
+		fields = append(fields, f)
+	}
+	return data.NewFrame("Testing results", fields...), nil
+}
+
+// Simulate evaluates the rule over the time range in the same way as Test. It returns the periods of states of every
+// alert instance and the notifications that the notification policies would have sent for the alerts of the rule.
+func (e *Engine) Simulate(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time, policies NotificationPolicies) (*Simulation, error) {
+	timeline := newTimelineBuilder(time.Duration(rule.IntervalSeconds) * time.Second)
+	simulator := newNotificationSimulator(policies)
+
+	// The alerts get the same built-in labels as in the scheduler so they match the notification policies the same way.
+	extraLabels := state.GetRuleExtraLabels(logger.FromContext(ctx), rule, "", false)
+	err := e.run(ctx, user, rule, from, to, extraLabels, nil, func(_ int, _ int, currentTime time.Time, states state.StateTransitions) {
+		timeline.add(currentTime, states)
+		simulator.advance(currentTime)
+		simulator.receive(currentTime, alertsFromStates(states, currentTime))
+	})
+	if err != nil {
+		return nil, err
+	}
+	simulator.advance(to)
+
+	return &Simulation{
+		Timeline:      timeline.build(),
+		Notifications: simulator.notifications,
+	}, nil
+}
+
+// run evaluates the rule at every interval in the range [from, to) and calls the callback with the state transitions of each evaluation.
+// The extra labels are added to the labels of every state. The init callback, if set, is called with the number of evaluations before the first evaluation.
+func (e *Engine) run(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time, extraLabels data.Labels, init func(length int), callback func(idx int, length int, now time.Time, states state.StateTransitions)) error {
 	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
 	logger := logger.FromContext(ctx)
 
 	if !from.Before(to) {
-		return nil, fmt.Errorf("%w: invalid interval of the backtesting [%d,%d]", ErrInvalidInputData, from.Unix(), to.Unix())
+		return fmt.Errorf("%w: invalid interval of the backtesting [%d,%d]", ErrInvalidInputData, from.Unix(), to.Unix())
 	}
 	if to.Sub(from).Seconds() < float64(rule.IntervalSeconds) {
-		return nil, fmt.Errorf("%w: interval of the backtesting [%d,%d] is less than evaluation interval [%ds]", ErrInvalidInputData, from.Unix(), to.Unix(), rule.IntervalSeconds)
+		return fmt.Errorf("%w: interval of the backtesting [%d,%d] is less than evaluation interval [%ds]", ErrInvalidInputData, from.Unix(), to.Unix(), rule.IntervalSeconds)
 	}
 	length := int(to.Sub(from).Seconds()) / int(rule.IntervalSeconds)
 
@@ -82,52 +144,30 @@ func (e *Engine) Test(ctx context.Context, user identity.Requester, rule *models
 		Rule:    rule,
 	})
 	if err != nil {
-		return nil, errors.Join(ErrInvalidInputData, err)
+		return errors.Join(ErrInvalidInputData, err)
 	}
 
 	logger.Info("Start testing alert rule", "from", from, "to", to, "interval", rule.IntervalSeconds, "evaluations", length)
 
 	start := time.Now()
-
-	tsField := data.NewField("Time", nil, make([]time.Time, length))
-	valueFields := make(map[data.Fingerprint]*data.Field)
+	if init != nil {
+		init(length)
+	}
 
 	err = evaluator.Eval(ruleCtx, from, time.Duration(rule.IntervalSeconds)*time.Second, length, func(idx int, currentTime time.Time, results eval.Results) error {
 		if idx >= length {
 			logger.Info("Unexpected evaluation. Skipping", "from", from, "to", to, "interval", rule.IntervalSeconds, "evaluationTime", currentTime, "evaluationIndex", idx, "expectedEvaluations", length)
 			return nil
 		}
-		states := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, nil, nil)
-		tsField.Set(idx, currentTime)
-		for _, s := range states {
-			field, ok := valueFields[s.CacheID]
-			if !ok {
-				field = data.NewField("", s.Labels, make([]*string, length))
-				valueFields[s.CacheID] = field
-			}
-			if s.State.State != eval.NoData { // set nil if NoData
-				value := s.State.State.String()
-				if s.StateReason != "" {
-					value += " (" + s.StateReason + ")"
-				}
-				field.Set(idx, &value)
-				continue
-			}
-		}
+		states := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, extraLabels, nil)
+		callback(idx, length, currentTime, states)
 		return nil
 	})
-	fields := make([]*data.Field, 0, len(valueFields)+1)
-	fields = append(fields, tsField)
-	for _, f := range valueFields {
-		fields = append(fields, f)
-	}
-	result := data.NewFrame("Testing results", fields...)
-
 	if err != nil {
-		return nil, err
+		return err
 	}
 	logger.Info("Rule testing finished successfully", "duration", time.Since(start))
-	return result, nil
+	return nil
 }
 
 func newBacktestingEvaluator(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition, reader eval.AlertingResultsReader) (backtestingEvaluator, error) {
@@ -79,6 +79,9 @@ func (d *dataEvaluator) Eval(_ context.Context, from time.Time, interval time.Du
 				EvaluatedAt: now,
 			})
 		}
+		if now.IsZero() { // there is no series in the data, so the time of the evaluation is the beginning of the interval
+			now = from.Add(time.Duration(i) * interval)
+		}
 		if len(result) == 0 {
 			result = append(result, eval.Result{
 				State:       eval.NoData,
@@ -290,4 +290,17 @@ func TestDataEvaluator_Eval(t *testing.T) {
 		})
 		require.ErrorIs(t, err, expectedError)
 	})
+	t.Run("should return NoData at the time of evaluation if there are no series", func(t *testing.T) {
+		empty := &dataEvaluator{refID: refID}
+		times := make([]time.Time, 0, 3)
+		err := empty.Eval(context.Background(), from, time.Second, 3, func(idx int, now time.Time, res eval.Results) error {
+			require.Len(t, res, 1)
+			require.Equal(t, eval.NoData, res[0].State)
+			require.Equal(t, now, res[0].EvaluatedAt)
+			times = append(times, now)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []time.Time{from, from.Add(time.Second), from.Add(2 * time.Second)}, times)
+	})
 }
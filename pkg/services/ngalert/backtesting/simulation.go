@@ -0,0 +1,338 @@
+package backtesting
+
+import (
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// Simulation is the result of backtesting of an alert rule with its notifications.
+type Simulation struct {
+	// Timeline contains the periods of states of every alert instance of the rule.
+	Timeline []InstanceTimeline
+	// Notifications contains the notifications sent for the alerts of the rule in the order they were sent.
+	Notifications []Notification
+}
+
+// InstanceTimeline is the sequence of states of a single alert instance.
+type InstanceTimeline struct {
+	Labels data.Labels
+	States []StatePeriod
+}
+
+// StatePeriod is a period of time [From, To) during which an alert instance was in the same state.
+type StatePeriod struct {
+	State  eval.State
+	Reason string
+	From   time.Time
+	To     time.Time
+}
+
+// Notification is a notification of a group of alerts to a receiver.
+type Notification struct {
+	Time        time.Time
+	Receiver    string
+	GroupLabels data.Labels
+	Firing      []data.Labels
+	Resolved    []data.Labels
+	// Muted is true if the notification was not sent because of the mute timings or active time intervals of the notification policy.
+	Muted bool
+}
+
+// NotificationPolicies are the notification policies and time intervals of an Alertmanager configuration.
+type NotificationPolicies struct {
+	Route         *config.Route
+	TimeIntervals map[string][]timeinterval.TimeInterval
+}
+
+type timelineBuilder struct {
+	interval  time.Duration
+	instances map[data.Fingerprint]*InstanceTimeline
+	order     []data.Fingerprint
+}
+
+func newTimelineBuilder(interval time.Duration) *timelineBuilder {
+	return &timelineBuilder{
+		interval:  interval,
+		instances: make(map[data.Fingerprint]*InstanceTimeline),
+	}
+}
+
+// add extends the periods of the instances by the states of the evaluation at the time now.
+// A period is extended only if the instance was in the same state at the previous evaluation.
+func (b *timelineBuilder) add(now time.Time, states state.StateTransitions) {
+	for _, s := range states {
+		timeline, ok := b.instances[s.CacheID]
+		if !ok {
+			timeline = &InstanceTimeline{Labels: s.Labels}
+			b.instances[s.CacheID] = timeline
+			b.order = append(b.order, s.CacheID)
+		}
+		if l := len(timeline.States); l > 0 {
+			last := &timeline.States[l-1]
+			if last.State == s.State.State && last.Reason == s.StateReason && last.To.Equal(now) {
+				last.To = now.Add(b.interval)
+				continue
+			}
+		}
+		timeline.States = append(timeline.States, StatePeriod{
+			State:  s.State.State,
+			Reason: s.StateReason,
+			From:   now,
+			To:     now.Add(b.interval),
+		})
+	}
+}
+
+func (b *timelineBuilder) build() []InstanceTimeline {
+	result := make([]InstanceTimeline, 0, len(b.order))
+	for _, fp := range b.order {
+		result = append(result, *b.instances[fp])
+	}
+	return result
+}
+
+type simulatedAlert struct {
+	labels   model.LabelSet
+	startsAt time.Time
+	endsAt   time.Time
+}
+
+func (a simulatedAlert) resolved(now time.Time) bool {
+	return !a.endsAt.IsZero() && !a.endsAt.After(now)
+}
+
+// alertsFromStates returns the alerts that the scheduler would send to the Alertmanager after the evaluation at the time now.
+// Pending and Normal states are not sent unless the state was resolved by the evaluation.
+func alertsFromStates(states state.StateTransitions, now time.Time) []simulatedAlert {
+	result := make([]simulatedAlert, 0, len(states))
+	for _, s := range states {
+		switch s.State.State {
+		case eval.Pending:
+			continue
+		case eval.Normal:
+			if s.ResolvedAt == nil || !s.ResolvedAt.Equal(now) {
+				continue
+			}
+		}
+		alert := state.StateToPostableAlert(s, nil)
+		labels := make(model.LabelSet, len(alert.Labels))
+		for k, v := range alert.Labels {
+			labels[model.LabelName(k)] = model.LabelValue(v)
+		}
+		result = append(result, simulatedAlert{
+			labels:   labels,
+			startsAt: time.Time(alert.StartsAt),
+			endsAt:   time.Time(alert.EndsAt),
+		})
+	}
+	return result
+}
+
+type aggregationGroup struct {
+	key    string
+	route  *dispatch.Route
+	labels model.LabelSet
+	alerts map[model.Fingerprint]simulatedAlert
+	next   time.Time
+}
+
+type notificationLogEntry struct {
+	firing   map[model.Fingerprint]struct{}
+	resolved map[model.Fingerprint]struct{}
+	time     time.Time
+}
+
+// notificationSimulator replays alerts through the notification policies the same way as the dispatcher of the Alertmanager:
+// alerts are aggregated in groups per matching policy, groups are flushed after the group wait and then at every group interval,
+// and a notification is sent only if the group changed since the last notification or the repeat interval has passed.
+// It assumes that all receivers send resolved notifications.
+type notificationSimulator struct {
+	route         *dispatch.Route
+	intervener    *timeinterval.Intervener
+	groups        map[string]*aggregationGroup
+	log           map[string]notificationLogEntry
+	notifications []Notification
+}
+
+func newNotificationSimulator(policies NotificationPolicies) *notificationSimulator {
+	var route *dispatch.Route
+	if policies.Route != nil {
+		route = dispatch.NewRoute(policies.Route, nil)
+	}
+	return &notificationSimulator{
+		route:         route,
+		intervener:    timeinterval.NewIntervener(policies.TimeIntervals),
+		groups:        make(map[string]*aggregationGroup),
+		log:           make(map[string]notificationLogEntry),
+		notifications: make([]Notification, 0),
+	}
+}
+
+// receive adds the alerts to the groups of the matching notification policies.
+func (s *notificationSimulator) receive(now time.Time, alerts []simulatedAlert) {
+	if s.route == nil {
+		return
+	}
+	for _, alert := range alerts {
+		for _, r := range s.route.Match(alert.labels) {
+			groupLabels := getGroupLabels(alert.labels, r)
+			key := r.Key() + ":" + groupLabels.String()
+			group, ok := s.groups[key]
+			if !ok {
+				group = &aggregationGroup{
+					key:    key,
+					route:  r,
+					labels: groupLabels,
+					alerts: make(map[model.Fingerprint]simulatedAlert),
+					next:   now.Add(r.RouteOpts.GroupWait),
+				}
+				s.groups[key] = group
+			}
+			group.alerts[alert.labels.Fingerprint()] = alert
+		}
+	}
+}
+
+// advance flushes all groups that are due until the time now inclusive.
+func (s *notificationSimulator) advance(now time.Time) {
+	for {
+		var group *aggregationGroup
+		for _, g := range s.groups {
+			if g.next.After(now) {
+				continue
+			}
+			if group == nil || g.next.Before(group.next) || (g.next.Equal(group.next) && g.key < group.key) {
+				group = g
+			}
+		}
+		if group == nil {
+			return
+		}
+		s.flush(group, group.next)
+		group.next = group.next.Add(group.route.RouteOpts.GroupInterval)
+		if len(group.alerts) == 0 {
+			delete(s.groups, group.key)
+		}
+	}
+}
+
+func (s *notificationSimulator) flush(group *aggregationGroup, now time.Time) {
+	firing := make(map[model.Fingerprint]struct{})
+	resolved := make(map[model.Fingerprint]struct{})
+	var firingLabels, resolvedLabels []data.Labels
+	for fp, alert := range group.alerts {
+		if alert.resolved(now) {
+			resolved[fp] = struct{}{}
+			resolvedLabels = append(resolvedLabels, labelsFromLabelSet(alert.labels))
+		} else {
+			firing[fp] = struct{}{}
+			firingLabels = append(firingLabels, labelsFromLabelSet(alert.labels))
+		}
+	}
+	// Resolved alerts are removed from the group after the flush.
+	for fp := range resolved {
+		delete(group.alerts, fp)
+	}
+
+	entry, ok := s.log[group.key]
+	if !needsUpdate(entry, ok, firing, resolved, now, group.route.RouteOpts.RepeatInterval) {
+		return
+	}
+
+	muted := s.muted(group.route, now)
+	sortLabels(firingLabels)
+	sortLabels(resolvedLabels)
+	s.notifications = append(s.notifications, Notification{
+		Time:        now,
+		Receiver:    group.route.RouteOpts.Receiver,
+		GroupLabels: labelsFromLabelSet(group.labels),
+		Firing:      firingLabels,
+		Resolved:    resolvedLabels,
+		Muted:       muted,
+	})
+	// Muted notifications are not recorded in the notification log, so they are sent at the next flush if the time interval is over.
+	if !muted {
+		s.log[group.key] = notificationLogEntry{
+			firing:   firing,
+			resolved: resolved,
+			time:     now,
+		}
+	}
+}
+
+// muted returns true if the time is in one of the mute time intervals of the route or not in any of its active time intervals.
+func (s *notificationSimulator) muted(r *dispatch.Route, now time.Time) bool {
+	if len(r.RouteOpts.MuteTimeIntervals) > 0 {
+		if muted, err := s.intervener.Mutes(r.RouteOpts.MuteTimeIntervals, now); err == nil && muted {
+			return true
+		}
+	}
+	if len(r.RouteOpts.ActiveTimeIntervals) > 0 {
+		if active, err := s.intervener.Mutes(r.RouteOpts.ActiveTimeIntervals, now); err == nil && !active {
+			return true
+		}
+	}
+	return false
+}
+
+// needsUpdate mirrors the deduplication of notifications in the Alertmanager.
+func needsUpdate(entry notificationLogEntry, exists bool, firing, resolved map[model.Fingerprint]struct{}, now time.Time, repeat time.Duration) bool {
+	// If the group was not notified before, notify right away unless it has only resolved alerts.
+	if !exists {
+		return len(firing) > 0
+	}
+	if !isSubset(firing, entry.firing) {
+		return true
+	}
+	// Notify about all alerts being resolved if the last notification had firing alerts.
+	if len(firing) == 0 {
+		return len(entry.firing) > 0
+	}
+	if !isSubset(resolved, entry.resolved) {
+		return true
+	}
+	// Nothing changed, only notify if the repeat interval has passed.
+	return entry.time.Before(now.Add(-repeat))
+}
+
+func isSubset(subset, set map[model.Fingerprint]struct{}) bool {
+	for fp := range subset {
+		if _, ok := set[fp]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func getGroupLabels(labels model.LabelSet, r *dispatch.Route) model.LabelSet {
+	groupLabels := model.LabelSet{}
+	for ln, lv := range labels {
+		if _, ok := r.RouteOpts.GroupBy[ln]; ok || r.RouteOpts.GroupByAll {
+			groupLabels[ln] = lv
+		}
+	}
+	return groupLabels
+}
+
+func labelsFromLabelSet(ls model.LabelSet) data.Labels {
+	result := make(data.Labels, len(ls))
+	for k, v := range ls {
+		result[string(k)] = string(v)
+	}
+	return result
+}
+
+func sortLabels(labels []data.Labels) {
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].String() < labels[j].String()
+	})
+}
@@ -0,0 +1,234 @@
+package backtesting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestTimelineBuilder(t *testing.T) {
+	from := time.Unix(0, 0)
+	labels := data.Labels{"instance": "a"}
+	transition := func(s eval.State, reason string) state.StateTransitions {
+		return state.StateTransitions{{State: &state.State{CacheID: labels.Fingerprint(), Labels: labels, State: s, StateReason: reason}}}
+	}
+
+	b := newTimelineBuilder(time.Second)
+	b.add(from, transition(eval.Normal, ""))
+	b.add(from.Add(time.Second), transition(eval.Pending, ""))
+	b.add(from.Add(2*time.Second), transition(eval.Alerting, ""))
+	b.add(from.Add(3*time.Second), transition(eval.Alerting, ""))
+	b.add(from.Add(4*time.Second), transition(eval.Alerting, models.StateReasonNoData))
+	// The instance is missing at the 6th evaluation.
+	b.add(from.Add(6*time.Second), transition(eval.Alerting, models.StateReasonNoData))
+
+	require.Equal(t, []InstanceTimeline{
+		{
+			Labels: labels,
+			States: []StatePeriod{
+				{State: eval.Normal, From: from, To: from.Add(time.Second)},
+				{State: eval.Pending, From: from.Add(time.Second), To: from.Add(2 * time.Second)},
+				{State: eval.Alerting, From: from.Add(2 * time.Second), To: from.Add(4 * time.Second)},
+				{State: eval.Alerting, Reason: models.StateReasonNoData, From: from.Add(4 * time.Second), To: from.Add(5 * time.Second)},
+				{State: eval.Alerting, Reason: models.StateReasonNoData, From: from.Add(6 * time.Second), To: from.Add(7 * time.Second)},
+			},
+		},
+	}, b.build())
+}
+
+func TestNotificationSimulator(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	duration := func(d time.Duration) *model.Duration {
+		md := model.Duration(d)
+		return &md
+	}
+	route := func(mutators ...func(r *config.Route)) *config.Route {
+		r := &config.Route{
+			Receiver:       "default",
+			GroupByStr:     []string{"alertname"},
+			GroupBy:        []model.LabelName{"alertname"},
+			GroupWait:      duration(30 * time.Second),
+			GroupInterval:  duration(time.Minute),
+			RepeatInterval: duration(time.Hour),
+		}
+		for _, m := range mutators {
+			m(r)
+		}
+		return r
+	}
+	alertA := model.LabelSet{"alertname": "test", "team": "a"}
+	alertB := model.LabelSet{"alertname": "test", "team": "b"}
+
+	// simulate feeds the alerts to the simulator every 10 seconds for 200 seconds the same way as the scheduler does,
+	// the alerts fire between 0 and 100 seconds.
+	simulate := func(policies NotificationPolicies, alerts ...model.LabelSet) []Notification {
+		s := newNotificationSimulator(policies)
+		for now := from; now.Before(from.Add(200 * time.Second)); now = now.Add(10 * time.Second) {
+			s.advance(now)
+			received := make([]simulatedAlert, 0, len(alerts))
+			for _, lbls := range alerts {
+				switch {
+				case now.Before(from.Add(100 * time.Second)):
+					received = append(received, simulatedAlert{labels: lbls, startsAt: from, endsAt: now.Add(40 * time.Second)})
+				case now.Equal(from.Add(100 * time.Second)):
+					received = append(received, simulatedAlert{labels: lbls, startsAt: from, endsAt: now})
+				}
+			}
+			s.receive(now, received)
+		}
+		s.advance(from.Add(200 * time.Second))
+		return s.notifications
+	}
+	labelsOf := func(ls ...model.LabelSet) []data.Labels {
+		result := make([]data.Labels, 0, len(ls))
+		for _, l := range ls {
+			result = append(result, labelsFromLabelSet(l))
+		}
+		return result
+	}
+
+	t.Run("sends firing and resolved notifications of a group", func(t *testing.T) {
+		notifications := simulate(NotificationPolicies{Route: route()}, alertA, alertB)
+		require.Equal(t, []Notification{
+			{Time: from.Add(30 * time.Second), Receiver: "default", GroupLabels: data.Labels{"alertname": "test"}, Firing: labelsOf(alertA, alertB)},
+			{Time: from.Add(150 * time.Second), Receiver: "default", GroupLabels: data.Labels{"alertname": "test"}, Resolved: labelsOf(alertA, alertB)},
+		}, notifications)
+	})
+
+	t.Run("sends notifications to matching policies", func(t *testing.T) {
+		matcher, err := labels.NewMatcher(labels.MatchEqual, "team", "b")
+		require.NoError(t, err)
+		policies := NotificationPolicies{Route: route(func(r *config.Route) {
+			r.Routes = []*config.Route{
+				{
+					Receiver: "team-b",
+					Matchers: config.Matchers{matcher},
+				},
+			}
+		})}
+		notifications := simulate(policies, alertA, alertB)
+		require.Len(t, notifications, 4)
+		require.Equal(t, "team-b", notifications[0].Receiver)
+		require.Equal(t, labelsOf(alertB), notifications[0].Firing)
+		require.Equal(t, "default", notifications[1].Receiver)
+		require.Equal(t, labelsOf(alertA), notifications[1].Firing)
+		require.Equal(t, labelsOf(alertB), notifications[2].Resolved)
+		require.Equal(t, labelsOf(alertA), notifications[3].Resolved)
+	})
+
+	t.Run("repeats notifications after repeat interval", func(t *testing.T) {
+		notifications := simulate(NotificationPolicies{Route: route(func(r *config.Route) {
+			r.RepeatInterval = duration(50 * time.Second)
+		})}, alertA)
+		require.Len(t, notifications, 3)
+		require.Equal(t, from.Add(30*time.Second), notifications[0].Time)
+		require.Equal(t, from.Add(90*time.Second), notifications[1].Time)
+		require.Equal(t, labelsOf(alertA), notifications[1].Firing)
+		require.Equal(t, from.Add(150*time.Second), notifications[2].Time)
+	})
+
+	t.Run("does not send notifications during mute timings", func(t *testing.T) {
+		policies := NotificationPolicies{
+			Route: route(func(r *config.Route) {
+				r.MuteTimeIntervals = []string{"first-minute"}
+			}),
+			TimeIntervals: map[string][]timeinterval.TimeInterval{
+				"first-minute": {{Times: []timeinterval.TimeRange{{StartMinute: 0, EndMinute: 1}}}},
+			},
+		}
+		notifications := simulate(policies, alertA)
+		require.Equal(t, []Notification{
+			{Time: from.Add(30 * time.Second), Receiver: "default", GroupLabels: data.Labels{"alertname": "test"}, Firing: labelsOf(alertA), Muted: true},
+			{Time: from.Add(90 * time.Second), Receiver: "default", GroupLabels: data.Labels{"alertname": "test"}, Firing: labelsOf(alertA)},
+			{Time: from.Add(150 * time.Second), Receiver: "default", GroupLabels: data.Labels{"alertname": "test"}, Resolved: labelsOf(alertA)},
+		}, notifications)
+	})
+
+	t.Run("does not send notifications outside of active time intervals", func(t *testing.T) {
+		policies := NotificationPolicies{
+			Route: route(func(r *config.Route) {
+				r.ActiveTimeIntervals = []string{"second-minute"}
+			}),
+			TimeIntervals: map[string][]timeinterval.TimeInterval{
+				"second-minute": {{Times: []timeinterval.TimeRange{{StartMinute: 1, EndMinute: 2}}}},
+			},
+		}
+		notifications := simulate(policies, alertA)
+		require.Len(t, notifications, 3)
+		require.True(t, notifications[0].Muted)
+		require.False(t, notifications[1].Muted)
+		require.True(t, notifications[2].Muted)
+	})
+
+	t.Run("does nothing without policies", func(t *testing.T) {
+		require.Empty(t, simulate(NotificationPolicies{}, alertA))
+	})
+}
+
+func TestEngineSimulate(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	instance := data.Labels{"instance": "a"}
+	evaluator := &fakeBacktestingEvaluator{
+		evalCallback: func(now time.Time) (eval.Results, error) {
+			s := eval.Normal
+			if now.Before(from.Add(50 * time.Second)) {
+				s = eval.Alerting
+			}
+			return eval.Results{{Instance: instance, State: s, EvaluatedAt: now}}, nil
+		},
+	}
+	backtestingEvaluatorFactory = func(ctx context.Context, evalFactory eval.EvaluatorFactory, user identity.Requester, condition models.Condition, r eval.AlertingResultsReader) (backtestingEvaluator, error) {
+		return evaluator, nil
+	}
+	t.Cleanup(func() {
+		backtestingEvaluatorFactory = newBacktestingEvaluator
+	})
+
+	engine := NewEngine(nil, nil, tracing.InitializeTracerForTest())
+	gen := models.RuleGen
+	rule := gen.With(
+		gen.WithInterval(10*time.Second),
+		gen.WithFor(20*time.Second),
+		gen.WithTitle("test"),
+		gen.WithLabels(map[string]string{"team": "a"}),
+		gen.WithNoDataExecAs(models.NoData),
+		gen.WithErrorExecAs(models.ErrorErrState),
+	).GenerateRef()
+
+	groupWait := model.Duration(0)
+	groupInterval := model.Duration(10 * time.Second)
+	simulation, err := engine.Simulate(context.Background(), nil, rule, from, from.Add(100*time.Second), NotificationPolicies{
+		Route: &config.Route{Receiver: "default", GroupWait: &groupWait, GroupInterval: &groupInterval},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, simulation.Timeline, 1)
+	require.Equal(t, "a", simulation.Timeline[0].Labels["instance"])
+	require.Equal(t, "test", simulation.Timeline[0].Labels[model.AlertNameLabel])
+	require.Equal(t, []StatePeriod{
+		{State: eval.Pending, From: from, To: from.Add(20 * time.Second)},
+		{State: eval.Alerting, From: from.Add(20 * time.Second), To: from.Add(50 * time.Second)},
+		{State: eval.Normal, From: from.Add(50 * time.Second), To: from.Add(100 * time.Second)},
+	}, simulation.Timeline[0].States)
+
+	require.Len(t, simulation.Notifications, 2)
+	require.Equal(t, from.Add(20*time.Second), simulation.Notifications[0].Time)
+	require.Len(t, simulation.Notifications[0].Firing, 1)
+	require.Equal(t, "a", simulation.Notifications[0].Firing[0]["team"])
+	// The alert is resolved at 50s after the group is flushed, so the resolved notification is sent at the next group interval.
+	require.Equal(t, from.Add(60*time.Second), simulation.Notifications[1].Time)
+	require.Len(t, simulation.Notifications[1].Resolved, 1)
+}
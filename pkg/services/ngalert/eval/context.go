@@ -19,6 +19,8 @@ type EvaluationContext struct {
 	Ctx                   context.Context
 	User                  identity.Requester
 	AlertingResultsReader AlertingResultsReader
+	// Inputs are the frames that the input queries of the condition read by the name of the input.
+	Inputs map[string]data.Frames
 }
 
 func NewContext(ctx context.Context, user identity.Requester) EvaluationContext {
@@ -35,3 +37,9 @@ func NewContextWithPreviousResults(ctx context.Context, user identity.Requester,
 		AlertingResultsReader: reader,
 	}
 }
+
+// WithInputs returns a copy of the context with the frames that the input queries of the condition read.
+func (c EvaluationContext) WithInputs(inputs map[string]data.Frames) EvaluationContext {
+	c.Inputs = inputs
+	return c
+}
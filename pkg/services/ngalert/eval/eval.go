@@ -54,6 +54,8 @@ type conditionEvaluator struct {
 	condition         models.Condition
 	evalTimeout       time.Duration
 	evalResultLimit   int
+	// alertingResults is read by hysteresis commands every time the pipeline is executed.
+	alertingResults AlertingResultsReader
 }
 
 func (r *conditionEvaluator) EvaluateRaw(ctx context.Context, now time.Time) (resp *backend.QueryDataResponse, err error) {
@@ -75,6 +77,9 @@ func (r *conditionEvaluator) EvaluateRaw(ctx context.Context, now time.Time) (re
 		defer cancel()
 		execCtx = timeoutCtx
 	}
+	if r.alertingResults != nil {
+		execCtx = expr.WithLoadedDimensionsReader(execCtx, r.alertingResults)
+	}
 	logger.FromContext(ctx).Debug("Executing pipeline", "commands", strings.Join(r.pipeline.GetCommandTypes(), ","), "datasources", strings.Join(r.pipeline.GetDatasourceTypes(), ","))
 	result, err := r.expressionService.ExecutePipeline(execCtx, now, r.pipeline)
 
@@ -90,6 +95,10 @@ func (r *conditionEvaluator) EvaluateRaw(ctx context.Context, now time.Time) (re
 		}
 	}
 
+	if stats := statsFromContext(ctx); stats != nil && result != nil {
+		stats.QueryResponseBytes = queryResponseBytes(r.pipeline, result)
+	}
+
 	return result, err
 }
 
@@ -354,6 +363,7 @@ func getExprRequest(ctx EvaluationContext, condition models.Condition, dsCacheSe
 		OrgId:   ctx.User.GetOrgID(),
 		Headers: buildDatasourceHeaders(ctx.Ctx, condition.Metadata),
 		User:    ctx.User,
+		Inputs:  ctx.Inputs,
 	}
 	datasources := make(map[string]*datasources.DataSource, len(condition.Data))
 
@@ -834,10 +844,10 @@ func (e *evaluatorImpl) Create(ctx EvaluationContext, condition models.Condition
 	if err != nil {
 		return nil, err
 	}
-	return e.create(condition, req)
+	return e.create(condition, req, ctx.AlertingResultsReader)
 }
 
-func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request) (ConditionEvaluator, error) {
+func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request, reader AlertingResultsReader) (ConditionEvaluator, error) {
 	pipeline, err := e.expressionService.BuildPipeline(req)
 	if err != nil {
 		return nil, err
@@ -851,6 +861,7 @@ func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request) (C
 				condition:         condition,
 				evalTimeout:       e.evaluationTimeout,
 				evalResultLimit:   e.evaluationResultLimit,
+				alertingResults:   reader,
 			}, nil
 		}
 		conditions = append(conditions, node.RefID())
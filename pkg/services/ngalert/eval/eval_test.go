@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/expr/mathexp"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -1031,6 +1032,56 @@ func TestEvaluateRaw(t *testing.T) {
 	})
 }
 
+func TestEvaluateRawLoadedDimensions(t *testing.T) {
+	t.Run("should pass the alerting results to hysteresis commands on every execution", func(t *testing.T) {
+		labels := data.Labels{"label": "value"}
+		loading, err := expr.NewThresholdCommand("B", "A", expr.ThresholdIsAbove, []float64{100})
+		require.NoError(t, err)
+		unloading, err := expr.NewThresholdCommand("B", "A", expr.ThresholdIsBelow, []float64{30})
+		require.NoError(t, err)
+		unloading.Invert = true
+		cmd, err := expr.NewHysteresisCommand("B", "A", *loading, *unloading, nil)
+		require.NoError(t, err)
+
+		reader := &fakeAlertingResultsReader{}
+		var result float64
+		e := conditionEvaluator{
+			expressionService: &fakeExpressionService{
+				hook: func(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (*backend.QueryDataResponse, error) {
+					n := mathexp.NewNumber("A", labels)
+					n.SetValue(util.Pointer(50.0))
+					res, err := cmd.Execute(ctx, now, mathexp.Vars{"A": mathexp.Results{Values: mathexp.Values{n}}}, tracing.InitializeTracerForTest())
+					if err != nil {
+						return nil, err
+					}
+					result = *res.Values[0].(mathexp.Number).GetFloat64Value()
+					return &backend.QueryDataResponse{}, nil
+				},
+			},
+			condition:       models.Condition{Condition: "B"},
+			evalTimeout:     -1,
+			alertingResults: reader,
+		}
+
+		_, err = e.EvaluateRaw(context.Background(), time.Now())
+		require.NoError(t, err)
+		require.Equal(t, 0.0, result)
+
+		reader.results = map[data.Fingerprint]struct{}{labels.Fingerprint(): {}}
+		_, err = e.EvaluateRaw(context.Background(), time.Now())
+		require.NoError(t, err)
+		require.Equal(t, 1.0, result)
+	})
+}
+
+type fakeAlertingResultsReader struct {
+	results map[data.Fingerprint]struct{}
+}
+
+func (f *fakeAlertingResultsReader) Read() map[data.Fingerprint]struct{} {
+	return f.results
+}
+
 func TestEvaluateRawLimit(t *testing.T) {
 	t.Run("should apply the limit to the successful query evaluation", func(t *testing.T) {
 		resp := backend.QueryDataResponse{
@@ -1294,6 +1345,35 @@ func TestCreate(t *testing.T) {
 
 		require.Equal(t, expectedHeaders, request.Headers)
 	})
+
+	t.Run("should pass inputs to the request", func(t *testing.T) {
+		q := models.CreateInputQuery("A", "job:rate")
+		condition := models.Condition{
+			Condition: q.RefID,
+			Data:      []models.AlertQuery{q},
+		}
+		inputs := map[string]data.Frames{"job:rate": {data.NewFrame("")}}
+
+		var request *expr.Request
+		factory := evaluatorImpl{
+			expressionService: fakeExpressionService{
+				buildHook: func(req *expr.Request) (expr.DataPipeline, error) {
+					request = req
+					return expr.DataPipeline{
+						fakeNode{refID: q.RefID},
+					}, nil
+				},
+			},
+		}
+
+		_, err := factory.Create(NewContext(context.Background(), &user.SignedInUser{}).WithInputs(inputs), condition)
+		require.NoError(t, err)
+
+		require.NotNil(t, request)
+		require.Equal(t, inputs, request.Inputs)
+		require.Len(t, request.Queries, 1)
+		require.Equal(t, expr.InputDatasourceUID, request.Queries[0].DataSource.UID)
+	})
 }
 
 type fakeExpressionService struct {
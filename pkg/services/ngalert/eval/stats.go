@@ -0,0 +1,75 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr"
+)
+
+// Stats contains the statistics of an evaluation of a condition.
+type Stats struct {
+	// QueryResponseBytes is the approximate size of the data returned by the data source queries.
+	QueryResponseBytes int64
+}
+
+type statsKey struct{}
+
+// WithStats returns a context that makes the condition evaluator record the statistics of the evaluation to stats.
+func WithStats(ctx context.Context, stats *Stats) context.Context {
+	return context.WithValue(ctx, statsKey{}, stats)
+}
+
+func statsFromContext(ctx context.Context) *Stats {
+	stats, _ := ctx.Value(statsKey{}).(*Stats)
+	return stats
+}
+
+// queryResponseBytes returns the approximate size of the data returned by the data source nodes of the pipeline.
+func queryResponseBytes(pipeline expr.DataPipeline, resp *backend.QueryDataResponse) int64 {
+	var size int64
+	for _, node := range pipeline {
+		if node.NodeType() != expr.TypeDatasourceNode {
+			continue
+		}
+		for _, frame := range resp.Responses[node.RefID()].Frames {
+			for _, field := range frame.Fields {
+				size += fieldBytes(field)
+			}
+		}
+	}
+	return size
+}
+
+// fieldBytes returns the size of the values of the field. Values of variable length are measured one by one,
+// values of fixed length are counted by the size of their type.
+func fieldBytes(field *data.Field) int64 {
+	switch field.Type().NonNullableType() {
+	case data.FieldTypeString, data.FieldTypeJSON:
+		var size int64
+		for i := 0; i < field.Len(); i++ {
+			v, ok := field.ConcreteAt(i)
+			if !ok {
+				continue
+			}
+			switch v := v.(type) {
+			case string:
+				size += int64(len(v))
+			case json.RawMessage:
+				size += int64(len(v))
+			}
+		}
+		return size
+	case data.FieldTypeBool, data.FieldTypeInt8, data.FieldTypeUint8:
+		return int64(field.Len())
+	case data.FieldTypeInt16, data.FieldTypeUint16:
+		return int64(field.Len()) * 2
+	case data.FieldTypeInt32, data.FieldTypeUint32, data.FieldTypeFloat32:
+		return int64(field.Len()) * 4
+	default:
+		return int64(field.Len()) * 8
+	}
+}
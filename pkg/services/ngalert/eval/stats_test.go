@@ -0,0 +1,72 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestEvaluateRawStats(t *testing.T) {
+	resp := &backend.QueryDataResponse{
+		Responses: backend.Responses{
+			"A": {
+				Frames: data.Frames{
+					data.NewFrame("",
+						data.NewField("Time", nil, []time.Time{time.Now(), time.Now()}),
+						data.NewField("Value", data.Labels{"foo": "bar"}, []float64{1, 2}),
+						data.NewField("Name", nil, []*string{nil, func() *string { s := "abc"; return &s }()}),
+						data.NewField("Meta", nil, []json.RawMessage{json.RawMessage(`{}`), json.RawMessage(`[1]`)}),
+						data.NewField("Flag", nil, []bool{true, false}),
+					),
+				},
+			},
+			"B": {
+				Frames: data.Frames{
+					data.NewFrame("", data.NewField("Value", nil, []float64{1, 2, 3})),
+				},
+			},
+		},
+	}
+	e := conditionEvaluator{
+		pipeline: expr.DataPipeline{
+			fakeDatasourceNode{fakeNode{refID: "A"}},
+			fakeNode{refID: "B"},
+		},
+		expressionService: &fakeExpressionService{
+			hook: func(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (*backend.QueryDataResponse, error) {
+				return resp, nil
+			},
+		},
+		condition:   models.Condition{Condition: "B"},
+		evalTimeout: -1,
+	}
+
+	t.Run("should record the size of the data returned by data source queries", func(t *testing.T) {
+		stats := &Stats{}
+		_, err := e.EvaluateRaw(WithStats(context.Background(), stats), time.Now())
+		require.NoError(t, err)
+		// 2 times, 2 floats, 1 string of 3 bytes, 2 JSON values of 2 and 3 bytes and 2 booleans.
+		require.Equal(t, int64(2*8+2*8+3+2+3+2), stats.QueryResponseBytes)
+	})
+
+	t.Run("should not fail without stats in the context", func(t *testing.T) {
+		_, err := e.EvaluateRaw(context.Background(), time.Now())
+		require.NoError(t, err)
+	})
+}
+
+type fakeDatasourceNode struct {
+	fakeNode
+}
+
+func (f fakeDatasourceNode) NodeType() expr.NodeType {
+	return expr.TypeDatasourceNode
+}
@@ -47,7 +47,7 @@ func (e *ConditionValidator) Validate(ctx EvaluationContext, condition models.Co
 			if !found {
 				return fmt.Errorf("datasource refID %s could not be found: %w", query.RefID, plugins.ErrPluginUnavailable)
 			}
-		case expr.TypeCMDNode:
+		case expr.TypeCMDNode, expr.TypeInputNode:
 		}
 	}
 	pipeline, err := e.expressionService.BuildPipeline(req)
@@ -28,10 +28,15 @@ type Scheduler struct {
 	SchedulePeriodicDuration            prometheus.Histogram
 	SchedulableAlertRules               prometheus.Gauge
 	SchedulableAlertRulesHash           prometheus.Gauge
+	ShardedAlertRules                   prometheus.Gauge
+	ShardRebalances                     prometheus.Counter
 	UpdateSchedulableAlertRulesDuration prometheus.Histogram
 	Ticker                              *ticker.Metrics
 	EvaluationMissed                    *prometheus.CounterVec
 	SimplifiedEditorRules               *prometheus.GaugeVec
+	RuleLastEvalDuration                *prometheus.GaugeVec
+	RuleLastEvalSeries                  *prometheus.GaugeVec
+	RuleLastEvalQueryBytes              *prometheus.GaugeVec
 }
 
 func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
@@ -164,6 +169,20 @@ func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
 				Name:      "schedule_alert_rules_hash",
 				Help:      "A hash of the alert rules that could be considered for evaluation at the next tick.",
 			}),
+		ShardedAlertRules: promauto.With(r).NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "schedule_sharded_alert_rules",
+				Help:      "The number of alert rules assigned to this instance when alert rules are sharded between the instances of the cluster.",
+			}),
+		ShardRebalances: promauto.With(r).NewCounter(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "schedule_shard_rebalances_total",
+				Help:      "The number of times alert rules were rebalanced between the instances of the cluster because the membership changed.",
+			}),
 		UpdateSchedulableAlertRulesDuration: promauto.With(r).NewHistogram(
 			prometheus.HistogramOpts{
 				Namespace: Namespace,
@@ -192,5 +211,32 @@ func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
 			},
 			[]string{"org", "setting"},
 		),
+		RuleLastEvalDuration: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_last_evaluation_duration_seconds",
+				Help:      "The time taken by the last evaluation of an alert rule.",
+			},
+			[]string{"org", "rule_uid"},
+		),
+		RuleLastEvalSeries: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_last_evaluation_series",
+				Help:      "The number of series produced by the last evaluation of an alert rule.",
+			},
+			[]string{"org", "rule_uid"},
+		),
+		RuleLastEvalQueryBytes: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_last_evaluation_query_response_bytes",
+				Help:      "The approximate size of the data returned by the queries in the last evaluation of an alert rule.",
+			},
+			[]string{"org", "rule_uid"},
+		),
 	}
 }
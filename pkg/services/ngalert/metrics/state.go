@@ -8,6 +8,7 @@ import (
 type State struct {
 	StateUpdateDuration   prometheus.Histogram
 	StateFullSyncDuration prometheus.Histogram
+	SeriesLimitExceeded   *prometheus.CounterVec
 	r                     prometheus.Registerer
 }
 
@@ -37,5 +38,14 @@ func NewStateMetrics(r prometheus.Registerer) *State {
 				Buckets:   []float64{0.01, 0.1, 1, 2, 5, 10, 60},
 			},
 		),
+		SeriesLimitExceeded: promauto.With(r).NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "state_series_limit_exceeded_total",
+				Help:      "The total number of rule evaluations whose series were discarded because they exceeded the limit of series.",
+			},
+			[]string{"org", "limit"},
+		),
 	}
 }
@@ -118,6 +118,26 @@ func (aq *AlertQuery) IsExpression() (bool, error) {
 	return expr.NodeTypeFromDatasourceUID(aq.DatasourceUID) == expr.TypeCMDNode, nil
 }
 
+// IsInput returns true if the alert query reads data that is provided to the evaluation instead of querying a data source.
+func (aq *AlertQuery) IsInput() bool {
+	return expr.NodeTypeFromDatasourceUID(aq.DatasourceUID) == expr.TypeInputNode
+}
+
+// InputName returns the name of the input that the alert query reads.
+// Alert rules read the output of a recording rule of their group with an input named after the metric of the recording rule.
+func (aq *AlertQuery) InputName() (string, error) {
+	var model struct {
+		Input string `json:"input"`
+	}
+	if err := json.Unmarshal(aq.Model, &model); err != nil {
+		return "", fmt.Errorf("failed to unmarshal query model: %w", err)
+	}
+	if model.Input == "" {
+		return "", fmt.Errorf("input query %s must have the name of the input", aq.RefID)
+	}
+	return model.Input, nil
+}
+
 // IsHysteresisExpression returns true if the model describes a hysteresis command expression. Returns error if the Model is not a valid JSON
 func (aq *AlertQuery) IsHysteresisExpression() (bool, error) {
 	if aq.modelProps == nil {
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"maps"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -164,6 +165,7 @@ const (
 	StateReasonUpdated       = "Updated"
 	StateReasonRuleDeleted   = "RuleDeleted"
 	StateReasonKeepLast      = "KeepLast"
+	StateReasonSuppressed    = "Suppressed"
 )
 
 func ConcatReasons(reasons ...string) string {
@@ -270,8 +272,36 @@ type AlertRule struct {
 	IsPaused             bool
 	NotificationSettings []NotificationSettings
 	Metadata             AlertRuleMetadata
+	// UpdatedBy is the UID of the user that created this version of the rule, if known.
+	UpdatedBy *UserUID
+	// SuppressedBy are the rules whose firing alerts suppress the alerts of this rule.
+	SuppressedBy []SuppressingRule
+}
+
+// SuppressingRule declares that the alerts of a rule are suppressed while another rule of the same organization
+// has firing alerts with the same values of the labels in Equal. Suppressed alerts are Normal with the reason Suppressed.
+type SuppressingRule struct {
+	// RuleUID is the UID of the rule whose firing alerts suppress the alerts.
+	RuleUID string `json:"rule_uid"`
+	// Equal are the labels that must have the same value in the firing alert and in the suppressed one.
+	// If empty, any firing alert of the rule suppresses all alerts.
+	Equal []string `json:"equal,omitempty"`
+}
+
+// Suppresses returns true if a firing alert with the labels firing suppresses an alert with the labels target.
+// Like in the inhibition rules of Alertmanager, a label that is missing in both is considered to have the same value.
+func (s SuppressingRule) Suppresses(firing, target map[string]string) bool {
+	for _, name := range s.Equal {
+		if firing[name] != target[name] {
+			return false
+		}
+	}
+	return true
 }
 
+// UserUID is the UID of a user.
+type UserUID string
+
 type AlertRuleMetadata struct {
 	EditorSettings EditorSettings `json:"editor_settings"`
 }
@@ -589,6 +619,10 @@ func (alertRule *AlertRule) ValidateAlertRule(cfg setting.UnifiedAlertingSetting
 		}
 	}
 
+	if err := validateSuppressedBy(alertRule); err != nil {
+		return err
+	}
+
 	if len(alertRule.NotificationSettings) > 0 {
 		if len(alertRule.NotificationSettings) != 1 {
 			return fmt.Errorf("%w: only one notification settings entry is allowed", ErrAlertRuleFailedValidation)
@@ -600,6 +634,28 @@ func (alertRule *AlertRule) ValidateAlertRule(cfg setting.UnifiedAlertingSetting
 	return nil
 }
 
+func validateSuppressedBy(rule *AlertRule) error {
+	seen := make(map[string]struct{}, len(rule.SuppressedBy))
+	for _, sr := range rule.SuppressedBy {
+		if sr.RuleUID == "" {
+			return fmt.Errorf("%w: suppressing rule must have a rule UID", ErrAlertRuleFailedValidation)
+		}
+		if sr.RuleUID == rule.UID {
+			return fmt.Errorf("%w: rule cannot be suppressed by itself", ErrAlertRuleFailedValidation)
+		}
+		if _, ok := seen[sr.RuleUID]; ok {
+			return fmt.Errorf("%w: rule %s is listed more than once in suppressing rules", ErrAlertRuleFailedValidation, sr.RuleUID)
+		}
+		seen[sr.RuleUID] = struct{}{}
+		for _, name := range sr.Equal {
+			if !prommodels.LabelName(name).IsValid() {
+				return fmt.Errorf("%w: invalid label name %q in suppressing rule %s", ErrAlertRuleFailedValidation, name, sr.RuleUID)
+			}
+		}
+	}
+	return nil
+}
+
 func validateAlertRuleFields(rule *AlertRule) error {
 	if _, err := ErrStateFromString(string(rule.ExecErrState)); err != nil {
 		return err
@@ -632,6 +688,7 @@ func clearRecordingRuleIgnoredFields(rule *AlertRule) {
 	rule.Condition = ""
 	rule.For = 0
 	rule.NotificationSettings = nil
+	rule.SuppressedBy = nil
 }
 
 func (alertRule *AlertRule) ResourceType() string {
@@ -660,6 +717,90 @@ func (alertRule *AlertRule) Type() RuleType {
 	return RuleTypeAlerting
 }
 
+// Inputs returns the names of the inputs that the queries of the rule read. They are the metrics of the recording rules
+// of the same group whose output the rule uses. Queries with an invalid input are skipped.
+func (alertRule *AlertRule) Inputs() []string {
+	var inputs []string
+	for i := range alertRule.Data {
+		q := &alertRule.Data[i]
+		if !q.IsInput() {
+			continue
+		}
+		name, err := q.InputName()
+		if err != nil || slices.Contains(inputs, name) {
+			continue
+		}
+		inputs = append(inputs, name)
+	}
+	return inputs
+}
+
+// ValidateRuleGroupInputs checks that every input that the rules of a group read is the metric of exactly one recording
+// rule of the group, and that the rules do not depend on each other in a cycle.
+func ValidateRuleGroupInputs(rules []*AlertRule) error {
+	recorders := make(map[string]*AlertRule)
+	duplicates := make(map[string]struct{})
+	for _, rule := range rules {
+		if rule.Type() != RuleTypeRecording {
+			continue
+		}
+		if _, ok := recorders[rule.Record.Metric]; ok {
+			duplicates[rule.Record.Metric] = struct{}{}
+		}
+		recorders[rule.Record.Metric] = rule
+	}
+
+	for _, rule := range rules {
+		for i := range rule.Data {
+			q := &rule.Data[i]
+			if !q.IsInput() {
+				continue
+			}
+			name, err := q.InputName()
+			if err != nil {
+				return fmt.Errorf("%w: rule '%s': %s", ErrAlertRuleFailedValidation, rule.Title, err)
+			}
+			if _, ok := recorders[name]; !ok {
+				return fmt.Errorf("%w: rule '%s' reads the metric %s that is not recorded by any recording rule of the group", ErrAlertRuleFailedValidation, rule.Title, name)
+			}
+			if _, ok := duplicates[name]; ok {
+				return fmt.Errorf("%w: rule '%s' reads the metric %s that is recorded by more than one recording rule of the group", ErrAlertRuleFailedValidation, rule.Title, name)
+			}
+		}
+	}
+
+	// Look for cycles with a depth-first search, in which a rule that is visited again before all of its inputs are
+	// visited is part of a cycle.
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	visits := make(map[*AlertRule]int, len(rules))
+	var visit func(rule *AlertRule) error
+	visit = func(rule *AlertRule) error {
+		switch visits[rule] {
+		case visiting:
+			return fmt.Errorf("%w: rule '%s' depends on its own output", ErrAlertRuleFailedValidation, rule.Title)
+		case visited:
+			return nil
+		}
+		visits[rule] = visiting
+		for _, name := range rule.Inputs() {
+			if err := visit(recorders[name]); err != nil {
+				return err
+			}
+		}
+		visits[rule] = visited
+		return nil
+	}
+	for _, rule := range rules {
+		if err := visit(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
 type GetAlertRuleByUIDQuery struct {
 	UID   string
@@ -908,3 +908,162 @@ func TestAlertRuleGetKeyWithGroup(t *testing.T) {
 		require.Equal(t, expected, rule.GetKeyWithGroup())
 	})
 }
+
+func TestValidateSuppressedBy(t *testing.T) {
+	testCases := []struct {
+		name         string
+		suppressedBy []SuppressingRule
+		expectedErr  string
+	}{
+		{
+			name: "valid suppressing rules",
+			suppressedBy: []SuppressingRule{
+				{RuleUID: "node-down", Equal: []string{"instance"}},
+				{RuleUID: "datacenter-down"},
+			},
+		},
+		{
+			name:         "empty rule UID",
+			suppressedBy: []SuppressingRule{{Equal: []string{"instance"}}},
+			expectedErr:  "suppressing rule must have a rule UID",
+		},
+		{
+			name:         "rule suppressed by itself",
+			suppressedBy: []SuppressingRule{{RuleUID: "rule-uid"}},
+			expectedErr:  "rule cannot be suppressed by itself",
+		},
+		{
+			name:         "duplicate suppressing rule",
+			suppressedBy: []SuppressingRule{{RuleUID: "node-down"}, {RuleUID: "node-down", Equal: []string{"instance"}}},
+			expectedErr:  "rule node-down is listed more than once",
+		},
+		{
+			name:         "invalid label name",
+			suppressedBy: []SuppressingRule{{RuleUID: "node-down", Equal: []string{"in-stance"}}},
+			expectedErr:  `invalid label name "in-stance"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := RuleGen.With(RuleMuts.WithSuppressedBy(tc.suppressedBy...)).GenerateRef()
+			rule.UID = "rule-uid"
+			err := validateSuppressedBy(rule)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+			require.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestSuppressingRuleSuppresses(t *testing.T) {
+	firing := map[string]string{"instance": "node-1", "job": "node"}
+
+	require.True(t, SuppressingRule{RuleUID: "node-down"}.Suppresses(firing, map[string]string{"instance": "node-2"}))
+	require.True(t, SuppressingRule{RuleUID: "node-down", Equal: []string{"instance"}}.Suppresses(firing, map[string]string{"instance": "node-1", "job": "service"}))
+	require.False(t, SuppressingRule{RuleUID: "node-down", Equal: []string{"instance"}}.Suppresses(firing, map[string]string{"instance": "node-2"}))
+	require.False(t, SuppressingRule{RuleUID: "node-down", Equal: []string{"instance", "job"}}.Suppresses(firing, map[string]string{"instance": "node-1"}))
+	// A label that is missing in both alerts has the same value.
+	require.True(t, SuppressingRule{RuleUID: "node-down", Equal: []string{"zone"}}.Suppresses(firing, map[string]string{"instance": "node-2"}))
+}
+
+func TestValidateRuleGroupInputs(t *testing.T) {
+	recording := func(title, metric string, queries ...AlertQuery) *AlertRule {
+		rule := RuleGen.With(RuleMuts.WithAllRecordingRules(), RuleMuts.WithTitle(title), RuleMuts.WithMetric(metric)).GenerateRef()
+		if len(queries) > 0 {
+			rule.Data = queries
+			rule.Record.From = queries[0].RefID
+		}
+		return rule
+	}
+	alerting := func(title string, queries ...AlertQuery) *AlertRule {
+		return RuleGen.With(RuleMuts.WithTitle(title), RuleMuts.WithQuery(queries...)).GenerateRef()
+	}
+
+	testCases := []struct {
+		name        string
+		rules       []*AlertRule
+		expectedErr string
+	}{
+		{
+			name: "rules without inputs",
+			rules: []*AlertRule{
+				recording("rate", "job:rate"),
+				alerting("alert", CreatePrometheusQuery("A", "up", 1000, 43200, false, "prom")),
+			},
+		},
+		{
+			name: "chain of rules",
+			rules: []*AlertRule{
+				alerting("alert", CreateInputQuery("A", "job:rate:sum"), CreateReduceExpression("B", "A", "last")),
+				recording("sum", "job:rate:sum", CreateInputQuery("A", "job:rate")),
+				recording("rate", "job:rate"),
+			},
+		},
+		{
+			name: "input of unknown metric",
+			rules: []*AlertRule{
+				recording("rate", "job:rate"),
+				alerting("alert", CreateInputQuery("A", "job:errors")),
+			},
+			expectedErr: "rule 'alert' reads the metric job:errors that is not recorded by any recording rule of the group",
+		},
+		{
+			name: "input of metric recorded twice",
+			rules: []*AlertRule{
+				recording("rate", "job:rate"),
+				recording("other rate", "job:rate"),
+				alerting("alert", CreateInputQuery("A", "job:rate")),
+			},
+			expectedErr: "rule 'alert' reads the metric job:rate that is recorded by more than one recording rule of the group",
+		},
+		{
+			name: "input without name",
+			rules: []*AlertRule{
+				alerting("alert", CreateInputQuery("A", "")),
+			},
+			expectedErr: "input query A must have the name of the input",
+		},
+		{
+			name: "cycle",
+			rules: []*AlertRule{
+				recording("first", "first", CreateInputQuery("A", "second")),
+				recording("second", "second", CreateInputQuery("A", "first")),
+			},
+			expectedErr: "depends on its own output",
+		},
+		{
+			name: "rule reading its own output",
+			rules: []*AlertRule{
+				recording("self", "self", CreateInputQuery("A", "self")),
+			},
+			expectedErr: "rule 'self' depends on its own output",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateRuleGroupInputs(tc.rules)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+			require.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestAlertRuleInputs(t *testing.T) {
+	rule := RuleGen.With(RuleMuts.WithQuery(
+		CreateInputQuery("A", "job:rate"),
+		CreateInputQuery("B", "job:errors"),
+		CreateInputQuery("C", "job:rate"),
+		CreateReduceExpression("D", "A", "last"),
+	)).GenerateRef()
+	require.Equal(t, []string{"job:rate", "job:errors"}, rule.Inputs())
+	require.Empty(t, RuleGen.With(RuleMuts.WithQuery(CreateReduceExpression("A", "B", "last"))).GenerateRef().Inputs())
+}
@@ -487,6 +487,12 @@ func (a *AlertRuleMutators) WithNotificationSettings(ns NotificationSettings) Al
 	}
 }
 
+func (a *AlertRuleMutators) WithSuppressedBy(suppressedBy ...SuppressingRule) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.SuppressedBy = suppressedBy
+	}
+}
+
 func (a *AlertRuleMutators) WithNoNotificationSettings() AlertRuleMutator {
 	return func(rule *AlertRule) {
 		rule.NotificationSettings = nil
@@ -622,6 +628,7 @@ func CopyRule(r *AlertRule, mutators ...AlertRuleMutator) *AlertRule {
 		ExecErrState:    r.ExecErrState,
 		For:             r.For,
 		Record:          r.Record,
+		IsPaused:        r.IsPaused,
 	}
 
 	if r.DashboardUID != nil {
@@ -666,10 +673,22 @@ func CopyRule(r *AlertRule, mutators ...AlertRuleMutator) *AlertRule {
 		}
 	}
 
+	if r.UpdatedBy != nil {
+		u := *r.UpdatedBy
+		result.UpdatedBy = &u
+	}
+
 	for _, s := range r.NotificationSettings {
 		result.NotificationSettings = append(result.NotificationSettings, CopyNotificationSettings(s))
 	}
 
+	for _, s := range r.SuppressedBy {
+		result.SuppressedBy = append(result.SuppressedBy, SuppressingRule{
+			RuleUID: s.RuleUID,
+			Equal:   slices.Clone(s.Equal),
+		})
+	}
+
 	if len(mutators) > 0 {
 		for _, mutator := range mutators {
 			mutator(&result)
@@ -783,6 +802,21 @@ func CreateLokiQuery(refID string, expr string, intervalMs int64, maxDataPoints
 	}
 }
 
+func CreateInputQuery(refID string, input string) AlertQuery {
+	return AlertQuery{
+		RefID:         refID,
+		DatasourceUID: expr.InputDatasourceUID,
+		Model: json.RawMessage(fmt.Sprintf(`
+		{
+			"refId": "%[1]s",
+			"input": "%[2]s",
+            "datasource": {
+                "uid": "%[3]s"
+            }
+		}`, refID, input, expr.InputDatasourceUID)),
+	}
+}
+
 func CreateHysteresisExpression(t *testing.T, refID string, inputRefID string, threshold int, recoveryThreshold int) AlertQuery {
 	t.Helper()
 	q := AlertQuery{
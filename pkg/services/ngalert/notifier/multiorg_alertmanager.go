@@ -406,6 +406,24 @@ func (moa *MultiOrgAlertmanager) StopAndWait() {
 	}
 }
 
+// ClusterMembers returns the names of the members of the high availability cluster, including this instance, and the
+// name of this instance. It returns no members if high availability is not configured.
+func (moa *MultiOrgAlertmanager) ClusterMembers() ([]string, string) {
+	switch p := moa.peer.(type) {
+	case *alertingCluster.Peer:
+		peers := p.Peers()
+		members := make([]string, 0, len(peers))
+		for _, peer := range peers {
+			members = append(members, peer.Name())
+		}
+		return members, p.Name()
+	case *redisPeer:
+		return p.Members(), p.withPrefix(p.name)
+	default:
+		return nil, ""
+	}
+}
+
 // AlertmanagerFor returns the Alertmanager instance for the organization provided.
 // When the organization does not have an active Alertmanager, it returns a ErrNoAlertmanagerForOrg.
 // When the Alertmanager of the organization is not ready, it returns a ErrAlertmanagerNotReady.
@@ -220,6 +220,12 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, user ident
 		return models.AlertRule{}, err
 	}
 	rule.Updated = time.Now()
+	if err := store.ValidateSuppressingRules(ctx, service.ruleStore, &store.GroupDelta{
+		GroupKey: rule.GetGroupKey(),
+		New:      []*models.AlertRule{&rule},
+	}); err != nil {
+		return models.AlertRule{}, err
+	}
 	if len(rule.NotificationSettings) > 0 {
 		validator, err := service.nsValidatorProvider.Validator(ctx, rule.OrgID)
 		if err != nil {
@@ -364,45 +370,177 @@ func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, user ident
 }
 
 func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, user identity.Requester, group models.AlertRuleGroup, provenance models.Provenance) error {
-	if err := models.ValidateRuleGroupInterval(group.Interval, service.baseIntervalSeconds); err != nil {
+	delta, err := service.calcAuthorizedDelta(ctx, user, group)
+	if err != nil {
 		return err
 	}
+	if delta.IsEmpty() {
+		return nil
+	}
+	return service.persistDelta(ctx, user, delta, provenance)
+}
+
+// ImportRuleGroups replaces the rule groups with the given groups in a single transaction. Other groups are not changed.
+// Rules without UID are matched to the existing rules of the folder by title, so files that do not contain UIDs of rules,
+// such as HCL exports, update the existing rules instead of re-creating them.
+// If dryRun is true, the changes are calculated and verified but not stored. Returns the changes of every group.
+func (service *AlertRuleService) ImportRuleGroups(ctx context.Context, user identity.Requester, groups []models.AlertRuleGroup, provenance models.Provenance, dryRun bool) ([]*store.GroupDelta, error) {
+	seen := make(map[models.AlertRuleGroupKey]struct{}, len(groups))
+	for _, group := range groups {
+		key := models.AlertRuleGroupKey{OrgID: user.GetOrgID(), NamespaceUID: group.FolderUID, RuleGroup: group.Title}
+		if _, ok := seen[key]; ok {
+			return nil, fmt.Errorf("%w: rule group '%s' in folder '%s' is specified more than once", models.ErrAlertRuleFailedValidation, group.Title, group.FolderUID)
+		}
+		seen[key] = struct{}{}
+	}
+
+	result := make([]*store.GroupDelta, 0, len(groups))
+	err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		for _, group := range groups {
+			created, err := service.resolveImportedRuleUIDs(ctx, user, &group)
+			if err != nil {
+				return err
+			}
+			delta, err := service.calcAuthorizedDelta(ctx, user, group)
+			if err != nil {
+				return fmt.Errorf("rule group '%s': %w", group.Title, err)
+			}
+			for _, rule := range delta.New {
+				if uid, ok := created[rule.Title]; ok {
+					rule.UID = uid
+				}
+			}
+			result = append(result, delta)
+			if delta.IsEmpty() {
+				continue
+			}
+			if dryRun {
+				if err := service.checkDeltaProvenance(ctx, user, delta, provenance); err != nil {
+					return fmt.Errorf("rule group '%s': %w", group.Title, err)
+				}
+				continue
+			}
+			if err := service.persistDelta(ctx, user, delta, provenance); err != nil {
+				return fmt.Errorf("rule group '%s': %w", group.Title, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// resolveImportedRuleUIDs prepares the rules of the imported group for the calculation of the changes.
+// Rules without UID get the UID of the existing rule in the folder with the same title, if any.
+// Rules with UID that does not exist in the organization are going to be created, and their UIDs are removed from the group
+// and returned by title, so they can be restored once the changes are calculated.
+func (service *AlertRuleService) resolveImportedRuleUIDs(ctx context.Context, user identity.Requester, group *models.AlertRuleGroup) (map[string]string, error) {
+	rules, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+		OrgID:         user.GetOrgID(),
+		NamespaceUIDs: []string{group.FolderUID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	byTitle := make(map[string]string, len(rules))
+	for _, r := range rules {
+		byTitle[r.Title] = r.UID
+	}
+
+	var uids []string
+	for i := range group.Rules {
+		rule := &group.Rules[i]
+		if rule.UID == "" {
+			rule.UID = byTitle[rule.Title]
+			continue
+		}
+		uids = append(uids, rule.UID)
+	}
+	if len(uids) == 0 {
+		return nil, nil
+	}
+
+	rules, err = service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+		OrgID:    user.GetOrgID(),
+		RuleUIDs: uids,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	found := make(map[string]struct{}, len(rules))
+	for _, r := range rules {
+		found[r.UID] = struct{}{}
+	}
+	created := make(map[string]string)
+	for i := range group.Rules {
+		rule := &group.Rules[i]
+		if rule.UID == "" {
+			continue
+		}
+		if _, ok := found[rule.UID]; !ok {
+			created[rule.Title] = rule.UID
+			rule.UID = ""
+		}
+	}
+	return created, nil
+}
+
+// calcAuthorizedDelta validates the group and calculates the changes to the group in the database.
+// Returns an error if the user is not authorized to apply the changes or the changes are not valid.
+func (service *AlertRuleService) calcAuthorizedDelta(ctx context.Context, user identity.Requester, group models.AlertRuleGroup) (*store.GroupDelta, error) {
+	if err := models.ValidateRuleGroupInterval(group.Interval, service.baseIntervalSeconds); err != nil {
+		return nil, err
+	}
+
+	rules := make([]*models.AlertRule, 0, len(group.Rules))
+	for i := range group.Rules {
+		rules = append(rules, &group.Rules[i])
+	}
+	if err := models.ValidateRuleGroupInputs(rules); err != nil {
+		return nil, err
+	}
 
 	delta, err := service.calcDelta(ctx, user, group)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if delta.IsEmpty() {
-		return nil
+		return delta, nil
 	}
 
 	// check if the current user has permissions to all rules and can bypass the regular authorization validation.
 	can, err := service.authz.CanWriteAllRules(ctx, user)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if !can {
 		if err := service.authz.AuthorizeRuleGroupWrite(ctx, user, delta); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
+	if err := store.ValidateSuppressingRules(ctx, service.ruleStore, delta); err != nil {
+		return nil, err
+	}
+
 	newOrUpdatedNotificationSettings := delta.NewOrUpdatedNotificationSettings()
 	if len(newOrUpdatedNotificationSettings) > 0 {
 		validator, err := service.nsValidatorProvider.Validator(ctx, delta.GroupKey.OrgID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		for _, s := range newOrUpdatedNotificationSettings {
 			if err := validator.Validate(s); err != nil {
-				return errors.Join(models.ErrAlertRuleFailedValidation, err)
+				return nil, errors.Join(models.ErrAlertRuleFailedValidation, err)
 			}
 		}
 	}
 
-	return service.persistDelta(ctx, user, delta, provenance)
+	return delta, nil
 }
 
 func (service *AlertRuleService) DeleteRuleGroup(ctx context.Context, user identity.Requester, namespaceUID, group string, provenance models.Provenance) error {
@@ -478,18 +616,12 @@ func (service *AlertRuleService) calcDelta(ctx context.Context, user identity.Re
 
 func (service *AlertRuleService) persistDelta(ctx context.Context, user identity.Requester, delta *store.GroupDelta, provenance models.Provenance) error {
 	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := service.checkDeltaProvenance(ctx, user, delta, provenance); err != nil {
+			return err
+		}
+
 		// Delete first as this could prevent future unique constraint violations.
 		if len(delta.Delete) > 0 {
-			for _, del := range delta.Delete {
-				// check that provenance is not changed in an invalid way
-				storedProvenance, err := service.provenanceStore.GetProvenance(ctx, del, user.GetOrgID())
-				if err != nil {
-					return err
-				}
-				if canUpdate := validation.CanUpdateProvenanceInRuleGroup(storedProvenance, provenance); !canUpdate {
-					return fmt.Errorf("cannot delete with provided provenance '%s', needs '%s'", provenance, storedProvenance)
-				}
-			}
 			if err := service.deleteRules(ctx, user.GetOrgID(), delta.Delete...); err != nil {
 				return err
 			}
@@ -498,14 +630,6 @@ func (service *AlertRuleService) persistDelta(ctx context.Context, user identity
 		if len(delta.Update) > 0 {
 			updates := make([]models.UpdateRule, 0, len(delta.Update))
 			for _, update := range delta.Update {
-				// check that provenance is not changed in an invalid way
-				storedProvenance, err := service.provenanceStore.GetProvenance(ctx, update.New, user.GetOrgID())
-				if err != nil {
-					return err
-				}
-				if canUpdate := validation.CanUpdateProvenanceInRuleGroup(storedProvenance, provenance); !canUpdate {
-					return fmt.Errorf("cannot update with provided provenance '%s', needs '%s'", provenance, storedProvenance)
-				}
 				updates = append(updates, models.UpdateRule{
 					Existing: update.Existing,
 					New:      *update.New,
@@ -541,6 +665,29 @@ func (service *AlertRuleService) persistDelta(ctx context.Context, user identity
 	})
 }
 
+// checkDeltaProvenance checks that the provenance of the deleted and updated rules is not changed in an invalid way.
+func (service *AlertRuleService) checkDeltaProvenance(ctx context.Context, user identity.Requester, delta *store.GroupDelta, provenance models.Provenance) error {
+	for _, del := range delta.Delete {
+		storedProvenance, err := service.provenanceStore.GetProvenance(ctx, del, user.GetOrgID())
+		if err != nil {
+			return err
+		}
+		if canUpdate := validation.CanUpdateProvenanceInRuleGroup(storedProvenance, provenance); !canUpdate {
+			return fmt.Errorf("cannot delete with provided provenance '%s', needs '%s'", provenance, storedProvenance)
+		}
+	}
+	for _, update := range delta.Update {
+		storedProvenance, err := service.provenanceStore.GetProvenance(ctx, update.New, user.GetOrgID())
+		if err != nil {
+			return err
+		}
+		if canUpdate := validation.CanUpdateProvenanceInRuleGroup(storedProvenance, provenance); !canUpdate {
+			return fmt.Errorf("cannot update with provided provenance '%s', needs '%s'", provenance, storedProvenance)
+		}
+	}
+	return nil
+}
+
 // UpdateAlertRule updates an alert rule.
 func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, user identity.Requester, rule models.AlertRule, provenance models.Provenance) (models.AlertRule, error) {
 	var storedRule *models.AlertRule
@@ -584,6 +731,12 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, user ident
 	if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
 		return models.AlertRule{}, fmt.Errorf("cannot change provenance from '%s' to '%s'", storedProvenance, provenance)
 	}
+	if err := store.ValidateSuppressingRules(ctx, service.ruleStore, &store.GroupDelta{
+		GroupKey: rule.GetGroupKey(),
+		Update:   []store.RuleDelta{{Existing: storedRule, New: &rule}},
+	}); err != nil {
+		return models.AlertRule{}, err
+	}
 	if len(rule.NotificationSettings) > 0 {
 		validator, err := service.nsValidatorProvider.Validator(ctx, rule.OrgID)
 		if err != nil {
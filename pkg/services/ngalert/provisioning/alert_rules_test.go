@@ -1065,6 +1065,107 @@ func TestDeleteAlertRule(t *testing.T) {
 	})
 }
 
+func TestImportRuleGroups(t *testing.T) {
+	orgID := rand.Int63()
+	u := &user.SignedInUser{OrgID: orgID}
+	groupKey := models.GenerateGroupKey(orgID)
+	gen := models.RuleGen
+	rules := gen.With(gen.WithGroupKey(groupKey), gen.WithIntervalSeconds(60), gen.WithUniqueGroupIndex()).GenerateManyRef(2)
+
+	initServiceWithData := func(t *testing.T, provenance models.Provenance) (*AlertRuleService, *fakes.RuleStore) {
+		service, ruleStore, provenanceStore, _ := initService(t)
+		ruleStore.Rules = map[int64][]*models.AlertRule{
+			orgID: rules,
+		}
+		for _, rule := range rules {
+			require.NoError(t, provenanceStore.SetProvenance(context.Background(), rule, orgID, provenance))
+		}
+		return service, ruleStore
+	}
+	// importedGroup returns the group as it would be parsed from a file without UIDs of rules.
+	importedGroup := func() models.AlertRuleGroup {
+		group := models.AlertRuleGroup{
+			Title:     groupKey.RuleGroup,
+			FolderUID: groupKey.NamespaceUID,
+			Interval:  60,
+		}
+		for _, rule := range rules {
+			r := models.CopyRule(rule)
+			r.UID = ""
+			group.Rules = append(group.Rules, *r)
+		}
+		group.Rules[1].Labels = map[string]string{"imported": "true"}
+		newRule := gen.With(gen.WithGroupKey(groupKey), gen.WithIntervalSeconds(60)).Generate()
+		newRule.UID = ""
+		group.Rules = append(group.Rules, newRule)
+		return group
+	}
+	recordedUpdates := func(ruleStore *fakes.RuleStore) []any {
+		return ruleStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			switch cmd.(type) {
+			case []models.UpdateRule, []models.AlertRule:
+				return cmd, true
+			}
+			return nil, false
+		})
+	}
+
+	// changedRules returns the updated rules excluding the rules that are updated only by recalculation of the group fields.
+	changedRules := func(delta *store.GroupDelta) []*models.AlertRule {
+		var result []*models.AlertRule
+		for _, update := range delta.Update {
+			if len(update.Diff) > 0 {
+				result = append(result, update.New)
+			}
+		}
+		return result
+	}
+
+	t.Run("should update rules matched by title", func(t *testing.T) {
+		service, ruleStore := initServiceWithData(t, models.ProvenanceAPI)
+
+		deltas, err := service.ImportRuleGroups(context.Background(), u, []models.AlertRuleGroup{importedGroup()}, models.ProvenanceAPI, false)
+		require.NoError(t, err)
+
+		require.Len(t, deltas, 1)
+		require.Empty(t, deltas[0].Delete)
+		require.Len(t, deltas[0].New, 1)
+		changed := changedRules(deltas[0])
+		require.Len(t, changed, 1)
+		assert.Equal(t, rules[1].UID, changed[0].UID)
+		assert.Equal(t, map[string]string{"imported": "true"}, changed[0].Labels)
+		require.Len(t, recordedUpdates(ruleStore), 2)
+	})
+
+	t.Run("should not change rules in dry run", func(t *testing.T) {
+		service, ruleStore := initServiceWithData(t, models.ProvenanceAPI)
+
+		deltas, err := service.ImportRuleGroups(context.Background(), u, []models.AlertRuleGroup{importedGroup()}, models.ProvenanceAPI, true)
+		require.NoError(t, err)
+
+		require.Len(t, deltas, 1)
+		require.Len(t, deltas[0].New, 1)
+		require.Len(t, changedRules(deltas[0]), 1)
+		require.Empty(t, recordedUpdates(ruleStore))
+	})
+
+	t.Run("should check provenance in dry run", func(t *testing.T) {
+		service, ruleStore := initServiceWithData(t, models.ProvenanceFile)
+
+		_, err := service.ImportRuleGroups(context.Background(), u, []models.AlertRuleGroup{importedGroup()}, models.ProvenanceAPI, true)
+		require.ErrorContains(t, err, "cannot update with provided provenance")
+		require.Empty(t, recordedUpdates(ruleStore))
+	})
+
+	t.Run("should reject duplicate groups", func(t *testing.T) {
+		service, ruleStore := initServiceWithData(t, models.ProvenanceAPI)
+
+		_, err := service.ImportRuleGroups(context.Background(), u, []models.AlertRuleGroup{importedGroup(), importedGroup()}, models.ProvenanceAPI, false)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.Empty(t, recordedUpdates(ruleStore))
+	})
+}
+
 func TestGetAlertRule(t *testing.T) {
 	orgID := rand.Int63()
 	u := &user.SignedInUser{OrgID: orgID}
@@ -245,6 +245,7 @@ func (a *alertRule) Run() error {
 
 	var currentFingerprint fingerprint
 	defer a.stopApplied()
+	defer a.deleteRuleMetrics()
 	for {
 		select {
 		// used by external services (API) to notify that rule is updated.
@@ -355,6 +356,10 @@ func (a *alertRule) Run() error {
 				states := a.stateManager.DeleteStateByRuleUID(ngmodels.WithRuleKey(ctx, a.key.AlertRuleKey), a.key, ngmodels.StateReasonRuleDeleted)
 				a.expireAndSend(grafanaCtx, states)
 			}
+			// the rule is evaluated by another instance of the cluster, which continues from the state in the database.
+			if errors.Is(grafanaCtx.Err(), errRuleHandedOff) {
+				a.stateManager.ForgetStateByRuleUID(a.key.AlertRuleKey)
+			}
 			a.logger.Debug("Stopping alert rule routine")
 			return nil
 		}
@@ -370,16 +375,21 @@ func (a *alertRule) evaluate(ctx context.Context, e *Evaluation, span trace.Span
 	sendDuration := a.metrics.SendDuration.WithLabelValues(orgID)
 
 	start := a.clock.Now()
+	stats := &eval.Stats{}
 
 	evalCtx := eval.NewContextWithPreviousResults(ctx, SchedulerUserFor(e.rule.OrgID), a.newLoadedMetricsReader(e.rule))
-	ruleEval, err := a.evalFactory.Create(evalCtx, e.rule.GetEvalCondition().WithSource("scheduler").WithFolder(e.folderTitle))
+	var ruleEval eval.ConditionEvaluator
+	inputs, err := waitForInputs(ctx, e)
+	if err == nil {
+		ruleEval, err = a.evalFactory.Create(evalCtx.WithInputs(inputs), e.rule.GetEvalCondition().WithSource("scheduler").WithFolder(e.folderTitle))
+	}
 	var results eval.Results
 	var dur time.Duration
 	if err != nil {
 		dur = a.clock.Now().Sub(start)
 		logger.Error("Failed to build rule evaluator", "error", err)
 	} else {
-		results, err = ruleEval.Evaluate(ctx, e.scheduledAt)
+		results, err = ruleEval.Evaluate(eval.WithStats(ctx, stats), e.scheduledAt)
 		dur = a.clock.Now().Sub(start)
 		if err != nil {
 			logger.Error("Failed to evaluate rule", "error", err, "duration", dur)
@@ -394,6 +404,10 @@ func (a *alertRule) evaluate(ctx context.Context, e *Evaluation, span trace.Span
 		return nil
 	}
 
+	a.metrics.RuleLastEvalDuration.WithLabelValues(orgID, a.key.UID).Set(dur.Seconds())
+	a.metrics.RuleLastEvalSeries.WithLabelValues(orgID, a.key.UID).Set(float64(len(results)))
+	a.metrics.RuleLastEvalQueryBytes.WithLabelValues(orgID, a.key.UID).Set(float64(stats.QueryResponseBytes))
+
 	if err != nil || results.HasErrors() {
 		evalAttemptFailures.Inc()
 
@@ -490,6 +504,14 @@ func (a *alertRule) resetState(ctx context.Context, isPaused bool) {
 	a.expireAndSend(ctx, states)
 }
 
+// deleteRuleMetrics deletes the metrics of the rule when its evaluation routine stops.
+func (a *alertRule) deleteRuleMetrics() {
+	orgID := fmt.Sprint(a.key.OrgID)
+	a.metrics.RuleLastEvalDuration.DeleteLabelValues(orgID, a.key.UID)
+	a.metrics.RuleLastEvalSeries.DeleteLabelValues(orgID, a.key.UID)
+	a.metrics.RuleLastEvalQueryBytes.DeleteLabelValues(orgID, a.key.UID)
+}
+
 // evalApplied is only used on tests.
 func (a *alertRule) evalApplied(now time.Time) {
 	if a.evalAppliedHook == nil {
@@ -25,6 +25,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log/logtest"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/util"
@@ -267,7 +268,7 @@ func TestAlertRule(t *testing.T) {
 }
 
 func blankRuleForTests(ctx context.Context, key models.AlertRuleKeyWithGroup) *alertRule {
-	return newAlertRule(ctx, key, nil, false, 0, nil, nil, nil, nil, nil, nil, log.NewNopLogger(), nil, nil, nil)
+	return newAlertRule(ctx, key, nil, false, 0, nil, nil, nil, nil, nil, metrics.NewSchedulerMetrics(prometheus.NewRegistry()), log.NewNopLogger(), nil, nil, nil)
 }
 
 func TestRuleRoutine(t *testing.T) {
@@ -462,6 +463,26 @@ func TestRuleRoutine(t *testing.T) {
 					"grafana_alerting_rule_send_alerts_duration_seconds")
 				require.NoError(t, err)
 			})
+
+			t.Run("it reports metrics of the rule", func(t *testing.T) {
+				expectedMetric := fmt.Sprintf(
+					`# HELP grafana_alerting_rule_last_evaluation_duration_seconds The time taken by the last evaluation of an alert rule.
+							# TYPE grafana_alerting_rule_last_evaluation_duration_seconds gauge
+							grafana_alerting_rule_last_evaluation_duration_seconds{org="%[1]d",rule_uid="%[2]s"} 0
+							# HELP grafana_alerting_rule_last_evaluation_series The number of series produced by the last evaluation of an alert rule.
+							# TYPE grafana_alerting_rule_last_evaluation_series gauge
+							grafana_alerting_rule_last_evaluation_series{org="%[1]d",rule_uid="%[2]s"} 1
+							# HELP grafana_alerting_rule_last_evaluation_query_response_bytes The approximate size of the data returned by the queries in the last evaluation of an alert rule.
+							# TYPE grafana_alerting_rule_last_evaluation_query_response_bytes gauge
+							grafana_alerting_rule_last_evaluation_query_response_bytes{org="%[1]d",rule_uid="%[2]s"} 0
+				`, rule.OrgID, rule.UID)
+
+				err := testutil.GatherAndCompare(reg, bytes.NewBufferString(expectedMetric),
+					"grafana_alerting_rule_last_evaluation_duration_seconds",
+					"grafana_alerting_rule_last_evaluation_series",
+					"grafana_alerting_rule_last_evaluation_query_response_bytes")
+				require.NoError(t, err)
+			})
 		})
 	}
 
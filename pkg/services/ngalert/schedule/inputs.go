@@ -0,0 +1,186 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
+)
+
+var (
+	errRecordingRuleNotEvaluated = errors.New("recording rule was not evaluated")
+	errRecordingRulePaused       = errors.New("recording rule is paused")
+	errRecordingRulesDisabled    = errors.New("recording rules are disabled")
+	errEvaluationDropped         = errors.New("evaluation was dropped because the previous one is too slow")
+)
+
+// recordingOutput is the output of the evaluation of a recording rule at a tick, which the rules of the same group that
+// read it wait for. Only the first output that is set is kept.
+type recordingOutput struct {
+	once   sync.Once
+	done   chan struct{}
+	frames data.Frames
+	err    error
+}
+
+func newRecordingOutput() *recordingOutput {
+	return &recordingOutput{done: make(chan struct{})}
+}
+
+// set sets the frames of the output, or the error if the recording rule failed to produce them.
+// It does nothing if the output has already been set or is nil.
+func (o *recordingOutput) set(frames data.Frames, err error) {
+	if o == nil {
+		return
+	}
+	o.once.Do(func() {
+		o.frames = frames
+		o.err = err
+		close(o.done)
+	})
+}
+
+// wait blocks until the output is set or the context is done.
+func (o *recordingOutput) wait(ctx context.Context) (data.Frames, error) {
+	select {
+	case <-o.done:
+		return o.frames, o.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// recordedFrames returns the series that the recording rule records at the time as numbers with the labels of the rule,
+// which is how the rules that read the output of the recording rule would get them from the remote storage.
+func recordedFrames(rule *ngmodels.AlertRule, t time.Time, frames data.Frames) (data.Frames, error) {
+	points, err := writer.PointsFromFrames(rule.Record.Metric, t, frames, rule.Labels)
+	if err != nil {
+		return nil, err
+	}
+	result := make(data.Frames, 0, len(points))
+	for _, p := range points {
+		v := p.Metric.V
+		frame := data.NewFrame("", data.NewField(p.Name, p.Labels, []*float64{&v}))
+		frame.SetMeta(&data.FrameMeta{
+			Type:        data.FrameTypeNumericMulti,
+			TypeVersion: data.FrameTypeVersion{0, 1},
+		})
+		result = append(result, frame)
+	}
+	return result, nil
+}
+
+// waitForInputs waits for the outputs of the recording rules that the rule of the evaluation reads, at most for the
+// interval of the rule, and returns their frames by the metric of the recording rules.
+func waitForInputs(ctx context.Context, e *Evaluation) (map[string]data.Frames, error) {
+	if len(e.inputs) == 0 {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(e.rule.IntervalSeconds)*time.Second)
+	defer cancel()
+	inputs := make(map[string]data.Frames, len(e.inputs))
+	for metric, output := range e.inputs {
+		frames, err := output.wait(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the output of the recording rule of metric %s: %w", metric, err)
+		}
+		inputs[metric] = frames
+	}
+	return inputs, nil
+}
+
+// chainedRules returns the keys of the rules that read the output of recording rules of their group, and of these recording rules.
+func chainedRules(rules []*ngmodels.AlertRule) map[ngmodels.AlertRuleKey]struct{} {
+	recorders := make(map[ngmodels.AlertRuleGroupKey]map[string]ngmodels.AlertRuleKey)
+	for _, rule := range rules {
+		if rule.Type() != ngmodels.RuleTypeRecording {
+			continue
+		}
+		gk := rule.GetGroupKey()
+		if recorders[gk] == nil {
+			recorders[gk] = make(map[string]ngmodels.AlertRuleKey)
+		}
+		recorders[gk][rule.Record.Metric] = rule.GetKey()
+	}
+
+	chained := make(map[ngmodels.AlertRuleKey]struct{})
+	for _, rule := range rules {
+		for _, metric := range rule.Inputs() {
+			chained[rule.GetKey()] = struct{}{}
+			if key, ok := recorders[rule.GetGroupKey()][metric]; ok {
+				chained[key] = struct{}{}
+			}
+		}
+	}
+	return chained
+}
+
+// linkInputs links the evaluations of the rules that read the output of recording rules of their group to the
+// evaluations of these recording rules at the same tick. It returns the items sorted so that every rule comes after the
+// recording rules that it reads, keeping the order of the rules that do not depend on each other.
+func linkInputs(items []readyToRunItem) []readyToRunItem {
+	recorders := make(map[ngmodels.AlertRuleGroupKey]map[string]int)
+	for i, item := range items {
+		if item.rule.Type() != ngmodels.RuleTypeRecording {
+			continue
+		}
+		gk := item.rule.GetGroupKey()
+		if recorders[gk] == nil {
+			recorders[gk] = make(map[string]int)
+		}
+		recorders[gk][item.rule.Record.Metric] = i
+	}
+
+	dependencies := make(map[int][]int)
+	for i := range items {
+		metrics := items[i].rule.Inputs()
+		if len(metrics) == 0 {
+			continue
+		}
+		items[i].inputs = make(map[string]*recordingOutput, len(metrics))
+		for _, metric := range metrics {
+			idx, ok := recorders[items[i].rule.GetGroupKey()][metric]
+			if !ok {
+				// The recording rule is not evaluated at this tick, for example because it was deleted.
+				output := newRecordingOutput()
+				output.set(nil, errRecordingRuleNotEvaluated)
+				items[i].inputs[metric] = output
+				continue
+			}
+			if items[idx].output == nil {
+				items[idx].output = newRecordingOutput()
+			}
+			items[i].inputs[metric] = items[idx].output
+			dependencies[i] = append(dependencies[i], idx)
+		}
+	}
+	if len(dependencies) == 0 {
+		return items
+	}
+
+	// Add the items in a depth-first order in which the recording rules that a rule reads come first.
+	// A cycle, which is rejected by the validation of the rule group, is ignored.
+	sorted := make([]readyToRunItem, 0, len(items))
+	visited := make([]bool, len(items))
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] {
+			return
+		}
+		visited[i] = true
+		for _, dep := range dependencies[i] {
+			visit(dep)
+		}
+		sorted = append(sorted, items[i])
+	}
+	for i := range items {
+		visit(i)
+	}
+	return sorted
+}
@@ -0,0 +1,168 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestRecordingOutput(t *testing.T) {
+	t.Run("keeps the first output", func(t *testing.T) {
+		output := newRecordingOutput()
+		frames := data.Frames{data.NewFrame("first")}
+		output.set(frames, nil)
+		output.set(nil, errors.New("second"))
+
+		result, err := output.wait(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, frames, result)
+	})
+
+	t.Run("waits until the output is set", func(t *testing.T) {
+		output := newRecordingOutput()
+		expectedErr := errors.New("failed")
+		go output.set(nil, expectedErr)
+
+		_, err := output.wait(context.Background())
+		require.ErrorIs(t, err, expectedErr)
+	})
+
+	t.Run("stops waiting when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := newRecordingOutput().wait(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("set does nothing if output is nil", func(t *testing.T) {
+		var output *recordingOutput
+		require.NotPanics(t, func() {
+			output.set(nil, nil)
+		})
+	})
+}
+
+func TestWaitForInputs(t *testing.T) {
+	rule := models.RuleGen.With(models.RuleMuts.WithInterval(time.Second)).GenerateRef()
+
+	t.Run("returns frames of inputs by metric", func(t *testing.T) {
+		frames := data.Frames{data.NewFrame("")}
+		output := newRecordingOutput()
+		output.set(frames, nil)
+		inputs, err := waitForInputs(context.Background(), &Evaluation{rule: rule, inputs: map[string]*recordingOutput{"job:rate": output}})
+		require.NoError(t, err)
+		require.Equal(t, map[string]data.Frames{"job:rate": frames}, inputs)
+	})
+
+	t.Run("returns error of an input", func(t *testing.T) {
+		output := newRecordingOutput()
+		output.set(nil, errRecordingRulePaused)
+		_, err := waitForInputs(context.Background(), &Evaluation{rule: rule, inputs: map[string]*recordingOutput{"job:rate": output}})
+		require.ErrorIs(t, err, errRecordingRulePaused)
+		require.ErrorContains(t, err, "job:rate")
+	})
+
+	t.Run("stops waiting after the interval of the rule", func(t *testing.T) {
+		_, err := waitForInputs(context.Background(), &Evaluation{rule: rule, inputs: map[string]*recordingOutput{"job:rate": newRecordingOutput()}})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("returns nothing if the rule has no inputs", func(t *testing.T) {
+		inputs, err := waitForInputs(context.Background(), &Evaluation{rule: rule})
+		require.NoError(t, err)
+		require.Nil(t, inputs)
+	})
+}
+
+func TestRecordedFrames(t *testing.T) {
+	rule := models.RuleGen.With(models.RuleMuts.WithAllRecordingRules(), models.RuleMuts.WithMetric("job:rate"), models.RuleMuts.WithLabels(data.Labels{"team": "a"})).GenerateRef()
+	value := 2.0
+	frames := data.Frames{
+		data.NewFrame("", data.NewField("A", data.Labels{"job": "api", "__name__": "rate"}, []*float64{&value})).SetMeta(&data.FrameMeta{
+			Type:        data.FrameTypeNumericMulti,
+			TypeVersion: data.FrameTypeVersion{0, 1},
+		}),
+	}
+
+	recorded, err := recordedFrames(rule, time.Now(), frames)
+	require.NoError(t, err)
+	require.Len(t, recorded, 1)
+	field := recorded[0].Fields[0]
+	require.Equal(t, "job:rate", field.Name)
+	require.Equal(t, data.Labels{"job": "api", "team": "a"}, field.Labels)
+	require.Equal(t, 2.0, *field.At(0).(*float64))
+}
+
+func TestLinkInputs(t *testing.T) {
+	gen := models.RuleGen
+	groupGen := gen.With(gen.WithGroupKey(models.GenerateGroupKey(1)))
+	item := func(rule *models.AlertRule) readyToRunItem {
+		return readyToRunItem{Evaluation: Evaluation{rule: rule}}
+	}
+	uids := func(items []readyToRunItem) []string {
+		result := make([]string, 0, len(items))
+		for _, item := range items {
+			result = append(result, item.rule.UID)
+		}
+		return result
+	}
+
+	alerting := groupGen.With(gen.WithQuery(models.CreateInputQuery("A", "job:rate:sum"), models.CreateReduceExpression("B", "A", "last"))).GenerateRef()
+	alerting.UID = "alerting"
+	sum := groupGen.With(gen.WithAllRecordingRules(), gen.WithMetric("job:rate:sum"), gen.WithQuery(models.CreateInputQuery("A", "job:rate"))).GenerateRef()
+	sum.UID = "sum"
+	rate := groupGen.With(gen.WithAllRecordingRules(), gen.WithMetric("job:rate")).GenerateRef()
+	rate.UID = "rate"
+	independent := groupGen.GenerateRef()
+	independent.UID = "independent"
+	otherGroup := gen.With(gen.WithQuery(models.CreateInputQuery("A", "job:rate"))).GenerateRef()
+	otherGroup.UID = "other-group"
+
+	t.Run("sorts rules after the recording rules they read and links their outputs", func(t *testing.T) {
+		items := linkInputs([]readyToRunItem{item(alerting), item(independent), item(sum), item(rate)})
+
+		require.Equal(t, []string{"rate", "sum", "alerting", "independent"}, uids(items))
+		require.NotNil(t, items[0].output)
+		require.NotNil(t, items[1].output)
+		require.Equal(t, map[string]*recordingOutput{"job:rate": items[0].output}, items[1].inputs)
+		require.Equal(t, map[string]*recordingOutput{"job:rate:sum": items[1].output}, items[2].inputs)
+		require.Nil(t, items[2].output)
+		require.Nil(t, items[3].output)
+		require.Nil(t, items[3].inputs)
+	})
+
+	t.Run("fails inputs of recording rules that are not evaluated at the tick", func(t *testing.T) {
+		items := linkInputs([]readyToRunItem{item(otherGroup), item(rate)})
+
+		require.Equal(t, []string{"other-group", "rate"}, uids(items))
+		require.Nil(t, items[1].output)
+		_, err := items[0].inputs["job:rate"].wait(context.Background())
+		require.ErrorIs(t, err, errRecordingRuleNotEvaluated)
+	})
+
+	t.Run("keeps the order if rules do not read recording rules", func(t *testing.T) {
+		items := linkInputs([]readyToRunItem{item(independent), item(rate)})
+		require.Equal(t, []string{"independent", "rate"}, uids(items))
+	})
+}
+
+func TestChainedRules(t *testing.T) {
+	gen := models.RuleGen
+	groupGen := gen.With(gen.WithGroupKey(models.GenerateGroupKey(1)))
+	alerting := groupGen.With(gen.WithQuery(models.CreateInputQuery("A", "job:rate"))).GenerateRef()
+	rate := groupGen.With(gen.WithAllRecordingRules(), gen.WithMetric("job:rate")).GenerateRef()
+	unused := groupGen.With(gen.WithAllRecordingRules(), gen.WithMetric("job:errors")).GenerateRef()
+	independent := groupGen.GenerateRef()
+
+	chained := chainedRules([]*models.AlertRule{alerting, rate, unused, independent})
+	require.Equal(t, map[models.AlertRuleKey]struct{}{
+		alerting.GetKey(): {},
+		rate.GetKey():     {},
+	}, chained)
+}
@@ -134,6 +134,7 @@ func (r *recordingRule) Run() error {
 			}
 			if !r.cfg.Enabled {
 				r.logger.Warn("Recording rule scheduled but subsystem is not enabled. Skipping")
+				eval.output.set(nil, errRecordingRulesDisabled)
 				return nil
 			}
 			// TODO: Skipping the "evalRunning" guard that the alert rule routine does, because it seems to be dead code and impossible to hit.
@@ -170,9 +171,20 @@ func (r *recordingRule) doEvaluate(ctx context.Context, ev *Evaluation) {
 
 	if ev.rule.IsPaused {
 		logger.Debug("Skip recording rule evaluation because it is paused")
+		ev.output.set(nil, errRecordingRulePaused)
 		return
 	}
 
+	var latestError error
+	// Make sure that the rules that read the output of the recording rule do not wait for it if it is not produced.
+	// This has no effect if the output has already been set.
+	defer func() {
+		if latestError == nil {
+			latestError = errRecordingRuleNotEvaluated
+		}
+		ev.output.set(nil, latestError)
+	}()
+
 	ctx, span := r.tracer.Start(ctx, "recording rule execution", trace.WithAttributes(
 		attribute.String("rule_uid", ev.rule.UID),
 		attribute.Int64("org_id", ev.rule.OrgID),
@@ -182,7 +194,6 @@ func (r *recordingRule) doEvaluate(ctx context.Context, ev *Evaluation) {
 	))
 	defer span.End()
 
-	var latestError error
 	for attempt := int64(1); attempt <= r.maxAttempts; attempt++ {
 		logger := logger.New("attempt", attempt)
 		if ctx.Err() != nil {
@@ -234,8 +245,12 @@ func (r *recordingRule) doEvaluate(ctx context.Context, ev *Evaluation) {
 }
 
 func (r *recordingRule) tryEvaluation(ctx context.Context, ev *Evaluation, logger log.Logger) error {
+	inputs, err := waitForInputs(ctx, ev)
+	if err != nil {
+		return err
+	}
 	evalStart := r.clock.Now()
-	evalCtx := eval.NewContext(ctx, SchedulerUserFor(ev.rule.OrgID))
+	evalCtx := eval.NewContext(ctx, SchedulerUserFor(ev.rule.OrgID)).WithInputs(inputs)
 	result, err := r.buildAndExecutePipeline(ctx, evalCtx, ev, logger)
 	evalDur := r.clock.Now().Sub(evalStart)
 	if err != nil {
@@ -261,8 +276,13 @@ func (r *recordingRule) tryEvaluation(ctx context.Context, ev *Evaluation, logge
 		))
 		logger.Debug("Query returned no data", "reason", err)
 		r.health.Store("nodata")
+		ev.output.set(nil, nil)
 		return nil
 	}
+	// The rules of the group that read the output get it in-process, whether it is written to the remote storage or not.
+	if ev.output != nil {
+		ev.output.set(recordedFrames(ev.rule, ev.scheduledAt, frames))
+	}
 
 	writeStart := r.clock.Now()
 	err = r.writer.Write(ctx, ev.rule.Record.Metric, ev.scheduledAt, frames, ev.rule.OrgID, ev.rule.Labels)
@@ -18,6 +18,7 @@ import (
 var (
 	errRuleDeleted   = errors.New("rule deleted")
 	errRuleRestarted = errors.New("rule restarted")
+	errRuleHandedOff = errors.New("rule handed off")
 )
 
 type ruleFactory interface {
@@ -96,6 +97,10 @@ type Evaluation struct {
 	scheduledAt time.Time
 	rule        *models.AlertRule
 	folderTitle string
+	// output is set by a recording rule to its output when rules of its group read it at the same tick.
+	output *recordingOutput
+	// inputs are the outputs of the recording rules of the group that the rule reads by their metric.
+	inputs map[string]*recordingOutput
 }
 
 func (e *Evaluation) Fingerprint() fingerprint {
@@ -312,6 +317,13 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 		writeBytes(tmp)
 	}
 
+	for _, sr := range rule.SuppressedBy {
+		writeString(sr.RuleUID)
+		for _, name := range sr.Equal {
+			writeString(name)
+		}
+	}
+
 	// fields that do not affect the state.
 	// TODO consider removing fields below from the fingerprint
 	writeInt(rule.ID)
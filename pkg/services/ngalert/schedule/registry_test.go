@@ -209,6 +209,9 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 					SimplifiedQueryAndExpressionsSection: false,
 				},
 			},
+			SuppressedBy: []models.SuppressingRule{
+				{RuleUID: "suppressing-uid", Equal: []string{"instance"}},
+			},
 		}
 		r2 := &models.AlertRule{
 			ID:        2,
@@ -253,6 +256,9 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 					SimplifiedQueryAndExpressionsSection: true,
 				},
 			},
+			SuppressedBy: []models.SuppressingRule{
+				{RuleUID: "suppressing-uid-2"},
+			},
 		}
 
 		excludedFields := map[string]struct{}{
@@ -260,6 +266,7 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			"Updated":         {},
 			"IntervalSeconds": {},
 			"Annotations":     {},
+			"UpdatedBy":       {},
 		}
 
 		tp := reflect.TypeOf(rule).Elem()
@@ -100,6 +100,9 @@ type schedule struct {
 	tracer tracing.Tracer
 
 	recordingWriter RecordingWriter
+
+	// sharder is set if the alert rules are sharded between the instances of the high availability cluster.
+	sharder *ruleSharder
 }
 
 // SchedulerCfg is the scheduler configuration.
@@ -119,6 +122,9 @@ type SchedulerCfg struct {
 	Tracer               tracing.Tracer
 	Log                  log.Logger
 	RecordingWriter      RecordingWriter
+	// ClusterMembership enables sharding of the alert rules between the members of the high availability cluster.
+	// If it is nil, this instance evaluates all alert rules.
+	ClusterMembership ClusterMembership
 }
 
 // NewScheduler returns a new scheduler.
@@ -149,6 +155,9 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		tracer:                cfg.Tracer,
 		recordingWriter:       cfg.RecordingWriter,
 	}
+	if cfg.ClusterMembership != nil {
+		sch.sharder = newRuleSharder(cfg.ClusterMembership, cfg.Log)
+	}
 
 	return &sch
 }
@@ -256,6 +265,15 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 
 	sch.updateRulesMetrics(alertRules)
 
+	// rules that read the output of recording rules of their group are evaluated at the same tick as these recording rules.
+	chained := chainedRules(alertRules)
+
+	// handedOff contains the rules that are evaluated by other instances of the cluster.
+	handedOff := make(map[ngmodels.AlertRuleKey]struct{})
+	if sch.sharder != nil {
+		alertRules, handedOff = sch.shardAlertRules(alertRules, chained)
+	}
+
 	readyToRun := make([]readyToRunItem, 0)
 	updatedRules := make([]ngmodels.AlertRuleKeyWithVersion, 0, len(updated)) // this is needed for tests only
 	restartedRules := make([]Rule, 0)
@@ -280,6 +298,8 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 	for _, item := range alertRules {
 		ruleRoutine, newRoutine := sch.registry.getOrCreate(ctx, item, ruleFactory)
 		key := item.GetKey()
+		// the rule might have been evaluated by another instance of the cluster, and its state must be taken over.
+		loadState := newRoutine && sch.sharder != nil && sch.sharder.started
 		logger := sch.log.FromContext(ctx).New(key.LogContext()...)
 
 		// enforce minimum evaluation interval
@@ -300,6 +320,9 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 
 		if newRoutine && !invalidInterval {
 			dispatcherGroup.Go(func() error {
+				if loadState {
+					sch.stateManager.LoadStateByRuleUID(ctx, item)
+				}
 				return ruleRoutine.Run()
 			})
 		}
@@ -312,7 +335,11 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		}
 
 		itemFrequency := item.IntervalSeconds / int64(sch.baseInterval.Seconds())
-		offset := jitterOffsetInTicks(item, sch.baseInterval, sch.jitterEvaluations)
+		jitterStrategy := sch.jitterEvaluations
+		if _, ok := chained[key]; ok && jitterStrategy == JitterByRule {
+			jitterStrategy = JitterByGroup
+		}
+		offset := jitterOffsetInTicks(item, sch.baseInterval, jitterStrategy)
 		isReadyToRun := item.IntervalSeconds != 0 && (tickNum%itemFrequency)-offset == 0
 
 		var folderTitle string
@@ -364,6 +391,7 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 	slices.SortFunc(readyToRun, func(a, b readyToRunItem) int {
 		return strings.Compare(a.rule.UID, b.rule.UID)
 	})
+	readyToRun = linkInputs(readyToRun)
 	for i := range readyToRun {
 		item := readyToRun[i]
 
@@ -371,10 +399,12 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 			key := item.rule.GetKey()
 			success, dropped := item.ruleRoutine.Eval(&item.Evaluation)
 			if !success {
+				item.output.set(nil, errRecordingRuleNotEvaluated)
 				sch.log.Debug("Scheduled evaluation was canceled because evaluation routine was stopped", append(key.LogContext(), "time", tick)...)
 				return
 			}
 			if dropped != nil {
+				dropped.output.set(nil, errEvaluationDropped)
 				sch.log.Warn("Tick dropped because alert rule evaluation is too slow", append(key.LogContext(), "time", tick, "droppedTick", dropped.scheduledAt)...)
 				orgID := fmt.Sprint(key.OrgID)
 				sch.metrics.EvaluationMissed.WithLabelValues(orgID, item.rule.Title).Inc()
@@ -387,11 +417,52 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		oldRoutine.Stop(errRuleRestarted)
 	}
 
-	// unregister and stop routines of the deleted alert rules
+	// unregister and stop routines of the deleted alert rules, and of the rules handed off to other instances
 	toDelete := make([]ngmodels.AlertRuleKey, 0, len(registeredDefinitions))
 	for key := range registeredDefinitions {
+		if _, ok := handedOff[key]; ok {
+			sch.handOffAlertRule(key)
+			continue
+		}
 		toDelete = append(toDelete, key)
 	}
 	sch.deleteAlertRule(toDelete...)
+	if sch.sharder != nil {
+		sch.sharder.started = true
+	}
 	return readyToRun, registeredDefinitions, updatedRules
 }
+
+// shardAlertRules returns the rules that are evaluated by this instance and the keys of the rules that are evaluated by
+// other instances of the cluster. On the first tick, the states of the rules of other instances, which were loaded
+// to the cache at startup, are removed from the cache.
+func (sch *schedule) shardAlertRules(alertRules []*ngmodels.AlertRule, chained map[ngmodels.AlertRuleKey]struct{}) ([]*ngmodels.AlertRule, map[ngmodels.AlertRuleKey]struct{}) {
+	if sch.sharder.update() {
+		sch.metrics.ShardRebalances.Inc()
+	}
+	owned := make([]*ngmodels.AlertRule, 0, len(alertRules))
+	handedOff := make(map[ngmodels.AlertRuleKey]struct{})
+	for _, rule := range alertRules {
+		if sch.sharder.owns(rule, chained) {
+			owned = append(owned, rule)
+			continue
+		}
+		handedOff[rule.GetKey()] = struct{}{}
+		if !sch.sharder.started {
+			sch.stateManager.ForgetStateByRuleUID(rule.GetKey())
+		}
+	}
+	sch.metrics.ShardedAlertRules.Set(float64(len(owned)))
+	return owned, handedOff
+}
+
+// handOffAlertRule stops evaluation of the rule that is evaluated by another instance of the cluster.
+// Unlike deleteAlertRule, the rule stays schedulable and its state is kept in the database for the new owner.
+func (sch *schedule) handOffAlertRule(key ngmodels.AlertRuleKey) {
+	ruleRoutine, ok := sch.registry.del(key)
+	if !ok {
+		return
+	}
+	sch.log.Debug("Alert rule is handed off to another instance", key.LogContext()...)
+	ruleRoutine.Stop(errRuleHandedOff)
+}
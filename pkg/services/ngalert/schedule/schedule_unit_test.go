@@ -553,6 +553,32 @@ func TestProcessTicks(t *testing.T) {
 		assert.Truef(t, slices.IsSorted(actualUids), "The scheduler rules should be sorted by UID but they aren't")
 		require.Equal(t, expectedUids, actualUids)
 	})
+
+	t.Run("rules that read the output of recording rules should be scheduled after them", func(t *testing.T) {
+		groupGen := gen.With(gen.WithOrgID(mainOrgID), gen.WithInterval(cfg.BaseInterval), gen.WithGroupKey(models.GenerateGroupKey(mainOrgID)))
+		recording := groupGen.With(gen.WithAllRecordingRules(), gen.WithMetric("job:rate")).GenerateRef()
+		recording.UID = "c"
+		alerting := groupGen.With(gen.WithQuery(models.CreateInputQuery("A", "job:rate"))).GenerateRef()
+		alerting.UID = "a"
+		other := groupGen.GenerateRef()
+		other.UID = "b"
+		ruleStore.rules = map[string]*models.AlertRule{}
+		ruleStore.PutRule(context.Background(), recording, alerting, other)
+
+		tick = tick.Add(cfg.BaseInterval)
+
+		scheduled, _, _ := sched.processTick(ctx, dispatcherGroup, tick)
+
+		actualUids := make([]string, 0, len(scheduled))
+		for _, rule := range scheduled {
+			actualUids = append(actualUids, rule.rule.UID)
+		}
+		require.Equal(t, []string{"c", "a", "b"}, actualUids)
+		require.NotNil(t, scheduled[0].output)
+		require.Equal(t, map[string]*recordingOutput{"job:rate": scheduled[0].output}, scheduled[1].inputs)
+		require.Nil(t, scheduled[2].output)
+		require.Nil(t, scheduled[2].inputs)
+	})
 }
 
 func TestSchedule_updateRulesMetrics(t *testing.T) {
@@ -0,0 +1,116 @@
+package schedule
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// shardTokensPerMember is the number of points that every member of the cluster takes on the hash ring.
+// More points make the distribution of the rules between the members more even.
+const shardTokensPerMember = 128
+
+// ClusterMembership provides the members of the high availability cluster between which the alert rules are sharded.
+type ClusterMembership interface {
+	// ClusterMembers returns the names of the members of the cluster, including this instance, and the name of this instance.
+	ClusterMembers() ([]string, string)
+}
+
+type shardToken struct {
+	hash   uint64
+	member string
+}
+
+// ruleSharder assigns every alert rule to one member of the cluster by consistent hashing of the rule key, so that
+// every rule is evaluated by only one instance and only a small part of the rules moves when the membership changes.
+type ruleSharder struct {
+	membership ClusterMembership
+	log        log.Logger
+
+	members []string
+	self    string
+	tokens  []shardToken
+	// started is true after the first tick that had the membership applied.
+	started bool
+}
+
+func newRuleSharder(membership ClusterMembership, logger log.Logger) *ruleSharder {
+	return &ruleSharder{membership: membership, log: logger}
+}
+
+// update rebuilds the hash ring if the membership of the cluster changed since the last call.
+// It returns true if the ring changed.
+func (s *ruleSharder) update() bool {
+	members, self := s.membership.ClusterMembers()
+	members = slices.Compact(slices.Sorted(slices.Values(members)))
+	if self == s.self && slices.Equal(members, s.members) {
+		return false
+	}
+
+	tokens := make([]shardToken, 0, len(members)*shardTokensPerMember)
+	for _, member := range members {
+		for i := 0; i < shardTokensPerMember; i++ {
+			tokens = append(tokens, shardToken{hash: shardHash(fmt.Sprintf("%s-%d", member, i)), member: member})
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].hash == tokens[j].hash {
+			return tokens[i].member < tokens[j].member
+		}
+		return tokens[i].hash < tokens[j].hash
+	})
+
+	s.log.Info("Cluster membership changed, rebalancing alert rules", "members", members, "self", self)
+	s.members = members
+	s.self = self
+	s.tokens = tokens
+	return true
+}
+
+// owns returns true if this instance evaluates the rule. Rules that read the output of recording rules of their group
+// are sharded by the group, so that the whole chain is evaluated by one instance.
+// If this instance is not a member of the cluster, for example, while it is joining, it owns all rules.
+func (s *ruleSharder) owns(rule *ngmodels.AlertRule, chained map[ngmodels.AlertRuleKey]struct{}) bool {
+	if len(s.tokens) == 0 || !slices.Contains(s.members, s.self) {
+		return true
+	}
+	return s.ownerOf(shardKey(rule, chained)) == s.self
+}
+
+// ownerOf returns the member that owns the key, which is the member of the first token on the ring at or after the hash
+// of the key.
+func (s *ruleSharder) ownerOf(key string) string {
+	h := shardHash(key)
+	i := sort.Search(len(s.tokens), func(i int) bool {
+		return s.tokens[i].hash >= h
+	})
+	if i == len(s.tokens) {
+		i = 0
+	}
+	return s.tokens[i].member
+}
+
+func shardKey(rule *ngmodels.AlertRule, chained map[ngmodels.AlertRuleKey]struct{}) string {
+	if _, ok := chained[rule.GetKey()]; ok {
+		return rule.GetGroupKey().String()
+	}
+	return rule.GetKey().String()
+}
+
+// shardHash returns the FNV-1a hash of the string, mixed by the finalizer of MurmurHash3 to spread keys that differ only
+// in the last characters, such as the tokens of a member, over the whole ring.
+func shardHash(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
@@ -0,0 +1,194 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+type fakeClusterMembership struct {
+	members []string
+	self    string
+}
+
+func (f *fakeClusterMembership) ClusterMembers() ([]string, string) {
+	return f.members, f.self
+}
+
+func TestRuleSharder(t *testing.T) {
+	gen := models.RuleGen
+	rules := gen.GenerateManyRef(300)
+	members := []string{"instance-1", "instance-2", "instance-3"}
+
+	newSharder := func(members []string, self string) *ruleSharder {
+		s := newRuleSharder(&fakeClusterMembership{members: members, self: self}, log.NewNopLogger())
+		s.update()
+		return s
+	}
+
+	owners := func(members []string) map[models.AlertRuleKey]string {
+		result := make(map[models.AlertRuleKey]string, len(rules))
+		for _, member := range members {
+			s := newSharder(members, member)
+			for _, rule := range rules {
+				if !s.owns(rule, nil) {
+					continue
+				}
+				_, ok := result[rule.GetKey()]
+				require.Falsef(t, ok, "rule %s is owned by more than one member", rule.UID)
+				result[rule.GetKey()] = member
+			}
+		}
+		return result
+	}
+
+	t.Run("every rule should be owned by exactly one member", func(t *testing.T) {
+		result := owners(members)
+		require.Len(t, result, len(rules))
+
+		perMember := make(map[string]int)
+		for _, member := range result {
+			perMember[member]++
+		}
+		for _, member := range members {
+			require.Greaterf(t, perMember[member], len(rules)/len(members)/2, "member %s owns too few rules", member)
+		}
+	})
+
+	t.Run("only rules of the member that left should move", func(t *testing.T) {
+		before := owners(members)
+		after := owners(members[:2])
+		for key, member := range before {
+			if member != members[2] {
+				require.Equal(t, member, after[key])
+			}
+		}
+	})
+
+	t.Run("rules that read the output of recording rules should be owned together with the group", func(t *testing.T) {
+		group := gen.With(gen.WithGroupKey(models.GenerateGroupKey(1))).GenerateManyRef(50)
+		chained := make(map[models.AlertRuleKey]struct{}, len(group))
+		for _, rule := range group {
+			chained[rule.GetKey()] = struct{}{}
+		}
+		s := newSharder(members, members[0])
+		owned := s.owns(group[0], chained)
+		for _, rule := range group {
+			require.Equal(t, owned, s.owns(rule, chained))
+		}
+	})
+
+	t.Run("should own all rules if it is not a member of the cluster", func(t *testing.T) {
+		for _, s := range []*ruleSharder{newSharder(nil, ""), newSharder(members, "instance-4")} {
+			for _, rule := range rules {
+				require.True(t, s.owns(rule, nil))
+			}
+		}
+	})
+
+	t.Run("update should report changes of the membership only", func(t *testing.T) {
+		membership := &fakeClusterMembership{members: members, self: members[0]}
+		s := newRuleSharder(membership, log.NewNopLogger())
+		require.True(t, s.update())
+		membership.members = []string{members[2], members[1], members[0], members[0]}
+		require.False(t, s.update())
+		membership.members = members[:2]
+		require.True(t, s.update())
+	})
+}
+
+func TestProcessTicksSharded(t *testing.T) {
+	ctx := context.Background()
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+
+	ruleStore := newFakeRulesStore()
+	instanceStore := &state.FakeInstanceStore{}
+	sched := setupScheduler(t, ruleStore, instanceStore, nil, nil, nil)
+	membership := &fakeClusterMembership{members: []string{"instance-1", "instance-2"}, self: "instance-1"}
+	sched.sharder = newRuleSharder(membership, log.NewNopLogger())
+
+	gen := models.RuleGen
+	rules := gen.With(gen.WithInterval(time.Second)).GenerateManyRef(20)
+	ruleStore.PutRule(ctx, rules...)
+
+	owner := newRuleSharder(&fakeClusterMembership{members: membership.members, self: "instance-1"}, log.NewNopLogger())
+	owner.update()
+	var owned, notOwned []models.AlertRuleKey
+	for _, rule := range rules {
+		if owner.owns(rule, nil) {
+			owned = append(owned, rule.GetKey())
+		} else {
+			notOwned = append(notOwned, rule.GetKey())
+		}
+	}
+	require.NotEmpty(t, owned)
+	require.NotEmpty(t, notOwned)
+
+	scheduledKeys := func(items []readyToRunItem) []models.AlertRuleKey {
+		keys := make([]models.AlertRuleKey, 0, len(items))
+		for _, item := range items {
+			keys = append(keys, item.rule.GetKey())
+		}
+		return keys
+	}
+	loadedRules := func() []string {
+		var uids []string
+		for _, op := range instanceStore.RecordedOps() {
+			if q, ok := op.(models.ListAlertInstancesQuery); ok {
+				uids = append(uids, q.RuleUID)
+			}
+		}
+		return uids
+	}
+
+	tick := time.Time{}
+
+	t.Run("on 1st tick only owned rules should be scheduled", func(t *testing.T) {
+		tick = tick.Add(time.Second)
+		scheduled, stopped, _ := sched.processTick(ctx, dispatcherGroup, tick)
+		require.ElementsMatch(t, owned, scheduledKeys(scheduled))
+		require.Empty(t, stopped)
+		for _, key := range notOwned {
+			require.False(t, sched.registry.exists(key))
+		}
+	})
+
+	t.Run("when a member leaves, its rules should be taken over with their state", func(t *testing.T) {
+		membership.members = []string{"instance-1"}
+		tick = tick.Add(time.Second)
+		scheduled, stopped, _ := sched.processTick(ctx, dispatcherGroup, tick)
+		require.Len(t, scheduled, len(rules))
+		require.Empty(t, stopped)
+
+		expected := make([]string, 0, len(notOwned))
+		for _, key := range notOwned {
+			expected = append(expected, key.UID)
+		}
+		require.Eventually(t, func() bool {
+			return len(loadedRules()) == len(expected)
+		}, time.Second, 10*time.Millisecond)
+		require.ElementsMatch(t, expected, loadedRules())
+	})
+
+	t.Run("when a member joins, its rules should be handed off but stay schedulable", func(t *testing.T) {
+		membership.members = []string{"instance-1", "instance-2"}
+		tick = tick.Add(time.Second)
+		scheduled, stopped, _ := sched.processTick(ctx, dispatcherGroup, tick)
+		require.ElementsMatch(t, owned, scheduledKeys(scheduled))
+		require.Len(t, stopped, len(notOwned))
+
+		schedulable, _ := sched.Rules()
+		require.Len(t, schedulable, len(rules))
+		for _, key := range notOwned {
+			require.Contains(t, stopped, key)
+			require.False(t, sched.registry.exists(key))
+		}
+	})
+}
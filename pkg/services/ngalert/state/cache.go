@@ -71,6 +71,20 @@ func (c *cache) countAlertsBy(state eval.State) float64 {
 	return count
 }
 
+// countStates returns the number of states of the rule and the number of states of all rules in the organization.
+func (c *cache) countStates(orgID int64, alertRuleUID string) (int64, int64) {
+	c.mtxStates.RLock()
+	defer c.mtxStates.RUnlock()
+	var rule, org int64
+	for uid, rs := range c.states[orgID] {
+		org += int64(len(rs.states))
+		if uid == alertRuleUID {
+			rule = int64(len(rs.states))
+		}
+	}
+	return rule, org
+}
+
 func (c *cache) getOrCreate(ctx context.Context, log log.Logger, alertRule *ngModels.AlertRule, result eval.Result, extraLabels data.Labels, externalURL *url.URL) *State {
 	// Calculation of state ID involves label and annotation expansion, which may be resource intensive operations, and doing it in the context guarded by mtxStates may create a lot of contention.
 	// Instead of just calculating ID we create an entire state - a candidate. If rule states already hold a state with this ID, this candidate will be discarded and the existing one will be returned.
@@ -261,6 +275,15 @@ func (c *cache) setAllStates(newStates map[int64]map[string]*ruleStates) {
 	c.states = newStates
 }
 
+func (c *cache) setRuleStates(orgID int64, alertRuleUID string, rs *ruleStates) {
+	c.mtxStates.Lock()
+	defer c.mtxStates.Unlock()
+	if _, ok := c.states[orgID]; !ok {
+		c.states[orgID] = make(map[string]*ruleStates)
+	}
+	c.states[orgID][alertRuleUID] = rs
+}
+
 func (c *cache) set(entry *State) {
 	c.mtxStates.Lock()
 	defer c.mtxStates.Unlock()
@@ -302,6 +325,14 @@ func (c *cache) getAll(orgID int64, skipNormalState bool) []*State {
 	return states
 }
 
+// hasRuleStates returns true if the cache contains the states of the rule, even if there are none.
+func (c *cache) hasRuleStates(orgID int64, alertRuleUID string) bool {
+	c.mtxStates.RLock()
+	defer c.mtxStates.RUnlock()
+	_, ok := c.states[orgID][alertRuleUID]
+	return ok
+}
+
 func (c *cache) getStatesForRuleUID(orgID int64, alertRuleUID string, skipNormalState bool) []*State {
 	c.mtxStates.RLock()
 	defer c.mtxStates.RUnlock()
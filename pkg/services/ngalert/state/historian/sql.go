@@ -0,0 +1,384 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+const (
+	stateHistoryTable      = "alert_state_history"
+	stateHistoryLabelTable = "alert_state_history_label"
+	// maxLabelLength is the size of the columns that store label names and values.
+	// Longer names and values are truncated, both when they are stored and when they are queried.
+	maxLabelLength = 190
+	// sqlCleanupInterval is how often transitions older than the retention are deleted.
+	sqlCleanupInterval = 10 * time.Minute
+)
+
+// stateHistoryEntry is a row of the alert_state_history table.
+type stateHistoryEntry struct {
+	ID            int64  `xorm:"pk autoincr 'id'"`
+	OrgID         int64  `xorm:"org_id"`
+	RuleUID       string `xorm:"rule_uid"`
+	RuleID        int64  `xorm:"rule_id"`
+	RuleTitle     string `xorm:"rule_title"`
+	RuleGroup     string `xorm:"rule_group"`
+	FolderUID     string `xorm:"folder_uid"`
+	DashboardUID  string `xorm:"dashboard_uid"`
+	PanelID       int64  `xorm:"panel_id"`
+	Condition     string `xorm:"rule_condition"`
+	Fingerprint   string `xorm:"fingerprint"`
+	PreviousState string `xorm:"previous_state"`
+	CurrentState  string `xorm:"current_state"`
+	Error         string `xorm:"error"`
+	Labels        string `xorm:"labels"`
+	StateValues   string `xorm:"state_values"`
+	TimeNs        int64  `xorm:"time_ns"`
+}
+
+func (stateHistoryEntry) TableName() string {
+	return stateHistoryTable
+}
+
+// stateHistoryLabel is a row of the alert_state_history_label table.
+type stateHistoryLabel struct {
+	ID         int64  `xorm:"pk autoincr 'id'"`
+	EntryID    int64  `xorm:"entry_id"`
+	OrgID      int64  `xorm:"org_id"`
+	LabelName  string `xorm:"label_name"`
+	LabelValue string `xorm:"label_value"`
+	TimeNs     int64  `xorm:"time_ns"`
+}
+
+func (stateHistoryLabel) TableName() string {
+	return stateHistoryLabelTable
+}
+
+// sqlRecord is a state transition to be written along with the labels of its alert instance.
+type sqlRecord struct {
+	entry  stateHistoryEntry
+	labels data.Labels
+}
+
+// SQLBackend is a state.Historian that records state history to dedicated tables of the Grafana database.
+type SQLBackend struct {
+	db        db.DB
+	maxAge    time.Duration
+	clock     clock.Clock
+	metrics   *metrics.Historian
+	log       log.Logger
+	ac        AccessControl
+	ruleStore RuleStore
+}
+
+// NewSQLBackend creates a SQLBackend. Transitions older than maxAge are deleted by Run. If maxAge is 0 they are kept forever.
+func NewSQLBackend(logger log.Logger, store db.DB, maxAge time.Duration, metrics *metrics.Historian, ruleStore RuleStore, ac AccessControl) *SQLBackend {
+	return &SQLBackend{
+		db:        store,
+		maxAge:    maxAge,
+		clock:     clock.New(),
+		metrics:   metrics,
+		log:       logger,
+		ac:        ac,
+		ruleStore: ruleStore,
+	}
+}
+
+// Record writes a number of state transitions for a given rule to the database.
+func (h *SQLBackend) Record(ctx context.Context, rule history_model.RuleMeta, states []state.StateTransition) <-chan error {
+	logger := h.log.FromContext(ctx)
+	records := statesToSQLRecords(rule, states, logger)
+
+	errCh := make(chan error, 1)
+	if len(records) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	// This is a new background job, so let's create a brand new context for it, like the other backends do.
+	writeCtx, cancel := context.WithTimeout(context.Background(), StateHistoryWriteTimeout)
+	writeCtx = history_model.WithRuleData(writeCtx, rule)
+
+	go func(ctx context.Context) {
+		defer cancel()
+		defer close(errCh)
+		logger := h.log.FromContext(ctx)
+		logger.Debug("Saving state history batch", "samples", len(records))
+		org := fmt.Sprint(rule.OrgID)
+		h.metrics.WritesTotal.WithLabelValues(org, "sql").Inc()
+		h.metrics.TransitionsTotal.WithLabelValues(org).Add(float64(len(records)))
+
+		if err := h.insert(ctx, records); err != nil {
+			logger.Error("Failed to save alert state history batch", "error", err)
+			h.metrics.WritesFailed.WithLabelValues(org, "sql").Inc()
+			h.metrics.TransitionsFailed.WithLabelValues(org).Add(float64(len(records)))
+			errCh <- fmt.Errorf("failed to save alert state history batch: %w", err)
+			return
+		}
+		logger.Debug("Done saving alert state history batch", "samples", len(records))
+	}(writeCtx)
+	return errCh
+}
+
+func (h *SQLBackend) insert(ctx context.Context, records []sqlRecord) error {
+	return h.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		labels := make([]stateHistoryLabel, 0, len(records))
+		for i := range records {
+			entry := &records[i].entry
+			if _, err := sess.Insert(entry); err != nil {
+				return fmt.Errorf("failed to insert state transition: %w", err)
+			}
+			for name, value := range records[i].labels {
+				labels = append(labels, stateHistoryLabel{
+					EntryID:    entry.ID,
+					OrgID:      entry.OrgID,
+					LabelName:  truncateLabel(name),
+					LabelValue: truncateLabel(value),
+					TimeNs:     entry.TimeNs,
+				})
+			}
+		}
+		if len(labels) == 0 {
+			return nil
+		}
+		if _, err := sess.BulkInsert(stateHistoryLabelTable, labels, sqlstore.NativeSettingsForDialect(h.db.GetDialect())); err != nil {
+			return fmt.Errorf("failed to insert labels of state transitions: %w", err)
+		}
+		return nil
+	})
+}
+
+// Query retrieves state history entries from the database and formats the results into a dataframe
+// that has the same shape as the one of the Loki backend.
+func (h *SQLBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	uids, err := folderUIDsForFilter(ctx, h.ac, h.ruleStore, query)
+	if err != nil {
+		return nil, err
+	}
+
+	now := h.clock.Now().UTC()
+	if query.To.IsZero() {
+		query.To = now
+	}
+	if query.From.IsZero() {
+		query.From = now.Add(-defaultQueryRange)
+	}
+
+	var entries []stateHistoryEntry
+	err = h.db.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table(stateHistoryTable).
+			Where("org_id = ?", query.OrgID).
+			And("time_ns >= ?", query.From.UnixNano()).
+			And("time_ns <= ?", query.To.UnixNano())
+		if query.RuleUID != "" {
+			q = q.And("rule_uid = ?", query.RuleUID)
+		}
+		if query.DashboardUID != "" {
+			q = q.And("dashboard_uid = ?", query.DashboardUID)
+		}
+		if query.PanelID != 0 {
+			q = q.And("panel_id = ?", query.PanelID)
+		}
+		if len(uids) > 0 {
+			args := make([]any, 0, len(uids))
+			for _, uid := range uids {
+				args = append(args, uid)
+			}
+			q = q.In("folder_uid", args...)
+		}
+
+		// Ensure that all queries we build are deterministic.
+		names := make([]string, 0, len(query.Labels))
+		for name := range query.Labels {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			q = q.And(
+				"EXISTS (SELECT 1 FROM "+stateHistoryLabelTable+" WHERE "+stateHistoryLabelTable+".entry_id = "+stateHistoryTable+".id AND "+stateHistoryLabelTable+".label_name = ? AND "+stateHistoryLabelTable+".label_value = ?)",
+				truncateLabel(name), truncateLabel(query.Labels[name]),
+			)
+		}
+
+		// Take the most recent transitions if there are more than the limit.
+		q = q.Desc("time_ns", "id")
+		if query.Limit > 0 {
+			q = q.Limit(query.Limit)
+		}
+		return q.Find(&entries)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query state history: %w", err)
+	}
+	return entriesToFrame(entries)
+}
+
+// Run periodically deletes the transitions older than the retention until the context is cancelled.
+func (h *SQLBackend) Run(ctx context.Context) error {
+	if h.maxAge <= 0 {
+		return nil
+	}
+	ticker := h.clock.Ticker(sqlCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			deleted, err := h.DeleteExpired(ctx)
+			if err != nil {
+				h.log.Error("Failed to delete expired state history", "error", err)
+				continue
+			}
+			h.log.Debug("Deleted expired state history", "transitions", deleted)
+		}
+	}
+}
+
+// DeleteExpired deletes the transitions older than the retention and returns how many were deleted.
+func (h *SQLBackend) DeleteExpired(ctx context.Context) (int64, error) {
+	if h.maxAge <= 0 {
+		return 0, nil
+	}
+	cutoff := h.clock.Now().Add(-h.maxAge).UnixNano()
+	var deleted int64
+	err := h.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("DELETE FROM "+stateHistoryLabelTable+" WHERE time_ns < ?", cutoff); err != nil {
+			return err
+		}
+		res, err := sess.Exec("DELETE FROM "+stateHistoryTable+" WHERE time_ns < ?", cutoff)
+		if err != nil {
+			return err
+		}
+		deleted, err = res.RowsAffected()
+		return err
+	})
+	return deleted, err
+}
+
+func statesToSQLRecords(rule history_model.RuleMeta, states []state.StateTransition, logger log.Logger) []sqlRecord {
+	records := make([]sqlRecord, 0, len(states))
+	for _, state := range states {
+		if !shouldRecord(state) {
+			continue
+		}
+
+		sanitizedLabels := removePrivateLabels(state.Labels)
+		labels, err := json.Marshal(sanitizedLabels)
+		if err != nil {
+			logger.Error("Failed to encode labels of state, skipping", "error", err)
+			continue
+		}
+		values, err := valuesAsDataBlob(state.State).Encode()
+		if err != nil {
+			logger.Error("Failed to encode values of state, skipping", "error", err)
+			continue
+		}
+		entry := stateHistoryEntry{
+			OrgID:         rule.OrgID,
+			RuleUID:       rule.UID,
+			RuleID:        rule.ID,
+			RuleTitle:     rule.Title,
+			RuleGroup:     rule.Group,
+			FolderUID:     rule.NamespaceUID,
+			DashboardUID:  rule.DashboardUID,
+			PanelID:       rule.PanelID,
+			Condition:     rule.Condition,
+			Fingerprint:   labelFingerprint(sanitizedLabels),
+			PreviousState: state.PreviousFormatted(),
+			CurrentState:  state.Formatted(),
+			Labels:        string(labels),
+			StateValues:   string(values),
+			TimeNs:        state.State.LastEvaluationTime.UnixNano(),
+		}
+		if state.State.State == eval.Error && state.Error != nil {
+			entry.Error = state.Error.Error()
+		}
+		records = append(records, sqlRecord{entry: entry, labels: sanitizedLabels})
+	}
+	return records
+}
+
+// entriesToFrame converts the entries, sorted from the most recent, to a frame sorted by time.
+// Each row holds the transition in the same format as the Loki backend and the labels of its rule.
+func entriesToFrame(entries []stateHistoryEntry) (*data.Frame, error) {
+	lbls := data.Labels(map[string]string{})
+	times := make([]time.Time, 0, len(entries))
+	lines := make([]json.RawMessage, 0, len(entries))
+	labels := make([]json.RawMessage, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		line := LokiEntry{
+			SchemaVersion:  1,
+			Previous:       e.PreviousState,
+			Current:        e.CurrentState,
+			Error:          e.Error,
+			Values:         simplejson.New(),
+			Condition:      e.Condition,
+			DashboardUID:   e.DashboardUID,
+			PanelID:        e.PanelID,
+			Fingerprint:    e.Fingerprint,
+			RuleTitle:      e.RuleTitle,
+			RuleID:         e.RuleID,
+			RuleUID:        e.RuleUID,
+			InstanceLabels: map[string]string{},
+		}
+		if e.StateValues != "" {
+			values, err := simplejson.NewJson([]byte(e.StateValues))
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode values of state transition %d: %w", e.ID, err)
+			}
+			line.Values = values
+		}
+		if err := json.Unmarshal([]byte(e.Labels), &line.InstanceLabels); err != nil {
+			return nil, fmt.Errorf("failed to decode labels of state transition %d: %w", e.ID, err)
+		}
+		lineJSON, err := json.Marshal(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize state transition %d: %w", e.ID, err)
+		}
+		lblsJSON, err := json.Marshal(map[string]string{
+			StateHistoryLabelKey: StateHistoryLabelValue,
+			OrgIDLabel:           fmt.Sprint(e.OrgID),
+			GroupLabel:           e.RuleGroup,
+			FolderUIDLabel:       e.FolderUID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize labels of state transition %d: %w", e.ID, err)
+		}
+		times = append(times, time.Unix(0, e.TimeNs))
+		lines = append(lines, lineJSON)
+		labels = append(labels, lblsJSON)
+	}
+
+	frame := data.NewFrame("states")
+	frame.Fields = append(frame.Fields, data.NewField(dfTime, lbls, times))
+	frame.Fields = append(frame.Fields, data.NewField(dfLine, lbls, lines))
+	frame.Fields = append(frame.Fields, data.NewField(dfLabels, lbls, labels))
+	return frame, nil
+}
+
+// truncateLabel truncates s to the size of the label columns.
+func truncateLabel(s string) string {
+	if utf8.RuneCountInString(s) <= maxLabelLength {
+		return s
+	}
+	return string([]rune(s)[:maxLabelLength])
+}
@@ -0,0 +1,216 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/folder"
+	acfakes "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol/fakes"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationSQLBackend(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	usr := accesscontrol.BackgroundUser("test", 1, org.RoleNone, nil)
+	ac := &acfakes.FakeRuleService{
+		CanReadAllRulesFunc: func(ctx context.Context, user identity.Requester) (bool, error) {
+			return true, nil
+		},
+	}
+	backend, mockClock := createTestSQLBackend(t, fakes.NewRuleStore(t), ac)
+	mockClock.Set(now)
+
+	rule := createTestRule()
+	otherRule := history_model.RuleMeta{
+		OrgID:        1,
+		ID:           456,
+		UID:          "other-rule-uid",
+		Group:        "other-group",
+		NamespaceUID: "other-folder",
+		Title:        "other-title",
+	}
+	transition := func(s eval.State, at time.Time, labels data.Labels) state.StateTransition {
+		return state.StateTransition{
+			PreviousState: eval.Normal,
+			State: &state.State{
+				State:              s,
+				Labels:             labels,
+				LastEvaluationTime: at,
+				Values:             map[string]float64{"A": 1},
+			},
+		}
+	}
+	require.NoError(t, <-backend.Record(context.Background(), rule, []state.StateTransition{
+		transition(eval.Alerting, now.Add(-3*time.Minute), data.Labels{"host": "a", "__private__": "x"}),
+		transition(eval.Alerting, now.Add(-2*time.Minute), data.Labels{"host": "b"}),
+	}))
+	require.NoError(t, <-backend.Record(context.Background(), otherRule, []state.StateTransition{
+		transition(eval.Pending, now.Add(-time.Minute), data.Labels{"host": "a", "env": "prod"}),
+	}))
+	// Transitions that do not change the state are not recorded.
+	require.NoError(t, <-backend.Record(context.Background(), rule, []state.StateTransition{
+		{PreviousState: eval.Normal, State: &state.State{State: eval.Normal, LastEvaluationTime: now}},
+	}))
+
+	query := func(t *testing.T, q models.HistoryQuery) []LokiEntry {
+		t.Helper()
+		q.OrgID = 1
+		q.SignedInUser = usr
+		frame, err := backend.Query(context.Background(), q)
+		require.NoError(t, err)
+		require.Len(t, frame.Fields, 3)
+		entries := make([]LokiEntry, 0, frame.Rows())
+		for i := 0; i < frame.Rows(); i++ {
+			var entry LokiEntry
+			require.NoError(t, json.Unmarshal(frame.Fields[1].At(i).(json.RawMessage), &entry))
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+	instances := func(entries []LokiEntry) []string {
+		result := make([]string, 0, len(entries))
+		for _, e := range entries {
+			result = append(result, e.RuleUID+"/"+e.InstanceLabels["host"])
+		}
+		return result
+	}
+
+	t.Run("returns all transitions sorted by time", func(t *testing.T) {
+		entries := query(t, models.HistoryQuery{})
+		require.Equal(t, []string{"rule-uid/a", "rule-uid/b", "other-rule-uid/a"}, instances(entries))
+
+		first := entries[0]
+		require.Equal(t, "Normal", first.Previous)
+		require.Equal(t, "Alerting", first.Current)
+		require.Equal(t, rule.Title, first.RuleTitle)
+		require.Equal(t, rule.ID, first.RuleID)
+		require.Equal(t, rule.DashboardUID, first.DashboardUID)
+		require.Equal(t, rule.PanelID, first.PanelID)
+		require.Equal(t, map[string]string{"host": "a"}, first.InstanceLabels)
+		require.Equal(t, labelFingerprint(data.Labels{"host": "a"}), first.Fingerprint)
+		require.Equal(t, 1.0, first.Values.Get("A").MustFloat64())
+	})
+
+	t.Run("filters by rule", func(t *testing.T) {
+		entries := query(t, models.HistoryQuery{RuleUID: otherRule.UID})
+		require.Equal(t, []string{"other-rule-uid/a"}, instances(entries))
+	})
+
+	t.Run("filters by dashboard and panel", func(t *testing.T) {
+		entries := query(t, models.HistoryQuery{DashboardUID: rule.DashboardUID, PanelID: rule.PanelID})
+		require.Equal(t, []string{"rule-uid/a", "rule-uid/b"}, instances(entries))
+		entries = query(t, models.HistoryQuery{DashboardUID: rule.DashboardUID, PanelID: 1})
+		require.Empty(t, entries)
+	})
+
+	t.Run("filters by labels", func(t *testing.T) {
+		entries := query(t, models.HistoryQuery{Labels: map[string]string{"host": "a"}})
+		require.Equal(t, []string{"rule-uid/a", "other-rule-uid/a"}, instances(entries))
+		entries = query(t, models.HistoryQuery{Labels: map[string]string{"host": "a", "env": "prod"}})
+		require.Equal(t, []string{"other-rule-uid/a"}, instances(entries))
+		entries = query(t, models.HistoryQuery{Labels: map[string]string{"__private__": "x"}})
+		require.Empty(t, entries)
+	})
+
+	t.Run("filters by time range", func(t *testing.T) {
+		entries := query(t, models.HistoryQuery{From: now.Add(-150 * time.Second), To: now.Add(-90 * time.Second)})
+		require.Equal(t, []string{"rule-uid/b"}, instances(entries))
+		entries = query(t, models.HistoryQuery{To: now.Add(-7 * time.Hour)})
+		require.Empty(t, entries)
+	})
+
+	t.Run("keeps the most recent transitions when limited", func(t *testing.T) {
+		entries := query(t, models.HistoryQuery{Limit: 2})
+		require.Equal(t, []string{"rule-uid/b", "other-rule-uid/a"}, instances(entries))
+	})
+
+	t.Run("returns only transitions of folders the user can read", func(t *testing.T) {
+		rules := fakes.NewRuleStore(t)
+		rules.Folders = map[int64][]*folder.Folder{1: {{UID: rule.NamespaceUID, OrgID: 1}, {UID: otherRule.NamespaceUID, OrgID: 1}}}
+		rules.Rules = map[int64][]*models.AlertRule{1: {}}
+		backend.ruleStore = rules
+		backend.ac = &acfakes.FakeRuleService{
+			HasAccessInFolderFunc: func(ctx context.Context, user identity.Requester, namespaced models.Namespaced) (bool, error) {
+				return namespaced.GetNamespaceUID() == otherRule.NamespaceUID, nil
+			},
+		}
+		t.Cleanup(func() { backend.ac = ac })
+
+		entries := query(t, models.HistoryQuery{})
+		require.Equal(t, []string{"other-rule-uid/a"}, instances(entries))
+
+		backend.ac = &acfakes.FakeRuleService{
+			AuthorizeAccessInFolderFunc: func(ctx context.Context, user identity.Requester, namespaced models.Namespaced) error {
+				return errors.New("forbidden")
+			},
+		}
+		stored := models.RuleGen.With(models.RuleMuts.WithOrgID(1)).GenerateRef()
+		stored.UID = rule.UID
+		rules.Rules = map[int64][]*models.AlertRule{1: {stored}}
+		_, err := backend.Query(context.Background(), models.HistoryQuery{OrgID: 1, RuleUID: rule.UID, SignedInUser: usr})
+		require.Error(t, err)
+	})
+
+	t.Run("deletes transitions older than the retention", func(t *testing.T) {
+		mockClock.Set(now.Add(backend.maxAge).Add(-150 * time.Second))
+		deleted, err := backend.DeleteExpired(context.Background())
+		require.NoError(t, err)
+		require.EqualValues(t, 1, deleted)
+
+		entries := query(t, models.HistoryQuery{From: now.Add(-time.Hour), To: now})
+		require.Equal(t, []string{"rule-uid/b", "other-rule-uid/a"}, instances(entries))
+		var labels int64
+		err = backend.db.WithDbSession(context.Background(), func(sess *db.Session) error {
+			labels, err = sess.Table(stateHistoryLabelTable).Count()
+			return err
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 3, labels)
+	})
+}
+
+func TestTruncateLabel(t *testing.T) {
+	long := make([]rune, maxLabelLength+10)
+	for i := range long {
+		long[i] = 'ü'
+	}
+	require.Equal(t, "value", truncateLabel("value"))
+	require.Equal(t, string(long[:maxLabelLength]), truncateLabel(string(long)))
+}
+
+func createTestSQLBackend(t *testing.T, rules RuleStore, ac AccessControl) (*SQLBackend, *clock.Mock) {
+	t.Helper()
+	sqlStore := db.InitTestDB(t)
+	met := metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem)
+	backend := NewSQLBackend(log.NewNopLogger(), sqlStore, 24*time.Hour, met, rules, ac)
+	mockClock := clock.NewMock()
+	backend.clock = mockClock
+	return backend, mockClock
+}
@@ -2,6 +2,8 @@ package state
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/url"
 	"strconv"
 	"strings"
@@ -24,6 +26,9 @@ var (
 	ResendDelay = 30 * time.Second
 )
 
+// ErrSeriesLimitExceeded is the error of the rules whose evaluation results are discarded because they exceed the limit of series.
+var ErrSeriesLimitExceeded = errors.New("series limit exceeded")
+
 // AlertInstanceManager defines the interface for querying the current alert instances.
 type AlertInstanceManager interface {
 	GetAll(orgID int64) []*State
@@ -56,6 +61,8 @@ type Manager struct {
 	doNotSaveNormalState           bool
 	applyNoDataAndErrorToAllStates bool
 	rulesPerRuleGroupLimit         int64
+	seriesPerRuleLimit             int64
+	seriesPerOrgLimit              int64
 
 	persister StatePersister
 }
@@ -75,6 +82,12 @@ type ManagerCfg struct {
 	// to all states when corresponding execution in the rule definition is set to either `Alerting` or `OK`
 	ApplyNoDataAndErrorToAllStates bool
 	RulesPerRuleGroupLimit         int64
+	// SeriesPerRuleLimit and SeriesPerOrgLimit limit the number of series (states) of a rule and of all rules of an organization.
+	// If an evaluation exceeds a limit, its results are replaced with an error. Zero or negative values disable the limit.
+	// The series are counted in the cache of this instance, so with sharded scheduling the limit per organization
+	// applies to the rules that each instance evaluates, and not to the whole cluster.
+	SeriesPerRuleLimit int64
+	SeriesPerOrgLimit  int64
 
 	DisableExecution bool
 
@@ -107,6 +120,8 @@ func NewManager(cfg ManagerCfg, statePersister StatePersister) *Manager {
 		doNotSaveNormalState:           cfg.DoNotSaveNormalState,
 		applyNoDataAndErrorToAllStates: cfg.ApplyNoDataAndErrorToAllStates,
 		rulesPerRuleGroupLimit:         cfg.RulesPerRuleGroupLimit,
+		seriesPerRuleLimit:             cfg.SeriesPerRuleLimit,
+		seriesPerOrgLimit:              cfg.SeriesPerOrgLimit,
 		persister:                      statePersister,
 		tracer:                         cfg.Tracer,
 	}
@@ -199,32 +214,8 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 				orgStates[entry.RuleUID] = rulesStates
 			}
 
-			lbs := map[string]string(entry.Labels)
-			cacheID := entry.Labels.Fingerprint()
-			var resultFp data.Fingerprint
-			if entry.ResultFingerprint != "" {
-				fp, err := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
-				if err != nil {
-					st.log.Error("Failed to parse result fingerprint of alert instance", "error", err, "ruleUID", entry.RuleUID)
-				}
-				resultFp = data.Fingerprint(fp)
-			}
-			rulesStates.states[cacheID] = &State{
-				AlertRuleUID:         entry.RuleUID,
-				OrgID:                entry.RuleOrgID,
-				CacheID:              cacheID,
-				Labels:               lbs,
-				State:                translateInstanceState(entry.CurrentState),
-				StateReason:          entry.CurrentReason,
-				LastEvaluationString: "",
-				StartsAt:             entry.CurrentStateSince,
-				EndsAt:               entry.CurrentStateEnd,
-				LastEvaluationTime:   entry.LastEvalTime,
-				Annotations:          annotations,
-				ResultFingerprint:    resultFp,
-				ResolvedAt:           entry.ResolvedAt,
-				LastSentAt:           entry.LastSentAt,
-			}
+			s := st.stateFromInstance(entry, annotations)
+			rulesStates.states[s.CacheID] = s
 			statesCount++
 		}
 	}
@@ -233,6 +224,69 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 	st.log.Info("State cache has been initialized", "states", statesCount, "duration", time.Since(startTime))
 }
 
+// LoadStateByRuleUID replaces the states of the rule in the cache with the states saved in the instance store.
+// It is used when the rule is taken over from another instance that evaluated it before.
+func (st *Manager) LoadStateByRuleUID(ctx context.Context, rule *ngModels.AlertRule) {
+	if st.instanceStore == nil {
+		return
+	}
+	logger := st.log.FromContext(ctx).New(rule.GetKey().LogContext()...)
+	alertInstances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{
+		RuleOrgID: rule.OrgID,
+		RuleUID:   rule.UID,
+	})
+	if err != nil {
+		logger.Error("Unable to fetch previous state of the rule", "error", err)
+		return
+	}
+
+	// nil safety.
+	annotations := rule.Annotations
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	rs := &ruleStates{states: make(map[data.Fingerprint]*State, len(alertInstances))}
+	for _, entry := range alertInstances {
+		s := st.stateFromInstance(entry, annotations)
+		rs.states[s.CacheID] = s
+	}
+	st.cache.setRuleStates(rule.OrgID, rule.UID, rs)
+	logger.Debug("State of the rule has been loaded", "states", len(rs.states))
+}
+
+// ForgetStateByRuleUID removes the states of the rule from the cache without changing them in the instance store.
+// It is used when the rule is handed off to another instance that continues from the saved states.
+func (st *Manager) ForgetStateByRuleUID(ruleKey ngModels.AlertRuleKey) {
+	st.cache.removeByRuleUID(ruleKey.OrgID, ruleKey.UID)
+}
+
+func (st *Manager) stateFromInstance(entry *ngModels.AlertInstance, annotations map[string]string) *State {
+	var resultFp data.Fingerprint
+	if entry.ResultFingerprint != "" {
+		fp, err := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
+		if err != nil {
+			st.log.Error("Failed to parse result fingerprint of alert instance", "error", err, "ruleUID", entry.RuleUID)
+		}
+		resultFp = data.Fingerprint(fp)
+	}
+	return &State{
+		AlertRuleUID:         entry.RuleUID,
+		OrgID:                entry.RuleOrgID,
+		CacheID:              entry.Labels.Fingerprint(),
+		Labels:               map[string]string(entry.Labels),
+		State:                translateInstanceState(entry.CurrentState),
+		StateReason:          entry.CurrentReason,
+		LastEvaluationString: "",
+		StartsAt:             entry.CurrentStateSince,
+		EndsAt:               entry.CurrentStateEnd,
+		LastEvaluationTime:   entry.LastEvalTime,
+		Annotations:          annotations,
+		ResultFingerprint:    resultFp,
+		ResolvedAt:           entry.ResolvedAt,
+		LastSentAt:           entry.LastSentAt,
+	}
+}
+
 func (st *Manager) Get(orgID int64, alertRuleUID string, stateId data.Fingerprint) *State {
 	return st.cache.get(orgID, alertRuleUID, stateId)
 }
@@ -330,6 +384,7 @@ func (st *Manager) ProcessEvalResults(
 
 	logger := st.log.FromContext(ctx)
 	logger.Debug("State manager processing evaluation results", "resultCount", len(results))
+	results = st.applySeriesLimits(logger, alertRule, results, evaluatedAt)
 	states := st.setNextStateForRule(ctx, alertRule, results, extraLabels, logger)
 
 	staleStates := st.deleteStaleStatesFromCache(ctx, logger, evaluatedAt, alertRule)
@@ -361,6 +416,42 @@ func (st *Manager) ProcessEvalResults(
 	return allChanges
 }
 
+// applySeriesLimits replaces the results with a single error result if they exceed the limit of series per rule or per organization.
+// The error is reported in the health of the rule, and the states of the discarded series are resolved as stale.
+// The series of the organization are counted in the cache, which contains only the rules evaluated by this instance.
+// Counting them in the instance store for every evaluation would be too expensive, so the limit applies per instance.
+func (st *Manager) applySeriesLimits(logger log.Logger, alertRule *ngModels.AlertRule, results eval.Results, evaluatedAt time.Time) eval.Results {
+	if st.seriesPerRuleLimit <= 0 && st.seriesPerOrgLimit <= 0 {
+		return results
+	}
+	series := int64(len(results))
+	var err error
+	var limit string
+	if st.seriesPerRuleLimit > 0 && series > st.seriesPerRuleLimit {
+		err = fmt.Errorf("%w: the rule produced %d series but the limit per rule is %d", ErrSeriesLimitExceeded, series, st.seriesPerRuleLimit)
+		limit = "rule"
+	} else if st.seriesPerOrgLimit > 0 {
+		ruleSeries, orgSeries := st.cache.countStates(alertRule.OrgID, alertRule.UID)
+		if total := orgSeries - ruleSeries + series; total > st.seriesPerOrgLimit {
+			err = fmt.Errorf("%w: the rule produced %d series and the organization would have %d series but the limit per organization is %d", ErrSeriesLimitExceeded, series, total, st.seriesPerOrgLimit)
+			limit = "org"
+		}
+	}
+	if err == nil {
+		return results
+	}
+
+	logger.Warn("Discarding evaluation results because they exceed the limit of series", "series", series, "limit", limit, "error", err)
+	if st.metrics != nil {
+		st.metrics.SeriesLimitExceeded.WithLabelValues(strconv.FormatInt(alertRule.OrgID, 10), limit).Inc()
+	}
+	var duration time.Duration
+	if len(results) > 0 {
+		duration = results[0].EvaluationDuration
+	}
+	return eval.Results{eval.NewResultFromError(err, evaluatedAt, duration)}
+}
+
 // updateLastSentAt returns the subset StateTransitions that need sending and updates their LastSentAt field.
 // Note: This is not idempotent, running this twice can (and usually will) return different results.
 func (st *Manager) updateLastSentAt(states StateTransitions, evaluatedAt time.Time) StateTransitions {
@@ -417,10 +508,11 @@ func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.
 			return transitions // if there are no current states for the rule. Create ones for each result
 		}
 	}
+	sup := st.newSuppression(ctx, alertRule, logger)
 	transitions := make([]StateTransition, 0, len(results))
 	for _, result := range results {
 		currentState := st.cache.getOrCreate(ctx, logger, alertRule, result, extraLabels, st.externalURL)
-		s := st.setNextState(ctx, alertRule, currentState, result, logger)
+		s := st.setNextState(ctx, alertRule, currentState, result, sup, logger)
 		transitions = append(transitions, s)
 	}
 	return transitions
@@ -428,16 +520,17 @@ func (st *Manager) setNextStateForRule(ctx context.Context, alertRule *ngModels.
 
 func (st *Manager) setNextStateForAll(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result, logger log.Logger) []StateTransition {
 	currentStates := st.cache.getStatesForRuleUID(alertRule.OrgID, alertRule.UID, false)
+	sup := st.newSuppression(ctx, alertRule, logger)
 	transitions := make([]StateTransition, 0, len(currentStates))
 	for _, currentState := range currentStates {
-		t := st.setNextState(ctx, alertRule, currentState, result, logger)
+		t := st.setNextState(ctx, alertRule, currentState, result, sup, logger)
 		transitions = append(transitions, t)
 	}
 	return transitions
 }
 
-// Set the current state based on evaluation results
-func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, currentState *State, result eval.Result, logger log.Logger) StateTransition {
+// Set the current state based on evaluation results. Alerting results of states suppressed by sup set the state to Normal.
+func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, currentState *State, result eval.Result, sup *suppression, logger log.Logger) StateTransition {
 	start := st.clock.Now()
 
 	currentState.LastEvaluationTime = result.EvaluatedAt
@@ -477,13 +570,20 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 		}
 	}
 
+	suppressed := result.State == eval.Alerting && sup.suppresses(currentState.Labels)
+
 	switch result.State {
 	case eval.Normal:
 		logger.Debug("Setting next state", "handler", "resultNormal")
 		resultNormal(currentState, alertRule, result, logger, "")
 	case eval.Alerting:
-		logger.Debug("Setting next state", "handler", "resultAlerting")
-		resultAlerting(currentState, alertRule, result, logger, "")
+		if suppressed {
+			logger.Debug("Setting next state", "handler", "resultSuppressed")
+			resultNormal(currentState, alertRule, result, logger, ngModels.StateReasonSuppressed)
+		} else {
+			logger.Debug("Setting next state", "handler", "resultAlerting")
+			resultAlerting(currentState, alertRule, result, logger, "")
+		}
 	case eval.Error:
 		logger.Debug("Setting next state", "handler", "resultError")
 		resultError(currentState, alertRule, result, logger)
@@ -502,6 +602,9 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 		result.State != eval.Alerting {
 		currentState.StateReason = resultStateReason(result, alertRule)
 	}
+	if suppressed {
+		currentState.StateReason = ngModels.StateReasonSuppressed
+	}
 
 	// Set Resolved property so the scheduler knows to send a postable alert
 	// to Alertmanager.
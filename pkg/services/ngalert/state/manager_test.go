@@ -245,6 +245,61 @@ func TestWarmStateCache(t *testing.T) {
 	})
 }
 
+func TestLoadAndForgetStateByRuleUID(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, 1)
+
+	const mainOrgID int64 = 1
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, 600, mainOrgID)
+	otherRule := tests.CreateTestAlertRule(t, ctx, dbstore, 600, mainOrgID)
+
+	for _, r := range []*models.AlertRule{rule, otherRule} {
+		labels := models.InstanceLabels{"test": r.UID}
+		_, hash, _ := labels.StringAndHash()
+		require.NoError(t, dbstore.SaveAlertInstance(ctx, models.AlertInstance{
+			AlertInstanceKey: models.AlertInstanceKey{
+				RuleOrgID:  r.OrgID,
+				RuleUID:    r.UID,
+				LabelsHash: hash,
+			},
+			CurrentState: models.InstanceStateFiring,
+			Labels:       labels,
+		}))
+	}
+
+	cfg := state.ManagerCfg{
+		Metrics:       metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		ExternalURL:   nil,
+		InstanceStore: dbstore,
+		Images:        &state.NoopImageService{},
+		Clock:         clock.NewMock(),
+		Historian:     &state.FakeHistorian{},
+		Tracer:        tracing.InitializeTracerForTest(),
+		Log:           log.New("ngalert.state.manager"),
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+
+	t.Run("should load states of the rule from the database", func(t *testing.T) {
+		st.LoadStateByRuleUID(ctx, rule)
+
+		states := st.GetStatesForRuleUID(rule.OrgID, rule.UID)
+		require.Len(t, states, 1)
+		require.Equal(t, eval.Alerting, states[0].State)
+		require.Equal(t, data.Labels{"test": rule.UID}, states[0].Labels)
+		require.Equal(t, rule.Annotations, states[0].Annotations)
+		require.Empty(t, st.GetStatesForRuleUID(otherRule.OrgID, otherRule.UID))
+	})
+
+	t.Run("should forget states of the rule without deleting them from the database", func(t *testing.T) {
+		st.ForgetStateByRuleUID(rule.GetKey())
+
+		require.Empty(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID))
+		instances, err := dbstore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: rule.OrgID, RuleUID: rule.UID})
+		require.NoError(t, err)
+		require.Len(t, instances, 1)
+	})
+}
+
 func TestDashboardAnnotations(t *testing.T) {
 	evaluationTime, err := time.Parse("2006-01-02", "2022-01-01")
 	require.NoError(t, err)
@@ -1673,6 +1728,191 @@ func TestProcessEvalResults(t *testing.T) {
 	})
 }
 
+func TestProcessEvalResults_SuppressedBy(t *testing.T) {
+	clk := clock.NewMock()
+	historian := &state.FakeHistorian{}
+	cfg := state.ManagerCfg{
+		Metrics:                 metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore:           &state.FakeInstanceStore{},
+		Images:                  &state.NotAvailableImageService{},
+		Clock:                   clk,
+		Historian:               historian,
+		Tracer:                  tracing.InitializeTracerForTest(),
+		Log:                     log.New("ngalert.state.manager"),
+		MaxStateSaveConcurrency: 1,
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+
+	gen := models.RuleGen.With(models.RuleMuts.WithOrgID(1), models.RuleMuts.WithFor(0))
+	nodeDown := gen.GenerateRef()
+	serviceDown := gen.With(gen.WithSuppressedBy(models.SuppressingRule{RuleUID: nodeDown.UID, Equal: []string{"instance"}})).GenerateRef()
+
+	result := func(s eval.State, instance string) eval.Result {
+		return eval.Result{State: s, Instance: data.Labels{"instance": instance}, EvaluatedAt: clk.Now()}
+	}
+	statesByInstance := func(rule *models.AlertRule) map[string]*state.State {
+		result := make(map[string]*state.State)
+		for _, s := range st.GetStatesForRuleUID(rule.OrgID, rule.UID) {
+			result[s.Labels["instance"]] = s
+		}
+		return result
+	}
+
+	st.ProcessEvalResults(context.Background(), clk.Now(), nodeDown, eval.Results{result(eval.Alerting, "node-1")}, nil, nil)
+	st.ProcessEvalResults(context.Background(), clk.Now(), serviceDown, eval.Results{result(eval.Alerting, "node-1"), result(eval.Alerting, "node-2")}, nil, nil)
+
+	states := statesByInstance(serviceDown)
+	require.Equal(t, eval.Normal, states["node-1"].State)
+	require.Equal(t, models.StateReasonSuppressed, states["node-1"].StateReason)
+	require.Equal(t, eval.Alerting, states["node-2"].State)
+	require.Empty(t, states["node-2"].StateReason)
+
+	var recorded []string
+	for _, tr := range historian.StateTransitions {
+		if tr.AlertRuleUID == serviceDown.UID {
+			recorded = append(recorded, tr.Labels["instance"]+": "+tr.PreviousFormatted()+" => "+tr.Formatted())
+		}
+	}
+	require.ElementsMatch(t, []string{"node-1: Normal => Normal (Suppressed)", "node-2: Normal => Alerting"}, recorded)
+
+	t.Run("alerts are no longer suppressed when the suppressing rule stops firing", func(t *testing.T) {
+		clk.Add(time.Minute)
+		st.ProcessEvalResults(context.Background(), clk.Now(), nodeDown, eval.Results{result(eval.Normal, "node-1")}, nil, nil)
+		st.ProcessEvalResults(context.Background(), clk.Now(), serviceDown, eval.Results{result(eval.Alerting, "node-1"), result(eval.Alerting, "node-2")}, nil, nil)
+
+		states := statesByInstance(serviceDown)
+		require.Equal(t, eval.Alerting, states["node-1"].State)
+		require.Empty(t, states["node-1"].StateReason)
+		require.Equal(t, eval.Alerting, states["node-2"].State)
+	})
+}
+
+func TestProcessEvalResults_SuppressedByRuleOfAnotherInstance(t *testing.T) {
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, 1)
+
+	const mainOrgID int64 = 1
+	// the suppressing rule is evaluated by another instance, which saved its state to the database.
+	nodeDown := tests.CreateTestAlertRule(t, ctx, dbstore, 600, mainOrgID)
+	labels := models.InstanceLabels{"instance": "node-1"}
+	_, hash, _ := labels.StringAndHash()
+	require.NoError(t, dbstore.SaveAlertInstance(ctx, models.AlertInstance{
+		AlertInstanceKey: models.AlertInstanceKey{
+			RuleOrgID:  nodeDown.OrgID,
+			RuleUID:    nodeDown.UID,
+			LabelsHash: hash,
+		},
+		CurrentState: models.InstanceStateFiring,
+		Labels:       labels,
+	}))
+
+	clk := clock.NewMock()
+	cfg := state.ManagerCfg{
+		Metrics:                 metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+		InstanceStore:           dbstore,
+		Images:                  &state.NotAvailableImageService{},
+		Clock:                   clk,
+		Historian:               &state.FakeHistorian{},
+		Tracer:                  tracing.InitializeTracerForTest(),
+		Log:                     log.New("ngalert.state.manager"),
+		MaxStateSaveConcurrency: 1,
+	}
+	st := state.NewManager(cfg, state.NewNoopPersister())
+
+	serviceDown := models.RuleGen.With(
+		models.RuleMuts.WithOrgID(mainOrgID),
+		models.RuleMuts.WithFor(0),
+		models.RuleMuts.WithSuppressedBy(models.SuppressingRule{RuleUID: nodeDown.UID, Equal: []string{"instance"}}),
+	).GenerateRef()
+	st.ProcessEvalResults(ctx, clk.Now(), serviceDown, eval.Results{
+		{State: eval.Alerting, Instance: data.Labels{"instance": "node-1"}, EvaluatedAt: clk.Now()},
+		{State: eval.Alerting, Instance: data.Labels{"instance": "node-2"}, EvaluatedAt: clk.Now()},
+	}, nil, nil)
+
+	states := make(map[string]*state.State)
+	for _, s := range st.GetStatesForRuleUID(serviceDown.OrgID, serviceDown.UID) {
+		states[s.Labels["instance"]] = s
+	}
+	require.Equal(t, eval.Normal, states["node-1"].State)
+	require.Equal(t, models.StateReasonSuppressed, states["node-1"].StateReason)
+	require.Equal(t, eval.Alerting, states["node-2"].State)
+}
+
+func TestProcessEvalResults_SeriesLimits(t *testing.T) {
+	clk := clock.NewMock()
+	newManager := func(perRule, perOrg int64) *state.Manager {
+		cfg := state.ManagerCfg{
+			Metrics:                 metrics.NewNGAlert(prometheus.NewPedanticRegistry()).GetStateMetrics(),
+			InstanceStore:           &state.FakeInstanceStore{},
+			Images:                  &state.NotAvailableImageService{},
+			Clock:                   clk,
+			Historian:               &state.FakeHistorian{},
+			Tracer:                  tracing.InitializeTracerForTest(),
+			Log:                     log.New("ngalert.state.manager"),
+			MaxStateSaveConcurrency: 1,
+			SeriesPerRuleLimit:      perRule,
+			SeriesPerOrgLimit:       perOrg,
+		}
+		return state.NewManager(cfg, state.NewNoopPersister())
+	}
+	gen := models.RuleGen.With(models.RuleMuts.WithOrgID(1), models.RuleMuts.WithInterval(10*time.Second), models.RuleMuts.WithFor(0), models.RuleMuts.WithErrorExecAs(models.ErrorErrState))
+	results := func(n int) eval.Results {
+		r := make(eval.Results, 0, n)
+		for i := 0; i < n; i++ {
+			r = append(r, eval.Result{State: eval.Alerting, Instance: data.Labels{"instance": fmt.Sprintf("node-%d", i)}, EvaluatedAt: clk.Now()})
+		}
+		return r
+	}
+
+	t.Run("should track the series within the limits", func(t *testing.T) {
+		st := newManager(3, 5)
+		rule := gen.GenerateRef()
+		st.ProcessEvalResults(context.Background(), clk.Now(), rule, results(3), nil, nil)
+
+		require.Len(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID), 3)
+		require.Equal(t, "ok", st.GetStatusForRuleUID(rule.OrgID, rule.UID).Health)
+	})
+
+	t.Run("should discard the series of the rule that exceeds the limit per rule", func(t *testing.T) {
+		st := newManager(3, -1)
+		rule := gen.GenerateRef()
+		st.ProcessEvalResults(context.Background(), clk.Now(), rule, results(2), nil, nil)
+		require.Len(t, st.GetStatesForRuleUID(rule.OrgID, rule.UID), 2)
+
+		st.ProcessEvalResults(context.Background(), clk.Now().Add(time.Minute), rule, results(4), nil, nil)
+
+		states := st.GetStatesForRuleUID(rule.OrgID, rule.UID)
+		require.Len(t, states, 1)
+		require.Equal(t, eval.Error, states[0].State)
+		status := st.GetStatusForRuleUID(rule.OrgID, rule.UID)
+		require.Equal(t, "error", status.Health)
+		require.ErrorIs(t, status.LastError, state.ErrSeriesLimitExceeded)
+		require.ErrorContains(t, status.LastError, "the rule produced 4 series but the limit per rule is 3")
+	})
+
+	t.Run("should discard the series of the rule that makes the organization exceed the limit per organization", func(t *testing.T) {
+		st := newManager(-1, 5)
+		rule1 := gen.GenerateRef()
+		rule2 := gen.GenerateRef()
+		otherOrgRule := gen.With(models.RuleMuts.WithOrgID(2)).GenerateRef()
+		st.ProcessEvalResults(context.Background(), clk.Now(), rule1, results(3), nil, nil)
+		st.ProcessEvalResults(context.Background(), clk.Now(), otherOrgRule, results(5), nil, nil)
+		require.Len(t, st.GetStatesForRuleUID(otherOrgRule.OrgID, otherOrgRule.UID), 5)
+
+		st.ProcessEvalResults(context.Background(), clk.Now(), rule2, results(3), nil, nil)
+
+		status := st.GetStatusForRuleUID(rule2.OrgID, rule2.UID)
+		require.Equal(t, "error", status.Health)
+		require.ErrorContains(t, status.LastError, "the organization would have 6 series but the limit per organization is 5")
+		require.Len(t, st.GetStatesForRuleUID(rule1.OrgID, rule1.UID), 3)
+
+		t.Run("and the series of the rule that already has them are replaced", func(t *testing.T) {
+			st.ProcessEvalResults(context.Background(), clk.Now().Add(time.Minute), rule1, results(3), nil, nil)
+			require.Equal(t, "ok", st.GetStatusForRuleUID(rule1.OrgID, rule1.UID).Health)
+		})
+	})
+}
+
 func printAllAnnotations(annos map[int64]annotations.Item) string {
 	b := strings.Builder{}
 	b.WriteRune('[')
@@ -0,0 +1,85 @@
+package state
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// suppression holds the labels of the firing alerts of the rules that suppress the alerts of a rule.
+type suppression struct {
+	rules  []ngModels.SuppressingRule
+	firing [][]data.Labels
+}
+
+// newSuppression collects the firing alerts of the rules that suppress the alerts of the rule.
+// It returns nil if the rule is not suppressed by any rule. The suppressing rules can be evaluated at the same time as
+// the rule, in which case the alerts of the rule are compared to their latest evaluation that has been processed.
+// The firing alerts of a suppressing rule are read from the cache. If the cache does not contain the rule, for example,
+// because it is evaluated by another instance with sharded scheduling, they are read from the instance store, which
+// contains the latest evaluation that the other instance saved.
+func (st *Manager) newSuppression(ctx context.Context, alertRule *ngModels.AlertRule, logger log.Logger) *suppression {
+	if len(alertRule.SuppressedBy) == 0 {
+		return nil
+	}
+	s := &suppression{
+		rules:  alertRule.SuppressedBy,
+		firing: make([][]data.Labels, 0, len(alertRule.SuppressedBy)),
+	}
+	for _, sr := range alertRule.SuppressedBy {
+		var firing []data.Labels
+		if st.cache.hasRuleStates(alertRule.OrgID, sr.RuleUID) {
+			for _, state := range st.cache.getStatesForRuleUID(alertRule.OrgID, sr.RuleUID, false) {
+				if state.State == eval.Alerting {
+					firing = append(firing, state.Labels)
+				}
+			}
+		} else {
+			firing = st.storedFiringLabels(ctx, alertRule.OrgID, sr.RuleUID, logger)
+		}
+		s.firing = append(s.firing, firing)
+	}
+	return s
+}
+
+// storedFiringLabels returns the labels of the firing alerts of the rule that are saved in the instance store.
+// If they cannot be read, the rule does not suppress any alerts.
+func (st *Manager) storedFiringLabels(ctx context.Context, orgID int64, ruleUID string, logger log.Logger) []data.Labels {
+	if st.instanceStore == nil {
+		return nil
+	}
+	alertInstances, err := st.instanceStore.ListAlertInstances(ctx, &ngModels.ListAlertInstancesQuery{
+		RuleOrgID: orgID,
+		RuleUID:   ruleUID,
+	})
+	if err != nil {
+		logger.Error("Unable to fetch the state of the suppressing rule", "suppressingRuleUID", ruleUID, "error", err)
+		return nil
+	}
+	var firing []data.Labels
+	for _, entry := range alertInstances {
+		if entry.CurrentState == ngModels.InstanceStateFiring {
+			firing = append(firing, data.Labels(entry.Labels))
+		}
+	}
+	return firing
+}
+
+// suppresses returns true if any firing alert of the suppressing rules suppresses an alert with the labels.
+func (s *suppression) suppresses(labels data.Labels) bool {
+	if s == nil {
+		return false
+	}
+	for i, sr := range s.rules {
+		for _, firing := range s.firing[i] {
+			if sr.Suppresses(firing, labels) {
+				return true
+			}
+		}
+	}
+	return false
+}
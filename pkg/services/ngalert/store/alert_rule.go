@@ -117,6 +117,33 @@ func (st DBstore) GetAlertRuleByUID(ctx context.Context, query *ngmodels.GetAler
 	return result, err
 }
 
+// GetAlertRuleVersions returns the stored versions of the alert rule with the given key, newest first.
+// The number of versions is limited by the setting RuleVersionRecordLimit.
+// It returns ngmodels.ErrAlertRuleNotFound if there are no versions of the rule.
+func (st DBstore) GetAlertRuleVersions(ctx context.Context, key ngmodels.AlertRuleKey) (result []*ngmodels.AlertRule, err error) {
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		versions := make([]alertRuleVersion, 0)
+		err := sess.Table(alertRuleVersion{}).Where("rule_org_id = ? AND rule_uid = ?", key.OrgID, key.UID).Desc("id").Find(&versions)
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			return ngmodels.ErrAlertRuleNotFound
+		}
+		result = make([]*ngmodels.AlertRule, 0, len(versions))
+		for _, version := range versions {
+			converted, err := alertRuleVersionToModelsAlertRule(version, st.Logger)
+			if err != nil {
+				st.Logger.Error("Invalid rule version found in DB store, ignoring it", "func", "GetAlertRuleVersions", "rule_uid", version.RuleUID, "version", version.Version, "error", err)
+				continue
+			}
+			result = append(result, &converted)
+		}
+		return nil
+	})
+	return result, err
+}
+
 // GetRuleByID retrieves models.AlertRule by ID.
 // It returns models.ErrAlertRuleNotFound if no alert rule is found for the provided ID.
 func (st DBstore) GetRuleByID(ctx context.Context, query ngmodels.GetAlertRuleByIDQuery) (result *ngmodels.AlertRule, err error) {
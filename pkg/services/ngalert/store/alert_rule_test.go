@@ -1655,6 +1655,60 @@ func TestIntegration_AlertRuleVersionsCleanup(t *testing.T) {
 	})
 }
 
+func TestIntegration_GetAlertRuleVersions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting = setting.UnifiedAlertingSettings{
+		BaseInterval:           time.Duration(rand.Int63n(100)+1) * time.Second,
+		RuleVersionRecordLimit: 10,
+	}
+	sqlStore := db.InitTestDB(t)
+	folderService := setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures())
+	store := createTestStore(sqlStore, folderService, &logtest.Fake{}, cfg.UnifiedAlerting, &fakeBus{})
+	generator := models.RuleGen
+	generator = generator.With(generator.WithIntervalMatching(store.Cfg.BaseInterval), generator.WithUniqueOrgID())
+
+	t.Run("should return versions newest first with their author", func(t *testing.T) {
+		rule := generator.GenerateRef()
+		rule.UpdatedBy = util.Pointer(models.UserUID("creator"))
+		ids, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*rule})
+		require.NoError(t, err)
+		rule.ID = ids[0].ID
+		rule.UID = ids[0].UID
+		rule.Version = 1
+
+		updated := models.CopyRule(rule)
+		updated.Title = "updated"
+		updated.UpdatedBy = util.Pointer(models.UserUID("editor"))
+		updated.SuppressedBy = []models.SuppressingRule{{RuleUID: "node-down", Equal: []string{"instance"}}}
+		err = store.UpdateAlertRules(context.Background(), []models.UpdateRule{{Existing: rule, New: *updated}})
+		require.NoError(t, err)
+
+		versions, err := store.GetAlertRuleVersions(context.Background(), rule.GetKey())
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+
+		require.Equal(t, int64(2), versions[0].Version)
+		require.Equal(t, "updated", versions[0].Title)
+		require.Equal(t, models.UserUID("editor"), *versions[0].UpdatedBy)
+		require.Equal(t, int64(1), versions[1].Version)
+		require.Equal(t, rule.Title, versions[1].Title)
+		require.Equal(t, models.UserUID("creator"), *versions[1].UpdatedBy)
+		require.Empty(t, versions[0].Diff(updated, "ID", "Version", "Updated", "DashboardUID", "PanelID"))
+
+		current, err := store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: rule.OrgID, UID: rule.UID})
+		require.NoError(t, err)
+		require.Equal(t, models.UserUID("editor"), *current.UpdatedBy)
+	})
+
+	t.Run("should return ErrAlertRuleNotFound if rule has no versions", func(t *testing.T) {
+		_, err := store.GetAlertRuleVersions(context.Background(), models.AlertRuleKey{OrgID: 1, UID: "unknown"})
+		require.ErrorIs(t, err, models.ErrAlertRuleNotFound)
+	})
+}
+
 func createTestStore(
 	sqlStore db.DB,
 	folderService folder.Service,
@@ -7,6 +7,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 func alertRuleToModelsAlertRule(ar alertRule, l log.Logger) (models.AlertRule, error) {
@@ -88,6 +89,17 @@ func alertRuleToModelsAlertRule(ar alertRule, l log.Logger) (models.AlertRule, e
 		}
 	}
 
+	if ar.UpdatedBy != nil {
+		result.UpdatedBy = util.Pointer(models.UserUID(*ar.UpdatedBy))
+	}
+
+	if ar.SuppressedBy != "" {
+		err = json.Unmarshal([]byte(ar.SuppressedBy), &result.SuppressedBy)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("failed to parse suppressing rules: %w", err)
+		}
+	}
+
 	return result, nil
 }
 
@@ -165,6 +177,18 @@ func alertRuleFromModelsAlertRule(ar models.AlertRule) (alertRule, error) {
 	}
 	result.Metadata = string(metadata)
 
+	if ar.UpdatedBy != nil {
+		result.UpdatedBy = util.Pointer(string(*ar.UpdatedBy))
+	}
+
+	if len(ar.SuppressedBy) > 0 {
+		suppressedByData, err := json.Marshal(ar.SuppressedBy)
+		if err != nil {
+			return alertRule{}, fmt.Errorf("failed to marshal suppressing rules: %w", err)
+		}
+		result.SuppressedBy = string(suppressedByData)
+	}
+
 	return result, nil
 }
 
@@ -192,5 +216,36 @@ func alertRuleToAlertRuleVersion(rule alertRule) alertRuleVersion {
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: rule.NotificationSettings,
 		Metadata:             rule.Metadata,
+		UpdatedBy:            rule.UpdatedBy,
+		SuppressedBy:         rule.SuppressedBy,
 	}
 }
+
+// alertRuleVersionToModelsAlertRule converts a record of the alert_rule_version table to the rule it is a version of.
+// The version does not store the ID, dashboard and panel of the rule, so they are not set.
+func alertRuleVersionToModelsAlertRule(version alertRuleVersion, l log.Logger) (models.AlertRule, error) {
+	return alertRuleToModelsAlertRule(alertRule{
+		OrgID:                version.RuleOrgID,
+		Title:                version.Title,
+		Condition:            version.Condition,
+		Data:                 version.Data,
+		Updated:              version.Created,
+		IntervalSeconds:      version.IntervalSeconds,
+		Version:              version.Version,
+		UID:                  version.RuleUID,
+		NamespaceUID:         version.RuleNamespaceUID,
+		RuleGroup:            version.RuleGroup,
+		RuleGroupIndex:       version.RuleGroupIndex,
+		Record:               version.Record,
+		NoDataState:          version.NoDataState,
+		ExecErrState:         version.ExecErrState,
+		For:                  version.For,
+		Annotations:          version.Annotations,
+		Labels:               version.Labels,
+		IsPaused:             version.IsPaused,
+		NotificationSettings: version.NotificationSettings,
+		Metadata:             version.Metadata,
+		UpdatedBy:            version.UpdatedBy,
+		SuppressedBy:         version.SuppressedBy,
+	}, l)
+}
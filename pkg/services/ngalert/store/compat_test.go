@@ -23,6 +23,20 @@ func TestAlertRuleToModelsAlertRule(t *testing.T) {
 		}
 	})
 
+	t.Run("make sure suppressing rules are not lost between conversions", func(t *testing.T) {
+		rule := g.With(g.WithSuppressedBy(ngmodels.SuppressingRule{RuleUID: "node-down", Equal: []string{"instance"}})).Generate()
+		r, err := alertRuleFromModelsAlertRule(rule)
+		require.NoError(t, err)
+		clone, err := alertRuleToModelsAlertRule(r, &logtest.Fake{})
+		require.NoError(t, err)
+		require.Equal(t, rule.SuppressedBy, clone.SuppressedBy)
+
+		version := alertRuleToAlertRuleVersion(r)
+		clone, err = alertRuleVersionToModelsAlertRule(version, &logtest.Fake{})
+		require.NoError(t, err)
+		require.Equal(t, rule.SuppressedBy, clone.SuppressedBy)
+	})
+
 	t.Run("should use NoData if NoDataState is not known", func(t *testing.T) {
 		rule, err := alertRuleFromModelsAlertRule(g.Generate())
 		require.NoError(t, err)
@@ -3,13 +3,14 @@ package store
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/util/cmputil"
 )
 
 // AlertRuleFieldsToIgnoreInDiff contains fields that are ignored when calculating the RuleDelta.Diff.
-var AlertRuleFieldsToIgnoreInDiff = [...]string{"ID", "Version", "Updated"}
+var AlertRuleFieldsToIgnoreInDiff = [...]string{"ID", "Version", "Updated", "UpdatedBy"}
 
 type RuleDelta struct {
 	Existing *models.AlertRule
@@ -57,6 +58,54 @@ type RuleReader interface {
 	GetAlertRulesGroupByRuleUID(ctx context.Context, query *models.GetAlertRulesGroupByRuleUIDQuery) ([]*models.AlertRule, error)
 }
 
+// ValidateSuppressingRules checks that the suppressing rules of the new and updated rules of the delta exist in the organization.
+// Rules that the delta creates count as existing, and rules that it deletes do not.
+func ValidateSuppressingRules(ctx context.Context, ruleReader RuleReader, delta *GroupDelta) error {
+	rules := make([]*models.AlertRule, 0, len(delta.New)+len(delta.Update))
+	rules = append(rules, delta.New...)
+	for _, d := range delta.Update {
+		rules = append(rules, d.New)
+	}
+
+	known := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		known[r.UID] = true
+	}
+	for _, r := range delta.Delete {
+		known[r.UID] = false
+	}
+
+	var lookup []string
+	for _, r := range rules {
+		for _, sr := range r.SuppressedBy {
+			if _, ok := known[sr.RuleUID]; !ok && !slices.Contains(lookup, sr.RuleUID) {
+				lookup = append(lookup, sr.RuleUID)
+			}
+		}
+	}
+	if len(lookup) > 0 {
+		existing, err := ruleReader.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+			OrgID:    delta.GroupKey.OrgID,
+			RuleUIDs: lookup,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to find suppressing rules: %w", err)
+		}
+		for _, r := range existing {
+			known[r.UID] = true
+		}
+	}
+
+	for _, r := range rules {
+		for _, sr := range r.SuppressedBy {
+			if !known[sr.RuleUID] {
+				return fmt.Errorf("%w: suppressing rule %s of rule '%s' does not exist", models.ErrAlertRuleFailedValidation, sr.RuleUID, r.Title)
+			}
+		}
+	}
+	return nil
+}
+
 // CalculateChanges calculates the difference between rules in the group in the database and the submitted rules. If a submitted rule has UID it tries to find it in the database (in other groups).
 // returns a list of rules that need to be added, updated and deleted. Deleted considered rules in the database that belong to the group but do not exist in the list of submitted rules.
 func CalculateChanges(ctx context.Context, ruleReader RuleReader, groupKey models.AlertRuleGroupKey, submittedRules []*models.AlertRuleWithOptionals) (*GroupDelta, error) {
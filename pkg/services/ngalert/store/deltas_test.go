@@ -487,6 +487,61 @@ func TestCalculateRuleDelete(t *testing.T) {
 	})
 }
 
+func TestValidateSuppressingRules(t *testing.T) {
+	gen := models.RuleGen
+	fakeStore := fakes.NewRuleStore(t)
+	existing := gen.GenerateRef()
+	otherOrg := gen.With(gen.WithOrgID(existing.OrgID + 1)).GenerateRef()
+	fakeStore.Rules[existing.OrgID] = []*models.AlertRule{existing}
+	fakeStore.Rules[otherOrg.OrgID] = []*models.AlertRule{otherOrg}
+	groupKey := models.AlertRuleGroupKey{OrgID: existing.OrgID, NamespaceUID: "folder", RuleGroup: "group"}
+
+	suppressedBy := func(uids ...string) *models.AlertRule {
+		rules := make([]models.SuppressingRule, 0, len(uids))
+		for _, uid := range uids {
+			rules = append(rules, models.SuppressingRule{RuleUID: uid})
+		}
+		return gen.With(gen.WithGroupKey(groupKey), gen.WithSuppressedBy(rules...)).GenerateRef()
+	}
+
+	t.Run("rules that exist in the org", func(t *testing.T) {
+		delta := &GroupDelta{GroupKey: groupKey, New: []*models.AlertRule{suppressedBy(existing.UID)}}
+		require.NoError(t, ValidateSuppressingRules(context.Background(), fakeStore, delta))
+	})
+
+	t.Run("rules created by the same delta", func(t *testing.T) {
+		suppressing := gen.With(gen.WithGroupKey(groupKey)).GenerateRef()
+		updated := suppressedBy(suppressing.UID)
+		delta := &GroupDelta{
+			GroupKey: groupKey,
+			New:      []*models.AlertRule{suppressing},
+			Update:   []RuleDelta{{Existing: updated, New: updated}},
+		}
+		require.NoError(t, ValidateSuppressingRules(context.Background(), fakeStore, delta))
+	})
+
+	t.Run("rules that do not exist", func(t *testing.T) {
+		delta := &GroupDelta{GroupKey: groupKey, New: []*models.AlertRule{suppressedBy(existing.UID, "missing")}}
+		err := ValidateSuppressingRules(context.Background(), fakeStore, delta)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "suppressing rule missing")
+	})
+
+	t.Run("rules of another org", func(t *testing.T) {
+		delta := &GroupDelta{GroupKey: groupKey, New: []*models.AlertRule{suppressedBy(otherOrg.UID)}}
+		require.ErrorIs(t, ValidateSuppressingRules(context.Background(), fakeStore, delta), models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("rules deleted by the same delta", func(t *testing.T) {
+		delta := &GroupDelta{
+			GroupKey: groupKey,
+			New:      []*models.AlertRule{suppressedBy(existing.UID)},
+			Delete:   []*models.AlertRule{existing},
+		}
+		require.ErrorIs(t, ValidateSuppressingRules(context.Background(), fakeStore, delta), models.ErrAlertRuleFailedValidation)
+	})
+}
+
 func TestCalculateRuleUpdate(t *testing.T) {
 	gen := models.RuleGen
 	fakeStore := fakes.NewRuleStore(t)
@@ -25,8 +25,10 @@ type alertRule struct {
 	Annotations          string
 	Labels               string
 	IsPaused             bool
-	NotificationSettings string `xorm:"notification_settings"`
-	Metadata             string `xorm:"metadata"`
+	NotificationSettings string  `xorm:"notification_settings"`
+	Metadata             string  `xorm:"metadata"`
+	UpdatedBy            *string `xorm:"updated_by"`
+	SuppressedBy         string  `xorm:"suppressed_by"`
 }
 
 func (a alertRule) TableName() string {
@@ -59,8 +61,10 @@ type alertRuleVersion struct {
 	Annotations          string
 	Labels               string
 	IsPaused             bool
-	NotificationSettings string `xorm:"notification_settings"`
-	Metadata             string `xorm:"metadata"`
+	NotificationSettings string  `xorm:"notification_settings"`
+	Metadata             string  `xorm:"metadata"`
+	UpdatedBy            *string `xorm:"updated_by"`
+	SuppressedBy         string  `xorm:"suppressed_by"`
 }
 
 func (a alertRuleVersion) TableName() string {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/rand"
 	"slices"
+	"sort"
 	"sync"
 	"testing"
 	"time"
@@ -21,7 +22,9 @@ type RuleStore struct {
 	t   *testing.T
 	mtx sync.Mutex
 	// OrgID -> RuleGroup -> Namespace -> Rules
-	Rules       map[int64][]*models.AlertRule
+	Rules map[int64][]*models.AlertRule
+	// Versions contains the previous versions of the rules returned by GetAlertRuleVersions
+	Versions    map[models.AlertRuleKey][]*models.AlertRule
 	Hook        func(cmd any) error // use Hook if you need to intercept some query and return an error
 	RecordedOps []any
 	Folders     map[int64][]*folder.Folder
@@ -34,8 +37,9 @@ type GenericRecordedQuery struct {
 
 func NewRuleStore(t *testing.T) *RuleStore {
 	return &RuleStore{
-		t:     t,
-		Rules: map[int64][]*models.AlertRule{},
+		t:        t,
+		Rules:    map[int64][]*models.AlertRule{},
+		Versions: map[models.AlertRuleKey][]*models.AlertRule{},
 		Hook: func(any) error {
 			return nil
 		},
@@ -144,6 +148,34 @@ func (f *RuleStore) GetAlertRuleByUID(_ context.Context, q *models.GetAlertRuleB
 	return nil, models.ErrAlertRuleNotFound
 }
 
+// GetAlertRuleVersions returns the versions of the rule from Versions and the current rule from Rules, newest first.
+func (f *RuleStore) GetAlertRuleVersions(_ context.Context, key models.AlertRuleKey) ([]*models.AlertRule, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	q := GenericRecordedQuery{
+		Name:   "GetAlertRuleVersions",
+		Params: []any{key},
+	}
+	f.RecordedOps = append(f.RecordedOps, q)
+	if err := f.Hook(q); err != nil {
+		return nil, err
+	}
+	result := make([]*models.AlertRule, 0, len(f.Versions[key])+1)
+	for _, rule := range f.Rules[key.OrgID] {
+		if rule.UID == key.UID {
+			result = append(result, rule)
+		}
+	}
+	result = append(result, f.Versions[key]...)
+	if len(result) == 0 {
+		return nil, models.ErrAlertRuleNotFound
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version > result[j].Version
+	})
+	return result, nil
+}
+
 func (f *RuleStore) GetAlertRulesGroupByRuleUID(_ context.Context, q *models.GetAlertRulesGroupByRuleUIDQuery) ([]*models.AlertRule, error) {
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
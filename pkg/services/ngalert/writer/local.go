@@ -0,0 +1,269 @@
+package writer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const localBackendType = "local"
+
+// orgIDLabel is the label that keeps the series of different organizations apart in the local storage.
+// It is added on write and removed from the results of queries.
+const orgIDLabel = "__grafana_org_id__"
+
+var ErrLocalStorageDisabled = errors.New("local storage of recording rules is not enabled")
+
+// LocalStorage is a time series database embedded in Grafana that stores the series written by recording rules
+// when the local backend is configured. The database is opened on first use and keeps the series for the configured retention.
+type LocalStorage struct {
+	enabled   bool
+	path      string
+	retention time.Duration
+	logger    log.Logger
+
+	mtx    sync.Mutex
+	db     *tsdb.DB
+	closed bool
+}
+
+func ProvideLocalStorage(cfg *setting.Cfg) *LocalStorage {
+	settings := cfg.UnifiedAlerting.RecordingRules
+	return NewLocalStorage(settings.Enabled && settings.Backend == setting.RecordingRuleBackendLocal, settings.LocalPath, settings.LocalRetention, log.New("ngalert.writer.local"))
+}
+
+func NewLocalStorage(enabled bool, path string, retention time.Duration, l log.Logger) *LocalStorage {
+	return &LocalStorage{
+		enabled:   enabled,
+		path:      path,
+		retention: retention,
+		logger:    l,
+	}
+}
+
+// IsDisabled returns true if recording rules do not write to the local storage.
+func (s *LocalStorage) IsDisabled() bool {
+	return !s.enabled
+}
+
+// Run closes the database when Grafana shuts down.
+func (s *LocalStorage) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return s.Close()
+}
+
+// Close closes the database if it is open. The storage cannot be used after it is closed.
+func (s *LocalStorage) Close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.closed = true
+	if s.db == nil {
+		return nil
+	}
+	err := s.db.Close()
+	s.db = nil
+	return err
+}
+
+func (s *LocalStorage) open() (*tsdb.DB, error) {
+	if !s.enabled {
+		return nil, ErrLocalStorageDisabled
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.closed {
+		return nil, errors.New("local storage of recording rules is closed")
+	}
+	if s.db != nil {
+		return s.db, nil
+	}
+
+	opts := tsdb.DefaultOptions()
+	opts.RetentionDuration = s.retention.Milliseconds()
+	db, err := tsdb.Open(s.path, s.logger, nil, opts, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open local storage of recording rules: %w", err)
+	}
+	s.logger.Info("Opened local storage of recording rules", "path", s.path, "retention", s.retention)
+	s.db = db
+	return db, nil
+}
+
+// Append stores the points of the organization. Samples that already exist are ignored.
+func (s *LocalStorage) Append(ctx context.Context, orgID int64, points []Point) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+
+	app := db.Appender(ctx)
+	for _, p := range points {
+		lbls := make(map[string]string, len(p.Labels)+2)
+		for k, v := range p.Labels {
+			lbls[k] = v
+		}
+		lbls[labels.MetricName] = p.Name
+		lbls[orgIDLabel] = fmt.Sprint(orgID)
+
+		_, err := app.Append(0, labels.FromMap(lbls), p.Metric.T.UnixMilli(), p.Metric.V)
+		if err == nil || errors.Is(err, storage.ErrDuplicateSampleForTimestamp) {
+			continue
+		}
+		if rollbackErr := app.Rollback(); rollbackErr != nil {
+			s.logger.Error("Failed to roll back the write", "error", rollbackErr)
+		}
+		if errors.Is(err, storage.ErrOutOfOrderSample) || errors.Is(err, storage.ErrOutOfBounds) || errors.Is(err, storage.ErrTooOldSample) {
+			return errors.Join(ErrRejectedWrite, err)
+		}
+		return errors.Join(ErrUnexpectedWriteFailure, err)
+	}
+	if err := app.Commit(); err != nil {
+		return errors.Join(ErrUnexpectedWriteFailure, err)
+	}
+	return nil
+}
+
+// LocalQuery is a query of the series in LocalStorage.
+type LocalQuery struct {
+	// Selector is a Prometheus series selector, for example `job:http_requests:rate5m{job="api"}`.
+	Selector string
+	From     time.Time
+	To       time.Time
+}
+
+// Query returns the samples of the series of the organization that match the selector in the time range of the query.
+// Every series is returned as a separate frame with a time field and a value field that has the labels of the series.
+func (s *LocalStorage) Query(ctx context.Context, orgID int64, q LocalQuery) (data.Frames, error) {
+	matchers, err := parser.ParseMetricSelector(q.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector: %w", err)
+	}
+	for _, m := range matchers {
+		if m.Name == orgIDLabel {
+			return nil, fmt.Errorf("invalid selector: label %s is reserved", orgIDLabel)
+		}
+	}
+	matchers = append(matchers, labels.MustNewMatcher(labels.MatchEqual, orgIDLabel, fmt.Sprint(orgID)))
+
+	db, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	querier, err := db.Querier(q.From.UnixMilli(), q.To.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := querier.Close(); err != nil {
+			s.logger.Warn("Failed to close querier", "error", err)
+		}
+	}()
+
+	frames := data.Frames{}
+	set := querier.Select(ctx, true, nil, matchers...)
+	var it chunkenc.Iterator
+	for set.Next() {
+		series := set.At()
+		times := make([]time.Time, 0)
+		values := make([]float64, 0)
+		it = series.Iterator(it)
+		for it.Next() == chunkenc.ValFloat {
+			t, v := it.At()
+			times = append(times, time.UnixMilli(t).UTC())
+			values = append(values, v)
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+
+		lbls := data.Labels{}
+		name := ""
+		series.Labels().Range(func(l labels.Label) {
+			switch l.Name {
+			case labels.MetricName:
+				name = l.Value
+			case orgIDLabel:
+			default:
+				lbls[l.Name] = l.Value
+			}
+		})
+		frame := data.NewFrame(name,
+			data.NewField(data.TimeSeriesTimeFieldName, nil, times),
+			data.NewField(data.TimeSeriesValueFieldName, lbls, values),
+		).SetMeta(&data.FrameMeta{
+			Type:        data.FrameTypeTimeSeriesMulti,
+			TypeVersion: data.FrameTypeVersion{0, 1},
+		})
+		frames = append(frames, frame)
+	}
+	if err := set.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(frames, func(i, j int) bool {
+		return frames[i].Name < frames[j].Name
+	})
+	return frames, nil
+}
+
+// LocalWriter writes the series of recording rules to LocalStorage.
+type LocalWriter struct {
+	storage *LocalStorage
+	clock   clock.Clock
+	logger  log.Logger
+	metrics *metrics.RemoteWriter
+}
+
+func NewLocalWriter(storage *LocalStorage, clock clock.Clock, l log.Logger, metrics *metrics.RemoteWriter) (*LocalWriter, error) {
+	if storage == nil || storage.IsDisabled() {
+		return nil, ErrLocalStorageDisabled
+	}
+	return &LocalWriter{
+		storage: storage,
+		clock:   clock,
+		logger:  l,
+		metrics: metrics,
+	}, nil
+}
+
+// Write writes the given frames to the local storage.
+func (w LocalWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, orgID int64, extraLabels map[string]string) error {
+	l := w.logger.FromContext(ctx)
+	lvs := []string{fmt.Sprint(orgID), localBackendType}
+
+	points, err := PointsFromFrames(name, t, frames, extraLabels)
+	if err != nil {
+		return errors.Join(ErrBadFrame, err)
+	}
+
+	l.Debug("Writing metric", "name", name)
+	writeStart := w.clock.Now()
+	err = w.storage.Append(ctx, orgID, points)
+	w.metrics.WriteDuration.WithLabelValues(lvs...).Observe(w.clock.Now().Sub(writeStart).Seconds())
+
+	status := http.StatusOK
+	if errors.Is(err, ErrRejectedWrite) {
+		status = http.StatusBadRequest
+	} else if err != nil {
+		status = http.StatusInternalServerError
+	}
+	lvs = append(lvs, fmt.Sprint(status))
+	w.metrics.WritesTotal.WithLabelValues(lvs...).Inc()
+
+	return err
+}
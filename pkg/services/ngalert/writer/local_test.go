@@ -0,0 +1,111 @@
+package writer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestLocalWriter_Write(t *testing.T) {
+	storage := NewLocalStorage(true, t.TempDir(), 24*time.Hour, log.New("test"))
+	t.Cleanup(func() {
+		require.NoError(t, storage.Close())
+	})
+	writer, err := NewLocalWriter(storage, clock.New(), log.New("test"), metrics.NewRemoteWriterMetrics(prometheus.NewRegistry()))
+	require.NoError(t, err)
+
+	now := time.Now().Truncate(time.Millisecond)
+	series := []map[string]string{{"foo": "1"}, {"foo": "2"}}
+	frames := frameGenFromLabels(t, data.FrameTypeNumericMulti, series)
+	ctx := ngmodels.WithRuleKey(context.Background(), ngmodels.GenerateRuleKey(1))
+	query := func(t *testing.T, orgID int64, selector string) data.Frames {
+		t.Helper()
+		result, err := storage.Query(context.Background(), orgID, LocalQuery{Selector: selector, From: now.Add(-time.Hour), To: now.Add(time.Hour)})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("error when frames are empty", func(t *testing.T) {
+		err := writer.Write(ctx, "test", now, data.Frames{data.NewFrame("test")}, 1, map[string]string{})
+		require.ErrorIs(t, err, ErrBadFrame)
+	})
+
+	t.Run("writes expected points", func(t *testing.T) {
+		require.NoError(t, writer.Write(ctx, "test", now, frames, 1, map[string]string{"extra": "label"}))
+		require.NoError(t, writer.Write(ctx, "test", now.Add(time.Minute), frames, 1, map[string]string{"extra": "label"}))
+
+		result := query(t, 1, `test{extra="label"}`)
+		require.Len(t, result, len(series))
+		for i, frame := range result {
+			require.Equal(t, "test", frame.Name)
+			require.Equal(t, data.FrameTypeTimeSeriesMulti, frame.Meta.Type)
+			require.Equal(t, data.Labels{"extra": "label", "foo": series[i]["foo"]}, frame.Fields[1].Labels)
+			require.Equal(t, []time.Time{now.UTC(), now.Add(time.Minute).UTC()}, []time.Time{frame.Fields[0].At(0).(time.Time), frame.Fields[0].At(1).(time.Time)})
+			expected := extractValue(t, frames, series[i], data.FrameTypeNumericMulti)
+			require.Equal(t, expected, frame.Fields[1].At(0))
+		}
+
+		require.Len(t, query(t, 1, `test{foo="2"}`), 1)
+		require.Empty(t, query(t, 1, `other`))
+	})
+
+	t.Run("ignores duplicate samples", func(t *testing.T) {
+		require.NoError(t, writer.Write(ctx, "test", now.Add(time.Minute), frames, 1, map[string]string{"extra": "label"}))
+	})
+
+	t.Run("rejects samples older than the latest one", func(t *testing.T) {
+		err := writer.Write(ctx, "test", now.Add(-time.Minute), frames, 1, map[string]string{"extra": "label"})
+		require.ErrorIs(t, err, ErrRejectedWrite)
+	})
+
+	t.Run("returns only series of the organization", func(t *testing.T) {
+		require.NoError(t, writer.Write(ctx, "test", now, frames[:1], 2, nil))
+		require.Len(t, query(t, 2, `test`), 1)
+		require.Len(t, query(t, 1, `test`), len(series))
+	})
+
+	t.Run("fails if selector uses the organization label", func(t *testing.T) {
+		_, err := storage.Query(context.Background(), 1, LocalQuery{Selector: `test{__grafana_org_id__="2"}`, From: now, To: now})
+		require.Error(t, err)
+		_, err = storage.Query(context.Background(), 1, LocalQuery{Selector: `{`, From: now, To: now})
+		require.Error(t, err)
+	})
+}
+
+func TestLocalStorage(t *testing.T) {
+	t.Run("fails if disabled", func(t *testing.T) {
+		storage := NewLocalStorage(false, t.TempDir(), time.Hour, log.New("test"))
+		require.True(t, storage.IsDisabled())
+		_, err := storage.Query(context.Background(), 1, LocalQuery{Selector: "test"})
+		require.ErrorIs(t, err, ErrLocalStorageDisabled)
+		_, err = NewLocalWriter(storage, clock.New(), log.New("test"), nil)
+		require.ErrorIs(t, err, ErrLocalStorageDisabled)
+	})
+
+	t.Run("keeps series after it is reopened", func(t *testing.T) {
+		path := t.TempDir()
+		now := time.Now()
+		storage := NewLocalStorage(true, path, time.Hour, log.New("test"))
+		require.NoError(t, storage.Append(context.Background(), 1, []Point{{Name: "test", Labels: map[string]string{}, Metric: Metric{T: now, V: 1}}}))
+		require.NoError(t, storage.Close())
+		require.Error(t, storage.Append(context.Background(), 1, nil))
+
+		storage = NewLocalStorage(true, path, time.Hour, log.New("test"))
+		t.Cleanup(func() {
+			require.NoError(t, storage.Close())
+		})
+		result, err := storage.Query(context.Background(), 1, LocalQuery{Selector: "test", From: now.Add(-time.Minute), To: now.Add(time.Minute)})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, 1.0, result[0].Fields[1].At(0))
+	})
+}
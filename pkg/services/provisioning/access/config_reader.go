@@ -0,0 +1,80 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+type configReader struct {
+	log log.Logger
+}
+
+func newConfigReader(logger log.Logger) configReader {
+	return configReader{
+		log: logger,
+	}
+}
+
+func (cr *configReader) readConfig(ctx context.Context, path string) ([]*AccessFile, error) {
+	var accessFiles []*AccessFile
+	cr.log.Debug("looking for access provisioning files", "path", path)
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("can't read access provisioning files from directory", "path", path, "error", err)
+		return accessFiles, nil
+	}
+
+	for _, file := range files {
+		cr.log.Debug("parsing access provisioning file", "path", path, "file.Name", file.Name())
+		if !cr.isYAML(file.Name()) && !cr.isJSON(file.Name()) {
+			cr.log.Warn(fmt.Sprintf("file has invalid suffix '%s' (.yaml,.yml,.json accepted), skipping", file.Name()))
+			continue
+		}
+		accessFileV1, err := cr.parseConfig(path, file)
+		if err != nil {
+			return nil, fmt.Errorf("failure to parse file %s: %w", file.Name(), err)
+		}
+		if accessFileV1 != nil {
+			accessFileV1.Filename = file.Name()
+			accessFile, err := accessFileV1.MapToModel()
+			if err != nil {
+				return nil, fmt.Errorf("failure to map file %s: %w", accessFileV1.Filename, err)
+			}
+			accessFiles = append(accessFiles, &accessFile)
+		}
+	}
+	return accessFiles, nil
+}
+
+func (cr *configReader) isYAML(file string) bool {
+	return strings.HasSuffix(file, ".yaml") || strings.HasSuffix(file, ".yml")
+}
+
+func (cr *configReader) isJSON(file string) bool {
+	return strings.HasSuffix(file, ".json")
+}
+
+func (cr *configReader) parseConfig(path string, file fs.DirEntry) (*AccessFileV1, error) {
+	filename, _ := filepath.Abs(filepath.Join(path, file.Name()))
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var cfg *AccessFileV1
+	err = yaml.Unmarshal(yamlFile, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
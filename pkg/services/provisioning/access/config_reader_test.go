@@ -0,0 +1,124 @@
+package access
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+)
+
+const (
+	testFileBrokenYAML               = "./testdata/common/broken-yaml"
+	testFileEmptyFile                = "./testdata/common/empty-file"
+	testFileEmptyFolder              = "./testdata/common/empty-folder"
+	testFileSupportedFiletypes       = "./testdata/common/supported-filetypes"
+	testFileCorrectProperties        = "./testdata/correct-properties"
+	testFileCorrectPropertiesWithOrg = "./testdata/correct-properties-with-org"
+	testFileInvalidPermission        = "./testdata/invalid-permission"
+)
+
+func TestConfigReader(t *testing.T) {
+	configReader := newConfigReader(log.NewNopLogger())
+	ctx := context.Background()
+	t.Run("a broken YAML file should error", func(t *testing.T) {
+		_, err := configReader.readConfig(ctx, testFileBrokenYAML)
+		require.Error(t, err)
+	})
+	t.Run("an empty file should not make the config reader error", func(t *testing.T) {
+		_, err := configReader.readConfig(ctx, testFileEmptyFile)
+		require.NoError(t, err)
+	})
+	t.Run("an empty folder should not make the config reader error", func(t *testing.T) {
+		_, err := configReader.readConfig(ctx, testFileEmptyFolder)
+		require.NoError(t, err)
+	})
+	t.Run("the config reader should support .yaml,.yml and .json files", func(t *testing.T) {
+		files, err := configReader.readConfig(ctx, testFileSupportedFiletypes)
+		require.NoError(t, err)
+		require.Len(t, files, 3)
+	})
+	t.Run("an invalid permission should error", func(t *testing.T) {
+		_, err := configReader.readConfig(ctx, testFileInvalidPermission)
+		require.ErrorContains(t, err, "exactly one of user, team, serviceAccount and role")
+	})
+	t.Run("a file with correct properties should not error", func(t *testing.T) {
+		files, err := configReader.readConfig(ctx, testFileCorrectProperties)
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		file := files[0]
+
+		require.Equal(t, []User{{
+			Login:    "editor",
+			Email:    "editor@example.com",
+			Name:     "Editor",
+			Password: "secret",
+			Orgs:     []UserOrg{{OrgID: 1, Role: org.RoleEditor}},
+		}}, file.Users)
+		require.Equal(t, []DeleteUser{{Login: "former-editor"}}, file.DeleteUsers)
+
+		require.Equal(t, []ServiceAccount{{OrgID: 1, Name: "ci", Role: org.RoleEditor}}, file.ServiceAccounts)
+		require.Equal(t, []DeleteServiceAccount{{OrgID: 1, Name: "former-ci"}}, file.DeleteServiceAccounts)
+
+		require.Equal(t, []Team{{
+			OrgID: 1,
+			Name:  "platform",
+			Email: "platform@example.com",
+			Members: []TeamMember{
+				{Login: "editor", Permission: "Member"},
+				{Login: "admin", Permission: "Admin"},
+			},
+		}}, file.Teams)
+		require.Equal(t, []DeleteTeam{{OrgID: 1, Name: "former-platform"}}, file.DeleteTeams)
+
+		require.Equal(t, []Folder{
+			{
+				OrgID:       1,
+				UID:         "platform",
+				Title:       "Platform",
+				Description: "Dashboards of the platform team",
+				Permissions: []Permission{
+					{Team: "platform", Permission: "Edit"},
+					{Role: "Viewer", Permission: "View"},
+				},
+			},
+			{
+				OrgID:     1,
+				UID:       "platform-alerts",
+				Title:     "Alerts",
+				ParentUID: "platform",
+			},
+		}, file.Folders)
+		t.Run("when no permissions are present they should not be managed", func(t *testing.T) {
+			require.Nil(t, file.Folders[1].Permissions)
+		})
+		require.Equal(t, []DeleteFolder{{OrgID: 1, UID: "former-platform"}}, file.DeleteFolders)
+
+		require.Equal(t, []DashboardPermissions{{
+			OrgID: 1,
+			UID:   "platform-overview",
+			Permissions: []Permission{
+				{ServiceAccount: "ci", Permission: "Admin"},
+				{User: "editor", Permission: "View"},
+			},
+		}}, file.DashboardPermissions)
+		require.Equal(t, []DeleteDashboardPermissions{{OrgID: 1, UID: "former-platform-overview"}}, file.DeleteDashboardPermissions)
+	})
+	t.Run("a file with correct properties and specific org should not error", func(t *testing.T) {
+		files, err := configReader.readConfig(ctx, testFileCorrectPropertiesWithOrg)
+		require.NoError(t, err)
+		t.Run("when an organization is set it should not overwrite if with the default of 1", func(t *testing.T) {
+			file := files[0]
+			require.Equal(t, int64(1337), file.Users[0].Orgs[0].OrgID)
+			require.Equal(t, int64(1337), file.ServiceAccounts[0].OrgID)
+			require.Equal(t, int64(1337), file.Teams[0].OrgID)
+			require.Equal(t, int64(1337), file.Folders[0].OrgID)
+			require.Equal(t, int64(1337), file.DashboardPermissions[0].OrgID)
+		})
+		t.Run("when the service account role is not set it should be Viewer", func(t *testing.T) {
+			require.Equal(t, org.RoleViewer, files[0].ServiceAccounts[0].Role)
+		})
+	})
+}
@@ -0,0 +1,65 @@
+package access
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+)
+
+type DashboardPermissionsProvisioner interface {
+	Provision(ctx context.Context, files []*AccessFile) error
+	Unprovision(ctx context.Context, files []*AccessFile) error
+}
+
+type defaultDashboardPermissionsProvisioner struct {
+	logger                      log.Logger
+	dashboardPermissionsService accesscontrol.DashboardPermissionsService
+	provenanceService           provenance.Service
+	resolver                    *principalResolver
+}
+
+func NewDashboardPermissionsProvisioner(logger log.Logger,
+	dashboardPermissionsService accesscontrol.DashboardPermissionsService,
+	provenanceService provenance.Service,
+	resolver *principalResolver) DashboardPermissionsProvisioner {
+	return &defaultDashboardPermissionsProvisioner{
+		logger:                      logger,
+		dashboardPermissionsService: dashboardPermissionsService,
+		provenanceService:           provenanceService,
+		resolver:                    resolver,
+	}
+}
+
+func (p *defaultDashboardPermissionsProvisioner) Provision(ctx context.Context, files []*AccessFile) error {
+	for _, file := range files {
+		for _, dp := range file.DashboardPermissions {
+			commands, err := p.resolver.permissionCommands(ctx, dp.OrgID, dp.Permissions)
+			if err != nil {
+				return fmt.Errorf("dashboard %s: %w", dp.UID, err)
+			}
+			if err := setPermissions(ctx, p.dashboardPermissionsService, dp.OrgID, dp.UID, commands); err != nil {
+				return fmt.Errorf("dashboard %s: %w", dp.UID, err)
+			}
+			err = p.provenanceService.SetProvenance(ctx, dp.OrgID, provenance.KindDashboardPermissions, dp.UID, provenance.ProvenanceFile)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *defaultDashboardPermissionsProvisioner) Unprovision(ctx context.Context, files []*AccessFile) error {
+	for _, file := range files {
+		for _, deleteDP := range file.DeleteDashboardPermissions {
+			err := p.provenanceService.DeleteProvenance(ctx, deleteDP.OrgID, provenance.KindDashboardPermissions, deleteDP.UID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+)
+
+type FolderProvisioner interface {
+	Provision(ctx context.Context, files []*AccessFile) error
+	Unprovision(ctx context.Context, files []*AccessFile) error
+}
+
+type defaultFolderProvisioner struct {
+	logger                   log.Logger
+	folderService            folder.Service
+	folderPermissionsService accesscontrol.FolderPermissionsService
+	provenanceService        provenance.Service
+	resolver                 *principalResolver
+}
+
+func NewFolderProvisioner(logger log.Logger,
+	folderService folder.Service,
+	folderPermissionsService accesscontrol.FolderPermissionsService,
+	provenanceService provenance.Service,
+	resolver *principalResolver) FolderProvisioner {
+	return &defaultFolderProvisioner{
+		logger:                   logger,
+		folderService:            folderService,
+		folderPermissionsService: folderPermissionsService,
+		provenanceService:        provenanceService,
+		resolver:                 resolver,
+	}
+}
+
+func (p *defaultFolderProvisioner) Provision(ctx context.Context, files []*AccessFile) error {
+	var folders []Folder
+	for _, file := range files {
+		folders = append(folders, file.Folders...)
+	}
+	folders, err := sortFolders(folders)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range folders {
+		if err := p.provisionFolder(ctx, f); err != nil {
+			return fmt.Errorf("folder %s: %w", f.UID, err)
+		}
+		if err := p.provisionPermissions(ctx, f); err != nil {
+			return fmt.Errorf("permissions of folder %s: %w", f.UID, err)
+		}
+		err = p.provenanceService.SetProvenance(ctx, f.OrgID, provenance.KindFolder, f.UID, provenance.ProvenanceFile)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortFolders sorts the folders so that the parents of folders come before them.
+func sortFolders(folders []Folder) ([]Folder, error) {
+	type key struct {
+		orgID int64
+		uid   string
+	}
+	byKey := make(map[key]Folder, len(folders))
+	for _, f := range folders {
+		byKey[key{f.OrgID, f.UID}] = f
+	}
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[key]int, len(folders))
+	sorted := make([]Folder, 0, len(folders))
+	var visit func(f Folder) error
+	visit = func(f Folder) error {
+		k := key{f.OrgID, f.UID}
+		switch state[k] {
+		case visiting:
+			return fmt.Errorf("folder %s is its own ancestor", f.UID)
+		case visited:
+			return nil
+		}
+		state[k] = visiting
+		if parent, ok := byKey[key{f.OrgID, f.ParentUID}]; ok && f.ParentUID != "" {
+			if err := visit(parent); err != nil {
+				return err
+			}
+		}
+		state[k] = visited
+		sorted = append(sorted, byKey[k])
+		return nil
+	}
+	for _, f := range folders {
+		if err := visit(f); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+func (p *defaultFolderProvisioner) provisionFolder(ctx context.Context, f Folder) error {
+	user := backgroundUser(f.OrgID)
+	existing, err := p.folderService.Get(ctx, &folder.GetFolderQuery{UID: &f.UID, OrgID: f.OrgID, SignedInUser: user})
+	if errors.Is(err, dashboards.ErrFolderNotFound) {
+		_, err := p.folderService.Create(ctx, &folder.CreateFolderCommand{
+			UID:          f.UID,
+			OrgID:        f.OrgID,
+			Title:        f.Title,
+			Description:  f.Description,
+			ParentUID:    f.ParentUID,
+			SignedInUser: user,
+		})
+		if err != nil {
+			return err
+		}
+		p.logger.Debug("created folder", "uid", f.UID, "org", f.OrgID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.Title != f.Title || existing.Description != f.Description {
+		_, err := p.folderService.Update(ctx, &folder.UpdateFolderCommand{
+			UID:            f.UID,
+			OrgID:          f.OrgID,
+			NewTitle:       &f.Title,
+			NewDescription: &f.Description,
+			Overwrite:      true,
+			SignedInUser:   user,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if existing.ParentUID != f.ParentUID {
+		_, err := p.folderService.Move(ctx, &folder.MoveFolderCommand{
+			UID:          f.UID,
+			NewParentUID: f.ParentUID,
+			OrgID:        f.OrgID,
+			SignedInUser: user,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *defaultFolderProvisioner) provisionPermissions(ctx context.Context, f Folder) error {
+	if f.Permissions == nil {
+		return p.provenanceService.DeleteProvenance(ctx, f.OrgID, provenance.KindFolderPermissions, f.UID)
+	}
+	commands, err := p.resolver.permissionCommands(ctx, f.OrgID, f.Permissions)
+	if err != nil {
+		return err
+	}
+	if err := setPermissions(ctx, p.folderPermissionsService, f.OrgID, f.UID, commands); err != nil {
+		return err
+	}
+	return p.provenanceService.SetProvenance(ctx, f.OrgID, provenance.KindFolderPermissions, f.UID, provenance.ProvenanceFile)
+}
+
+func (p *defaultFolderProvisioner) Unprovision(ctx context.Context, files []*AccessFile) error {
+	for _, file := range files {
+		for _, deleteFolder := range file.DeleteFolders {
+			err := p.folderService.Delete(ctx, &folder.DeleteFolderCommand{
+				UID:          deleteFolder.UID,
+				OrgID:        deleteFolder.OrgID,
+				SignedInUser: backgroundUser(deleteFolder.OrgID),
+			})
+			if err != nil && !errors.Is(err, dashboards.ErrFolderNotFound) {
+				return fmt.Errorf("folder %s: %w", deleteFolder.UID, err)
+			}
+			err = p.provenanceService.DeleteProvenance(ctx, deleteFolder.OrgID, provenance.KindFolder, deleteFolder.UID)
+			if err != nil {
+				return err
+			}
+			err = p.provenanceService.DeleteProvenance(ctx, deleteFolder.OrgID, provenance.KindFolderPermissions, deleteFolder.UID)
+			if err != nil {
+				return err
+			}
+			p.logger.Debug("deleted folder", "uid", deleteFolder.UID, "org", deleteFolder.OrgID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package access
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+type FolderV1 struct {
+	OrgID       values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID         values.StringValue `json:"uid" yaml:"uid"`
+	Title       values.StringValue `json:"title" yaml:"title"`
+	Description values.StringValue `json:"description" yaml:"description"`
+	ParentUID   values.StringValue `json:"parentUid" yaml:"parentUid"`
+	Permissions []PermissionV1     `json:"permissions" yaml:"permissions"`
+}
+
+func (v1 *FolderV1) mapToModel() (Folder, error) {
+	uid := strings.TrimSpace(v1.UID.Value())
+	if uid == "" {
+		return Folder{}, errors.New("folder missing uid")
+	}
+	title := strings.TrimSpace(v1.Title.Value())
+	if title == "" {
+		return Folder{}, fmt.Errorf("folder %s missing title", uid)
+	}
+	parentUID := strings.TrimSpace(v1.ParentUID.Value())
+	if parentUID == uid {
+		return Folder{}, fmt.Errorf("folder %s cannot be its own parent", uid)
+	}
+	permissions, err := mapPermissions(v1.Permissions)
+	if err != nil {
+		return Folder{}, fmt.Errorf("folder %s: %w", uid, err)
+	}
+	return Folder{
+		OrgID:       orgIDOrDefault(v1.OrgID),
+		UID:         uid,
+		Title:       title,
+		Description: strings.TrimSpace(v1.Description.Value()),
+		ParentUID:   parentUID,
+		Permissions: permissions,
+	}, nil
+}
+
+type Folder struct {
+	OrgID       int64
+	UID         string
+	Title       string
+	Description string
+	ParentUID   string
+	// Permissions are all permissions of the folder. Permissions that are not in the list are removed. If the
+	// permissions are nil, the permissions of the folder are not provisioned.
+	Permissions []Permission
+}
+
+type DeleteFolderV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID   values.StringValue `json:"uid" yaml:"uid"`
+}
+
+func (v1 *DeleteFolderV1) mapToModel() (DeleteFolder, error) {
+	uid := strings.TrimSpace(v1.UID.Value())
+	if uid == "" {
+		return DeleteFolder{}, errors.New("delete folder missing uid")
+	}
+	return DeleteFolder{OrgID: orgIDOrDefault(v1.OrgID), UID: uid}, nil
+}
+
+type DeleteFolder struct {
+	OrgID int64
+	UID   string
+}
@@ -0,0 +1,127 @@
+package access
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// PermissionV1 grants a permission on a folder or a dashboard to exactly one of a user, a team, a service account or
+// a basic role.
+type PermissionV1 struct {
+	// User is the login or the email of the user.
+	User           values.StringValue `json:"user" yaml:"user"`
+	Team           values.StringValue `json:"team" yaml:"team"`
+	ServiceAccount values.StringValue `json:"serviceAccount" yaml:"serviceAccount"`
+	Role           values.StringValue `json:"role" yaml:"role"`
+	Permission     values.StringValue `json:"permission" yaml:"permission"`
+}
+
+func (v1 *PermissionV1) mapToModel() (Permission, error) {
+	p := Permission{
+		User:           strings.TrimSpace(v1.User.Value()),
+		Team:           strings.TrimSpace(v1.Team.Value()),
+		ServiceAccount: strings.TrimSpace(v1.ServiceAccount.Value()),
+		Role:           strings.TrimSpace(v1.Role.Value()),
+		Permission:     strings.TrimSpace(v1.Permission.Value()),
+	}
+
+	targets := 0
+	for _, target := range []string{p.User, p.Team, p.ServiceAccount, p.Role} {
+		if target != "" {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return Permission{}, errors.New("permission must have exactly one of user, team, serviceAccount and role")
+	}
+	if p.Role != "" {
+		if role := org.RoleType(p.Role); role == org.RoleNone || !role.IsValid() {
+			return Permission{}, fmt.Errorf("permission has invalid role %q", p.Role)
+		}
+	}
+	switch p.Permission {
+	case "View", "Edit", "Admin":
+	default:
+		return Permission{}, fmt.Errorf("permission has invalid permission %q, must be View, Edit or Admin", p.Permission)
+	}
+	return p, nil
+}
+
+type Permission struct {
+	User           string
+	Team           string
+	ServiceAccount string
+	Role           string
+	Permission     string
+}
+
+func mapPermissions(permissionsV1 []PermissionV1) ([]Permission, error) {
+	if permissionsV1 == nil {
+		return nil, nil
+	}
+	permissions := make([]Permission, 0, len(permissionsV1))
+	for _, permissionV1 := range permissionsV1 {
+		p, err := permissionV1.mapToModel()
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, p)
+	}
+	return permissions, nil
+}
+
+type DashboardPermissionsV1 struct {
+	OrgID       values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID         values.StringValue `json:"uid" yaml:"uid"`
+	Permissions []PermissionV1     `json:"permissions" yaml:"permissions"`
+}
+
+func (v1 *DashboardPermissionsV1) mapToModel() (DashboardPermissions, error) {
+	uid := strings.TrimSpace(v1.UID.Value())
+	if uid == "" {
+		return DashboardPermissions{}, errors.New("dashboard permissions missing uid")
+	}
+	permissions, err := mapPermissions(v1.Permissions)
+	if err != nil {
+		return DashboardPermissions{}, fmt.Errorf("dashboard %s: %w", uid, err)
+	}
+	if permissions == nil {
+		permissions = []Permission{}
+	}
+	return DashboardPermissions{
+		OrgID:       orgIDOrDefault(v1.OrgID),
+		UID:         uid,
+		Permissions: permissions,
+	}, nil
+}
+
+type DashboardPermissions struct {
+	OrgID int64
+	UID   string
+	// Permissions are all permissions of the dashboard. Permissions that are not in the list are removed.
+	Permissions []Permission
+}
+
+type DeleteDashboardPermissionsV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID   values.StringValue `json:"uid" yaml:"uid"`
+}
+
+func (v1 *DeleteDashboardPermissionsV1) mapToModel() (DeleteDashboardPermissions, error) {
+	uid := strings.TrimSpace(v1.UID.Value())
+	if uid == "" {
+		return DeleteDashboardPermissions{}, errors.New("delete dashboard permissions missing uid")
+	}
+	return DeleteDashboardPermissions{OrgID: orgIDOrDefault(v1.OrgID), UID: uid}, nil
+}
+
+// DeleteDashboardPermissions stops provisioning the permissions of a dashboard. The permissions are kept, but can be
+// changed in the UI again.
+type DeleteDashboardPermissions struct {
+	OrgID int64
+	UID   string
+}
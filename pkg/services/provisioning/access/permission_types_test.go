@@ -0,0 +1,93 @@
+package access
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestPermissions(t *testing.T) {
+	tests := []struct {
+		desc     string
+		config   string
+		expected Permission
+		err      string
+	}{
+		{
+			desc:     "permission of a user",
+			config:   "{user: editor, permission: Edit}",
+			expected: Permission{User: "editor", Permission: "Edit"},
+		},
+		{
+			desc:     "permission of a team",
+			config:   "{team: platform, permission: Admin}",
+			expected: Permission{Team: "platform", Permission: "Admin"},
+		},
+		{
+			desc:     "permission of a service account",
+			config:   "{serviceAccount: ci, permission: View}",
+			expected: Permission{ServiceAccount: "ci", Permission: "View"},
+		},
+		{
+			desc:     "permission of a basic role",
+			config:   "{role: Viewer, permission: View}",
+			expected: Permission{Role: "Viewer", Permission: "View"},
+		},
+		{
+			desc:   "permission without target should error",
+			config: "{permission: View}",
+			err:    "exactly one of",
+		},
+		{
+			desc:   "permission with several targets should error",
+			config: "{user: editor, serviceAccount: ci, permission: View}",
+			err:    "exactly one of",
+		},
+		{
+			desc:   "permission of an invalid role should error",
+			config: "{role: Owner, permission: View}",
+			err:    "invalid role",
+		},
+		{
+			desc:   "permission of the None role should error",
+			config: "{role: None, permission: View}",
+			err:    "invalid role",
+		},
+		{
+			desc:   "invalid permission should error",
+			config: "{user: editor, permission: Write}",
+			err:    "invalid permission",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var permissionV1 PermissionV1
+			require.NoError(t, yaml.Unmarshal([]byte(tt.config), &permissionV1))
+			p, err := permissionV1.mapToModel()
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, p)
+		})
+	}
+}
+
+func TestDashboardPermissions(t *testing.T) {
+	t.Run("missing uid should error on mapping", func(t *testing.T) {
+		var dpV1 DashboardPermissionsV1
+		require.NoError(t, yaml.Unmarshal([]byte("{permissions: []}"), &dpV1))
+		_, err := dpV1.mapToModel()
+		require.Error(t, err)
+	})
+	t.Run("missing permissions should remove all permissions", func(t *testing.T) {
+		var dpV1 DashboardPermissionsV1
+		require.NoError(t, yaml.Unmarshal([]byte("{uid: dashboard}"), &dpV1))
+		dp, err := dpV1.mapToModel()
+		require.NoError(t, err)
+		require.NotNil(t, dp.Permissions)
+		require.Empty(t, dp.Permissions)
+	})
+}
@@ -0,0 +1,165 @@
+package access
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+var provisionerPermissions = []accesscontrol.Permission{
+	{Action: accesscontrol.ActionOrgUsersRead, Scope: accesscontrol.ScopeUsersAll},
+	{Action: accesscontrol.ActionTeamsRead, Scope: accesscontrol.ScopeTeamsAll},
+	{Action: accesscontrol.ActionTeamsPermissionsRead, Scope: accesscontrol.ScopeTeamsAll},
+	{Action: dashboards.ActionFoldersCreate, Scope: dashboards.ScopeFoldersAll},
+	{Action: dashboards.ActionFoldersRead, Scope: dashboards.ScopeFoldersAll},
+	{Action: dashboards.ActionFoldersWrite, Scope: dashboards.ScopeFoldersAll},
+	{Action: dashboards.ActionFoldersDelete, Scope: dashboards.ScopeFoldersAll},
+	{Action: dashboards.ActionFoldersPermissionsRead, Scope: dashboards.ScopeFoldersAll},
+	{Action: dashboards.ActionDashboardsRead, Scope: dashboards.ScopeFoldersAll},
+	{Action: dashboards.ActionDashboardsRead, Scope: dashboards.ScopeDashboardsAll},
+	{Action: dashboards.ActionDashboardsDelete, Scope: dashboards.ScopeFoldersAll},
+	{Action: dashboards.ActionDashboardsPermissionsRead, Scope: dashboards.ScopeFoldersAll},
+	{Action: dashboards.ActionDashboardsPermissionsRead, Scope: dashboards.ScopeDashboardsAll},
+}
+
+// backgroundUser returns the identity that the provisioners use in the organization.
+func backgroundUser(orgID int64) identity.Requester {
+	return accesscontrol.BackgroundUser("access_provisioning", orgID, org.RoleAdmin, provisionerPermissions)
+}
+
+// permissionsService is the part of the resource permissions services that the provisioners use.
+type permissionsService interface {
+	GetPermissions(ctx context.Context, user identity.Requester, resourceID string) ([]accesscontrol.ResourcePermission, error)
+	SetPermissions(ctx context.Context, orgID int64, resourceID string, commands ...accesscontrol.SetResourcePermissionCommand) ([]accesscontrol.ResourcePermission, error)
+}
+
+// setPermissions sets the managed permissions of the resource to commands, and removes the managed permissions of
+// users, teams and basic roles that are not in commands.
+func setPermissions(ctx context.Context, service permissionsService, orgID int64, resourceID string, commands []accesscontrol.SetResourcePermissionCommand) error {
+	current, err := service.GetPermissions(ctx, backgroundUser(orgID), resourceID)
+	if err != nil {
+		return err
+	}
+
+	type assignment struct {
+		userID      int64
+		teamID      int64
+		builtinRole string
+	}
+	assigned := make(map[assignment]bool, len(commands)+len(current))
+	for _, cmd := range commands {
+		assigned[assignment{cmd.UserID, cmd.TeamID, cmd.BuiltinRole}] = true
+	}
+	for _, p := range current {
+		if !p.IsManaged || p.IsInherited {
+			continue
+		}
+		a := assignment{p.UserId, p.TeamId, p.BuiltInRole}
+		if assigned[a] {
+			continue
+		}
+		assigned[a] = true
+		commands = append(commands, accesscontrol.SetResourcePermissionCommand{
+			UserID:      p.UserId,
+			TeamID:      p.TeamId,
+			BuiltinRole: p.BuiltInRole,
+		})
+	}
+
+	if len(commands) == 0 {
+		return nil
+	}
+	_, err = service.SetPermissions(ctx, orgID, resourceID, commands...)
+	return err
+}
+
+// principalResolver looks up the users, teams and service accounts that permissions are granted to.
+type principalResolver struct {
+	userService           user.Service
+	teamService           team.Service
+	serviceAccountService serviceaccounts.Service
+}
+
+func newPrincipalResolver(userService user.Service, teamService team.Service, serviceAccountService serviceaccounts.Service) *principalResolver {
+	return &principalResolver{
+		userService:           userService,
+		teamService:           teamService,
+		serviceAccountService: serviceAccountService,
+	}
+}
+
+func (r *principalResolver) userID(ctx context.Context, login string) (int64, error) {
+	usr, err := r.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: login})
+	if err != nil {
+		return 0, fmt.Errorf("user %s: %w", login, err)
+	}
+	return usr.ID, nil
+}
+
+// findTeam returns the team with the name in the organization, or nil if there is none.
+func (r *principalResolver) findTeam(ctx context.Context, orgID int64, name string) (*team.TeamDTO, error) {
+	result, err := r.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{
+		OrgID:        orgID,
+		Name:         name,
+		Limit:        1,
+		Page:         1,
+		SignedInUser: backgroundUser(orgID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Teams) == 0 {
+		return nil, nil
+	}
+	return result.Teams[0], nil
+}
+
+func (r *principalResolver) teamID(ctx context.Context, orgID int64, name string) (int64, error) {
+	t, err := r.findTeam(ctx, orgID, name)
+	if err != nil {
+		return 0, fmt.Errorf("team %s: %w", name, err)
+	}
+	if t == nil {
+		return 0, fmt.Errorf("team %s: %w", name, team.ErrTeamNotFound)
+	}
+	return t.ID, nil
+}
+
+func (r *principalResolver) serviceAccountID(ctx context.Context, orgID int64, name string) (int64, error) {
+	id, err := r.serviceAccountService.RetrieveServiceAccountIdByName(ctx, orgID, name)
+	if err != nil {
+		return 0, fmt.Errorf("service account %s: %w", name, err)
+	}
+	return id, nil
+}
+
+// permissionCommands resolves the permissions of a folder or a dashboard to commands of the permissions services.
+func (r *principalResolver) permissionCommands(ctx context.Context, orgID int64, permissions []Permission) ([]accesscontrol.SetResourcePermissionCommand, error) {
+	commands := make([]accesscontrol.SetResourcePermissionCommand, 0, len(permissions))
+	for _, p := range permissions {
+		cmd := accesscontrol.SetResourcePermissionCommand{Permission: p.Permission}
+		var err error
+		switch {
+		case p.User != "":
+			cmd.UserID, err = r.userID(ctx, p.User)
+		case p.Team != "":
+			cmd.TeamID, err = r.teamID(ctx, orgID, p.Team)
+		case p.ServiceAccount != "":
+			cmd.UserID, err = r.serviceAccountID(ctx, orgID, p.ServiceAccount)
+		default:
+			cmd.BuiltinRole = p.Role
+		}
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, cmd)
+	}
+	return commands, nil
+}
@@ -0,0 +1,76 @@
+package access
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+)
+
+type fakePermissionsService struct {
+	current []accesscontrol.ResourcePermission
+	set     []accesscontrol.SetResourcePermissionCommand
+}
+
+func (s *fakePermissionsService) GetPermissions(ctx context.Context, user identity.Requester, resourceID string) ([]accesscontrol.ResourcePermission, error) {
+	return s.current, nil
+}
+
+func (s *fakePermissionsService) SetPermissions(ctx context.Context, orgID int64, resourceID string, commands ...accesscontrol.SetResourcePermissionCommand) ([]accesscontrol.ResourcePermission, error) {
+	s.set = append(s.set, commands...)
+	return nil, nil
+}
+
+func TestSetPermissions(t *testing.T) {
+	t.Run("should remove managed permissions that are not provisioned", func(t *testing.T) {
+		svc := &fakePermissionsService{current: []accesscontrol.ResourcePermission{
+			{UserId: 1, IsManaged: true},
+			{TeamId: 2, IsManaged: true},
+			{BuiltInRole: "Viewer", IsManaged: true},
+			{BuiltInRole: "Editor", IsManaged: true, IsInherited: true},
+			{BuiltInRole: "Admin"},
+		}}
+		err := setPermissions(context.Background(), svc, 1, "uid", []accesscontrol.SetResourcePermissionCommand{
+			{TeamID: 2, Permission: "Edit"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []accesscontrol.SetResourcePermissionCommand{
+			{TeamID: 2, Permission: "Edit"},
+			{UserID: 1},
+			{BuiltinRole: "Viewer"},
+		}, svc.set)
+	})
+	t.Run("should not set permissions when there are none", func(t *testing.T) {
+		svc := &fakePermissionsService{}
+		require.NoError(t, setPermissions(context.Background(), svc, 1, "uid", nil))
+		require.Nil(t, svc.set)
+	})
+}
+
+func TestSortFolders(t *testing.T) {
+	t.Run("parents should come before their subfolders", func(t *testing.T) {
+		sorted, err := sortFolders([]Folder{
+			{OrgID: 1, UID: "c", ParentUID: "b"},
+			{OrgID: 1, UID: "b", ParentUID: "a"},
+			{OrgID: 1, UID: "a"},
+			{OrgID: 2, UID: "c", ParentUID: "existing"},
+		})
+		require.NoError(t, err)
+		uids := make([]string, 0, len(sorted))
+		for _, f := range sorted {
+			uids = append(uids, f.UID)
+		}
+		require.Equal(t, []string{"a", "b", "c", "c"}, uids)
+		require.Equal(t, int64(2), sorted[3].OrgID)
+	})
+	t.Run("a folder that is its own ancestor should error", func(t *testing.T) {
+		_, err := sortFolders([]Folder{
+			{OrgID: 1, UID: "a", ParentUID: "b"},
+			{OrgID: 1, UID: "b", ParentUID: "a"},
+		})
+		require.Error(t, err)
+	})
+}
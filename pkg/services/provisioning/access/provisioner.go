@@ -0,0 +1,102 @@
+package access
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type ProvisionerConfig struct {
+	Path                        string
+	UserService                 user.Service
+	OrgService                  org.Service
+	TeamService                 team.Service
+	TeamPermissionsService      accesscontrol.TeamPermissionsService
+	AccessControlService        accesscontrol.Service
+	ServiceAccountService       serviceaccounts.Service
+	FolderService               folder.Service
+	FolderPermissionsService    accesscontrol.FolderPermissionsService
+	DashboardPermissionsService accesscontrol.DashboardPermissionsService
+	ProvenanceService           provenance.Service
+}
+
+// Provision provisions the users, service accounts, teams and folders, and removes the ones in the delete sections.
+// The permissions of dashboards are provisioned by ProvisionDashboardPermissions, because the dashboards are usually
+// provisioned after this.
+func Provision(ctx context.Context, cfg ProvisionerConfig) error {
+	logger := log.New("provisioning.access")
+	cfgReader := newConfigReader(logger)
+	files, err := cfgReader.readConfig(ctx, cfg.Path)
+	if err != nil {
+		return err
+	}
+	logger.Info("starting to provision access")
+	logger.Debug("read all access files", "file_count", len(files))
+	resolver := newPrincipalResolver(cfg.UserService, cfg.TeamService, cfg.ServiceAccountService)
+	userProvisioner := NewUserProvisioner(logger, cfg.UserService, cfg.OrgService, cfg.TeamService, cfg.AccessControlService, cfg.ProvenanceService)
+	err = userProvisioner.Provision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("users: %w", err)
+	}
+	saProvisioner := NewServiceAccountProvisioner(logger, cfg.ServiceAccountService, cfg.ProvenanceService)
+	err = saProvisioner.Provision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("service accounts: %w", err)
+	}
+	teamProvisioner := NewTeamProvisioner(logger, cfg.TeamService, cfg.TeamPermissionsService, cfg.AccessControlService, cfg.ProvenanceService, resolver)
+	err = teamProvisioner.Provision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("teams: %w", err)
+	}
+	folderProvisioner := NewFolderProvisioner(logger, cfg.FolderService, cfg.FolderPermissionsService, cfg.ProvenanceService, resolver)
+	err = folderProvisioner.Provision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("folders: %w", err)
+	}
+	// Remove the folders before the teams, users and service accounts that might have permissions on them.
+	err = folderProvisioner.Unprovision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("folders: %w", err)
+	}
+	err = teamProvisioner.Unprovision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("teams: %w", err)
+	}
+	err = saProvisioner.Unprovision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("service accounts: %w", err)
+	}
+	err = userProvisioner.Unprovision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("users: %w", err)
+	}
+	logger.Info("finished to provision access")
+	return nil
+}
+
+// ProvisionDashboardPermissions provisions the permissions of dashboards.
+func ProvisionDashboardPermissions(ctx context.Context, cfg ProvisionerConfig) error {
+	logger := log.New("provisioning.access")
+	cfgReader := newConfigReader(logger)
+	files, err := cfgReader.readConfig(ctx, cfg.Path)
+	if err != nil {
+		return err
+	}
+	resolver := newPrincipalResolver(cfg.UserService, cfg.TeamService, cfg.ServiceAccountService)
+	dpProvisioner := NewDashboardPermissionsProvisioner(logger, cfg.DashboardPermissionsService, cfg.ProvenanceService, resolver)
+	if err := dpProvisioner.Provision(ctx, files); err != nil {
+		return fmt.Errorf("dashboard permissions: %w", err)
+	}
+	if err := dpProvisioner.Unprovision(ctx, files); err != nil {
+		return fmt.Errorf("dashboard permissions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+)
+
+type ServiceAccountProvisioner interface {
+	Provision(ctx context.Context, files []*AccessFile) error
+	Unprovision(ctx context.Context, files []*AccessFile) error
+}
+
+type defaultServiceAccountProvisioner struct {
+	logger                log.Logger
+	serviceAccountService serviceaccounts.Service
+	provenanceService     provenance.Service
+}
+
+func NewServiceAccountProvisioner(logger log.Logger,
+	serviceAccountService serviceaccounts.Service,
+	provenanceService provenance.Service) ServiceAccountProvisioner {
+	return &defaultServiceAccountProvisioner{
+		logger:                logger,
+		serviceAccountService: serviceAccountService,
+		provenanceService:     provenanceService,
+	}
+}
+
+func (p *defaultServiceAccountProvisioner) Provision(ctx context.Context, files []*AccessFile) error {
+	for _, file := range files {
+		for _, sa := range file.ServiceAccounts {
+			id, err := p.provisionServiceAccount(ctx, sa)
+			if err != nil {
+				return err
+			}
+			err = p.provenanceService.SetProvenance(ctx, sa.OrgID, provenance.KindServiceAccount, strconv.FormatInt(id, 10), provenance.ProvenanceFile)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *defaultServiceAccountProvisioner) provisionServiceAccount(ctx context.Context, sa ServiceAccount) (int64, error) {
+	id, err := p.serviceAccountService.RetrieveServiceAccountIdByName(ctx, sa.OrgID, sa.Name)
+	if errors.Is(err, serviceaccounts.ErrServiceAccountNotFound) {
+		created, err := p.serviceAccountService.CreateServiceAccount(ctx, sa.OrgID, &serviceaccounts.CreateServiceAccountForm{
+			Name:       sa.Name,
+			Role:       &sa.Role,
+			IsDisabled: &sa.IsDisabled,
+		})
+		if err != nil {
+			return 0, err
+		}
+		p.logger.Debug("created service account", "name", sa.Name, "org", sa.OrgID, "serviceAccountId", created.Id)
+		return created.Id, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = p.serviceAccountService.UpdateServiceAccount(ctx, sa.OrgID, id, &serviceaccounts.UpdateServiceAccountForm{
+		Name:             &sa.Name,
+		ServiceAccountID: id,
+		Role:             &sa.Role,
+		IsDisabled:       &sa.IsDisabled,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (p *defaultServiceAccountProvisioner) Unprovision(ctx context.Context, files []*AccessFile) error {
+	for _, file := range files {
+		for _, deleteSA := range file.DeleteServiceAccounts {
+			id, err := p.serviceAccountService.RetrieveServiceAccountIdByName(ctx, deleteSA.OrgID, deleteSA.Name)
+			if errors.Is(err, serviceaccounts.ErrServiceAccountNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := p.serviceAccountService.DeleteServiceAccount(ctx, deleteSA.OrgID, id); err != nil {
+				return err
+			}
+			err = p.provenanceService.DeleteProvenance(ctx, deleteSA.OrgID, provenance.KindServiceAccount, strconv.FormatInt(id, 10))
+			if err != nil {
+				return err
+			}
+			p.logger.Debug("deleted service account", "name", deleteSA.Name, "org", deleteSA.OrgID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package access
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+type ServiceAccountV1 struct {
+	OrgID    values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name     values.StringValue `json:"name" yaml:"name"`
+	Role     values.StringValue `json:"role" yaml:"role"`
+	Disabled values.BoolValue   `json:"disabled" yaml:"disabled"`
+}
+
+func (v1 *ServiceAccountV1) mapToModel() (ServiceAccount, error) {
+	name := strings.TrimSpace(v1.Name.Value())
+	if name == "" {
+		return ServiceAccount{}, errors.New("service account missing name")
+	}
+	role := org.RoleType(strings.TrimSpace(v1.Role.Value()))
+	if role == "" {
+		role = org.RoleViewer
+	}
+	if !role.IsValid() {
+		return ServiceAccount{}, fmt.Errorf("service account %s has invalid role %q", name, role)
+	}
+	return ServiceAccount{
+		OrgID:      orgIDOrDefault(v1.OrgID),
+		Name:       name,
+		Role:       role,
+		IsDisabled: v1.Disabled.Value(),
+	}, nil
+}
+
+type ServiceAccount struct {
+	OrgID      int64
+	Name       string
+	Role       org.RoleType
+	IsDisabled bool
+}
+
+type DeleteServiceAccountV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name  values.StringValue `json:"name" yaml:"name"`
+}
+
+func (v1 *DeleteServiceAccountV1) mapToModel() (DeleteServiceAccount, error) {
+	name := strings.TrimSpace(v1.Name.Value())
+	if name == "" {
+		return DeleteServiceAccount{}, errors.New("delete service account missing name")
+	}
+	return DeleteServiceAccount{OrgID: orgIDOrDefault(v1.OrgID), Name: name}, nil
+}
+
+type DeleteServiceAccount struct {
+	OrgID int64
+	Name  string
+}
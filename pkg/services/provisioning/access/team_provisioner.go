@@ -0,0 +1,125 @@
+package access
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	"github.com/grafana/grafana/pkg/services/team"
+)
+
+type TeamProvisioner interface {
+	Provision(ctx context.Context, files []*AccessFile) error
+	Unprovision(ctx context.Context, files []*AccessFile) error
+}
+
+type defaultTeamProvisioner struct {
+	logger                 log.Logger
+	teamService            team.Service
+	teamPermissionsService accesscontrol.TeamPermissionsService
+	accessControlService   accesscontrol.Service
+	provenanceService      provenance.Service
+	resolver               *principalResolver
+}
+
+func NewTeamProvisioner(logger log.Logger,
+	teamService team.Service,
+	teamPermissionsService accesscontrol.TeamPermissionsService,
+	accessControlService accesscontrol.Service,
+	provenanceService provenance.Service,
+	resolver *principalResolver) TeamProvisioner {
+	return &defaultTeamProvisioner{
+		logger:                 logger,
+		teamService:            teamService,
+		teamPermissionsService: teamPermissionsService,
+		accessControlService:   accessControlService,
+		provenanceService:      provenanceService,
+		resolver:               resolver,
+	}
+}
+
+func (p *defaultTeamProvisioner) Provision(ctx context.Context, files []*AccessFile) error {
+	for _, file := range files {
+		for _, t := range file.Teams {
+			teamID, err := p.provisionTeam(ctx, t)
+			if err != nil {
+				return err
+			}
+			if err := p.provisionMembers(ctx, t, teamID); err != nil {
+				return err
+			}
+			err = p.provenanceService.SetProvenance(ctx, t.OrgID, provenance.KindTeam, strconv.FormatInt(teamID, 10), provenance.ProvenanceFile)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *defaultTeamProvisioner) provisionTeam(ctx context.Context, t Team) (int64, error) {
+	existing, err := p.resolver.findTeam(ctx, t.OrgID, t.Name)
+	if err != nil {
+		return 0, err
+	}
+	if existing == nil {
+		created, err := p.teamService.CreateTeam(ctx, t.Name, t.Email, t.OrgID)
+		if err != nil {
+			return 0, err
+		}
+		p.logger.Debug("created team", "name", t.Name, "org", t.OrgID, "teamId", created.ID)
+		return created.ID, nil
+	}
+
+	err = p.teamService.UpdateTeam(ctx, &team.UpdateTeamCommand{
+		ID:    existing.ID,
+		Name:  t.Name,
+		Email: t.Email,
+		OrgID: t.OrgID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return existing.ID, nil
+}
+
+// provisionMembers sets the members of the team, which are the users with a permission on the team.
+func (p *defaultTeamProvisioner) provisionMembers(ctx context.Context, t Team, teamID int64) error {
+	commands := make([]accesscontrol.SetResourcePermissionCommand, 0, len(t.Members))
+	for _, member := range t.Members {
+		userID, err := p.resolver.userID(ctx, member.Login)
+		if err != nil {
+			return err
+		}
+		commands = append(commands, accesscontrol.SetResourcePermissionCommand{UserID: userID, Permission: member.Permission})
+	}
+	return setPermissions(ctx, p.teamPermissionsService, t.OrgID, strconv.FormatInt(teamID, 10), commands)
+}
+
+func (p *defaultTeamProvisioner) Unprovision(ctx context.Context, files []*AccessFile) error {
+	for _, file := range files {
+		for _, deleteTeam := range file.DeleteTeams {
+			existing, err := p.resolver.findTeam(ctx, deleteTeam.OrgID, deleteTeam.Name)
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				continue
+			}
+			if err := p.teamService.DeleteTeam(ctx, &team.DeleteTeamCommand{OrgID: deleteTeam.OrgID, ID: existing.ID}); err != nil {
+				return err
+			}
+			if err := p.accessControlService.DeleteTeamPermissions(ctx, deleteTeam.OrgID, existing.ID); err != nil {
+				return err
+			}
+			err = p.provenanceService.DeleteProvenance(ctx, deleteTeam.OrgID, provenance.KindTeam, strconv.FormatInt(existing.ID, 10))
+			if err != nil {
+				return err
+			}
+			p.logger.Debug("deleted team", "name", deleteTeam.Name, "org", deleteTeam.OrgID)
+		}
+	}
+	return nil
+}
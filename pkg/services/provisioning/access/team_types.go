@@ -0,0 +1,85 @@
+package access
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+const (
+	teamPermissionMember = "Member"
+	teamPermissionAdmin  = "Admin"
+)
+
+type TeamV1 struct {
+	OrgID   values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name    values.StringValue `json:"name" yaml:"name"`
+	Email   values.StringValue `json:"email" yaml:"email"`
+	Members []TeamMemberV1     `json:"members" yaml:"members"`
+}
+
+type TeamMemberV1 struct {
+	// Login is the login or the email of the user.
+	Login      values.StringValue `json:"login" yaml:"login"`
+	Permission values.StringValue `json:"permission" yaml:"permission"`
+}
+
+func (v1 *TeamV1) mapToModel() (Team, error) {
+	name := strings.TrimSpace(v1.Name.Value())
+	if name == "" {
+		return Team{}, errors.New("team missing name")
+	}
+	t := Team{
+		OrgID: orgIDOrDefault(v1.OrgID),
+		Name:  name,
+		Email: strings.TrimSpace(v1.Email.Value()),
+	}
+	for _, memberV1 := range v1.Members {
+		login := strings.TrimSpace(memberV1.Login.Value())
+		if login == "" {
+			return Team{}, fmt.Errorf("member of team %s missing login", name)
+		}
+		permission := strings.TrimSpace(memberV1.Permission.Value())
+		if permission == "" {
+			permission = teamPermissionMember
+		}
+		if permission != teamPermissionMember && permission != teamPermissionAdmin {
+			return Team{}, fmt.Errorf("member %s of team %s has invalid permission %q, must be %s or %s", login, name, permission, teamPermissionMember, teamPermissionAdmin)
+		}
+		t.Members = append(t.Members, TeamMember{Login: login, Permission: permission})
+	}
+	return t, nil
+}
+
+type Team struct {
+	OrgID int64
+	Name  string
+	Email string
+	// Members are all members of the team. Members that are not in the list are removed from the team.
+	Members []TeamMember
+}
+
+type TeamMember struct {
+	Login      string
+	Permission string
+}
+
+type DeleteTeamV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name  values.StringValue `json:"name" yaml:"name"`
+}
+
+func (v1 *DeleteTeamV1) mapToModel() (DeleteTeam, error) {
+	name := strings.TrimSpace(v1.Name.Value())
+	if name == "" {
+		return DeleteTeam{}, errors.New("delete team missing name")
+	}
+	return DeleteTeam{OrgID: orgIDOrDefault(v1.OrgID), Name: name}, nil
+}
+
+type DeleteTeam struct {
+	OrgID int64
+	Name  string
+}
@@ -0,0 +1,161 @@
+package access
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+type configVersion struct {
+	APIVersion values.Int64Value `json:"apiVersion" yaml:"apiVersion"`
+}
+
+type AccessFile struct {
+	configVersion
+	Filename                   string
+	Users                      []User
+	DeleteUsers                []DeleteUser
+	Teams                      []Team
+	DeleteTeams                []DeleteTeam
+	ServiceAccounts            []ServiceAccount
+	DeleteServiceAccounts      []DeleteServiceAccount
+	Folders                    []Folder
+	DeleteFolders              []DeleteFolder
+	DashboardPermissions       []DashboardPermissions
+	DeleteDashboardPermissions []DeleteDashboardPermissions
+}
+
+type AccessFileV1 struct {
+	configVersion
+	Filename                   string
+	Users                      []UserV1                       `json:"users" yaml:"users"`
+	DeleteUsers                []DeleteUserV1                 `json:"deleteUsers" yaml:"deleteUsers"`
+	Teams                      []TeamV1                       `json:"teams" yaml:"teams"`
+	DeleteTeams                []DeleteTeamV1                 `json:"deleteTeams" yaml:"deleteTeams"`
+	ServiceAccounts            []ServiceAccountV1             `json:"serviceAccounts" yaml:"serviceAccounts"`
+	DeleteServiceAccounts      []DeleteServiceAccountV1       `json:"deleteServiceAccounts" yaml:"deleteServiceAccounts"`
+	Folders                    []FolderV1                     `json:"folders" yaml:"folders"`
+	DeleteFolders              []DeleteFolderV1               `json:"deleteFolders" yaml:"deleteFolders"`
+	DashboardPermissions       []DashboardPermissionsV1       `json:"dashboardPermissions" yaml:"dashboardPermissions"`
+	DeleteDashboardPermissions []DeleteDashboardPermissionsV1 `json:"deleteDashboardPermissions" yaml:"deleteDashboardPermissions"`
+}
+
+func (fileV1 *AccessFileV1) MapToModel() (AccessFile, error) {
+	accessFile := AccessFile{}
+	accessFile.Filename = fileV1.Filename
+	if err := fileV1.mapUsers(&accessFile); err != nil {
+		return AccessFile{}, fmt.Errorf("failure parsing users: %w", err)
+	}
+	if err := fileV1.mapTeams(&accessFile); err != nil {
+		return AccessFile{}, fmt.Errorf("failure parsing teams: %w", err)
+	}
+	if err := fileV1.mapServiceAccounts(&accessFile); err != nil {
+		return AccessFile{}, fmt.Errorf("failure parsing service accounts: %w", err)
+	}
+	if err := fileV1.mapFolders(&accessFile); err != nil {
+		return AccessFile{}, fmt.Errorf("failure parsing folders: %w", err)
+	}
+	if err := fileV1.mapDashboardPermissions(&accessFile); err != nil {
+		return AccessFile{}, fmt.Errorf("failure parsing dashboard permissions: %w", err)
+	}
+	return accessFile, nil
+}
+
+func (fileV1 *AccessFileV1) mapUsers(accessFile *AccessFile) error {
+	for _, userV1 := range fileV1.Users {
+		u, err := userV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		accessFile.Users = append(accessFile.Users, u)
+	}
+	for _, deleteV1 := range fileV1.DeleteUsers {
+		delReq, err := deleteV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		accessFile.DeleteUsers = append(accessFile.DeleteUsers, delReq)
+	}
+	return nil
+}
+
+func (fileV1 *AccessFileV1) mapTeams(accessFile *AccessFile) error {
+	for _, teamV1 := range fileV1.Teams {
+		t, err := teamV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		accessFile.Teams = append(accessFile.Teams, t)
+	}
+	for _, deleteV1 := range fileV1.DeleteTeams {
+		delReq, err := deleteV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		accessFile.DeleteTeams = append(accessFile.DeleteTeams, delReq)
+	}
+	return nil
+}
+
+func (fileV1 *AccessFileV1) mapServiceAccounts(accessFile *AccessFile) error {
+	for _, saV1 := range fileV1.ServiceAccounts {
+		sa, err := saV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		accessFile.ServiceAccounts = append(accessFile.ServiceAccounts, sa)
+	}
+	for _, deleteV1 := range fileV1.DeleteServiceAccounts {
+		delReq, err := deleteV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		accessFile.DeleteServiceAccounts = append(accessFile.DeleteServiceAccounts, delReq)
+	}
+	return nil
+}
+
+func (fileV1 *AccessFileV1) mapFolders(accessFile *AccessFile) error {
+	for _, folderV1 := range fileV1.Folders {
+		f, err := folderV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		accessFile.Folders = append(accessFile.Folders, f)
+	}
+	for _, deleteV1 := range fileV1.DeleteFolders {
+		delReq, err := deleteV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		accessFile.DeleteFolders = append(accessFile.DeleteFolders, delReq)
+	}
+	return nil
+}
+
+func (fileV1 *AccessFileV1) mapDashboardPermissions(accessFile *AccessFile) error {
+	for _, dpV1 := range fileV1.DashboardPermissions {
+		dp, err := dpV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		accessFile.DashboardPermissions = append(accessFile.DashboardPermissions, dp)
+	}
+	for _, deleteV1 := range fileV1.DeleteDashboardPermissions {
+		delReq, err := deleteV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		accessFile.DeleteDashboardPermissions = append(accessFile.DeleteDashboardPermissions, delReq)
+	}
+	return nil
+}
+
+// orgIDOrDefault returns the organization of the resource, which is the main organization if it is not set.
+func orgIDOrDefault(v values.Int64Value) int64 {
+	orgID := v.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	return orgID
+}
@@ -0,0 +1,153 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type UserProvisioner interface {
+	Provision(ctx context.Context, files []*AccessFile) error
+	Unprovision(ctx context.Context, files []*AccessFile) error
+}
+
+type defaultUserProvisioner struct {
+	logger               log.Logger
+	userService          user.Service
+	orgService           org.Service
+	teamService          team.Service
+	accessControlService accesscontrol.Service
+	provenanceService    provenance.Service
+}
+
+func NewUserProvisioner(logger log.Logger,
+	userService user.Service,
+	orgService org.Service,
+	teamService team.Service,
+	accessControlService accesscontrol.Service,
+	provenanceService provenance.Service) UserProvisioner {
+	return &defaultUserProvisioner{
+		logger:               logger,
+		userService:          userService,
+		orgService:           orgService,
+		teamService:          teamService,
+		accessControlService: accessControlService,
+		provenanceService:    provenanceService,
+	}
+}
+
+func (p *defaultUserProvisioner) Provision(ctx context.Context, files []*AccessFile) error {
+	for _, file := range files {
+		for _, u := range file.Users {
+			userID, err := p.provisionUser(ctx, u)
+			if err != nil {
+				return err
+			}
+			for _, userOrg := range u.Orgs {
+				if err := p.provisionOrgUser(ctx, userID, u.Login, userOrg); err != nil {
+					return err
+				}
+			}
+			err = p.provenanceService.SetProvenance(ctx, provenance.GlobalOrgID, provenance.KindUser, strconv.FormatInt(userID, 10), provenance.ProvenanceFile)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *defaultUserProvisioner) provisionUser(ctx context.Context, u User) (int64, error) {
+	existing, err := p.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: u.Login})
+	if errors.Is(err, user.ErrUserNotFound) {
+		created, err := p.userService.Create(ctx, &user.CreateUserCommand{
+			Login:      u.Login,
+			Email:      u.Email,
+			Name:       u.Name,
+			Password:   user.Password(u.Password),
+			IsAdmin:    u.IsGrafanaAdmin,
+			IsDisabled: u.IsDisabled,
+		})
+		if err != nil {
+			return 0, err
+		}
+		p.logger.Debug("created user", "login", u.Login, "userId", created.ID)
+		return created.ID, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	err = p.userService.Update(ctx, &user.UpdateUserCommand{
+		UserID:         existing.ID,
+		Login:          u.Login,
+		Email:          u.Email,
+		Name:           u.Name,
+		IsGrafanaAdmin: &u.IsGrafanaAdmin,
+		IsDisabled:     &u.IsDisabled,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return existing.ID, nil
+}
+
+func (p *defaultUserProvisioner) provisionOrgUser(ctx context.Context, userID int64, login string, userOrg UserOrg) error {
+	err := p.orgService.AddOrgUser(ctx, &org.AddOrgUserCommand{
+		LoginOrEmail: login,
+		Role:         userOrg.Role,
+		OrgID:        userOrg.OrgID,
+		UserID:       userID,
+	})
+	if errors.Is(err, org.ErrOrgUserAlreadyAdded) {
+		return p.orgService.UpdateOrgUser(ctx, &org.UpdateOrgUserCommand{
+			Role:   userOrg.Role,
+			OrgID:  userOrg.OrgID,
+			UserID: userID,
+		})
+	}
+	return err
+}
+
+func (p *defaultUserProvisioner) Unprovision(ctx context.Context, files []*AccessFile) error {
+	for _, file := range files {
+		for _, deleteUser := range file.DeleteUsers {
+			existing, err := p.userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: deleteUser.Login})
+			if errors.Is(err, user.ErrUserNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := p.deleteUser(ctx, existing.ID); err != nil {
+				return err
+			}
+			p.logger.Debug("deleted user", "login", deleteUser.Login, "userId", existing.ID)
+		}
+	}
+	return nil
+}
+
+// deleteUser deletes the user with its organization and team memberships and its permissions.
+func (p *defaultUserProvisioner) deleteUser(ctx context.Context, userID int64) error {
+	if err := p.userService.Delete(ctx, &user.DeleteUserCommand{UserID: userID}); err != nil {
+		return err
+	}
+	if err := p.orgService.DeleteUserFromAll(ctx, userID); err != nil {
+		return err
+	}
+	if err := p.teamService.RemoveUsersMemberships(ctx, userID); err != nil {
+		return err
+	}
+	if err := p.accessControlService.DeleteUserPermissions(ctx, accesscontrol.GlobalOrgID, userID); err != nil {
+		return err
+	}
+	return p.provenanceService.DeleteProvenance(ctx, provenance.GlobalOrgID, provenance.KindUser, strconv.FormatInt(userID, 10))
+}
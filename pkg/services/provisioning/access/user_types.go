@@ -0,0 +1,83 @@
+package access
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+type UserV1 struct {
+	Login          values.StringValue `json:"login" yaml:"login"`
+	Email          values.StringValue `json:"email" yaml:"email"`
+	Name           values.StringValue `json:"name" yaml:"name"`
+	Password       values.StringValue `json:"password" yaml:"password"`
+	IsGrafanaAdmin values.BoolValue   `json:"isGrafanaAdmin" yaml:"isGrafanaAdmin"`
+	Disabled       values.BoolValue   `json:"disabled" yaml:"disabled"`
+	Orgs           []UserOrgV1        `json:"orgs" yaml:"orgs"`
+}
+
+type UserOrgV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Role  values.StringValue `json:"role" yaml:"role"`
+}
+
+func (v1 *UserV1) mapToModel() (User, error) {
+	login := strings.TrimSpace(v1.Login.Value())
+	if login == "" {
+		return User{}, errors.New("user missing login")
+	}
+	u := User{
+		Login:          login,
+		Email:          strings.TrimSpace(v1.Email.Value()),
+		Name:           strings.TrimSpace(v1.Name.Value()),
+		Password:       v1.Password.Value(),
+		IsGrafanaAdmin: v1.IsGrafanaAdmin.Value(),
+		IsDisabled:     v1.Disabled.Value(),
+	}
+	for _, orgV1 := range v1.Orgs {
+		role := org.RoleType(strings.TrimSpace(orgV1.Role.Value()))
+		if !role.IsValid() {
+			return User{}, fmt.Errorf("user %s has invalid role %q", login, role)
+		}
+		u.Orgs = append(u.Orgs, UserOrg{
+			OrgID: orgIDOrDefault(orgV1.OrgID),
+			Role:  role,
+		})
+	}
+	return u, nil
+}
+
+type User struct {
+	Login string
+	Email string
+	Name  string
+	// Password is only set when the user is created, so that users can change it afterwards.
+	Password       string
+	IsGrafanaAdmin bool
+	IsDisabled     bool
+	Orgs           []UserOrg
+}
+
+type UserOrg struct {
+	OrgID int64
+	Role  org.RoleType
+}
+
+type DeleteUserV1 struct {
+	Login values.StringValue `json:"login" yaml:"login"`
+}
+
+func (v1 *DeleteUserV1) mapToModel() (DeleteUser, error) {
+	login := strings.TrimSpace(v1.Login.Value())
+	if login == "" {
+		return DeleteUser{}, errors.New("delete user missing login")
+	}
+	return DeleteUser{Login: login}, nil
+}
+
+type DeleteUser struct {
+	Login string
+}
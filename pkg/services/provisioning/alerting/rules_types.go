@@ -77,6 +77,7 @@ type AlertRuleV1 struct {
 	IsPaused             values.BoolValue        `json:"isPaused" yaml:"isPaused"`
 	NotificationSettings *NotificationSettingsV1 `json:"notification_settings" yaml:"notification_settings"`
 	Record               *RecordV1               `json:"record" yaml:"record"`
+	SuppressedBy         []SuppressingRuleV1     `json:"suppressed_by" yaml:"suppressed_by"`
 }
 
 func withFallback(value, fallback string) *string {
@@ -162,6 +163,9 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		}
 		alertRule.Record = &record
 	}
+	for _, srV1 := range rule.SuppressedBy {
+		alertRule.SuppressedBy = append(alertRule.SuppressedBy, srV1.mapToModel())
+	}
 	return alertRule, nil
 }
 
@@ -281,3 +285,22 @@ func (record *RecordV1) mapToModel() (models.Record, error) {
 		From:   record.From.Value(),
 	}, nil
 }
+
+type SuppressingRuleV1 struct {
+	RuleUID values.StringValue   `json:"rule_uid" yaml:"rule_uid"`
+	Equal   []values.StringValue `json:"equal" yaml:"equal"`
+}
+
+func (srV1 *SuppressingRuleV1) mapToModel() models.SuppressingRule {
+	var equal []string
+	for _, value := range srV1.Equal {
+		if value.Value() == "" {
+			continue
+		}
+		equal = append(equal, value.Value())
+	}
+	return models.SuppressingRule{
+		RuleUID: srV1.RuleUID.Value(),
+		Equal:   equal,
+	}
+}
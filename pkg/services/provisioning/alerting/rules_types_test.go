@@ -200,6 +200,19 @@ func TestRules(t *testing.T) {
 		require.Len(t, ruleMapped.NotificationSettings, 1)
 		require.Equal(t, models.NotificationSettings{Receiver: "test-receiver"}, ruleMapped.NotificationSettings[0])
 	})
+	t.Run("a rule with suppressing rules should map them correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.SuppressedBy = []SuppressingRuleV1{
+			{RuleUID: stringToStringValue("node-down"), Equal: []values.StringValue{stringToStringValue("instance")}},
+			{RuleUID: stringToStringValue("maintenance")},
+		}
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, []models.SuppressingRule{
+			{RuleUID: "node-down", Equal: []string{"instance"}},
+			{RuleUID: "maintenance"},
+		}, ruleMapped.SuppressedBy)
+	})
 }
 
 func TestNotificationsSettingsV1MapToModel(t *testing.T) {
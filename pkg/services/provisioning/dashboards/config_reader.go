@@ -17,6 +17,7 @@ import (
 
 type configReader struct {
 	path       string
+	dataPath   string
 	log        log.Logger
 	orgService org.Service
 }
@@ -103,6 +104,7 @@ func (cr *configReader) readConfig(ctx context.Context) ([]*config, error) {
 		if dashboard.Type == "" {
 			dashboard.Type = "file"
 		}
+		dashboard.dataPath = cr.dataPath
 
 		if dashboard.UpdateIntervalSeconds == 0 {
 			dashboard.UpdateIntervalSeconds = 10
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/folder"
@@ -21,10 +22,11 @@ type DashboardProvisioner interface {
 	GetProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
 	CleanUpOrphanedDashboards(ctx context.Context)
+	PushDashboardFromUI(ctx context.Context, provisioning *dashboards.DashboardProvisioning, dash *dashboards.Dashboard, message string, user identity.Requester) error
 }
 
 // DashboardProvisionerFactory creates DashboardProvisioners based on input
-type DashboardProvisionerFactory func(context.Context, string, dashboards.DashboardProvisioningService, org.Service, utils.DashboardStore, folder.Service) (DashboardProvisioner, error)
+type DashboardProvisionerFactory func(context.Context, string, string, dashboards.DashboardProvisioningService, org.Service, utils.DashboardStore, folder.Service) (DashboardProvisioner, error)
 
 // Provisioner is responsible for syncing dashboard from disk to Grafana's database.
 type Provisioner struct {
@@ -39,10 +41,11 @@ func (provider *Provisioner) HasDashboardSources() bool {
 	return len(provider.fileReaders) > 0
 }
 
-// New returns a new DashboardProvisioner
-func New(ctx context.Context, configDirectory string, provisioner dashboards.DashboardProvisioningService, orgService org.Service, dashboardStore utils.DashboardStore, folderService folder.Service) (DashboardProvisioner, error) {
+// New returns a new DashboardProvisioner. Providers keep their state, such as the checkouts of git providers, in
+// the data directory.
+func New(ctx context.Context, configDirectory, dataPath string, provisioner dashboards.DashboardProvisioningService, orgService org.Service, dashboardStore utils.DashboardStore, folderService folder.Service) (DashboardProvisioner, error) {
 	logger := log.New("provisioning.dashboard")
-	cfgReader := &configReader{path: configDirectory, log: logger, orgService: orgService}
+	cfgReader := &configReader{path: configDirectory, dataPath: dataPath, log: logger, orgService: orgService}
 	configs, err := cfgReader.readConfig(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%v: %w", "Failed to read dashboards config", err)
@@ -130,6 +133,21 @@ func (provider *Provisioner) GetAllowUIUpdatesFromConfig(name string) bool {
 	return false
 }
 
+// PushDashboardFromUI writes a provisioned dashboard that was saved from the UI back to the source of its provider.
+// Only git providers with the pushUiUpdates option write dashboards back, by pushing a commit to their branch. For all
+// other providers it does nothing.
+func (provider *Provisioner) PushDashboardFromUI(ctx context.Context, provisioning *dashboards.DashboardProvisioning, dash *dashboards.Dashboard, message string, user identity.Requester) error {
+	for _, reader := range provider.fileReaders {
+		if reader.Cfg.Name == provisioning.Name {
+			if reader.repo == nil || !reader.repo.push {
+				return nil
+			}
+			return reader.pushDashboard(ctx, provisioning.ExternalID, dash, message, user)
+		}
+	}
+	return nil
+}
+
 func getFileReaders(
 	configs []*config,
 	logger log.Logger,
@@ -153,6 +171,18 @@ func getFileReaders(
 				return nil, fmt.Errorf("failed to create file reader for config %v: %w", config.Name, err)
 			}
 			readers = append(readers, fileReader)
+		case "git":
+			gitReader, err := NewGitDashboardFileReader(
+				config,
+				logger.New("type", config.Type, "name", config.Name),
+				service,
+				store,
+				folderService,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create git reader for config %v: %w", config.Name, err)
+			}
+			readers = append(readers, gitReader)
 		default:
 			return nil, fmt.Errorf("type %s is not supported", config.Type)
 		}
@@ -1,6 +1,11 @@
 package dashboards
 
-import "context"
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
 
 // Calls is a mock implementation of the provisioner interface
 type calls struct {
@@ -8,6 +13,7 @@ type calls struct {
 	PollChanges                 []any
 	GetProvisionerResolvedPath  []any
 	GetAllowUIUpdatesFromConfig []any
+	PushDashboardFromUI         []any
 }
 
 // ProvisionerMock is a mock implementation of `Provisioner`
@@ -17,6 +23,7 @@ type ProvisionerMock struct {
 	PollChangesFunc                 func(ctx context.Context)
 	GetProvisionerResolvedPathFunc  func(name string) string
 	GetAllowUIUpdatesFromConfigFunc func(name string) bool
+	PushDashboardFromUIFunc         func(ctx context.Context, provisioning *dashboards.DashboardProvisioning, dash *dashboards.Dashboard, message string, user identity.Requester) error
 }
 
 // NewDashboardProvisionerMock returns a new dashboardprovisionermock
@@ -67,3 +74,12 @@ func (dpm *ProvisionerMock) GetAllowUIUpdatesFromConfig(name string) bool {
 
 // CleanUpOrphanedDashboards not implemented for mocks
 func (dpm *ProvisionerMock) CleanUpOrphanedDashboards(ctx context.Context) {}
+
+// PushDashboardFromUI is a mock implementation of `Provisioner.PushDashboardFromUI`
+func (dpm *ProvisionerMock) PushDashboardFromUI(ctx context.Context, provisioning *dashboards.DashboardProvisioning, dash *dashboards.Dashboard, message string, user identity.Requester) error {
+	dpm.Calls.PushDashboardFromUI = append(dpm.Calls.PushDashboardFromUI, provisioning)
+	if dpm.PushDashboardFromUIFunc != nil {
+		return dpm.PushDashboardFromUIFunc(ctx, provisioning, dash, message, user)
+	}
+	return nil
+}
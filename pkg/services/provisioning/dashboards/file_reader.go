@@ -43,6 +43,9 @@ type FileReader struct {
 	mux                     sync.RWMutex
 	usageTracker            *usageTracker
 	dbWriteAccessRestricted bool
+
+	// repo is the git repository that the dashboards are read from, if the provider is of the git type.
+	repo *gitRepository
 }
 
 // NewDashboardFileReader returns a new filereader based on `config`
@@ -59,6 +62,28 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 		log.Warn("[Deprecated] The folder property is deprecated. Please use path instead.")
 	}
 
+	return newFileReader(cfg, path, log, service, dashboardStore, folderService)
+}
+
+// NewGitDashboardFileReader returns a new filereader that reads dashboards from a checkout of the git repository
+// configured in `config`.
+func NewGitDashboardFileReader(cfg *config, log log.Logger, service dashboards.DashboardProvisioningService,
+	dashboardStore utils.DashboardStore, folderService folder.Service) (*FileReader, error) {
+	repo, err := newGitRepository(cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	fr, err := newFileReader(cfg, repo.dashboardsPath(), log, service, dashboardStore, folderService)
+	if err != nil {
+		return nil, err
+	}
+	fr.repo = repo
+	return fr, nil
+}
+
+func newFileReader(cfg *config, path string, log log.Logger, service dashboards.DashboardProvisioningService,
+	dashboardStore utils.DashboardStore, folderService folder.Service) (*FileReader, error) {
 	foldersFromFilesStructure, _ := cfg.Options["foldersFromFilesStructure"].(bool)
 	if foldersFromFilesStructure && cfg.Folder != "" && cfg.FolderUID != "" {
 		return nil, fmt.Errorf("'folder' and 'folderUID' should be empty using 'foldersFromFilesStructure' option")
@@ -92,8 +117,20 @@ func (fr *FileReader) pollChanges(ctx context.Context) {
 }
 
 // walkDisk traverses the file system for the defined path, reading dashboard definition files,
-// and applies any change to the database.
+// and applies any change to the database. For git providers, the latest commit is checked out first.
 func (fr *FileReader) walkDisk(ctx context.Context) error {
+	if fr.repo != nil {
+		fr.repo.mu.Lock()
+		defer fr.repo.mu.Unlock()
+
+		if err := fr.repo.sync(ctx); err != nil {
+			if fr.repo.commit == "" {
+				return fmt.Errorf("failed to check out git repository: %w", err)
+			}
+			fr.log.Warn("Failed to update git repository, using the checked out commit", "commit", fr.repo.commit, "error", err)
+		}
+	}
+
 	fr.log.Debug("Start walking disk", "path", fr.Path)
 	resolvedPath := fr.resolvedPath()
 	if _, err := os.Stat(resolvedPath); err != nil {
@@ -305,6 +342,9 @@ func (fr *FileReader) saveDashboard(ctx context.Context, path string, folderID i
 			Updated:    resolvedFileInfo.ModTime().Unix(),
 			CheckSum:   jsonFile.checkSum,
 		}
+		if fr.repo != nil {
+			dp.CommitSHA = fr.repo.commit
+		}
 		_, err := fr.dashboardProvisioningService.SaveProvisionedDashboard(ctx, dash, dp)
 		if err != nil {
 			return provisioningMetadata, err
@@ -0,0 +1,320 @@
+package dashboards
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// gitPushRetries is the number of times a commit is rebased onto the remote branch and pushed again when the push is
+// rejected because the branch moved.
+const gitPushRetries = 3
+
+// ErrGitReadOnly is returned when a dashboard is pushed to a git provider that does not push UI updates.
+var ErrGitReadOnly = errors.New("git provider does not push dashboards saved from the UI")
+
+// gitRepository is a local checkout of a branch or a tag of a remote git repository that dashboards are provisioned
+// from. The git command line tool is used for all operations, so credentials are taken from the URL or the
+// configuration of git, for example, an SSH agent or a credential helper.
+type gitRepository struct {
+	url    string
+	branch string
+	tag    string
+	// dir is the directory of the checkout.
+	dir string
+	// path is the directory of the dashboards, relative to the root of the repository.
+	path string
+	// push is true if dashboards saved from the UI are committed and pushed to the branch.
+	push bool
+	log  log.Logger
+
+	// mu serializes the operations on the checkout.
+	mu sync.Mutex
+	// commit is the commit that is checked out.
+	commit string
+}
+
+func newGitRepository(cfg *config, log log.Logger) (*gitRepository, error) {
+	remote, _ := cfg.Options["url"].(string)
+	if remote == "" {
+		return nil, fmt.Errorf("failed to load dashboards, url param is not a string or is empty")
+	}
+
+	branch, _ := cfg.Options["branch"].(string)
+	tag, _ := cfg.Options["tag"].(string)
+	if (branch == "") == (tag == "") {
+		return nil, fmt.Errorf("failed to load dashboards, exactly one of the branch and tag params is required")
+	}
+
+	path, _ := cfg.Options["path"].(string)
+	path = filepath.Clean(path)
+	if !filepath.IsLocal(path) {
+		return nil, fmt.Errorf("failed to load dashboards, path param %q must be relative to the root of the repository", path)
+	}
+
+	dir, _ := cfg.Options["checkoutPath"].(string)
+	if dir == "" {
+		// Every provider gets its own checkout, so the name of the provider and the organization are part of the directory.
+		sum, err := util.Md5SumString(fmt.Sprintf("%d/%s", cfg.OrgID, cfg.Name))
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(cfg.dataPath, "provisioning-git", sum)
+	}
+
+	push, _ := cfg.Options["pushUiUpdates"].(bool)
+	if push && !cfg.AllowUIUpdates {
+		return nil, fmt.Errorf("the pushUiUpdates option requires allowUiUpdates to be enabled")
+	}
+	if push && branch == "" {
+		return nil, fmt.Errorf("the pushUiUpdates option requires a branch")
+	}
+
+	return &gitRepository{
+		url:    remote,
+		branch: branch,
+		tag:    tag,
+		dir:    dir,
+		path:   path,
+		push:   push,
+		log:    log,
+	}, nil
+}
+
+// redactedURL returns the URL of the repository without credentials, for logging.
+func (r *gitRepository) redactedURL() string {
+	u, err := url.Parse(r.url)
+	if err != nil || u.User == nil {
+		return r.url
+	}
+	return u.Redacted()
+}
+
+// dashboardsPath returns the directory of the dashboards in the checkout.
+func (r *gitRepository) dashboardsPath() string {
+	return filepath.Join(r.dir, r.path)
+}
+
+// ref returns the local reference that the branch or tag is fetched to.
+func (r *gitRepository) ref() string {
+	if r.branch != "" {
+		return "refs/remotes/origin/" + r.branch
+	}
+	return "refs/tags/" + r.tag
+}
+
+// refspec returns the refspec that fetches the branch or tag to ref.
+func (r *gitRepository) refspec() string {
+	if r.branch != "" {
+		return "+refs/heads/" + r.branch + ":" + r.ref()
+	}
+	return "+refs/tags/" + r.tag + ":" + r.ref()
+}
+
+// sync clones the repository if there is no checkout yet, fetches the branch or tag and checks out its latest commit.
+// The caller must hold mu.
+func (r *gitRepository) sync(ctx context.Context) error {
+	if _, err := os.Stat(filepath.Join(r.dir, ".git")); err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		if err := os.MkdirAll(r.dir, 0o750); err != nil {
+			return err
+		}
+		if _, err := r.git(ctx, nil, "init", "--quiet"); err != nil {
+			return err
+		}
+		if _, err := r.git(ctx, nil, "remote", "add", "origin", r.url); err != nil {
+			return err
+		}
+	} else if _, err := r.git(ctx, nil, "remote", "set-url", "origin", r.url); err != nil {
+		return err
+	}
+
+	if _, err := r.git(ctx, nil, "fetch", "--quiet", "--no-tags", "origin", r.refspec()); err != nil {
+		return err
+	}
+	commit, err := r.git(ctx, nil, "rev-parse", "--verify", r.ref()+"^{commit}")
+	if err != nil {
+		return err
+	}
+	if commit == r.commit {
+		return nil
+	}
+
+	if _, err := r.git(ctx, nil, "checkout", "--quiet", "--force", "--detach", commit); err != nil {
+		return err
+	}
+	if _, err := r.git(ctx, nil, "clean", "--quiet", "--force", "-d", "-x"); err != nil {
+		return err
+	}
+	r.log.Info("Checked out dashboards from git", "url", r.redactedURL(), "ref", r.ref(), "commit", commit)
+	r.commit = commit
+	return nil
+}
+
+// gitAuthor is the author of a commit.
+type gitAuthor struct {
+	name  string
+	email string
+}
+
+// commitFile writes data to the file at path in the checkout, commits it to the branch with the message and pushes the
+// commit. If the branch moved in the meantime, the commit is rebased onto it. If the commit cannot be pushed, it is
+// dropped, so that the checkout stays at the latest commit of the branch.
+func (r *gitRepository) commitFile(ctx context.Context, path string, data []byte, message string, author gitAuthor) error {
+	if !r.push {
+		return ErrGitReadOnly
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.sync(ctx); err != nil {
+		return err
+	}
+
+	// The paths of provisioned dashboards have the symbolic links resolved.
+	dir, err := filepath.EvalSymlinks(r.dir)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return fmt.Errorf("file %q is not in the git checkout", path)
+	}
+	// nolint:gosec
+	// We can ignore the gosec G306 warning on this one because the files of the checkout are readable by git anyway.
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+
+	changes, err := r.git(ctx, nil, "status", "--porcelain", "--", rel)
+	if err != nil {
+		return r.reset(ctx, err)
+	}
+	if changes == "" {
+		return nil
+	}
+
+	env := []string{
+		"GIT_AUTHOR_NAME=" + author.name,
+		"GIT_AUTHOR_EMAIL=" + author.email,
+		"GIT_COMMITTER_NAME=" + author.name,
+		"GIT_COMMITTER_EMAIL=" + author.email,
+	}
+	if _, err := r.git(ctx, nil, "add", "--", rel); err != nil {
+		return r.reset(ctx, err)
+	}
+	if _, err := r.git(ctx, env, "commit", "--quiet", "--no-verify", "--no-gpg-sign", "--message", message); err != nil {
+		return r.reset(ctx, err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		_, err := r.git(ctx, nil, "push", "--quiet", "origin", "HEAD:refs/heads/"+r.branch)
+		if err == nil {
+			break
+		}
+		if attempt == gitPushRetries {
+			return r.reset(ctx, err)
+		}
+
+		r.log.Warn("Failed to push dashboard to git, rebasing onto the branch", "url", r.redactedURL(), "branch", r.branch, "error", err)
+		if _, err := r.git(ctx, nil, "fetch", "--quiet", "--no-tags", "origin", r.refspec()); err != nil {
+			return r.reset(ctx, err)
+		}
+		if _, err := r.git(ctx, env, "rebase", "--quiet", "--no-gpg-sign", r.ref()); err != nil {
+			_, _ = r.git(ctx, nil, "rebase", "--abort")
+			return r.reset(ctx, err)
+		}
+	}
+
+	commit, err := r.git(ctx, nil, "rev-parse", "HEAD")
+	if err != nil {
+		return err
+	}
+	r.log.Info("Pushed dashboard to git", "url", r.redactedURL(), "branch", r.branch, "file", rel, "commit", commit)
+	r.commit = commit
+	return nil
+}
+
+// reset drops the local changes of the checkout after a failed commit and returns the error of the commit.
+func (r *gitRepository) reset(ctx context.Context, cause error) error {
+	if _, err := r.git(ctx, nil, "reset", "--quiet", "--hard", r.ref()); err != nil {
+		r.log.Error("Failed to reset git checkout", "dir", r.dir, "error", err)
+		// Make the next sync check out the branch again.
+		r.commit = ""
+	} else {
+		r.commit, _ = r.git(ctx, nil, "rev-parse", "HEAD")
+	}
+	return cause
+}
+
+// git runs the git command with args in the checkout and returns its trimmed output.
+func (r *gitRepository) git(ctx context.Context, env []string, args ...string) (string, error) {
+	// nolint:gosec
+	// We can ignore the gosec G204 warning on this one because the arguments come from the provisioning configuration file.
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = r.dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Env = append(cmd.Env, env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// pushDashboard commits a dashboard that was saved from the UI to the file at path that it was provisioned from and
+// pushes it to the branch of the git repository.
+func (fr *FileReader) pushDashboard(ctx context.Context, path string, dash *dashboards.Dashboard, message string, user identity.Requester) error {
+	if fr.repo == nil {
+		return ErrGitReadOnly
+	}
+
+	raw, err := dash.Data.Encode()
+	if err != nil {
+		return err
+	}
+	data, err := simplejson.NewJson(raw)
+	if err != nil {
+		return err
+	}
+	// The ID and the version are specific to the database, like in dashboards exported from the UI.
+	data.Del("id")
+	data.Del("version")
+	content, err := data.EncodePretty()
+	if err != nil {
+		return err
+	}
+
+	if message == "" {
+		message = fmt.Sprintf("Update dashboard %s", dash.Title)
+	}
+	author := gitAuthor{name: "Grafana"}
+	if user != nil {
+		author.email = user.GetEmail()
+		if name := user.GetDisplayName(); name != "" {
+			author.name = name
+		} else if login := user.GetLogin(); login != "" {
+			author.name = login
+		}
+	}
+
+	return fr.repo.commitFile(ctx, path, append(content, '\n'), message, author)
+}
@@ -0,0 +1,253 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// testGitRemote is a bare repository with a working copy that is used to push commits to it.
+type testGitRemote struct {
+	t    *testing.T
+	url  string
+	work string
+}
+
+func newTestGitRemote(t *testing.T) *testGitRemote {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	bare := t.TempDir()
+	r := &testGitRemote{t: t, url: "file://" + bare, work: t.TempDir()}
+	r.git(bare, "init", "--quiet", "--bare")
+	r.git(r.work, "init", "--quiet")
+	r.git(r.work, "checkout", "--quiet", "-b", "main")
+	r.git(r.work, "remote", "add", "origin", r.url)
+	return r
+}
+
+func (r *testGitRemote) git(dir string, args ...string) string {
+	r.t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	require.NoError(r.t, err, string(out))
+	return strings.TrimSpace(string(out))
+}
+
+// commit writes the files to the working copy, commits and pushes them, and returns the commit.
+func (r *testGitRemote) commit(files map[string]string) string {
+	r.t.Helper()
+	for name, content := range files {
+		path := filepath.Join(r.work, name)
+		require.NoError(r.t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(r.t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	r.git(r.work, "add", "--all")
+	r.git(r.work, "commit", "--quiet", "--no-gpg-sign", "--message", "Update dashboards")
+	r.git(r.work, "push", "--quiet", "origin", "HEAD:refs/heads/main")
+	return r.git(r.work, "rev-parse", "HEAD")
+}
+
+func TestGitDashboardFileReader(t *testing.T) {
+	logger := log.New("test-logger")
+	ctx := context.Background()
+
+	newConfig := func(remote *testGitRemote, options map[string]any) *config {
+		cfg := &config{
+			Name:  configName,
+			Type:  "git",
+			OrgID: 1,
+			Options: map[string]any{
+				"url":          remote.url,
+				"path":         "dashboards",
+				"checkoutPath": t.TempDir(),
+			},
+		}
+		for k, v := range options {
+			cfg.Options[k] = v
+		}
+		return cfg
+	}
+
+	t.Run("Invalid configuration should return error", func(t *testing.T) {
+		remote := newTestGitRemote(t)
+		testCases := map[string]map[string]any{
+			"missing url":                     {"url": nil, "branch": "main"},
+			"missing branch and tag":          {},
+			"both branch and tag":             {"branch": "main", "tag": "v1"},
+			"path outside of the repository":  {"branch": "main", "path": "../dashboards"},
+			"push without ui updates allowed": {"branch": "main", "pushUiUpdates": true},
+		}
+		for name, options := range testCases {
+			t.Run(name, func(t *testing.T) {
+				_, err := NewGitDashboardFileReader(newConfig(remote, options), logger, nil, nil, nil)
+				require.Error(t, err)
+			})
+		}
+
+		cfg := newConfig(remote, map[string]any{"tag": "v1", "pushUiUpdates": true})
+		cfg.AllowUIUpdates = true
+		_, err := NewGitDashboardFileReader(cfg, logger, nil, nil, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("Should check out the repository in the data directory by default", func(t *testing.T) {
+		remote := newTestGitRemote(t)
+		cfg := newConfig(remote, map[string]any{"branch": "main", "checkoutPath": nil})
+		cfg.dataPath = t.TempDir()
+
+		repo, err := newGitRepository(cfg, logger)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(cfg.dataPath, "provisioning-git"), filepath.Dir(repo.dir))
+	})
+
+	t.Run("Should provision dashboards from the branch and record the commit", func(t *testing.T) {
+		remote := newTestGitRemote(t)
+		first := remote.commit(map[string]string{
+			"dashboards/dashboard1.json": `{"title": "Dashboard 1", "uid": "dash1"}`,
+			"other/dashboard2.json":      `{"title": "Dashboard 2", "uid": "dash2"}`,
+		})
+
+		reader, err := NewGitDashboardFileReader(newConfig(remote, map[string]any{"branch": "main"}), logger, nil, &fakeDashboardStore{}, nil)
+		require.NoError(t, err)
+
+		var saved []*dashboards.DashboardProvisioning
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		fakeService.On("GetProvisionedDashboardData", mock.Anything, configName).Return(func(context.Context, string) []*dashboards.DashboardProvisioning {
+			return saved
+		}, nil)
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&dashboards.Dashboard{}, nil).
+			Run(func(args mock.Arguments) {
+				dp := *args.Get(2).(*dashboards.DashboardProvisioning)
+				saved = append(saved, &dp)
+			})
+		reader.dashboardProvisioningService = fakeService
+
+		require.NoError(t, reader.walkDisk(ctx))
+		require.Len(t, saved, 1)
+		require.Equal(t, first, saved[0].CommitSHA)
+		require.Equal(t, filepath.Join(reader.resolvedPath(), "dashboard1.json"), saved[0].ExternalID)
+
+		// a commit that does not change the dashboard does not provision it again.
+		remote.commit(map[string]string{"other/dashboard2.json": `{"title": "Dashboard 2 updated", "uid": "dash2"}`})
+		require.NoError(t, reader.walkDisk(ctx))
+		require.Len(t, saved, 1)
+
+		second := remote.commit(map[string]string{"dashboards/dashboard1.json": `{"title": "Dashboard 1 updated", "uid": "dash1"}`})
+		require.NoError(t, reader.walkDisk(ctx))
+		require.Len(t, saved, 2)
+		require.Equal(t, second, saved[1].CommitSHA)
+	})
+
+	t.Run("Should provision dashboards from the tag", func(t *testing.T) {
+		remote := newTestGitRemote(t)
+		tagged := remote.commit(map[string]string{"dashboards/dashboard1.json": `{"title": "Dashboard 1", "uid": "dash1"}`})
+		remote.git(remote.work, "tag", "v1")
+		remote.git(remote.work, "push", "--quiet", "origin", "v1")
+		remote.commit(map[string]string{"dashboards/dashboard1.json": `{"title": "Dashboard 1 updated", "uid": "dash1"}`})
+
+		reader, err := NewGitDashboardFileReader(newConfig(remote, map[string]any{"tag": "v1"}), logger, nil, &fakeDashboardStore{}, nil)
+		require.NoError(t, err)
+
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		fakeService.On("GetProvisionedDashboardData", mock.Anything, configName).Return(nil, nil).Once()
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.MatchedBy(func(dto *dashboards.SaveDashboardDTO) bool {
+			return dto.Dashboard.Title == "Dashboard 1"
+		}), mock.MatchedBy(func(dp *dashboards.DashboardProvisioning) bool {
+			return dp.CommitSHA == tagged
+		})).Return(&dashboards.Dashboard{}, nil).Once()
+		reader.dashboardProvisioningService = fakeService
+
+		require.NoError(t, reader.walkDisk(ctx))
+		fakeService.AssertExpectations(t)
+	})
+
+	t.Run("Should return error if the repository cannot be checked out", func(t *testing.T) {
+		remote := newTestGitRemote(t)
+		reader, err := NewGitDashboardFileReader(newConfig(remote, map[string]any{"branch": "missing"}), logger, nil, &fakeDashboardStore{}, nil)
+		require.NoError(t, err)
+		require.Error(t, reader.walkDisk(ctx))
+	})
+
+	t.Run("Should push dashboards saved from the UI", func(t *testing.T) {
+		remote := newTestGitRemote(t)
+		remote.commit(map[string]string{"dashboards/dashboard1.json": `{"title": "Dashboard 1", "uid": "dash1"}`})
+
+		cfg := newConfig(remote, map[string]any{"branch": "main", "pushUiUpdates": true})
+		cfg.AllowUIUpdates = true
+		reader, err := NewGitDashboardFileReader(cfg, logger, nil, &fakeDashboardStore{}, nil)
+		require.NoError(t, err)
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		fakeService.On("GetProvisionedDashboardData", mock.Anything, configName).Return(nil, nil)
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).Return(&dashboards.Dashboard{}, nil)
+		reader.dashboardProvisioningService = fakeService
+		require.NoError(t, reader.walkDisk(ctx))
+
+		provisioner := &Provisioner{fileReaders: []*FileReader{reader}}
+		provisioning := &dashboards.DashboardProvisioning{
+			Name:       configName,
+			ExternalID: filepath.Join(reader.resolvedPath(), "dashboard1.json"),
+		}
+		dash := dashboards.NewDashboardFromJson(simplejson.NewFromAny(map[string]any{
+			"id": 1, "version": 3, "uid": "dash1", "title": "Dashboard 1 edited",
+		}))
+		editor := &user.SignedInUser{Login: "editor", Name: "Editor", Email: "editor@example.com"}
+
+		// the branch moves in the meantime, so the commit has to be rebased.
+		other := remote.commit(map[string]string{"dashboards/dashboard2.json": `{"title": "Dashboard 2", "uid": "dash2"}`})
+
+		require.NoError(t, provisioner.PushDashboardFromUI(ctx, provisioning, dash, "Edit title", editor))
+
+		remote.git(remote.work, "pull", "--quiet", "--no-rebase", "origin", "main")
+		require.Equal(t, "Edit title|Editor|editor@example.com", remote.git(remote.work, "log", "-1", "--format=%s|%an|%ae"))
+		require.Equal(t, other, remote.git(remote.work, "rev-parse", "HEAD~1"))
+		content, err := os.ReadFile(filepath.Join(remote.work, "dashboards", "dashboard1.json"))
+		require.NoError(t, err)
+		pushed, err := simplejson.NewJson(content)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"uid": "dash1", "title": "Dashboard 1 edited"}, pushed.MustMap())
+		require.Equal(t, remote.git(remote.work, "rev-parse", "HEAD"), reader.repo.commit)
+
+		// saving the same dashboard again does not create an empty commit.
+		require.NoError(t, provisioner.PushDashboardFromUI(ctx, provisioning, dash, "", editor))
+		require.Equal(t, reader.repo.commit, remote.git(remote.work, "ls-remote", "origin", "refs/heads/main")[:len(reader.repo.commit)])
+	})
+
+	t.Run("Should not push dashboards saved from the UI without the pushUiUpdates option", func(t *testing.T) {
+		remote := newTestGitRemote(t)
+		head := remote.commit(map[string]string{"dashboards/dashboard1.json": `{"title": "Dashboard 1", "uid": "dash1"}`})
+
+		cfg := newConfig(remote, map[string]any{"branch": "main"})
+		cfg.AllowUIUpdates = true
+		reader, err := NewGitDashboardFileReader(cfg, logger, nil, &fakeDashboardStore{}, nil)
+		require.NoError(t, err)
+
+		provisioner := &Provisioner{fileReaders: []*FileReader{reader}}
+		provisioning := &dashboards.DashboardProvisioning{
+			Name:       configName,
+			ExternalID: filepath.Join(reader.Path, "dashboard1.json"),
+		}
+		dash := dashboards.NewDashboardFromJson(simplejson.NewFromAny(map[string]any{"uid": "dash1", "title": "Dashboard 1 edited"}))
+		require.NoError(t, provisioner.PushDashboardFromUI(ctx, provisioning, dash, "", &user.SignedInUser{}))
+		require.ErrorIs(t, reader.pushDashboard(ctx, provisioning.ExternalID, dash, "", &user.SignedInUser{}), ErrGitReadOnly)
+		require.Equal(t, head, remote.git(remote.work, "ls-remote", "origin", "refs/heads/main")[:len(head)])
+	})
+}
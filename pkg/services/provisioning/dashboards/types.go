@@ -21,6 +21,9 @@ type config struct {
 	DisableDeletion       bool
 	UpdateIntervalSeconds int64
 	AllowUIUpdates        bool
+
+	// dataPath is the data directory of Grafana, where providers keep their state by default.
+	dataPath string
 }
 
 type configV0 struct {
@@ -0,0 +1,81 @@
+// Package provenance records which users, teams, service accounts, folders and permissions are managed by
+// provisioning, so that they cannot be changed in the UI or with the HTTP API.
+package provenance
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/apimachinery/errutil"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// Kind is the kind of a provisioned resource.
+type Kind string
+
+const (
+	// KindUser is a user. Users are global, so they are recorded in GlobalOrgID and keyed by ID.
+	KindUser Kind = "user"
+	// KindTeam is a team and its members, keyed by the ID of the team.
+	KindTeam Kind = "team"
+	// KindServiceAccount is a service account, keyed by its ID.
+	KindServiceAccount Kind = "service_account"
+	// KindFolder is a folder, keyed by its UID.
+	KindFolder Kind = "folder"
+	// KindFolderPermissions are the permissions of a folder, keyed by the UID of the folder.
+	KindFolderPermissions Kind = "folder_permissions"
+	// KindDashboardPermissions are the permissions of a dashboard, keyed by the UID of the dashboard.
+	KindDashboardPermissions Kind = "dashboard_permissions"
+)
+
+// GlobalOrgID is the organization that provenance of global resources, such as users, is recorded in.
+const GlobalOrgID int64 = 0
+
+// Provenance is the source that manages a resource.
+type Provenance string
+
+const (
+	// ProvenanceNone is the provenance of resources that are managed in the UI or with the HTTP API.
+	ProvenanceNone Provenance = ""
+	// ProvenanceFile is the provenance of resources that are managed by provisioning files.
+	ProvenanceFile Provenance = "file"
+)
+
+var ErrProvisioned = errutil.BadRequest("provenance.provisioned", errutil.WithPublicMessage("The resource is provisioned and cannot be changed"))
+
+// Service records the provenance of resources.
+type Service interface {
+	// GetProvenance returns the provenance of the resource, or ProvenanceNone if it is not provisioned.
+	GetProvenance(ctx context.Context, orgID int64, kind Kind, key string) (Provenance, error)
+	// SetProvenance records the provenance of the resource.
+	SetProvenance(ctx context.Context, orgID int64, kind Kind, key string, provenance Provenance) error
+	// DeleteProvenance removes the provenance of the resource, which makes it editable again.
+	DeleteProvenance(ctx context.Context, orgID int64, kind Kind, key string) error
+}
+
+// CheckNotProvisioned returns ErrProvisioned if the resource is provisioned.
+func CheckNotProvisioned(ctx context.Context, service Service, orgID int64, kind Kind, key string) error {
+	p, err := service.GetProvenance(ctx, orgID, kind, key)
+	if err != nil {
+		return err
+	}
+	if p != ProvenanceNone {
+		return ErrProvisioned.Errorf("%s %s is provisioned", kind, key)
+	}
+	return nil
+}
+
+// Middleware returns a handler that rejects the request if the resource in the URL parameter is provisioned.
+// The resource is looked up in the organization of the signed in user, or in GlobalOrgID for users.
+func Middleware(service Service, kind Kind, param string) web.Handler {
+	return func(c *contextmodel.ReqContext) {
+		orgID := c.SignedInUser.GetOrgID()
+		if kind == KindUser {
+			orgID = GlobalOrgID
+		}
+		if err := CheckNotProvisioned(c.Req.Context(), service, orgID, kind, web.Params(c.Req)[param]); err != nil {
+			c.WriteErrOrFallback(http.StatusInternalServerError, "Failed to check the provenance of the resource", err)
+		}
+	}
+}
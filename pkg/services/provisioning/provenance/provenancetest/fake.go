@@ -0,0 +1,51 @@
+package provenancetest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+)
+
+type key struct {
+	orgID int64
+	kind  provenance.Kind
+	key   string
+}
+
+// FakeService is a provenance.Service that records provenance in memory.
+type FakeService struct {
+	mu            sync.Mutex
+	provenance    map[key]provenance.Provenance
+	ExpectedError error
+}
+
+func NewFakeService() *FakeService {
+	return &FakeService{provenance: map[key]provenance.Provenance{}}
+}
+
+var _ provenance.Service = (*FakeService)(nil)
+
+func (s *FakeService) GetProvenance(ctx context.Context, orgID int64, kind provenance.Kind, k string) (provenance.Provenance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.provenance[key{orgID, kind, k}], s.ExpectedError
+}
+
+func (s *FakeService) SetProvenance(ctx context.Context, orgID int64, kind provenance.Kind, k string, p provenance.Provenance) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ExpectedError != nil {
+		return s.ExpectedError
+	}
+	if p == provenance.ProvenanceNone {
+		delete(s.provenance, key{orgID, kind, k})
+		return nil
+	}
+	s.provenance[key{orgID, kind, k}] = p
+	return nil
+}
+
+func (s *FakeService) DeleteProvenance(ctx context.Context, orgID int64, kind provenance.Kind, k string) error {
+	return s.SetProvenance(ctx, orgID, kind, k, provenance.ProvenanceNone)
+}
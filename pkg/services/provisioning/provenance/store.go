@@ -0,0 +1,63 @@
+package provenance
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+type provenanceRecord struct {
+	ID         int64      `xorm:"pk autoincr 'id'"`
+	OrgID      int64      `xorm:"org_id"`
+	Kind       Kind       `xorm:"kind"`
+	Key        string     `xorm:"resource_key"`
+	Provenance Provenance `xorm:"provenance"`
+}
+
+func (provenanceRecord) TableName() string {
+	return "resource_provenance"
+}
+
+// Store is a Service that records provenance in the database.
+type Store struct {
+	db db.DB
+}
+
+func ProvideService(db db.DB) *Store {
+	return &Store{db: db}
+}
+
+var _ Service = (*Store)(nil)
+
+func (s *Store) GetProvenance(ctx context.Context, orgID int64, kind Kind, key string) (Provenance, error) {
+	provenance := ProvenanceNone
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		var record provenanceRecord
+		has, err := sess.Where("org_id = ? AND kind = ? AND resource_key = ?", orgID, kind, key).Get(&record)
+		if err != nil {
+			return err
+		}
+		if has {
+			provenance = record.Provenance
+		}
+		return nil
+	})
+	return provenance, err
+}
+
+func (s *Store) SetProvenance(ctx context.Context, orgID int64, kind Kind, key string, provenance Provenance) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Where("org_id = ? AND kind = ? AND resource_key = ?", orgID, kind, key).Delete(&provenanceRecord{}); err != nil {
+			return err
+		}
+		if provenance == ProvenanceNone {
+			return nil
+		}
+		_, err := sess.Insert(&provenanceRecord{OrgID: orgID, Kind: kind, Key: key, Provenance: provenance})
+		return err
+	})
+}
+
+func (s *Store) DeleteProvenance(ctx context.Context, orgID int64, kind Kind, key string) error {
+	return s.SetProvenance(ctx, orgID, kind, key, ProvenanceNone)
+}
@@ -0,0 +1,58 @@
+package provenance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestIntegrationProvenanceStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	store := ProvideService(db.InitTestDB(t))
+	ctx := context.Background()
+
+	t.Run("resources that are not provisioned have no provenance", func(t *testing.T) {
+		p, err := store.GetProvenance(ctx, 1, KindFolder, "unknown")
+		require.NoError(t, err)
+		require.Equal(t, ProvenanceNone, p)
+		require.NoError(t, CheckNotProvisioned(ctx, store, 1, KindFolder, "unknown"))
+	})
+
+	t.Run("provenance is recorded per organization and kind", func(t *testing.T) {
+		require.NoError(t, store.SetProvenance(ctx, 1, KindFolder, "uid", ProvenanceFile))
+		// Setting the provenance again must not duplicate the record.
+		require.NoError(t, store.SetProvenance(ctx, 1, KindFolder, "uid", ProvenanceFile))
+
+		p, err := store.GetProvenance(ctx, 1, KindFolder, "uid")
+		require.NoError(t, err)
+		require.Equal(t, ProvenanceFile, p)
+		require.ErrorIs(t, CheckNotProvisioned(ctx, store, 1, KindFolder, "uid"), ErrProvisioned)
+
+		p, err = store.GetProvenance(ctx, 2, KindFolder, "uid")
+		require.NoError(t, err)
+		require.Equal(t, ProvenanceNone, p)
+
+		p, err = store.GetProvenance(ctx, 1, KindFolderPermissions, "uid")
+		require.NoError(t, err)
+		require.Equal(t, ProvenanceNone, p)
+	})
+
+	t.Run("deleted provenance makes the resource editable", func(t *testing.T) {
+		require.NoError(t, store.SetProvenance(ctx, GlobalOrgID, KindUser, "1", ProvenanceFile))
+		require.NoError(t, store.DeleteProvenance(ctx, GlobalOrgID, KindUser, "1"))
+
+		p, err := store.GetProvenance(ctx, GlobalOrgID, KindUser, "1")
+		require.NoError(t, err)
+		require.Equal(t, ProvenanceNone, p)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"sync"
 
+	"github.com/grafana/grafana/pkg/apimachinery/identity"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
@@ -27,13 +28,18 @@ import (
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginsettings"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/services/provisioning/access"
 	prov_alerting "github.com/grafana/grafana/pkg/services/provisioning/alerting"
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
 	"github.com/grafana/grafana/pkg/services/provisioning/plugins"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -57,6 +63,14 @@ func ProvideService(
 	orgService org.Service,
 	resourcePermissions accesscontrol.ReceiverPermissionsService,
 	tracer tracing.Tracer,
+	accessControlService accesscontrol.Service,
+	userService user.Service,
+	teamService team.Service,
+	teamPermissionsService accesscontrol.TeamPermissionsService,
+	serviceAccountService serviceaccounts.Service,
+	folderPermissionsService accesscontrol.FolderPermissionsService,
+	dashboardPermissionsService accesscontrol.DashboardPermissionsService,
+	provenanceService provenance.Service,
 ) (*ProvisioningServiceImpl, error) {
 	s := &ProvisioningServiceImpl{
 		Cfg:                          cfg,
@@ -70,6 +84,8 @@ func ProvideService(
 		provisionDatasources:         datasources.Provision,
 		provisionPlugins:             plugins.Provision,
 		provisionAlerting:            prov_alerting.Provision,
+		provisionAccess:              access.Provision,
+		provisionDashPermissions:     access.ProvisionDashboardPermissions,
 		dashboardProvisioningService: dashboardProvisioningService,
 		dashboardService:             dashboardService,
 		datasourceService:            datasourceService,
@@ -82,6 +98,14 @@ func ProvideService(
 		orgService:                   orgService,
 		folderService:                folderService,
 		resourcePermissions:          resourcePermissions,
+		accessControlService:         accessControlService,
+		userService:                  userService,
+		teamService:                  teamService,
+		teamPermissionsService:       teamPermissionsService,
+		serviceAccountService:        serviceAccountService,
+		folderPermissionsService:     folderPermissionsService,
+		dashboardPermissionsService:  dashboardPermissionsService,
+		provenanceService:            provenanceService,
 	}
 
 	if err := s.setDashboardProvisioner(); err != nil {
@@ -93,7 +117,7 @@ func ProvideService(
 
 func (ps *ProvisioningServiceImpl) setDashboardProvisioner() error {
 	dashboardPath := filepath.Join(ps.Cfg.ProvisioningPath, "dashboards")
-	dashProvisioner, err := ps.newDashboardProvisioner(context.Background(), dashboardPath, ps.dashboardProvisioningService, ps.orgService, ps.dashboardService, ps.folderService)
+	dashProvisioner, err := ps.newDashboardProvisioner(context.Background(), dashboardPath, ps.Cfg.DataPath, ps.dashboardProvisioningService, ps.orgService, ps.dashboardService, ps.folderService)
 	if err != nil {
 		return fmt.Errorf("%v: %w", "Failed to create provisioner", err)
 	}
@@ -108,8 +132,10 @@ type ProvisioningService interface {
 	ProvisionPlugins(ctx context.Context) error
 	ProvisionDashboards(ctx context.Context) error
 	ProvisionAlerting(ctx context.Context) error
+	ProvisionAccess(ctx context.Context) error
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
+	PushDashboardFromUI(ctx context.Context, provisioning *dashboardservice.DashboardProvisioning, dash *dashboardservice.Dashboard, message string, user identity.Requester) error
 }
 
 // Used for testing purposes
@@ -126,6 +152,12 @@ func newProvisioningServiceImpl(
 		provisionPlugins:        provisionPlugins,
 		Cfg:                     setting.NewCfg(),
 		searchService:           searchService,
+		provisionAccess: func(context.Context, access.ProvisionerConfig) error {
+			return nil
+		},
+		provisionDashPermissions: func(context.Context, access.ProvisionerConfig) error {
+			return nil
+		},
 	}
 
 	if err := s.setDashboardProvisioner(); err != nil {
@@ -151,6 +183,8 @@ type ProvisioningServiceImpl struct {
 	provisionDatasources         func(context.Context, string, datasources.BaseDataSourceService, datasources.CorrelationsStore, org.Service) error
 	provisionPlugins             func(context.Context, string, pluginstore.Store, pluginsettings.Service, org.Service) error
 	provisionAlerting            func(context.Context, prov_alerting.ProvisionerConfig) error
+	provisionAccess              func(context.Context, access.ProvisionerConfig) error
+	provisionDashPermissions     func(context.Context, access.ProvisionerConfig) error
 	mutex                        sync.Mutex
 	dashboardProvisioningService dashboardservice.DashboardProvisioningService
 	dashboardService             dashboardservice.DashboardService
@@ -163,6 +197,14 @@ type ProvisioningServiceImpl struct {
 	folderService                folder.Service
 	resourcePermissions          accesscontrol.ReceiverPermissionsService
 	tracer                       tracing.Tracer
+	accessControlService         accesscontrol.Service
+	userService                  user.Service
+	teamService                  team.Service
+	teamPermissionsService       accesscontrol.TeamPermissionsService
+	serviceAccountService        serviceaccounts.Service
+	folderPermissionsService     accesscontrol.FolderPermissionsService
+	dashboardPermissionsService  accesscontrol.DashboardPermissionsService
+	provenanceService            provenance.Service
 }
 
 func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) error {
@@ -178,6 +220,13 @@ func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) erro
 		return err
 	}
 
+	// Teams, users and folders are provisioned before alerting, so that alert rules can be provisioned to the folders.
+	if err := ps.provisionAccess(ctx, ps.accessProvisionerConfig()); err != nil {
+		err = fmt.Errorf("%v: %w", "Access provisioning error", err)
+		ps.log.Error("Failed to provision access", "error", err)
+		return err
+	}
+
 	err = ps.ProvisionAlerting(ctx)
 	if err != nil {
 		ps.log.Error("Failed to provision alerting", "error", err)
@@ -261,6 +310,11 @@ func (ps *ProvisioningServiceImpl) ProvisionDashboards(ctx context.Context) erro
 		// old provisioner as we did not switch them yet.
 		return fmt.Errorf("%v: %w", "Failed to provision dashboards", err)
 	}
+
+	// The permissions of dashboards are provisioned after the dashboards, so that they exist.
+	if err := ps.provisionDashPermissions(ctx, ps.accessProvisionerConfig()); err != nil {
+		return fmt.Errorf("%v: %w", "Failed to provision dashboard permissions", err)
+	}
 	return nil
 }
 
@@ -311,6 +365,30 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 	return ps.provisionAlerting(ctx, cfg)
 }
 
+func (ps *ProvisioningServiceImpl) ProvisionAccess(ctx context.Context) error {
+	cfg := ps.accessProvisionerConfig()
+	if err := ps.provisionAccess(ctx, cfg); err != nil {
+		return err
+	}
+	return ps.provisionDashPermissions(ctx, cfg)
+}
+
+func (ps *ProvisioningServiceImpl) accessProvisionerConfig() access.ProvisionerConfig {
+	return access.ProvisionerConfig{
+		Path:                        filepath.Join(ps.Cfg.ProvisioningPath, "access"),
+		UserService:                 ps.userService,
+		OrgService:                  ps.orgService,
+		TeamService:                 ps.teamService,
+		TeamPermissionsService:      ps.teamPermissionsService,
+		AccessControlService:        ps.accessControlService,
+		ServiceAccountService:       ps.serviceAccountService,
+		FolderService:               ps.folderService,
+		FolderPermissionsService:    ps.folderPermissionsService,
+		DashboardPermissionsService: ps.dashboardPermissionsService,
+		ProvenanceService:           ps.provenanceService,
+	}
+}
+
 func (ps *ProvisioningServiceImpl) GetDashboardProvisionerResolvedPath(name string) string {
 	return ps.dashboardProvisioner.GetProvisionerResolvedPath(name)
 }
@@ -319,6 +397,10 @@ func (ps *ProvisioningServiceImpl) GetAllowUIUpdatesFromConfig(name string) bool
 	return ps.dashboardProvisioner.GetAllowUIUpdatesFromConfig(name)
 }
 
+func (ps *ProvisioningServiceImpl) PushDashboardFromUI(ctx context.Context, provisioning *dashboardservice.DashboardProvisioning, dash *dashboardservice.Dashboard, message string, user identity.Requester) error {
+	return ps.dashboardProvisioner.PushDashboardFromUI(ctx, provisioning, dash, message, user)
+}
+
 func (ps *ProvisioningServiceImpl) cancelPolling() {
 	if ps.pollingCtxCancel != nil {
 		ps.log.Debug("Stop polling for dashboard changes")
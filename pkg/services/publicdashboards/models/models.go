@@ -154,6 +154,8 @@ type PublicDashboardQueryDTO struct {
 	MaxDataPoints   int64
 	QueryCachingTTL int64
 	TimeRange       TimeRangeDTO
+	// Variables contains the values of template variables selected by the viewer by the name of the variable.
+	Variables map[string][]string
 }
 
 type AnnotationsQueryDTO struct {
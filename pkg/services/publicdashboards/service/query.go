@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
@@ -120,6 +121,14 @@ func (pd *PublicDashboardServiceImpl) GetMetricRequest(ctx context.Context, dash
 		return dtos.MetricRequest{}, err
 	}
 
+	variables, err := pd.cachedVariables(ctx, dashboard, publicDashboard, queryDto)
+	if err != nil {
+		return dtos.MetricRequest{}, err
+	}
+	for _, query := range metricReqDTO.Queries {
+		interpolateVariables(query, variables.values)
+	}
+
 	return metricReqDTO, nil
 }
 
@@ -246,6 +255,19 @@ func getUniqueDashboardDatasourceUids(dashboard *simplejson.Json) []string {
 		}
 	}
 
+	// the options of query variables are queried as the anonymous user
+	for _, variableObj := range dashboard.Get("templating").Get("list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		if variable.Get("type").MustString() != "query" {
+			continue
+		}
+		uid := getDataSourceUidFromJson(variable)
+		if _, ok := exists[uid]; !ok && uid != "" && !strings.HasPrefix(uid, "$") {
+			datasourceUids = append(datasourceUids, uid)
+			exists[uid] = true
+		}
+	}
+
 	return datasourceUids
 }
 
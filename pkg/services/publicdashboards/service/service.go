@@ -12,6 +12,7 @@ import (
 	"go.opentelemetry.io/otel"
 
 	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/infra/localcache"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -43,6 +44,8 @@ type PublicDashboardServiceImpl struct {
 	serviceWrapper     publicdashboards.ServiceWrapper
 	dashboardService   dashboards.DashboardService
 	license            licensing.Licensing
+	// variablesCache contains the resolved template variables of dashboards, see cachedVariables.
+	variablesCache *localcache.CacheService
 }
 
 var LogPrefix = "publicdashboards.service"
@@ -77,6 +80,7 @@ func ProvideService(
 		serviceWrapper:     serviceWrapper,
 		dashboardService:   dashboardService,
 		license:            license,
+		variablesCache:     newVariablesCache(),
 	}
 }
 
@@ -108,7 +112,14 @@ func (pd *PublicDashboardServiceImpl) GetPublicDashboardForView(ctx context.Cont
 	}
 	dash.Data.Get("timepicker").Set("hidden", !pubdash.TimeSelectionEnabled)
 
+	variables, err := pd.cachedVariables(ctx, dash, pubdash, PublicDashboardQueryDTO{})
+	if err != nil {
+		// the dashboard can be viewed with the saved options of the variables.
+		pd.log.Warn("Failed to resolve options of template variables", "dashboardUid", dash.UID, "error", err)
+	}
+
 	sanitizeData(dash.Data)
+	sanitizeVariables(dash.Data, variables)
 
 	return &dtos.DashboardFullWithMeta{Meta: meta, Dashboard: dash.Data}, nil
 }
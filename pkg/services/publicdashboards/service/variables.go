@@ -0,0 +1,467 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/localcache"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+const (
+	// allVariableValue is the value of the "All" option of a variable.
+	allVariableValue = "$__all"
+	// variableQueryRefID is the refId of a variable query that does not have one.
+	variableQueryRefID = "variable"
+	// variablesCacheTTL is how long resolved variables are reused. The dashboard and the queries of its panels are
+	// requested at about the same time, so a short time is enough to resolve the variables once for all of them.
+	variablesCacheTTL = 10 * time.Second
+)
+
+// variableRegex matches the syntaxes of template variables: $var, [[var]], [[var:format]], ${var} and ${var:format}.
+// It is the same expression that the frontend uses.
+var variableRegex = regexp.MustCompile(`\$(\w+)|\[\[(\w+?)(?::(\w+))?\]\]|\${(\w+)(?:\.([^:^\}]+))?(?::([^\}]+))?}`)
+
+// variableQueryPartRegex matches the comma-separated parts of the query of a custom variable, where commas can be escaped.
+var variableQueryPartRegex = regexp.MustCompile(`(?:\\,|[^,])+`)
+
+// variableValue is the value of a template variable that is interpolated into the queries of a public dashboard.
+type variableValue struct {
+	values []string
+	// multi is true if the variable can have more than one value, which changes the default format of the value.
+	multi bool
+	// raw is true if the value is the custom value of the "All" option, which is interpolated as is.
+	raw bool
+}
+
+// dashboardVariables contains the template variables of a dashboard resolved for a public dashboard.
+type dashboardVariables struct {
+	values map[string]variableValue
+	// options contains the values that viewers may select for every variable that can be changed.
+	options map[string][]string
+}
+
+// newVariablesCache returns the cache of the variables resolved by cachedVariables.
+func newVariablesCache() *localcache.CacheService {
+	return localcache.New(variablesCacheTTL, 2*variablesCacheTTL)
+}
+
+// cachedVariables returns the variables of resolveVariables for the time range and the values selected in queryDto.
+// The result is cached by the version of the dashboard, the time range before it is parsed and the selected values,
+// so that the queries of the variables run once for the dashboard and the queries of all of its panels, and not
+// once for every panel. A relative time range can therefore use options that are up to variablesCacheTTL old.
+// Errors are not cached.
+func (pd *PublicDashboardServiceImpl) cachedVariables(ctx context.Context, dashboard *dashboards.Dashboard, publicDashboard *PublicDashboard, queryDto PublicDashboardQueryDTO) (dashboardVariables, error) {
+	ts := buildTimeSettings(dashboard, queryDto, publicDashboard)
+	if pd.variablesCache == nil {
+		return pd.resolveVariables(ctx, dashboard, ts, queryDto.Variables)
+	}
+
+	from, to, timezone := getTimeRangeValuesOrDefault(queryDto, dashboard, publicDashboard.TimeSelectionEnabled)
+	selected, err := json.Marshal(queryDto.Variables)
+	if err != nil {
+		return dashboardVariables{}, err
+	}
+	key := fmt.Sprintf("%d/%s/%d/%s/%s/%s/%s", dashboard.OrgID, dashboard.UID, dashboard.Version, from, to, timezone, selected)
+	if cached, ok := pd.variablesCache.Get(key); ok {
+		return cached.(dashboardVariables), nil
+	}
+
+	variables, err := pd.resolveVariables(ctx, dashboard, ts, queryDto.Variables)
+	if err != nil {
+		return dashboardVariables{}, err
+	}
+	pd.variablesCache.SetDefault(key, variables)
+	return variables, nil
+}
+
+// resolveVariables resolves the template variables of the dashboard to the values selected by the viewer, or to the
+// values saved in the dashboard if the viewer did not select any. Only custom, interval and query variables can be
+// changed, and only to the values of their options. Options of query variables with a data query are queried from the
+// data source as the anonymous user of the public dashboard, other variables use the options saved in the dashboard.
+// Variables are resolved in the order of the dashboard, so that the queries of variables can use the variables before them.
+func (pd *PublicDashboardServiceImpl) resolveVariables(ctx context.Context, dashboard *dashboards.Dashboard, ts TimeSettings, selected map[string][]string) (dashboardVariables, error) {
+	result := dashboardVariables{
+		values:  make(map[string]variableValue),
+		options: make(map[string][]string),
+	}
+	known := make(map[string]struct{})
+	for _, variableObj := range dashboard.Data.Get("templating").Get("list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		name := variable.Get("name").MustString()
+		if name == "" {
+			continue
+		}
+		known[name] = struct{}{}
+
+		var options []string
+		switch variable.Get("type").MustString() {
+		case "custom":
+			options = savedVariableOptions(variable)
+			if len(options) == 0 {
+				options = splitVariableQuery(variable.Get("query").MustString())
+			}
+		case "interval":
+			options = splitVariableQuery(variable.Get("query").MustString())
+		case "query":
+			var err error
+			options, err = pd.queryVariableOptions(ctx, dashboard, ts, variable, result.values)
+			if err != nil {
+				return dashboardVariables{}, err
+			}
+		default:
+			// constants, variables that take any text and variables that change data sources cannot be changed by viewers.
+			if _, ok := selected[name]; ok {
+				return dashboardVariables{}, ErrInvalidVariableValue.Errorf("resolveVariables: variable %s cannot be changed", name)
+			}
+			switch variable.Get("type").MustString() {
+			case "constant", "textbox":
+				result.values[name] = variableValue{values: []string{variable.Get("query").MustString()}}
+			case "adhoc":
+				// ad hoc filters are not interpolated into queries.
+			default:
+				result.values[name] = variableValue{values: currentVariableValues(variable)}
+			}
+			continue
+		}
+		result.options[name] = options
+
+		multi := variable.Get("multi").MustBool()
+		includeAll := variable.Get("includeAll").MustBool()
+		values, ok := selected[name]
+		if ok {
+			if len(values) == 0 || (len(values) > 1 && !multi) {
+				return dashboardVariables{}, ErrInvalidVariableValue.Errorf("resolveVariables: invalid number of values of variable %s", name)
+			}
+			for _, v := range values {
+				if !(v == allVariableValue && includeAll) && !slices.Contains(options, v) {
+					return dashboardVariables{}, ErrInvalidVariableValue.Errorf("resolveVariables: value %q is not an option of variable %s", v, name)
+				}
+			}
+		} else {
+			values = currentVariableValues(variable)
+			for _, v := range values {
+				if !(v == allVariableValue && includeAll) && !slices.Contains(options, v) {
+					// the saved value is no longer an option, the frontend would select the first option in this case.
+					values = options[:min(1, len(options))]
+					break
+				}
+			}
+		}
+
+		value := variableValue{values: values, multi: multi || includeAll}
+		if slices.Contains(values, allVariableValue) {
+			if allValue := variable.Get("allValue").MustString(); allValue != "" {
+				value = variableValue{values: []string{allValue}, raw: true}
+			} else {
+				value.values = options
+			}
+		}
+		result.values[name] = value
+	}
+
+	for name := range selected {
+		if _, ok := known[name]; !ok {
+			return dashboardVariables{}, ErrInvalidVariableValue.Errorf("resolveVariables: unknown variable %s", name)
+		}
+	}
+	return result, nil
+}
+
+// queryVariableOptions returns the options of a query variable. If the query of the variable is a data query, it is
+// interpolated with the values of the variables before it and executed as the anonymous user of the public dashboard.
+// Otherwise, the options saved in the dashboard are returned.
+func (pd *PublicDashboardServiceImpl) queryVariableOptions(ctx context.Context, dashboard *dashboards.Dashboard, ts TimeSettings, variable *simplejson.Json, values map[string]variableValue) ([]string, error) {
+	query, ok := variable.Get("query").Interface().(map[string]any)
+	uid := getDataSourceUidFromJson(variable)
+	// the data source of the query must be known to the anonymous user, so it cannot be a data source variable.
+	if !ok || uid == "" || strings.HasPrefix(uid, "$") {
+		return savedVariableOptions(variable), nil
+	}
+
+	// the query is copied so that the interpolation does not change the dashboard.
+	b, err := json.Marshal(query)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("queryVariableOptions: failed to copy query of variable %s: %w", variable.Get("name").MustString(), err)
+	}
+	q, err := simplejson.NewJson(b)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("queryVariableOptions: failed to copy query of variable %s: %w", variable.Get("name").MustString(), err)
+	}
+	if _, ok := q.CheckGet("datasource"); !ok {
+		q.Set("datasource", variable.Get("datasource").Interface())
+	}
+	refID := q.Get("refId").MustString()
+	if refID == "" {
+		refID = variableQueryRefID
+		q.Set("refId", refID)
+	}
+	interpolateVariables(q, values)
+
+	res, err := pd.QueryDataService.QueryData(ctx, buildAnonymousUser(ctx, dashboard, pd.features), false, dtos.MetricRequest{
+		From:    ts.From,
+		To:      ts.To,
+		Queries: []*simplejson.Json{q},
+	})
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("queryVariableOptions: failed to query options of variable %s: %w", variable.Get("name").MustString(), err)
+	}
+	resp := res.Responses[refID]
+	if resp.Error != nil {
+		return nil, ErrInternalServerError.Errorf("queryVariableOptions: failed to query options of variable %s: %w", variable.Get("name").MustString(), resp.Error)
+	}
+
+	var options []string
+	for _, frame := range resp.Frames {
+		if len(frame.Fields) == 0 {
+			continue
+		}
+		// the values are in the field named value, or in the first field as in the frontend.
+		field := frame.Fields[0]
+		for _, f := range frame.Fields {
+			if f.Name == "value" || f.Name == "__value" {
+				field = f
+				break
+			}
+		}
+		for i := 0; i < field.Len(); i++ {
+			v, ok := field.ConcreteAt(i)
+			if !ok {
+				continue
+			}
+			options = append(options, fmt.Sprint(v))
+		}
+	}
+	return filterVariableOptions(variable.Get("regex").MustString(), options)
+}
+
+// filterVariableOptions keeps the options that match the regex of the variable. If the regex has a capturing group,
+// the option is replaced with the text of the group named value, or of the first group.
+func filterVariableOptions(regex string, options []string) ([]string, error) {
+	if regex == "" {
+		return dedupVariableOptions(options), nil
+	}
+	// the regex can be written in the JavaScript syntax /regex/flags.
+	if strings.HasPrefix(regex, "/") {
+		if i := strings.LastIndex(regex, "/"); i > 0 {
+			flags := regex[i+1:]
+			regex = regex[1:i]
+			if strings.Contains(flags, "i") {
+				regex = "(?i)" + regex
+			}
+		}
+	}
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("filterVariableOptions: invalid regex %q: %w", regex, err)
+	}
+	group := 1
+	if i := re.SubexpIndex("value"); i > 0 {
+		group = i
+	}
+	filtered := make([]string, 0, len(options))
+	for _, option := range options {
+		match := re.FindStringSubmatch(option)
+		if match == nil {
+			continue
+		}
+		if len(match) > group && match[group] != "" {
+			option = match[group]
+		}
+		filtered = append(filtered, option)
+	}
+	return dedupVariableOptions(filtered), nil
+}
+
+func dedupVariableOptions(options []string) []string {
+	seen := make(map[string]struct{}, len(options))
+	result := make([]string, 0, len(options))
+	for _, option := range options {
+		if _, ok := seen[option]; ok {
+			continue
+		}
+		seen[option] = struct{}{}
+		result = append(result, option)
+	}
+	return result
+}
+
+// savedVariableOptions returns the values of the options saved in the dashboard, except the "All" option.
+func savedVariableOptions(variable *simplejson.Json) []string {
+	var options []string
+	for _, optionObj := range variable.Get("options").MustArray() {
+		option := simplejson.NewFromAny(optionObj)
+		values := jsonStrings(option.Get("value"))
+		for _, v := range values {
+			if v != allVariableValue {
+				options = append(options, v)
+			}
+		}
+	}
+	return options
+}
+
+// splitVariableQuery returns the values of the options in the query of a custom or interval variable,
+// which is a comma-separated list of values, or of pairs "text : value".
+func splitVariableQuery(query string) []string {
+	var options []string
+	for _, part := range variableQueryPartRegex.FindAllString(query, -1) {
+		part = strings.ReplaceAll(part, `\,`, ",")
+		if text, value, ok := strings.Cut(part, " : "); ok && text != "" {
+			part = value
+		}
+		if part = strings.TrimSpace(part); part != "" {
+			options = append(options, part)
+		}
+	}
+	return options
+}
+
+func currentVariableValues(variable *simplejson.Json) []string {
+	return jsonStrings(variable.Get("current").Get("value"))
+}
+
+// jsonStrings returns the value that can be a string or an array of strings as a slice.
+func jsonStrings(value *simplejson.Json) []string {
+	if s, err := value.String(); err == nil {
+		return []string{s}
+	}
+	return value.MustStringArray()
+}
+
+// interpolateVariables replaces the template variables in all strings of the query with their values.
+// Variables that are not in values, such as global variables, are kept for the data source.
+func interpolateVariables(query *simplejson.Json, values map[string]variableValue) {
+	if len(values) == 0 {
+		return
+	}
+	dsType := query.Get("datasource").Get("type").MustString()
+	for k, v := range query.MustMap() {
+		query.Set(k, interpolateJSONValue(v, values, dsType))
+	}
+}
+
+func interpolateJSONValue(v any, values map[string]variableValue, dsType string) any {
+	switch v := v.(type) {
+	case string:
+		return interpolateString(v, values, dsType)
+	case map[string]any:
+		for k, item := range v {
+			v[k] = interpolateJSONValue(item, values, dsType)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = interpolateJSONValue(item, values, dsType)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func interpolateString(s string, values map[string]variableValue, dsType string) string {
+	return variableRegex.ReplaceAllStringFunc(s, func(match string) string {
+		groups := variableRegex.FindStringSubmatch(match)
+		name := groups[1] + groups[2] + groups[4]
+		format := groups[3] + groups[6]
+		value, ok := values[name]
+		if !ok {
+			return match
+		}
+		return formatVariableValue(value, format, dsType)
+	})
+}
+
+// formatVariableValue formats the value of a variable with the format of the variable expression. If there is no
+// format, values of variables with a single value are interpolated as is, and values of multi-value variables are
+// formatted in the syntax of the data source.
+func formatVariableValue(value variableValue, format string, dsType string) string {
+	if value.raw {
+		return strings.Join(value.values, ",")
+	}
+	values := value.values
+	if format == "" {
+		if !value.multi {
+			return strings.Join(values, ",")
+		}
+		switch dsType {
+		case "prometheus", "loki":
+			format = "regex"
+		case "mysql", "postgres", "grafana-postgresql-datasource", "mssql":
+			format = "singlequote"
+		default:
+			format = "glob"
+		}
+	}
+
+	switch format {
+	case "regex":
+		escaped := make([]string, 0, len(values))
+		for _, v := range values {
+			escaped = append(escaped, regexp.QuoteMeta(v))
+		}
+		if len(escaped) == 1 {
+			return escaped[0]
+		}
+		return "(" + strings.Join(escaped, "|") + ")"
+	case "pipe":
+		return strings.Join(values, "|")
+	case "csv", "raw", "text":
+		return strings.Join(values, ",")
+	case "json":
+		b, _ := json.Marshal(values)
+		return string(b)
+	case "singlequote":
+		return quoteVariableValues(values, "'", `\'`)
+	case "sqlstring":
+		return quoteVariableValues(values, "'", "''")
+	case "doublequote":
+		return quoteVariableValues(values, `"`, `\"`)
+	default:
+		if len(values) == 1 {
+			return values[0]
+		}
+		return "{" + strings.Join(values, ",") + "}"
+	}
+}
+
+func quoteVariableValues(values []string, quote string, escapedQuote string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, quote+strings.ReplaceAll(v, quote, escapedQuote)+quote)
+	}
+	return strings.Join(quoted, ",")
+}
+
+// sanitizeVariables removes the queries of the query variables from the dashboard, and replaces their options with the
+// options that viewers may select, if they are known, so that the frontend does not need to query them.
+func sanitizeVariables(data *simplejson.Json, variables dashboardVariables) {
+	for _, variableObj := range data.Get("templating").Get("list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		if variable.Get("type").MustString() != "query" {
+			continue
+		}
+		variable.Del("query")
+		variable.Del("definition")
+		variable.Set("refresh", 0)
+
+		resolved, ok := variables.options[variable.Get("name").MustString()]
+		if !ok {
+			continue
+		}
+		options := make([]any, 0, len(resolved))
+		for _, option := range resolved {
+			options = append(options, map[string]any{"text": option, "value": option, "selected": false})
+		}
+		variable.Set("options", options)
+	}
+}
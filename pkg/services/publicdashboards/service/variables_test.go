@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/service/intervalv2"
+	"github.com/grafana/grafana/pkg/services/query"
+)
+
+const dashboardWithVariables = `
+{
+  "panels": [
+    {
+      "id": 1,
+      "targets": [
+        {
+          "datasource": {"type": "prometheus", "uid": "prom"},
+          "expr": "up{env=~\"$env\", service=\"${service}\", region=\"[[region]]\"}",
+          "legendFormat": "${env:csv} $__interval",
+          "refId": "A"
+        }
+      ]
+    }
+  ],
+  "templating": {
+    "list": [
+      {
+        "name": "env",
+        "type": "custom",
+        "query": "prod,staging,dev",
+        "multi": true,
+        "includeAll": true,
+        "current": {"text": "prod", "value": ["prod"]}
+      },
+      {
+        "name": "service",
+        "type": "query",
+        "datasource": {"type": "prometheus", "uid": "prom"},
+        "query": {"query": "label_values(up{env=~\"$env\"}, service)", "refId": "PrometheusVariableQueryEditor-VariableQuery"},
+        "current": {"text": "api", "value": "api"}
+      },
+      {
+        "name": "region",
+        "type": "query",
+        "datasource": {"type": "graphite", "uid": "graphite"},
+        "query": "regions.*",
+        "options": [{"text": "eu", "value": "eu"}, {"text": "us", "value": "us"}],
+        "current": {"text": "us", "value": "us"}
+      },
+      {
+        "name": "cluster",
+        "type": "constant",
+        "query": "main"
+      },
+      {
+        "name": "filter",
+        "type": "textbox",
+        "query": "default",
+        "current": {"text": "default", "value": "default"}
+      }
+    ]
+  }
+}`
+
+func TestResolveVariables(t *testing.T) {
+	newService := func(t *testing.T) (*PublicDashboardServiceImpl, *query.FakeQueryService, *dashboards.Dashboard) {
+		dashData, err := simplejson.NewJson([]byte(dashboardWithVariables))
+		require.NoError(t, err)
+		fakeQueryService := &query.FakeQueryService{}
+		fakeQueryService.On("QueryData", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&backend.QueryDataResponse{
+			Responses: backend.Responses{
+				"PrometheusVariableQueryEditor-VariableQuery": {
+					Frames: data.Frames{data.NewFrame("", data.NewField("text", nil, []string{"api", "web", "api"}))},
+				},
+			},
+		}, nil)
+		service := &PublicDashboardServiceImpl{QueryDataService: fakeQueryService, features: featuremgmt.WithFeatures()}
+		return service, fakeQueryService, &dashboards.Dashboard{OrgID: 1, Data: dashData}
+	}
+	ts := TimeSettings{From: "1000", To: "2000"}
+
+	t.Run("resolves variables to the saved values", func(t *testing.T) {
+		service, fakeQueryService, dashboard := newService(t)
+
+		variables, err := service.resolveVariables(context.Background(), dashboard, ts, nil)
+		require.NoError(t, err)
+		require.Equal(t, map[string]variableValue{
+			"env":     {values: []string{"prod"}, multi: true},
+			"service": {values: []string{"api"}},
+			"region":  {values: []string{"us"}},
+			"cluster": {values: []string{"main"}},
+			"filter":  {values: []string{"default"}},
+		}, variables.values)
+		require.Equal(t, map[string][]string{
+			"env":     {"prod", "staging", "dev"},
+			"service": {"api", "web"},
+			"region":  {"eu", "us"},
+		}, variables.options)
+
+		// the query of the variable is interpolated with the values of the variables before it.
+		req := fakeQueryService.Calls[0].Arguments.Get(3).(dtos.MetricRequest)
+		require.Equal(t, "1000", req.From)
+		require.Len(t, req.Queries, 1)
+		require.Equal(t, `label_values(up{env=~"prod"}, service)`, req.Queries[0].Get("query").MustString())
+		require.Equal(t, "prom", req.Queries[0].Get("datasource").Get("uid").MustString())
+		// the dashboard is not changed by the interpolation.
+		require.Contains(t, dashboard.Data.Get("templating").Get("list").GetIndex(1).Get("query").Get("query").MustString(), "$env")
+	})
+
+	t.Run("resolves variables to the values selected by the viewer", func(t *testing.T) {
+		service, _, dashboard := newService(t)
+
+		variables, err := service.resolveVariables(context.Background(), dashboard, ts, map[string][]string{
+			"env":     {"staging", "dev"},
+			"service": {"web"},
+			"region":  {"eu"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, variableValue{values: []string{"staging", "dev"}, multi: true}, variables.values["env"])
+		require.Equal(t, variableValue{values: []string{"web"}}, variables.values["service"])
+		require.Equal(t, variableValue{values: []string{"eu"}}, variables.values["region"])
+	})
+
+	t.Run("resolves the all option to all options", func(t *testing.T) {
+		service, _, dashboard := newService(t)
+
+		variables, err := service.resolveVariables(context.Background(), dashboard, ts, map[string][]string{"env": {"$__all"}})
+		require.NoError(t, err)
+		require.Equal(t, variableValue{values: []string{"prod", "staging", "dev"}, multi: true}, variables.values["env"])
+
+		dashboard.Data.Get("templating").Get("list").GetIndex(0).Set("allValue", ".*")
+		variables, err = service.resolveVariables(context.Background(), dashboard, ts, map[string][]string{"env": {"$__all"}})
+		require.NoError(t, err)
+		require.Equal(t, variableValue{values: []string{".*"}, raw: true}, variables.values["env"])
+	})
+
+	t.Run("returns an error if the viewer selects values that are not allowed", func(t *testing.T) {
+		testCases := map[string]map[string][]string{
+			"value that is not an option":                 {"env": {"prod", `"} or vector(1) or {a="`}},
+			"value that is not returned by the query":     {"service": {"db"}},
+			"more than one value of a single-value field": {"service": {"api", "web"}},
+			"no values":                    {"env": {}},
+			"value of a constant":          {"cluster": {"other"}},
+			"value of a text box":          {"filter": {"anything"}},
+			"value of an unknown variable": {"unknown": {"value"}},
+		}
+		for name, selected := range testCases {
+			t.Run(name, func(t *testing.T) {
+				service, _, dashboard := newService(t)
+				_, err := service.resolveVariables(context.Background(), dashboard, ts, selected)
+				require.ErrorIs(t, err, ErrInvalidVariableValue)
+			})
+		}
+	})
+}
+
+func TestCachedVariables(t *testing.T) {
+	dashData, err := simplejson.NewJson([]byte(dashboardWithVariables))
+	require.NoError(t, err)
+	fakeQueryService := &query.FakeQueryService{}
+	fakeQueryService.On("QueryData", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&backend.QueryDataResponse{
+		Responses: backend.Responses{
+			"PrometheusVariableQueryEditor-VariableQuery": {
+				Frames: data.Frames{data.NewFrame("", data.NewField("text", nil, []string{"api", "web"}))},
+			},
+		},
+	}, nil)
+	service := &PublicDashboardServiceImpl{QueryDataService: fakeQueryService, features: featuremgmt.WithFeatures(), variablesCache: newVariablesCache()}
+	dashboard := &dashboards.Dashboard{OrgID: 1, UID: "dash", Version: 1, Data: dashData}
+	publicDashboard := &PublicDashboard{TimeSelectionEnabled: true}
+	queryDto := PublicDashboardQueryDTO{
+		TimeRange: TimeRangeDTO{From: "now-1h", To: "now"},
+		Variables: map[string][]string{"service": {"web"}},
+	}
+	resolve := func(dashboard *dashboards.Dashboard, queryDto PublicDashboardQueryDTO) dashboardVariables {
+		variables, err := service.cachedVariables(context.Background(), dashboard, publicDashboard, queryDto)
+		require.NoError(t, err)
+		return variables
+	}
+
+	// the queries of all panels reuse the variables resolved for the first one.
+	variables := resolve(dashboard, queryDto)
+	require.Equal(t, variableValue{values: []string{"web"}}, variables.values["service"])
+	require.Equal(t, variables, resolve(dashboard, queryDto))
+	fakeQueryService.AssertNumberOfCalls(t, "QueryData", 1)
+
+	// another time range, other selected values or a new version of the dashboard resolve the variables again.
+	otherRange := queryDto
+	otherRange.TimeRange = TimeRangeDTO{From: "now-6h", To: "now"}
+	resolve(dashboard, otherRange)
+	fakeQueryService.AssertNumberOfCalls(t, "QueryData", 2)
+
+	otherValues := queryDto
+	otherValues.Variables = map[string][]string{"service": {"api"}}
+	require.Equal(t, variableValue{values: []string{"api"}}, resolve(dashboard, otherValues).values["service"])
+	fakeQueryService.AssertNumberOfCalls(t, "QueryData", 3)
+
+	newVersion := *dashboard
+	newVersion.Version = 2
+	resolve(&newVersion, queryDto)
+	fakeQueryService.AssertNumberOfCalls(t, "QueryData", 4)
+
+	// errors are not cached.
+	invalid := queryDto
+	invalid.Variables = map[string][]string{"service": {"db"}}
+	for i := 0; i < 2; i++ {
+		_, err := service.cachedVariables(context.Background(), dashboard, publicDashboard, invalid)
+		require.ErrorIs(t, err, ErrInvalidVariableValue)
+	}
+	fakeQueryService.AssertNumberOfCalls(t, "QueryData", 6)
+}
+
+func TestInterpolateVariables(t *testing.T) {
+	values := map[string]variableValue{
+		"single": {values: []string{"a.b"}},
+		"multi":  {values: []string{"a.b", "c'd"}, multi: true},
+		"all":    {values: []string{".*"}, raw: true},
+	}
+
+	testCases := []struct {
+		dsType   string
+		query    string
+		expected string
+	}{
+		{dsType: "prometheus", query: "$single", expected: "a.b"},
+		{dsType: "prometheus", query: "${multi}", expected: `(a\.b|c'd)`},
+		{dsType: "prometheus", query: "[[all]]", expected: ".*"},
+		{dsType: "mysql", query: "IN ($multi)", expected: `IN ('a.b','c\'d')`},
+		{dsType: "graphite", query: "servers.$multi.cpu", expected: "servers.{a.b,c'd}.cpu"},
+		{dsType: "graphite", query: "${multi:csv}", expected: "a.b,c'd"},
+		{dsType: "graphite", query: "${multi:pipe}", expected: "a.b|c'd"},
+		{dsType: "graphite", query: "${multi:json}", expected: `["a.b","c'd"]`},
+		{dsType: "graphite", query: "${multi:sqlstring}", expected: `'a.b','c''d'`},
+		{dsType: "graphite", query: "${multi:doublequote}", expected: `"a.b","c'd"`},
+		{dsType: "graphite", query: "[[single:regex]]", expected: `a\.b`},
+		{dsType: "graphite", query: "$unknown $__interval", expected: "$unknown $__interval"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			query := simplejson.NewFromAny(map[string]any{
+				"datasource": map[string]any{"type": tc.dsType},
+				"nested":     []any{map[string]any{"expr": tc.query}},
+			})
+			interpolateVariables(query, values)
+			require.Equal(t, tc.expected, query.Get("nested").GetIndex(0).Get("expr").MustString())
+		})
+	}
+}
+
+func TestGetMetricRequestWithVariables(t *testing.T) {
+	dashData, err := simplejson.NewJson([]byte(dashboardWithVariables))
+	require.NoError(t, err)
+	dashboard := &dashboards.Dashboard{OrgID: 1, Data: dashData}
+	dashboard.Data.Set("time", map[string]any{"from": "now-1h", "to": "now"})
+	fakeQueryService := &query.FakeQueryService{}
+	fakeQueryService.On("QueryData", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&backend.QueryDataResponse{
+		Responses: backend.Responses{
+			"PrometheusVariableQueryEditor-VariableQuery": {
+				Frames: data.Frames{data.NewFrame("", data.NewField("text", nil, []string{"api", "web"}))},
+			},
+		},
+	}, nil)
+	service := &PublicDashboardServiceImpl{
+		QueryDataService:   fakeQueryService,
+		features:           featuremgmt.WithFeatures(),
+		intervalCalculator: intervalv2.NewCalculator(),
+	}
+	publicDashboard := &PublicDashboard{IsEnabled: true}
+
+	metricReq, err := service.GetMetricRequest(context.Background(), dashboard, publicDashboard, 1, PublicDashboardQueryDTO{
+		IntervalMs:    1,
+		MaxDataPoints: 1,
+		Variables:     map[string][]string{"env": {"prod", "dev"}, "service": {"web"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, metricReq.Queries, 1)
+	require.Equal(t, `up{env=~"(prod|dev)", service="web", region="us"}`, metricReq.Queries[0].Get("expr").MustString())
+	require.Equal(t, "prod,dev $__interval", metricReq.Queries[0].Get("legendFormat").MustString())
+
+	_, err = service.GetMetricRequest(context.Background(), dashboard, publicDashboard, 1, PublicDashboardQueryDTO{
+		IntervalMs:    1,
+		MaxDataPoints: 1,
+		Variables:     map[string][]string{"service": {`web"} or up{job="x`}},
+	})
+	require.ErrorIs(t, err, ErrInvalidVariableValue)
+}
+
+func TestSanitizeVariables(t *testing.T) {
+	data, err := simplejson.NewJson([]byte(dashboardWithVariables))
+	require.NoError(t, err)
+
+	sanitizeVariables(data, dashboardVariables{options: map[string][]string{"service": {"api", "web"}}})
+
+	service := data.Get("templating").Get("list").GetIndex(1)
+	_, ok := service.CheckGet("query")
+	require.False(t, ok)
+	require.Equal(t, 0, service.Get("refresh").MustInt())
+	require.Equal(t, []any{
+		map[string]any{"text": "api", "value": "api", "selected": false},
+		map[string]any{"text": "web", "value": "web", "selected": false},
+	}, service.Get("options").MustArray())
+
+	// the saved options are kept if the options are not resolved.
+	region := data.Get("templating").Get("list").GetIndex(2)
+	_, ok = region.CheckGet("query")
+	require.False(t, ok)
+	require.Len(t, region.Get("options").MustArray(), 2)
+
+	// custom variables are not changed.
+	require.Equal(t, "prod,staging,dev", data.Get("templating").Get("list").GetIndex(0).Get("query").MustString())
+}
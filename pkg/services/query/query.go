@@ -203,6 +203,7 @@ func buildErrorResponses(err error, queries []*simplejson.Json) splitResponse {
 func (s *ServiceImpl) handleExpressions(ctx context.Context, user identity.Requester, parsedReq *parsedRequest) (*backend.QueryDataResponse, error) {
 	exprReq := expr.Request{
 		Queries: []expr.Query{},
+		Explain: parsedReq.explain,
 	}
 
 	if user != nil { // for passthrough authentication, SSE does not authenticate
@@ -281,6 +282,7 @@ func (s *ServiceImpl) parseMetricRequest(ctx context.Context, user identity.Requ
 	timeRange := gtime.NewTimeRange(reqDTO.From, reqDTO.To)
 	req := &parsedRequest{
 		hasExpression: false,
+		explain:       reqDTO.Explain,
 		parsedQueries: make(map[string][]parsedQuery),
 		dsTypes:       make(map[string]bool),
 	}
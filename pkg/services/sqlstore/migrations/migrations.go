@@ -138,6 +138,14 @@ func (oss *OSSMigrations) AddMigration(mg *Migrator) {
 	accesscontrol.AddActionSetPermissionsMigrator(mg)
 
 	externalsession.AddMigration(mg)
+
+	ualert.AddRuleUpdatedByColumn(mg)
+
+	ualert.AddStateHistoryTables(mg)
+
+	ualert.AddRuleSuppressedByColumn(mg)
+
+	addResourceProvenanceMigrations(mg)
 }
 
 func addStarMigrations(mg *Migrator) {
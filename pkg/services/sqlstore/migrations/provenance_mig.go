@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addResourceProvenanceMigrations(mg *Migrator) {
+	resourceProvenanceV1 := Table{
+		Name: "resource_provenance",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, Nullable: false, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "kind", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "resource_key", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "provenance", Type: DB_NVarchar, Length: 40, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "kind", "resource_key"}, Type: UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create resource_provenance table v1", NewAddTableMigration(resourceProvenanceV1))
+
+	mg.AddMigration("add unique index resource_provenance.org_id-kind-resource_key", NewAddIndexMigration(resourceProvenanceV1, resourceProvenanceV1.Indices[0]))
+}
@@ -0,0 +1,21 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddRuleSuppressedByColumn adds column to store the rules whose firing alerts suppress the alerts of an alerting rule.
+func AddRuleSuppressedByColumn(mg *migrator.Migrator) {
+	column := &migrator.Column{
+		Name:     "suppressed_by",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}
+
+	mg.AddMigration(
+		"add suppressed_by column to alert_rule table",
+		migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, column),
+	)
+	mg.AddMigration(
+		"add suppressed_by column to alert_rule_version table",
+		migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, column),
+	)
+}
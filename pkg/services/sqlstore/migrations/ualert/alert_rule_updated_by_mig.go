@@ -0,0 +1,22 @@
+package ualert
+
+import "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+
+// AddRuleUpdatedByColumn adds column to store the UID of the user that created or updated a version of an alerting rule.
+func AddRuleUpdatedByColumn(mg *migrator.Migrator) {
+	column := &migrator.Column{
+		Name:     "updated_by",
+		Type:     migrator.DB_NVarchar,
+		Length:   40,
+		Nullable: true,
+	}
+
+	mg.AddMigration(
+		"add updated_by column to alert_rule table",
+		migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, column),
+	)
+	mg.AddMigration(
+		"add updated_by column to alert_rule_version table",
+		migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, column),
+	)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/licensing"
 	pref "github.com/grafana/grafana/pkg/services/preference"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
 	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
@@ -22,6 +23,7 @@ type TeamAPI struct {
 	cfg                    *setting.Cfg
 	preferenceService      pref.Service
 	ds                     dashboards.DashboardService
+	provenanceService      provenance.Service
 	logger                 log.Logger
 }
 
@@ -36,6 +38,7 @@ func ProvideTeamAPI(
 	cfg *setting.Cfg,
 	preferenceService pref.Service,
 	ds dashboards.DashboardService,
+	provenanceService provenance.Service,
 ) *TeamAPI {
 	tapi := &TeamAPI{
 		teamService:            teamService,
@@ -46,6 +49,7 @@ func ProvideTeamAPI(
 		cfg:                    cfg,
 		preferenceService:      preferenceService,
 		ds:                     ds,
+		provenanceService:      provenanceService,
 		logger:                 log.New("team-api"),
 	}
 
@@ -56,25 +60,26 @@ func ProvideTeamAPI(
 func (tapi *TeamAPI) registerRoutes(router routing.RouteRegister, ac accesscontrol.AccessControl) {
 	authorize := accesscontrol.Middleware(ac)
 	teamResolver := team.MiddlewareTeamUIDResolver(tapi.teamService, ":teamId")
+	notProvisioned := provenance.Middleware(tapi.provenanceService, provenance.KindTeam, ":teamId")
 	router.Group("/api", func(apiRoute routing.RouteRegister) {
 		// team (admin permission required)
 		apiRoute.Group("/teams", func(teamsRoute routing.RouteRegister) {
 			teamsRoute.Post("/", authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsCreate)),
 				routing.Wrap(tapi.createTeam))
 			teamsRoute.Put("/:teamId", teamResolver, authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsWrite,
-				accesscontrol.ScopeTeamsID)), routing.Wrap(tapi.updateTeam))
+				accesscontrol.ScopeTeamsID)), notProvisioned, routing.Wrap(tapi.updateTeam))
 			teamsRoute.Delete("/:teamId", teamResolver, authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsDelete,
-				accesscontrol.ScopeTeamsID)), routing.Wrap(tapi.deleteTeamByID))
+				accesscontrol.ScopeTeamsID)), notProvisioned, routing.Wrap(tapi.deleteTeamByID))
 			teamsRoute.Get("/:teamId/members", teamResolver, authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsPermissionsRead,
 				accesscontrol.ScopeTeamsID)), routing.Wrap(tapi.getTeamMembers))
 			teamsRoute.Post("/:teamId/members", teamResolver, authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsPermissionsWrite,
-				accesscontrol.ScopeTeamsID)), routing.Wrap(tapi.addTeamMember))
+				accesscontrol.ScopeTeamsID)), notProvisioned, routing.Wrap(tapi.addTeamMember))
 			teamsRoute.Put("/:teamId/members/:userId", teamResolver, authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsPermissionsWrite,
-				accesscontrol.ScopeTeamsID)), routing.Wrap(tapi.updateTeamMember))
+				accesscontrol.ScopeTeamsID)), notProvisioned, routing.Wrap(tapi.updateTeamMember))
 			teamsRoute.Put("/:teamId/members", teamResolver, authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsPermissionsWrite,
-				accesscontrol.ScopeTeamsID)), routing.Wrap(tapi.setTeamMemberships))
+				accesscontrol.ScopeTeamsID)), notProvisioned, routing.Wrap(tapi.setTeamMemberships))
 			teamsRoute.Delete("/:teamId/members/:userId", teamResolver, authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsPermissionsWrite,
-				accesscontrol.ScopeTeamsID)), routing.Wrap(tapi.removeTeamMember))
+				accesscontrol.ScopeTeamsID)), notProvisioned, routing.Wrap(tapi.removeTeamMember))
 			teamsRoute.Get("/:teamId/preferences", teamResolver, authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsRead,
 				accesscontrol.ScopeTeamsID)), routing.Wrap(tapi.getTeamPreferences))
 			teamsRoute.Put("/:teamId/preferences", teamResolver, authorize(accesscontrol.EvalPermission(accesscontrol.ActionTeamsWrite,
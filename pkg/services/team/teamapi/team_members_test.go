@@ -20,6 +20,8 @@ import (
 	"github.com/grafana/grafana/pkg/services/licensing"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/preference/preftest"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance"
+	"github.com/grafana/grafana/pkg/services/provisioning/provenance/provenancetest"
 	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/services/team/teamtest"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -48,6 +50,7 @@ func SetupAPITestServer(t *testing.T, teamService team.Service, opts ...func(a *
 		cfg,
 		preftest.NewPreferenceServiceFake(),
 		dashboards.NewFakeDashboardService(t),
+		provenancetest.NewFakeService(),
 	)
 	for _, o := range opts {
 		o(a)
@@ -197,6 +200,47 @@ func TestDeleteTeamMembersAPIEndpoint(t *testing.T) {
 	})
 }
 
+func TestProvisionedTeamMembersAPIEndpoint(t *testing.T) {
+	provenanceService := provenancetest.NewFakeService()
+	require.NoError(t, provenanceService.SetProvenance(context.Background(), 1, provenance.KindTeam, "1", provenance.ProvenanceFile))
+	router := routing.NewRouteRegister()
+	ProvideTeamAPI(router,
+		&teamtest.FakeService{ExpectedIsMember: true},
+		actest.FakeService{},
+		acimpl.ProvideAccessControl(featuremgmt.WithFeatures(), zanzana.NewNoopClient()),
+		&actest.FakePermissionsService{},
+		&usertest.FakeUserService{},
+		&licensing.OSSLicensingService{},
+		setting.NewCfg(),
+		preftest.NewPreferenceServiceFake(),
+		dashboards.NewFakeDashboardService(t),
+		provenanceService,
+	)
+	server := webtest.NewServer(t, router)
+
+	t.Run("should not be able to add member to provisioned team", func(t *testing.T) {
+		req := webtest.RequestWithSignedInUser(
+			server.NewRequest(http.MethodPost, "/api/teams/1/members", strings.NewReader("{\"userId\": 1}")),
+			authedUserWithPermissions(1, 1, []accesscontrol.Permission{{Action: accesscontrol.ActionTeamsPermissionsWrite, Scope: "teams:id:1"}}),
+		)
+		res, err := server.SendJSON(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+
+	t.Run("should not be able to delete member of provisioned team", func(t *testing.T) {
+		req := webtest.RequestWithSignedInUser(
+			server.NewRequest(http.MethodDelete, "/api/teams/1/members/1", nil),
+			authedUserWithPermissions(1, 1, []accesscontrol.Permission{{Action: accesscontrol.ActionTeamsPermissionsWrite, Scope: "teams:id:1"}}),
+		)
+		res, err := server.SendJSON(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	})
+}
+
 func Test_getTeamMembershipUpdates(t *testing.T) {
 	type testCase struct {
 		description     string
@@ -303,6 +347,7 @@ func Test_getTeamMembershipUpdates(t *testing.T) {
 				cfg,
 				preftest.NewPreferenceServiceFake(),
 				dashboards.NewFakeDashboardService(t),
+				provenancetest.NewFakeService(),
 			)
 
 			user := &user.SignedInUser{UserID: 1, OrgID: 1, OrgRole: org.RoleAdmin, Permissions: map[int64]map[string][]string{1: {accesscontrol.ActionOrgUsersRead: {"users:id:*"}}}}
@@ -415,6 +415,16 @@ type Cfg struct {
 
 	// ExpressionsEnabled specifies whether expressions are enabled.
 	ExpressionsEnabled bool
+	// SQLExpressionMaxInputRows is the maximum number of rows of each input table of a SQL expression. 0 means no limit.
+	SQLExpressionMaxInputRows int64
+	// SQLExpressionMaxOutputRows is the maximum number of rows a SQL expression may return. 0 means no limit.
+	SQLExpressionMaxOutputRows int64
+	// SQLExpressionMemoryLimitMB is the memory limit in megabytes of the database that runs a SQL expression. 0 means no limit.
+	SQLExpressionMemoryLimitMB int64
+	// SQLExpressionTimeout is the maximum duration of a SQL expression. 0 means no limit.
+	SQLExpressionTimeout time.Duration
+	// SQLExpressionDuckDBPath is the path of the DuckDB command line interface that runs SQL expressions.
+	SQLExpressionDuckDBPath string
 
 	ImageUploadProvider string
 
@@ -754,6 +764,11 @@ func (cfg *Cfg) readAnnotationSettings() error {
 func (cfg *Cfg) readExpressionsSettings() {
 	expressions := cfg.Raw.Section("expressions")
 	cfg.ExpressionsEnabled = expressions.Key("enabled").MustBool(true)
+	cfg.SQLExpressionMaxInputRows = expressions.Key("sql_expression_max_input_rows").MustInt64(0)
+	cfg.SQLExpressionMaxOutputRows = expressions.Key("sql_expression_max_output_rows").MustInt64(0)
+	cfg.SQLExpressionMemoryLimitMB = expressions.Key("sql_expression_memory_limit_mb").MustInt64(0)
+	cfg.SQLExpressionTimeout = expressions.Key("sql_expression_timeout").MustDuration(10 * time.Second)
+	cfg.SQLExpressionDuckDBPath = expressions.Key("sql_expression_duckdb_path").MustString("duckdb")
 }
 
 type AnnotationCleanupSettings struct {
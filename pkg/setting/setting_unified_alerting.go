@@ -65,6 +65,13 @@ const (
 	lokiDefaultMaxQueryLength      = 721 * time.Hour // 30d1h, matches the default value in Loki
 	defaultRecordingRequestTimeout = 10 * time.Second
 	lokiDefaultMaxQuerySize        = 65536 // 64kb
+	stateHistorySQLDefaultMaxAge   = 30 * 24 * time.Hour
+	recordingLocalDefaultRetention = 15 * 24 * time.Hour
+
+	// RecordingRuleBackendPrometheus writes the series of recording rules to a Prometheus remote write endpoint.
+	RecordingRuleBackendPrometheus = "prometheus"
+	// RecordingRuleBackendLocal writes the series of recording rules to a time series database embedded in Grafana.
+	RecordingRuleBackendLocal = "local"
 )
 
 type UnifiedAlertingSettings struct {
@@ -90,6 +97,7 @@ type UnifiedAlertingSettings struct {
 	HARedisMaxConns                 int
 	HARedisTLSEnabled               bool
 	HARedisTLSConfig                dstls.ClientConfig
+	HAShardedScheduling             bool
 	MaxAttempts                     int64
 	MinInterval                     time.Duration
 	EvaluationTimeout               time.Duration
@@ -115,6 +123,9 @@ type UnifiedAlertingSettings struct {
 	MaxStateSaveConcurrency   int
 	StatePeriodicSaveInterval time.Duration
 	RulesPerRuleGroupLimit    int64
+	// SeriesPerRuleLimit and SeriesPerOrgLimit limit the number of series tracked by the state manager. Negative values mean no limit.
+	SeriesPerRuleLimit int64
+	SeriesPerOrgLimit  int64
 
 	// Retention period for Alertmanager notification log entries.
 	NotificationLogRetention time.Duration
@@ -130,11 +141,17 @@ type UnifiedAlertingSettings struct {
 
 type RecordingRuleSettings struct {
 	Enabled           bool
+	Backend           string
 	URL               string
 	BasicAuthUsername string
 	BasicAuthPassword string
 	CustomHeaders     map[string]string
 	Timeout           time.Duration
+
+	// LocalPath is the directory of the embedded time series database used by the local backend.
+	LocalPath string
+	// LocalRetention is the duration for which the local backend keeps the recorded series.
+	LocalRetention time.Duration
 }
 
 // RemoteAlertmanagerSettings contains the configuration needed
@@ -174,6 +191,8 @@ type UnifiedAlertingStateHistorySettings struct {
 	MultiPrimary          string
 	MultiSecondaries      []string
 	ExternalLabels        map[string]string
+	// SQLMaxAge is how long the "sql" backend keeps state history for. 0 keeps it forever.
+	SQLMaxAge time.Duration
 }
 
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
@@ -282,6 +301,7 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	uaCfg.HARedisTLSConfig.InsecureSkipVerify = ua.Key("ha_redis_tls_insecure_skip_verify").MustBool(false)
 	uaCfg.HARedisTLSConfig.CipherSuites = ua.Key("ha_redis_tls_cipher_suites").MustString("")
 	uaCfg.HARedisTLSConfig.MinVersion = ua.Key("ha_redis_tls_min_version").MustString("")
+	uaCfg.HAShardedScheduling = ua.Key("ha_sharded_scheduling").MustBool(false)
 
 	// TODO load from ini file
 	uaCfg.DefaultConfiguration = alertmanagerDefaultConfiguration
@@ -367,6 +387,8 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	quotas := iniFile.Section("quota")
 	uaCfg.RulesPerRuleGroupLimit = quotas.Key("alerting_rule_group_rules").MustInt64(100)
 	uaCfg.EvaluationResultLimit = quotas.Key("alerting_rule_evaluation_results").MustInt(-1)
+	uaCfg.SeriesPerRuleLimit = quotas.Key("alerting_rule_series").MustInt64(-1)
+	uaCfg.SeriesPerOrgLimit = quotas.Key("alerting_org_series").MustInt64(-1)
 
 	remoteAlertmanager := iniFile.Section("remote.alertmanager")
 	uaCfgRemoteAM := RemoteAlertmanagerSettings{
@@ -422,16 +444,31 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		MultiPrimary:          stateHistory.Key("primary").MustString(""),
 		MultiSecondaries:      splitTrim(stateHistory.Key("secondaries").MustString(""), ","),
 		ExternalLabels:        stateHistoryLabels.KeysHash(),
+		SQLMaxAge:             stateHistory.Key("sql_max_age").MustDuration(stateHistorySQLDefaultMaxAge),
 	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
 	rr := iniFile.Section("recording_rules")
 	uaCfgRecordingRules := RecordingRuleSettings{
 		Enabled:           rr.Key("enabled").MustBool(false),
+		Backend:           rr.Key("backend").MustString(RecordingRuleBackendPrometheus),
 		URL:               rr.Key("url").MustString(""),
 		BasicAuthUsername: rr.Key("basic_auth_username").MustString(""),
 		BasicAuthPassword: rr.Key("basic_auth_password").MustString(""),
 		Timeout:           rr.Key("timeout").MustDuration(defaultRecordingRequestTimeout),
+		LocalPath:         makeAbsolute(rr.Key("local_path").MustString("recording_rules"), cfg.DataPath),
+	}
+	uaCfgRecordingRules.LocalRetention, err = gtime.ParseDuration(valueAsString(rr, "local_retention", recordingLocalDefaultRetention.String()))
+	if err != nil {
+		return err
+	}
+	switch uaCfgRecordingRules.Backend {
+	case RecordingRuleBackendPrometheus, RecordingRuleBackendLocal:
+	default:
+		return fmt.Errorf("setting 'backend' in section 'recording_rules' is invalid, only '%s' and '%s' are allowed", RecordingRuleBackendPrometheus, RecordingRuleBackendLocal)
+	}
+	if uaCfgRecordingRules.LocalRetention <= 0 {
+		return fmt.Errorf("setting 'local_retention' in section 'recording_rules' must be greater than 0")
 	}
 
 	rrHeaders := iniFile.Section("recording_rules.custom_headers")
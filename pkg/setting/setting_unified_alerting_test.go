@@ -2,6 +2,7 @@ package setting
 
 import (
 	"math/rand"
+	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
@@ -73,6 +74,36 @@ func TestCfg_ReadUnifiedAlertingSettings(t *testing.T) {
 			require.Equal(t, SchedulerBaseInterval, cfg.UnifiedAlerting.BaseInterval)
 		})
 	})
+
+	t.Run("should read recording rules backend", func(t *testing.T) {
+		require.Equal(t, RecordingRuleBackendPrometheus, cfg.UnifiedAlerting.RecordingRules.Backend)
+		require.Equal(t, filepath.Join(cfg.DataPath, "recording_rules"), cfg.UnifiedAlerting.RecordingRules.LocalPath)
+		require.Equal(t, 15*24*time.Hour, cfg.UnifiedAlerting.RecordingRules.LocalRetention)
+
+		s, err := cfg.Raw.NewSection("recording_rules")
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			cfg.Raw.DeleteSection("recording_rules")
+			require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		})
+		_, err = s.NewKey("backend", RecordingRuleBackendLocal)
+		require.NoError(t, err)
+		_, err = s.NewKey("local_path", "/var/lib/grafana-recordings")
+		require.NoError(t, err)
+		_, err = s.NewKey("local_retention", "2d")
+		require.NoError(t, err)
+
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.Equal(t, RecordingRuleBackendLocal, cfg.UnifiedAlerting.RecordingRules.Backend)
+		require.Equal(t, "/var/lib/grafana-recordings", cfg.UnifiedAlerting.RecordingRules.LocalPath)
+		require.Equal(t, 48*time.Hour, cfg.UnifiedAlerting.RecordingRules.LocalRetention)
+
+		t.Run("and fail if backend is unknown", func(t *testing.T) {
+			_, err = s.NewKey("backend", "influxdb")
+			require.NoError(t, err)
+			require.Error(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		})
+	})
 }
 
 func TestUnifiedAlertingSettings(t *testing.T) {
@@ -80,6 +80,17 @@ func TestBacktesting(t *testing.T) {
 			var result data.Frame
 			require.NoErrorf(t, json.Unmarshal([]byte(body), &result), "cannot parse response to data frame")
 		})
+
+		t.Run("should simulate notifications", func(t *testing.T) {
+			request, ok := testData["data"]
+			require.Truef(t, ok, "The data file does not contain a field `data`")
+
+			status, body := apiCli.SubmitRuleForBacktestSimulation(t, request)
+			require.Equalf(t, http.StatusOK, status, "Response: %s", body)
+			var result apimodels.BacktestSimulationResult
+			require.NoErrorf(t, json.Unmarshal([]byte(body), &result), "cannot parse response to simulation result")
+			require.NotEmpty(t, result.Timeline)
+		})
 	})
 
 	t.Run("and request contains query", func(t *testing.T) {
@@ -118,6 +118,7 @@ func TestIntegrationAlertRulePermissions(t *testing.T) {
 
 			pathsToIgnore := []string{
 				"GrafanaManagedAlert.Updated",
+				"GrafanaManagedAlert.UpdatedBy",
 				"GrafanaManagedAlert.UID",
 				"GrafanaManagedAlert.ID",
 				"GrafanaManagedAlert.Data.Model",
@@ -419,6 +420,7 @@ func TestIntegrationAlertRuleNestedPermissions(t *testing.T) {
 
 			pathsToIgnore := []string{
 				"GrafanaManagedAlert.Updated",
+				"GrafanaManagedAlert.UpdatedBy",
 				"GrafanaManagedAlert.UID",
 				"GrafanaManagedAlert.ID",
 				"GrafanaManagedAlert.Data.Model",
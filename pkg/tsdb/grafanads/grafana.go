@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/store"
 	"github.com/grafana/grafana/pkg/services/unifiedSearch"
@@ -53,15 +54,20 @@ var (
 	)
 )
 
-func ProvideService(search searchV2.SearchService, searchNext unifiedSearch.SearchService, store store.StorageService, features featuremgmt.FeatureToggles) *Service {
-	return newService(search, searchNext, store, features)
+func ProvideService(search searchV2.SearchService, searchNext unifiedSearch.SearchService, store store.StorageService, features featuremgmt.FeatureToggles, recordings *writer.LocalStorage) *Service {
+	var recordingStorage recordingStorage
+	if recordings != nil {
+		recordingStorage = recordings
+	}
+	return newService(search, searchNext, store, features, recordingStorage)
 }
 
-func newService(search searchV2.SearchService, searchNext unifiedSearch.SearchService, store store.StorageService, features featuremgmt.FeatureToggles) *Service {
+func newService(search searchV2.SearchService, searchNext unifiedSearch.SearchService, store store.StorageService, features featuremgmt.FeatureToggles, recordings recordingStorage) *Service {
 	s := &Service{
 		search:     search,
 		searchNext: searchNext,
 		store:      store,
+		recordings: recordings,
 		log:        log.New("grafanads"),
 		features:   features,
 	}
@@ -69,11 +75,17 @@ func newService(search searchV2.SearchService, searchNext unifiedSearch.SearchSe
 	return s
 }
 
+// recordingStorage reads the series written by recording rules to the local storage.
+type recordingStorage interface {
+	Query(ctx context.Context, orgID int64, q writer.LocalQuery) (data.Frames, error)
+}
+
 // Service exists regardless of user settings
 type Service struct {
 	search     searchV2.SearchService
 	searchNext unifiedSearch.SearchService
 	store      store.StorageService
+	recordings recordingStorage
 	log        log.Logger
 	features   featuremgmt.FeatureToggles
 }
@@ -103,6 +115,8 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 			response.Responses[q.RefID] = s.doReadQuery(ctx, q)
 		case queryTypeSearch, queryTypeSearchNext:
 			response.Responses[q.RefID] = s.doSearchQuery(ctx, req, q)
+		case queryTypeRecordedSeries:
+			response.Responses[q.RefID] = s.doRecordedSeriesQuery(ctx, req, q)
 		default:
 			response.Responses[q.RefID] = backend.DataResponse{
 				Error: fmt.Errorf("unknown query type"),
@@ -169,6 +183,36 @@ func (s *Service) doReadQuery(ctx context.Context, query backend.DataQuery) back
 	return response
 }
 
+func (s *Service) doRecordedSeriesQuery(ctx context.Context, req *backend.QueryDataRequest, query backend.DataQuery) backend.DataResponse {
+	q := &recordedSeriesQueryModel{}
+	response := backend.DataResponse{}
+	err := json.Unmarshal(query.JSON, &q)
+	if err != nil {
+		response.Error = err
+		return response
+	}
+
+	if s.recordings == nil {
+		response.Error = writer.ErrLocalStorageDisabled
+		return response
+	}
+
+	frames, err := s.recordings.Query(ctx, req.PluginContext.OrgID, writer.LocalQuery{
+		Selector: q.Selector,
+		From:     query.TimeRange.From,
+		To:       query.TimeRange.To,
+	})
+	if err != nil {
+		response.Error = err
+		return response
+	}
+	for _, frame := range frames {
+		frame.RefID = query.RefID
+	}
+	response.Frames = frames
+	return response
+}
+
 func (s *Service) doRandomWalk(query backend.DataQuery) backend.DataResponse {
 	response := backend.DataResponse{}
 
@@ -17,6 +17,10 @@ const (
 	// currently only .csv files are supported,
 	// other file types will eventually be supported (parquet, etc)
 	queryTypeRead = "read"
+
+	// queryTypeRecordedSeries returns the series written by recording rules
+	// to the local storage that match a Prometheus series selector
+	queryTypeRecordedSeries = "recordedSeries"
 )
 
 type listQueryModel struct {
@@ -25,3 +29,7 @@ type listQueryModel struct {
 type readQueryModel struct {
 	Path string `json:"path"`
 }
+
+type recordedSeriesQueryModel struct {
+	Selector string `json:"selector"`
+}
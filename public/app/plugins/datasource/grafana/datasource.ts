@@ -147,6 +147,10 @@ export class GrafanaDatasource extends DataSourceWithBackend<GrafanaQuery> {
         if (!target.queryType) {
           target.queryType = GrafanaQueryType.RandomWalk;
         }
+        if (target.queryType === GrafanaQueryType.RecordedSeries) {
+          targets.push({ ...target, selector: templateSrv.replace(target.selector, request.scopedVars) });
+          continue;
+        }
         targets.push(target);
       }
     }
@@ -20,6 +20,7 @@ export enum GrafanaQueryType {
   Read = 'read',
   Search = 'search',
   SearchNext = 'searchNext',
+  RecordedSeries = 'recordedSeries',
 }
 
 export interface GrafanaQuery extends DataQuery {
@@ -33,6 +34,7 @@ export interface GrafanaQuery extends DataQuery {
   snapshot?: DataFrameJSON[];
   timeRegion?: TimeRegionConfig;
   file?: GrafanaQueryFile;
+  selector?: string; // for recorded series
 }
 
 export interface GrafanaQueryFile {
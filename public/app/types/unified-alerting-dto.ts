@@ -241,7 +241,14 @@ export interface PostableGrafanaRuleDefinition {
     metric: string;
     from: string;
   };
+  suppressed_by?: GrafanaSuppressingRule[];
 }
+
+export interface GrafanaSuppressingRule {
+  rule_uid: string;
+  equal?: string[];
+}
+
 export interface GrafanaRuleDefinition extends PostableGrafanaRuleDefinition {
   id?: string;
   uid: string;